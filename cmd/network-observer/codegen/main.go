@@ -36,17 +36,28 @@ func (r {{ $typeName }}) Get{{ .FieldName }}() {{ .FieldType }} {
   return r.{{ .FieldName }}
 }
 {{- end}}{{end}}
+
+// Implements NetworkRecord interface for the generated record objects
+{{range .RecordSetterTypes}}{{$typeName := .TypeName}}
+{{- range .Fields}}
+// Set{{ .FieldName }}
+func (r *{{ $typeName }}) Set{{ .FieldName }}(v {{ .FieldType }}) {
+  r.{{ .FieldName }} = v
+}
+{{- end}}{{end}}
 `
 
 var (
 	responseFieldTagRegexp = regexp.MustCompile(`json:"(results|count|timeRangeCount)[,"]`)
-	recordFieldTagRegexp   = regexp.MustCompile(`json:"(startTime|endTime)[,"]`)
+	recordFieldTagRegexp   = regexp.MustCompile(`json:"(identity|startTime|endTime)[,"]`)
+	recordSetterTagRegexp  = regexp.MustCompile(`json:"(network)[,"]`)
 )
 
 type Codegen struct {
-	PackageName   string
-	ResponseTypes []StructType
-	RecordTypes   []StructType
+	PackageName       string
+	ResponseTypes     []StructType
+	RecordTypes       []StructType
+	RecordSetterTypes []StructType
 }
 
 type StructType struct {
@@ -116,6 +127,13 @@ func main() {
 					Fields:   getters,
 				})
 			}
+			setters := fieldsMatchingType(st, recordSetterTagRegexp)
+			if len(setters) > 0 {
+				codegen.RecordSetterTypes = append(codegen.RecordSetterTypes, StructType{
+					TypeName: ts.Name.String(),
+					Fields:   setters,
+				})
+			}
 		default:
 			//return false
 		}
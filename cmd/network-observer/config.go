@@ -1,72 +1,139 @@
 package main
 
 import (
-	"crypto/tls"
-	"crypto/x509"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
+	"regexp"
 	"time"
 
-	"github.com/skupperproject/skupper/internal/utils/tlscfg"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/pkg/vanflow/session"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
 )
 
+// defaultNetworkName is the name of the network observed through the
+// router-endpoint flag when no networks file is configured
+const defaultNetworkName = "default"
+
 type Config struct {
 	APIListenAddress    string
 	APIEnableAccessLogs bool
-	APITLS              TLSSpec
+	APITLS              session.TLSSpec
 
 	EnableConsole   bool
 	ConsoleLocation string
 	PrometheusAPI   string
 
 	RouterURL     string
-	RouterTLS     TLSSpec
+	RouterTLS     session.TLSSpec
+	NetworksFile  string
 	FlowRecordTTL time.Duration
+	StoreDir      string
+
+	RetentionConfigFile string
+
+	CaptureFile string
+	ReplayFile  string
+	ReplaySpeed float64
+
+	OTLPEndpoint        string
+	OTLPMetricsInterval time.Duration
+
+	AlertRulesFile   string
+	ProbesConfigFile string
+	AuthConfigFile   string
 
 	VanflowLoggingProfile string
+	FlowLoggingConfigFile string
 
 	EnableProfile bool
 	CORSAllowAll  bool
 }
 
-type TLSSpec struct {
-	CA         string
-	Cert       string
-	Key        string
-	SkipVerify bool
+// NetworkSpec configures the router endpoint used to observe an application
+// network
+type NetworkSpec struct {
+	Name      string          `json:"name"`
+	RouterURL string          `json:"routerEndpoint"`
+	RouterTLS session.TLSSpec `json:"tls,omitempty"`
 }
 
-func (t TLSSpec) hasCert() bool {
-	return len(t.Cert) > 0
+type networksFile struct {
+	Networks []NetworkSpec `json:"networks"`
 }
 
-func (t TLSSpec) config() (*tls.Config, error) {
-	config := tlscfg.Modern()
-
-	config.InsecureSkipVerify = t.SkipVerify
+var networkNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
 
-	if len(t.CA) > 0 && !t.SkipVerify {
-		certPool := x509.NewCertPool()
-		file, err := os.ReadFile(t.CA)
-		if err != nil {
-			return nil, err
+// loadNetworks reads the list of networks to observe from a yaml file
+func loadNetworks(path string) ([]NetworkSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file networksFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, err
+	}
+	if len(file.Networks) == 0 {
+		return nil, fmt.Errorf("no networks configured")
+	}
+	var errs []error
+	names := make(map[string]bool, len(file.Networks))
+	for i, network := range file.Networks {
+		switch {
+		case !networkNamePattern.MatchString(network.Name):
+			errs = append(errs, fmt.Errorf("networks[%d]: name %q must consist of lower case alphanumeric characters or '-'", i, network.Name))
+		case names[network.Name]:
+			errs = append(errs, fmt.Errorf("networks[%d]: duplicate network name %q", i, network.Name))
 		}
-		if ok := certPool.AppendCertsFromPEM(file); !ok {
-			return nil, fmt.Errorf("failed to add CA to certificate pool")
+		names[network.Name] = true
+		if network.RouterURL == "" {
+			errs = append(errs, fmt.Errorf("networks[%d]: routerEndpoint is required", i))
 		}
-		config.RootCAs = certPool
 	}
+	return file.Networks, errors.Join(errs...)
+}
+
+// retentionFile configures how long the collector retains records
+type retentionFile struct {
+	MemoryLimit *resource.Quantity             `json:"memoryLimit,omitempty"`
+	Policies    map[string]retentionPolicySpec `json:"policies,omitempty"`
+}
+
+type retentionPolicySpec struct {
+	TerminatedTTL metav1.Duration `json:"terminatedTTL,omitempty"`
+	MaxRecords    int             `json:"maxRecords,omitempty"`
+}
 
-	if t.hasCert() {
-		tlsCert, err := tls.LoadX509KeyPair(t.Cert, t.Key)
-		if err != nil {
-			return nil, err
+// loadRetentionConfig reads the collector retention configuration from a
+// yaml file
+func loadRetentionConfig(path string) (collector.RetentionConfig, error) {
+	var cfg collector.RetentionConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	var file retentionFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return cfg, err
+	}
+	if file.MemoryLimit != nil {
+		cfg.MemoryLimit = file.MemoryLimit.Value()
+	}
+	if len(file.Policies) > 0 {
+		cfg.Policies = make(map[string]collector.RetentionPolicy, len(file.Policies))
+		for typ, policy := range file.Policies {
+			cfg.Policies[typ] = collector.RetentionPolicy{
+				TerminatedTTL: policy.TerminatedTTL.Duration,
+				MaxRecords:    policy.MaxRecords,
+			}
 		}
-		config.Certificates = []tls.Certificate{tlsCert}
 	}
-
-	return config, nil
+	return cfg, cfg.Validate()
 }
 
 func parsePrometheusAPI(base string) (*url.URL, error) {
@@ -10,6 +10,7 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/auth"
 )
 
 func handleMetrics(reg *prometheus.Registry) http.Handler {
@@ -75,6 +76,12 @@ func handleGetUser() http.Handler {
 	handleEmpty := handleNoContent()
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var response UserResponse
+		if user, ok := auth.UserFromContext(r.Context()); ok {
+			response.Username = user.Name
+			response.AuthMode = "native"
+			json.NewEncoder(w).Encode(response)
+			return
+		}
 		if cookie, err := r.Cookie("_oauth_proxy"); err == nil && cookie != nil {
 			if cookieDecoded, _ := base64.StdEncoding.DecodeString(cookie.Value); cookieDecoded != nil {
 				response.Username = string(cookieDecoded)
@@ -0,0 +1,194 @@
+package alerts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// RuleType identifies the condition a rule evaluates
+type RuleType string
+
+const (
+	// RuleLinkDown fires when a router link reports a status other than up
+	RuleLinkDown RuleType = "linkDown"
+	// RuleSiteMissing fires when a previously discovered site is no longer
+	// present
+	RuleSiteMissing RuleType = "siteMissing"
+	// RuleConnectionErrorRate fires when the proportion of connections to a
+	// routing key that ended in a listener or connector error exceeds a
+	// threshold
+	RuleConnectionErrorRate RuleType = "connectionErrorRate"
+	// RuleListenerWithoutConnector fires when a listener's address has no
+	// matching connector
+	RuleListenerWithoutConnector RuleType = "listenerWithoutConnector"
+)
+
+// SinkType identifies where notifications are sent
+type SinkType string
+
+const (
+	// SinkWebhook posts notifications as json to a URL
+	SinkWebhook SinkType = "webhook"
+	// SinkFile appends notifications to a file as newline delimited json
+	SinkFile SinkType = "file"
+)
+
+const (
+	defaultEvaluationInterval = 15 * time.Second
+	defaultErrorRateWindow    = 5 * time.Minute
+	defaultSinkTimeout        = 10 * time.Second
+	defaultSeverity           = "warning"
+)
+
+// Config for the alerting engine
+type Config struct {
+	// EvaluationInterval is how often rules are evaluated
+	EvaluationInterval Duration `json:"evaluationInterval,omitempty"`
+	// Rules to evaluate
+	Rules []RuleConfig `json:"rules"`
+	// Sinks to send notifications to when alerts fire and resolve
+	Sinks []SinkConfig `json:"sinks,omitempty"`
+}
+
+// RuleConfig defines an alerting rule
+type RuleConfig struct {
+	// Name of the rule. Must be unique.
+	Name string `json:"name"`
+	// Type of condition the rule evaluates
+	Type RuleType `json:"type"`
+	// Severity included in alerts for the rule. Defaults to warning.
+	Severity string `json:"severity,omitempty"`
+	// For is how long a condition must hold before the alert fires
+	For Duration `json:"for,omitempty"`
+
+	// RoutingKey limits a connectionErrorRate rule to a single routing key.
+	// When unset the rate is evaluated for each routing key separately.
+	RoutingKey string `json:"routingKey,omitempty"`
+	// Threshold is the error rate between 0 and 1 above which a
+	// connectionErrorRate rule fires
+	Threshold float64 `json:"threshold,omitempty"`
+	// Window is how far back connections are considered by a
+	// connectionErrorRate rule. Defaults to 5m.
+	Window Duration `json:"window,omitempty"`
+	// MinConnections is the number of connections within the window needed
+	// before a connectionErrorRate rule is evaluated. Defaults to 1.
+	MinConnections int `json:"minConnections,omitempty"`
+
+	// ForgetAfter is how long a siteMissing rule remembers a site that is
+	// no longer present. When unset sites are remembered until the
+	// observer restarts.
+	ForgetAfter Duration `json:"forgetAfter,omitempty"`
+}
+
+// SinkConfig defines where notifications are sent
+type SinkConfig struct {
+	// Name of the sink used in logs
+	Name string `json:"name,omitempty"`
+	// Type of sink
+	Type SinkType `json:"type"`
+	// URL notifications are posted to by webhook sinks
+	URL string `json:"url,omitempty"`
+	// Path to the file notifications are appended to by file sinks
+	Path string `json:"path,omitempty"`
+	// Timeout for webhook requests. Defaults to 10s.
+	Timeout Duration `json:"timeout,omitempty"`
+}
+
+// LoadConfig reads an alerting configuration from a yaml or json file
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read alerting config: %w", err)
+	}
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse alerting config %q: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid alerting config %q: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate checks the configuration for errors
+func (c Config) Validate() error {
+	var errs []error
+	if c.EvaluationInterval.Duration < 0 {
+		errs = append(errs, fmt.Errorf("evaluationInterval must not be negative"))
+	}
+	names := make(map[string]bool, len(c.Rules))
+	for i, rule := range c.Rules {
+		if rule.Name == "" {
+			errs = append(errs, fmt.Errorf("rules[%d]: name is required", i))
+		} else if names[rule.Name] {
+			errs = append(errs, fmt.Errorf("rules[%d]: duplicate rule name %q", i, rule.Name))
+		}
+		names[rule.Name] = true
+		if rule.For.Duration < 0 {
+			errs = append(errs, fmt.Errorf("rules[%d]: for must not be negative", i))
+		}
+		switch rule.Type {
+		case RuleLinkDown, RuleListenerWithoutConnector:
+		case RuleSiteMissing:
+			if rule.ForgetAfter.Duration < 0 {
+				errs = append(errs, fmt.Errorf("rules[%d]: forgetAfter must not be negative", i))
+			}
+		case RuleConnectionErrorRate:
+			if rule.Threshold < 0 || rule.Threshold >= 1 {
+				errs = append(errs, fmt.Errorf("rules[%d]: threshold must be at least 0 and less than 1", i))
+			}
+			if rule.Window.Duration < 0 {
+				errs = append(errs, fmt.Errorf("rules[%d]: window must not be negative", i))
+			}
+			if rule.MinConnections < 0 {
+				errs = append(errs, fmt.Errorf("rules[%d]: minConnections must not be negative", i))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("rules[%d]: unknown rule type %q", i, rule.Type))
+		}
+	}
+	for i, sink := range c.Sinks {
+		switch sink.Type {
+		case SinkWebhook:
+			u, err := url.Parse(sink.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+				errs = append(errs, fmt.Errorf("sinks[%d]: url must be an http or https URL", i))
+			}
+		case SinkFile:
+			if sink.Path == "" {
+				errs = append(errs, fmt.Errorf("sinks[%d]: path is required", i))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("sinks[%d]: unknown sink type %q", i, sink.Type))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Duration is a time.Duration encoded as a string such as "30s"
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
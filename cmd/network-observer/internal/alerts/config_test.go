@@ -0,0 +1,74 @@
+package alerts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(t *testing.T, contents string) string {
+		t.Helper()
+		path := filepath.Join(dir, t.Name()+".yaml")
+		assert.NilError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NilError(t, os.WriteFile(path, []byte(contents), 0o644))
+		return path
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		cfg, err := LoadConfig(write(t, `
+evaluationInterval: 5s
+rules:
+- name: backend-errors
+  type: connectionErrorRate
+  routingKey: backend
+  threshold: 0.1
+  window: 10m
+  for: 1m
+sinks:
+- type: webhook
+  url: https://alerts.example.com/hook
+- type: file
+  path: /var/log/alerts.log
+`))
+		assert.NilError(t, err)
+		assert.Equal(t, cfg.EvaluationInterval.Duration, 5*time.Second)
+		assert.Equal(t, cfg.Rules[0].Window.Duration, 10*time.Minute)
+		assert.Equal(t, cfg.Rules[0].For.Duration, time.Minute)
+		assert.Equal(t, len(cfg.Sinks), 2)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := LoadConfig(write(t, `
+rules:
+- name: down
+  type: linkDown
+  fro: 1m
+`))
+		assert.ErrorContains(t, err, `unknown field "fro"`)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := LoadConfig(write(t, `
+rules:
+- name: down
+  type: linkDown
+- name: down
+  type: linkFlapping
+- name: errors
+  type: connectionErrorRate
+  threshold: 2
+sinks:
+- type: webhook
+  url: ftp://example.com
+`))
+		assert.ErrorContains(t, err, `rules[1]: duplicate rule name "down"`)
+		assert.ErrorContains(t, err, `rules[1]: unknown rule type "linkFlapping"`)
+		assert.ErrorContains(t, err, "rules[2]: threshold must be at least 0 and less than 1")
+		assert.ErrorContains(t, err, "sinks[0]: url must be an http or https URL")
+	})
+}
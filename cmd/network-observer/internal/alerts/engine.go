@@ -0,0 +1,309 @@
+package alerts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+)
+
+const (
+	// maxSinkRetryInterval bounds the backoff between attempts to deliver
+	// notifications to a failing sink
+	maxSinkRetryInterval = 5 * time.Minute
+	// maxPendingNotifications bounds the notifications held for a failing
+	// sink. The oldest are dropped first.
+	maxPendingNotifications = 1000
+)
+
+// AlertState is the state of an active alert
+type AlertState string
+
+const (
+	// StatePending alerts have a condition that has not yet held for the
+	// rule's for duration
+	StatePending AlertState = "pending"
+	// StateFiring alerts have been notified to sinks
+	StateFiring AlertState = "firing"
+)
+
+// NotificationStatus is the status reported to sinks
+type NotificationStatus string
+
+const (
+	NotificationFiring   NotificationStatus = "firing"
+	NotificationResolved NotificationStatus = "resolved"
+)
+
+// Alert is an active instance of a rule's condition
+type Alert struct {
+	// Fingerprint uniquely identifies the alert across evaluations
+	Fingerprint string            `json:"fingerprint"`
+	Rule        string            `json:"rule"`
+	Severity    string            `json:"severity"`
+	State       AlertState        `json:"state"`
+	Labels      map[string]string `json:"labels"`
+	Summary     string            `json:"summary"`
+	// ActiveAt is when the condition was first observed
+	ActiveAt time.Time `json:"activeAt"`
+	// FiredAt is when the alert started firing
+	FiredAt *time.Time `json:"firedAt,omitempty"`
+}
+
+// Notification is sent to sinks when an alert fires or resolves. Each
+// alert is notified once when it fires and once when it resolves.
+type Notification struct {
+	Status      NotificationStatus `json:"status"`
+	Fingerprint string             `json:"fingerprint"`
+	Rule        string             `json:"rule"`
+	Severity    string             `json:"severity"`
+	Labels      map[string]string  `json:"labels"`
+	Summary     string             `json:"summary"`
+	StartsAt    time.Time          `json:"startsAt"`
+	EndsAt      *time.Time         `json:"endsAt,omitempty"`
+}
+
+// RuleStatus summarizes a rule and its active alerts
+type RuleStatus struct {
+	Name     string   `json:"name"`
+	Type     RuleType `json:"type"`
+	Severity string   `json:"severity"`
+	For      Duration `json:"for"`
+	Pending  int      `json:"pending"`
+	Firing   int      `json:"firing"`
+}
+
+// Engine periodically evaluates alerting rules against the collector's
+// records and notifies sinks as alerts fire and resolve.
+type Engine struct {
+	logger   *slog.Logger
+	records  store.Interface
+	interval time.Duration
+	rules    []rule
+	sinks    []*namedSink
+
+	mu     sync.Mutex
+	alerts map[string]*Alert
+}
+
+type rule struct {
+	RuleConfig
+	evaluator evaluator
+}
+
+// namedSink holds the notifications that could not yet be delivered to a
+// sink until they are sent successfully
+type namedSink struct {
+	name string
+	Sink
+	pending []Notification
+	backoff backoff.BackOff
+	retryAt time.Time
+}
+
+// New creates an alerting Engine from a validated configuration
+func New(logger *slog.Logger, records store.Interface, cfg Config) (*Engine, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	e := &Engine{
+		logger:   logger,
+		records:  records,
+		interval: cfg.EvaluationInterval.Duration,
+		alerts:   make(map[string]*Alert),
+	}
+	if e.interval == 0 {
+		e.interval = defaultEvaluationInterval
+	}
+	for _, ruleCfg := range cfg.Rules {
+		if ruleCfg.Severity == "" {
+			ruleCfg.Severity = defaultSeverity
+		}
+		e.rules = append(e.rules, rule{RuleConfig: ruleCfg, evaluator: newEvaluator(ruleCfg)})
+	}
+	for i, sinkCfg := range cfg.Sinks {
+		name := sinkCfg.Name
+		if name == "" {
+			name = fmt.Sprintf("%s-%d", sinkCfg.Type, i)
+		}
+		e.sinks = append(e.sinks, e.newNamedSink(name, newSink(sinkCfg)))
+	}
+	return e, nil
+}
+
+func (e *Engine) newNamedSink(name string, sink Sink) *namedSink {
+	return &namedSink{
+		name: name,
+		Sink: sink,
+		backoff: backoff.NewExponentialBackOff(
+			backoff.WithInitialInterval(e.interval),
+			backoff.WithMaxInterval(maxSinkRetryInterval),
+			backoff.WithMaxElapsedTime(0),
+		),
+	}
+}
+
+// Run evaluates rules until the context is cancelled
+func (e *Engine) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			e.notify(ctx, now, e.evaluate(now))
+		}
+	}
+}
+
+// Alerts returns the pending and firing alerts ordered by rule and
+// fingerprint
+func (e *Engine) Alerts() []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]Alert, 0, len(e.alerts))
+	for _, alert := range e.alerts {
+		out = append(out, *alert)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Rule != out[j].Rule {
+			return out[i].Rule < out[j].Rule
+		}
+		return out[i].Fingerprint < out[j].Fingerprint
+	})
+	return out
+}
+
+// Rules returns the status of each configured rule
+func (e *Engine) Rules() []RuleStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]RuleStatus, len(e.rules))
+	byName := make(map[string]*RuleStatus, len(e.rules))
+	for i, r := range e.rules {
+		out[i] = RuleStatus{
+			Name:     r.Name,
+			Type:     r.Type,
+			Severity: r.Severity,
+			For:      r.For,
+		}
+		byName[r.Name] = &out[i]
+	}
+	for _, alert := range e.alerts {
+		status := byName[alert.Rule]
+		switch alert.State {
+		case StatePending:
+			status.Pending++
+		case StateFiring:
+			status.Firing++
+		}
+	}
+	return out
+}
+
+// evaluate runs each rule and updates the set of active alerts, returning
+// the notifications for alerts that started firing or resolved.
+func (e *Engine) evaluate(now time.Time) []Notification {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var notifications []Notification
+	active := make(map[string]bool, len(e.alerts))
+	for _, r := range e.rules {
+		for _, cond := range r.evaluator.evaluate(now, e.records) {
+			fingerprint := alertFingerprint(r.Name, cond.key)
+			active[fingerprint] = true
+			alert, ok := e.alerts[fingerprint]
+			if !ok {
+				alert = &Alert{
+					Fingerprint: fingerprint,
+					Rule:        r.Name,
+					Severity:    r.Severity,
+					State:       StatePending,
+					ActiveAt:    now,
+				}
+				e.alerts[fingerprint] = alert
+			}
+			alert.Labels = cond.labels
+			alert.Summary = cond.summary
+			if alert.State == StatePending && now.Sub(alert.ActiveAt) >= r.For.Duration {
+				firedAt := now
+				alert.State = StateFiring
+				alert.FiredAt = &firedAt
+				notifications = append(notifications, newNotification(*alert, NotificationFiring, nil))
+			}
+		}
+	}
+	for fingerprint, alert := range e.alerts {
+		if active[fingerprint] {
+			continue
+		}
+		delete(e.alerts, fingerprint)
+		if alert.State == StateFiring {
+			endsAt := now
+			notifications = append(notifications, newNotification(*alert, NotificationResolved, &endsAt))
+		}
+	}
+	return notifications
+}
+
+// notify queues the notifications for each sink and sends each sink its
+// pending notifications. Notifications a sink fails to accept stay pending
+// and are retried with backoff on later calls. Not safe for concurrent use.
+func (e *Engine) notify(ctx context.Context, now time.Time, notifications []Notification) {
+	for _, n := range notifications {
+		e.logger.Info("Alert "+string(n.Status),
+			slog.String("rule", n.Rule),
+			slog.String("severity", n.Severity),
+			slog.String("summary", n.Summary))
+	}
+	for _, sink := range e.sinks {
+		sink.pending = append(sink.pending, notifications...)
+		if dropped := len(sink.pending) - maxPendingNotifications; dropped > 0 {
+			e.logger.Warn("dropping undelivered alert notifications",
+				slog.String("sink", sink.name),
+				slog.Int("dropped", dropped))
+			sink.pending = append([]Notification(nil), sink.pending[dropped:]...)
+		}
+		if len(sink.pending) == 0 || now.Before(sink.retryAt) {
+			continue
+		}
+		if err := sink.Send(ctx, sink.pending); err != nil {
+			sink.retryAt = now.Add(sink.backoff.NextBackOff())
+			e.logger.Error("failed to send alert notifications",
+				slog.String("sink", sink.name),
+				slog.Int("pending", len(sink.pending)),
+				slog.Time("retryAt", sink.retryAt),
+				slog.Any("error", err))
+			continue
+		}
+		sink.pending = nil
+		sink.retryAt = time.Time{}
+		sink.backoff.Reset()
+	}
+}
+
+func newNotification(alert Alert, status NotificationStatus, endsAt *time.Time) Notification {
+	return Notification{
+		Status:      status,
+		Fingerprint: alert.Fingerprint,
+		Rule:        alert.Rule,
+		Severity:    alert.Severity,
+		Labels:      alert.Labels,
+		Summary:     alert.Summary,
+		StartsAt:    *alert.FiredAt,
+		EndsAt:      endsAt,
+	}
+}
+
+func alertFingerprint(rule, key string) string {
+	sum := sha256.Sum256([]byte(rule + "\x00" + key))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,229 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+	"gotest.tools/v3/assert"
+)
+
+func TestEngineLifecycle(t *testing.T) {
+	records := store.NewSyncMapStore(store.SyncMapStoreConfig{Indexers: collector.RecordIndexers()})
+	engine, err := New(slog.Default(), records, Config{
+		Rules: []RuleConfig{
+			{Name: "link-down", Type: RuleLinkDown, Severity: "critical", For: Duration{30 * time.Second}},
+		},
+	})
+	assert.NilError(t, err)
+
+	records.Add(vanflow.RouterRecord{BaseRecord: vanflow.NewBase("router-1"), Name: ptrTo("west")}, store.SourceRef{ID: "test"})
+	records.Add(vanflow.LinkRecord{BaseRecord: vanflow.NewBase("link-1"), Parent: ptrTo("router-1"), Name: ptrTo("west-east"), Status: ptrTo("up")}, store.SourceRef{ID: "test"})
+
+	t0 := time.Now()
+	assert.Equal(t, len(engine.evaluate(t0)), 0)
+	assert.Equal(t, len(engine.Alerts()), 0)
+
+	records.Update(vanflow.LinkRecord{BaseRecord: vanflow.NewBase("link-1"), Parent: ptrTo("router-1"), Name: ptrTo("west-east"), Status: ptrTo("down")})
+	assert.Equal(t, len(engine.evaluate(t0.Add(time.Second))), 0)
+	alerts := engine.Alerts()
+	assert.Equal(t, len(alerts), 1)
+	assert.Equal(t, alerts[0].State, StatePending)
+	assert.Equal(t, alerts[0].Summary, "link west-east from router west is down")
+	assert.Equal(t, engine.Rules()[0].Pending, 1)
+
+	notifications := engine.evaluate(t0.Add(31 * time.Second))
+	assert.Equal(t, len(notifications), 1)
+	assert.Equal(t, notifications[0].Status, NotificationFiring)
+	assert.Equal(t, notifications[0].Severity, "critical")
+	assert.Equal(t, notifications[0].Labels["routerName"], "west")
+	assert.Equal(t, engine.Rules()[0].Firing, 1)
+
+	// firing alerts are not notified again while the condition holds
+	assert.Equal(t, len(engine.evaluate(t0.Add(45*time.Second))), 0)
+
+	records.Update(vanflow.LinkRecord{BaseRecord: vanflow.NewBase("link-1"), Parent: ptrTo("router-1"), Name: ptrTo("west-east"), Status: ptrTo("up")})
+	notifications = engine.evaluate(t0.Add(time.Minute))
+	assert.Equal(t, len(notifications), 1)
+	assert.Equal(t, notifications[0].Status, NotificationResolved)
+	assert.Equal(t, notifications[0].Fingerprint, alerts[0].Fingerprint)
+	assert.Equal(t, *notifications[0].EndsAt, t0.Add(time.Minute))
+	assert.Equal(t, len(engine.Alerts()), 0)
+
+	// pending alerts resolve without notification
+	records.Update(vanflow.LinkRecord{BaseRecord: vanflow.NewBase("link-1"), Parent: ptrTo("router-1"), Status: ptrTo("down")})
+	assert.Equal(t, len(engine.evaluate(t0.Add(2*time.Minute))), 0)
+	records.Update(vanflow.LinkRecord{BaseRecord: vanflow.NewBase("link-1"), Parent: ptrTo("router-1"), Status: ptrTo("up")})
+	assert.Equal(t, len(engine.evaluate(t0.Add(3*time.Minute))), 0)
+}
+
+func TestRules(t *testing.T) {
+	now := time.Now()
+	records := store.NewSyncMapStore(store.SyncMapStoreConfig{Indexers: collector.RecordIndexers()})
+	flows := store.NewSyncMapStore(store.SyncMapStoreConfig{})
+	source := store.SourceRef{ID: "test"}
+
+	records.Add(vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1"), Name: ptrTo("west")}, source)
+	records.Add(vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-2"), Name: ptrTo("east")}, source)
+	records.Add(vanflow.ListenerRecord{BaseRecord: vanflow.NewBase("listener-1"), Name: ptrTo("frontend"), Address: ptrTo("frontend")}, source)
+	records.Add(vanflow.ListenerRecord{BaseRecord: vanflow.NewBase("listener-2"), Name: ptrTo("backend"), Address: ptrTo("backend")}, source)
+	records.Add(vanflow.ConnectorRecord{BaseRecord: vanflow.NewBase("connector-1"), Address: ptrTo("backend")}, source)
+	for i, tc := range []struct {
+		RoutingKey string
+		Age        time.Duration
+		Error      bool
+	}{
+		{RoutingKey: "backend", Error: true},
+		{RoutingKey: "backend", Error: true},
+		{RoutingKey: "backend"},
+		{RoutingKey: "backend", Age: time.Hour, Error: true},
+		{RoutingKey: "frontend", Error: true},
+		{RoutingKey: "database"},
+		{RoutingKey: "database"},
+	} {
+		id := "flow-" + string(rune('a'+i))
+		flow := vanflow.TransportBiflowRecord{BaseRecord: vanflow.NewBase(id, now.Add(-tc.Age))}
+		if tc.Error {
+			flow.ErrorConnector = ptrTo("connection refused")
+		}
+		flows.Add(flow, source)
+		records.Add(collector.ConnectionRecord{ID: id, RoutingKey: tc.RoutingKey, FlowStore: flows}, source)
+	}
+
+	keys := func(conditions []condition) []string {
+		var out []string
+		for _, c := range conditions {
+			out = append(out, c.key)
+		}
+		return out
+	}
+
+	t.Run("listener without connector", func(t *testing.T) {
+		conditions := newEvaluator(RuleConfig{Type: RuleListenerWithoutConnector}).evaluate(now, records)
+		assert.DeepEqual(t, keys(conditions), []string{"listener-1"})
+		assert.Equal(t, conditions[0].summary, "listener frontend has no connector for address frontend")
+	})
+
+	t.Run("connection error rate", func(t *testing.T) {
+		conditions := newEvaluator(RuleConfig{Type: RuleConnectionErrorRate, Threshold: 0.5, MinConnections: 2}).evaluate(now, records)
+		assert.DeepEqual(t, keys(conditions), []string{"backend"})
+		assert.Equal(t, conditions[0].labels["connections"], "3")
+		assert.Equal(t, conditions[0].labels["errors"], "2")
+
+		conditions = newEvaluator(RuleConfig{Type: RuleConnectionErrorRate, RoutingKey: "frontend"}).evaluate(now, records)
+		assert.DeepEqual(t, keys(conditions), []string{"frontend"})
+	})
+
+	t.Run("site missing", func(t *testing.T) {
+		eval := newEvaluator(RuleConfig{Type: RuleSiteMissing, ForgetAfter: Duration{time.Hour}})
+		assert.Equal(t, len(eval.evaluate(now, records)), 0)
+		deleted, _ := records.Delete("site-2")
+		conditions := eval.evaluate(now, records)
+		assert.DeepEqual(t, keys(conditions), []string{"site-2"})
+		assert.Equal(t, conditions[0].summary, "site east is no longer present in the network")
+		assert.Equal(t, len(eval.evaluate(now.Add(2*time.Hour), records)), 0)
+
+		records.Add(deleted.Record, source)
+		assert.Equal(t, len(eval.evaluate(now, records)), 0)
+	})
+}
+
+func TestSinks(t *testing.T) {
+	received := make(chan webhookPayload, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload webhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- payload
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "alerts.log")
+
+	engine, err := New(slog.Default(), store.NewSyncMapStore(store.SyncMapStoreConfig{}), Config{
+		Sinks: []SinkConfig{
+			{Type: SinkWebhook, URL: srv.URL},
+			{Type: SinkFile, Path: path},
+		},
+	})
+	assert.NilError(t, err)
+	firedAt := time.Now()
+	notifications := []Notification{
+		{Status: NotificationFiring, Rule: "one", StartsAt: firedAt},
+		{Status: NotificationResolved, Rule: "two", StartsAt: firedAt, EndsAt: &firedAt},
+	}
+	engine.notify(context.Background(), time.Now(), notifications)
+
+	payload := <-received
+	assert.Equal(t, len(payload.Alerts), 2)
+	assert.Equal(t, payload.Alerts[1].Status, NotificationResolved)
+
+	contents, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+	assert.Equal(t, len(lines), 2)
+	var first Notification
+	assert.NilError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, first.Rule, "one")
+}
+
+type failingSink struct {
+	failures int
+	received [][]Notification
+}
+
+func (s *failingSink) Send(ctx context.Context, notifications []Notification) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("unavailable")
+	}
+	s.received = append(s.received, notifications)
+	return nil
+}
+
+func TestSinkRetry(t *testing.T) {
+	engine, err := New(slog.Default(), store.NewSyncMapStore(store.SyncMapStoreConfig{}), Config{})
+	assert.NilError(t, err)
+	sink := &failingSink{failures: 2}
+	engine.sinks = append(engine.sinks, engine.newNamedSink("failing", sink))
+
+	t0 := time.Now()
+	first := Notification{Status: NotificationFiring, Rule: "one", StartsAt: t0}
+	engine.notify(context.Background(), t0, []Notification{first})
+	assert.Equal(t, len(sink.received), 0)
+	assert.Equal(t, len(engine.sinks[0].pending), 1)
+
+	// notifications queue behind the undelivered ones until the retry is due
+	second := Notification{Status: NotificationResolved, Rule: "one", StartsAt: t0, EndsAt: &t0}
+	engine.notify(context.Background(), t0.Add(time.Second), []Notification{second})
+	assert.Equal(t, sink.failures, 1)
+	assert.Equal(t, len(engine.sinks[0].pending), 2)
+
+	engine.notify(context.Background(), t0.Add(time.Minute), nil)
+	assert.Equal(t, sink.failures, 0)
+	assert.Equal(t, len(sink.received), 0)
+
+	engine.notify(context.Background(), t0.Add(time.Hour), nil)
+	assert.DeepEqual(t, sink.received, [][]Notification{{first, second}})
+	assert.Equal(t, len(engine.sinks[0].pending), 0)
+
+	// delivered notifications are not sent again
+	engine.notify(context.Background(), t0.Add(2*time.Hour), nil)
+	assert.Equal(t, len(sink.received), 1)
+}
+
+func ptrTo[T any](c T) *T {
+	return &c
+}
@@ -0,0 +1,260 @@
+package alerts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+)
+
+// condition is an instance of a rule's condition holding at evaluation time
+type condition struct {
+	// key uniquely identifies the condition within a rule
+	key     string
+	labels  map[string]string
+	summary string
+}
+
+// evaluator finds the conditions holding for a rule
+type evaluator interface {
+	evaluate(now time.Time, records store.Interface) []condition
+}
+
+func newEvaluator(cfg RuleConfig) evaluator {
+	switch cfg.Type {
+	case RuleLinkDown:
+		return linkDownEvaluator{}
+	case RuleSiteMissing:
+		return &siteMissingEvaluator{
+			forgetAfter: cfg.ForgetAfter.Duration,
+			seen:        make(map[string]*siteState),
+		}
+	case RuleConnectionErrorRate:
+		window := cfg.Window.Duration
+		if window == 0 {
+			window = defaultErrorRateWindow
+		}
+		minConnections := cfg.MinConnections
+		if minConnections == 0 {
+			minConnections = 1
+		}
+		return errorRateEvaluator{
+			routingKey:     cfg.RoutingKey,
+			threshold:      cfg.Threshold,
+			window:         window,
+			minConnections: minConnections,
+		}
+	case RuleListenerWithoutConnector:
+		return listenerWithoutConnectorEvaluator{}
+	}
+	return nil
+}
+
+type linkDownEvaluator struct{}
+
+func (linkDownEvaluator) evaluate(now time.Time, records store.Interface) []condition {
+	var out []condition
+	for _, entry := range listByType[vanflow.LinkRecord](records) {
+		link := entry.Record.(vanflow.LinkRecord)
+		if link.Status == nil || strings.EqualFold(*link.Status, "up") {
+			continue
+		}
+		labels := map[string]string{
+			"linkId": link.ID,
+			"status": *link.Status,
+		}
+		name := link.ID
+		if link.Name != nil {
+			name = *link.Name
+			labels["linkName"] = name
+		}
+		router := "unknown"
+		if link.Parent != nil {
+			labels["routerId"] = *link.Parent
+			router = *link.Parent
+			if routerEntry, ok := records.Get(*link.Parent); ok {
+				if r, ok := routerEntry.Record.(vanflow.RouterRecord); ok && r.Name != nil {
+					router = *r.Name
+					labels["routerName"] = router
+				}
+			}
+		}
+		out = append(out, condition{
+			key:     link.ID,
+			labels:  labels,
+			summary: fmt.Sprintf("link %s from router %s is %s", name, router, *link.Status),
+		})
+	}
+	return out
+}
+
+// siteMissingEvaluator remembers the sites it has seen so that it can
+// report the ones that have since gone away.
+type siteMissingEvaluator struct {
+	forgetAfter time.Duration
+	seen        map[string]*siteState
+}
+
+type siteState struct {
+	name        string
+	missingFrom time.Time
+}
+
+func (e *siteMissingEvaluator) evaluate(now time.Time, records store.Interface) []condition {
+	present := make(map[string]bool)
+	for _, entry := range listByType[vanflow.SiteRecord](records) {
+		site := entry.Record.(vanflow.SiteRecord)
+		present[site.ID] = true
+		state, ok := e.seen[site.ID]
+		if !ok {
+			state = &siteState{}
+			e.seen[site.ID] = state
+		}
+		state.missingFrom = time.Time{}
+		if site.Name != nil {
+			state.name = *site.Name
+		}
+	}
+
+	var out []condition
+	for id, state := range e.seen {
+		if present[id] {
+			continue
+		}
+		if state.missingFrom.IsZero() {
+			state.missingFrom = now
+		}
+		if e.forgetAfter > 0 && now.Sub(state.missingFrom) >= e.forgetAfter {
+			delete(e.seen, id)
+			continue
+		}
+		name := state.name
+		if name == "" {
+			name = id
+		}
+		out = append(out, condition{
+			key:     id,
+			labels:  map[string]string{"siteId": id, "siteName": state.name},
+			summary: fmt.Sprintf("site %s is no longer present in the network", name),
+		})
+	}
+	return out
+}
+
+type errorRateEvaluator struct {
+	routingKey     string
+	threshold      float64
+	window         time.Duration
+	minConnections int
+}
+
+func (e errorRateEvaluator) evaluate(now time.Time, records store.Interface) []condition {
+	type counts struct {
+		connections int
+		errors      int
+	}
+	byKey := make(map[string]*counts)
+	since := now.Add(-e.window)
+	for _, entry := range records.Index(store.TypeIndex, store.Entry{Record: collector.ConnectionRecord{}}) {
+		conn, ok := entry.Record.(collector.ConnectionRecord)
+		if !ok || conn.FlowStore == nil {
+			continue
+		}
+		if e.routingKey != "" && conn.RoutingKey != e.routingKey {
+			continue
+		}
+		flowEntry, ok := conn.FlowStore.Get(conn.ID)
+		if !ok {
+			continue
+		}
+		flow, ok := flowEntry.Record.(vanflow.TransportBiflowRecord)
+		if !ok {
+			continue
+		}
+		start := conn.StartTime
+		if flow.StartTime != nil {
+			start = flow.StartTime.Time
+		}
+		if start.Before(since) {
+			continue
+		}
+		c, ok := byKey[conn.RoutingKey]
+		if !ok {
+			c = &counts{}
+			byKey[conn.RoutingKey] = c
+		}
+		c.connections++
+		if flow.ErrorListener != nil || flow.ErrorConnector != nil {
+			c.errors++
+		}
+	}
+
+	var out []condition
+	for routingKey, c := range byKey {
+		if c.connections < e.minConnections {
+			continue
+		}
+		rate := float64(c.errors) / float64(c.connections)
+		if rate <= e.threshold {
+			continue
+		}
+		out = append(out, condition{
+			key: routingKey,
+			labels: map[string]string{
+				"routingKey":  routingKey,
+				"connections": fmt.Sprint(c.connections),
+				"errors":      fmt.Sprint(c.errors),
+			},
+			summary: fmt.Sprintf("%.1f%% of %d connections to %s failed in the last %s",
+				rate*100, c.connections, routingKey, e.window),
+		})
+	}
+	return out
+}
+
+type listenerWithoutConnectorEvaluator struct{}
+
+func (listenerWithoutConnectorEvaluator) evaluate(now time.Time, records store.Interface) []condition {
+	connectorAddresses := make(map[string]bool)
+	for _, entry := range listByType[vanflow.ConnectorRecord](records) {
+		connector := entry.Record.(vanflow.ConnectorRecord)
+		if connector.Address != nil {
+			connectorAddresses[*connector.Address] = true
+		}
+	}
+	var out []condition
+	for _, entry := range listByType[vanflow.ListenerRecord](records) {
+		listener := entry.Record.(vanflow.ListenerRecord)
+		if listener.Address == nil || connectorAddresses[*listener.Address] {
+			continue
+		}
+		name := listener.ID
+		labels := map[string]string{
+			"listenerId": listener.ID,
+			"address":    *listener.Address,
+		}
+		if listener.Name != nil {
+			name = *listener.Name
+			labels["listenerName"] = name
+		}
+		out = append(out, condition{
+			key:     listener.ID,
+			labels:  labels,
+			summary: fmt.Sprintf("listener %s has no connector for address %s", name, *listener.Address),
+		})
+	}
+	return out
+}
+
+func listByType[T vanflow.Record](records store.Interface) []store.Entry {
+	var r T
+	entries := records.Index(store.TypeIndex, store.Entry{Record: r})
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Record.Identity() < entries[j].Record.Identity()
+	})
+	return entries
+}
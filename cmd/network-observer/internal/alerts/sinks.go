@@ -0,0 +1,93 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// Sink delivers alert notifications
+type Sink interface {
+	Send(ctx context.Context, notifications []Notification) error
+}
+
+func newSink(cfg SinkConfig) Sink {
+	switch cfg.Type {
+	case SinkWebhook:
+		timeout := cfg.Timeout.Duration
+		if timeout == 0 {
+			timeout = defaultSinkTimeout
+		}
+		return &webhookSink{
+			url:    cfg.URL,
+			client: &http.Client{Timeout: timeout},
+		}
+	case SinkFile:
+		return &fileSink{path: cfg.Path}
+	}
+	return nil
+}
+
+// webhookPayload is the body posted to webhook sinks
+type webhookPayload struct {
+	Alerts []Notification `json:"alerts"`
+}
+
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func (s *webhookSink) Send(ctx context.Context, notifications []Notification) error {
+	body, err := json.Marshal(webhookPayload{Alerts: notifications})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+	return nil
+}
+
+// fileSink appends notifications to a file as newline delimited json
+type fileSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (s *fileSink) Send(ctx context.Context, notifications []Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, n := range notifications {
+		if err := enc.Encode(n); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
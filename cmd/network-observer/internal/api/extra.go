@@ -18,10 +18,18 @@ type Record interface {
 	GetEndTime() uint64
 }
 
+// NetworkRecord is a record that can be tagged with the name of the network
+// it was observed in
+type NetworkRecord interface {
+	GetIdentity() string
+	SetNetwork(*string)
+}
+
 var (
 	_ ResponseSetter[SiteRecord]           = (*SiteResponse)(nil)
 	_ CollectionResponseSetter[SiteRecord] = (*SiteListResponse)(nil)
 	_ Record                               = (*SiteRecord)(nil)
+	_ NetworkRecord                        = (*SiteRecord)(nil)
 )
 
 type AtmarkDelimitedString string
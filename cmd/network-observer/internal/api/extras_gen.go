@@ -251,6 +251,11 @@ func (r ApplicationFlowRecord) GetEndTime() uint64 {
 	return r.EndTime
 }
 
+// GetIdentity
+func (r ApplicationFlowRecord) GetIdentity() string {
+	return r.Identity
+}
+
 // GetStartTime
 func (r ApplicationFlowRecord) GetStartTime() uint64 {
 	return r.StartTime
@@ -261,6 +266,11 @@ func (r ComponentRecord) GetEndTime() uint64 {
 	return r.EndTime
 }
 
+// GetIdentity
+func (r ComponentRecord) GetIdentity() string {
+	return r.Identity
+}
+
 // GetStartTime
 func (r ComponentRecord) GetStartTime() uint64 {
 	return r.StartTime
@@ -271,6 +281,11 @@ func (r ConnectionRecord) GetEndTime() uint64 {
 	return r.EndTime
 }
 
+// GetIdentity
+func (r ConnectionRecord) GetIdentity() string {
+	return r.Identity
+}
+
 // GetStartTime
 func (r ConnectionRecord) GetStartTime() uint64 {
 	return r.StartTime
@@ -281,6 +296,11 @@ func (r ConnectorRecord) GetEndTime() uint64 {
 	return r.EndTime
 }
 
+// GetIdentity
+func (r ConnectorRecord) GetIdentity() string {
+	return r.Identity
+}
+
 // GetStartTime
 func (r ConnectorRecord) GetStartTime() uint64 {
 	return r.StartTime
@@ -291,6 +311,11 @@ func (r FlowAggregateRecord) GetEndTime() uint64 {
 	return r.EndTime
 }
 
+// GetIdentity
+func (r FlowAggregateRecord) GetIdentity() string {
+	return r.Identity
+}
+
 // GetStartTime
 func (r FlowAggregateRecord) GetStartTime() uint64 {
 	return r.StartTime
@@ -301,6 +326,11 @@ func (r ListenerRecord) GetEndTime() uint64 {
 	return r.EndTime
 }
 
+// GetIdentity
+func (r ListenerRecord) GetIdentity() string {
+	return r.Identity
+}
+
 // GetStartTime
 func (r ListenerRecord) GetStartTime() uint64 {
 	return r.StartTime
@@ -311,6 +341,11 @@ func (r ProcessRecord) GetEndTime() uint64 {
 	return r.EndTime
 }
 
+// GetIdentity
+func (r ProcessRecord) GetIdentity() string {
+	return r.Identity
+}
+
 // GetStartTime
 func (r ProcessRecord) GetStartTime() uint64 {
 	return r.StartTime
@@ -321,6 +356,11 @@ func (r RouterAccessRecord) GetEndTime() uint64 {
 	return r.EndTime
 }
 
+// GetIdentity
+func (r RouterAccessRecord) GetIdentity() string {
+	return r.Identity
+}
+
 // GetStartTime
 func (r RouterAccessRecord) GetStartTime() uint64 {
 	return r.StartTime
@@ -331,6 +371,11 @@ func (r RouterLinkRecord) GetEndTime() uint64 {
 	return r.EndTime
 }
 
+// GetIdentity
+func (r RouterLinkRecord) GetIdentity() string {
+	return r.Identity
+}
+
 // GetStartTime
 func (r RouterLinkRecord) GetStartTime() uint64 {
 	return r.StartTime
@@ -341,6 +386,11 @@ func (r RouterRecord) GetEndTime() uint64 {
 	return r.EndTime
 }
 
+// GetIdentity
+func (r RouterRecord) GetIdentity() string {
+	return r.Identity
+}
+
 // GetStartTime
 func (r RouterRecord) GetStartTime() uint64 {
 	return r.StartTime
@@ -351,6 +401,11 @@ func (r ServiceRecord) GetEndTime() uint64 {
 	return r.EndTime
 }
 
+// GetIdentity
+func (r ServiceRecord) GetIdentity() string {
+	return r.Identity
+}
+
 // GetStartTime
 func (r ServiceRecord) GetStartTime() uint64 {
 	return r.StartTime
@@ -361,6 +416,11 @@ func (r SiteRecord) GetEndTime() uint64 {
 	return r.EndTime
 }
 
+// GetIdentity
+func (r SiteRecord) GetIdentity() string {
+	return r.Identity
+}
+
 // GetStartTime
 func (r SiteRecord) GetStartTime() uint64 {
 	return r.StartTime
@@ -371,7 +431,79 @@ func (r BaseRecord) GetEndTime() uint64 {
 	return r.EndTime
 }
 
+// GetIdentity
+func (r BaseRecord) GetIdentity() string {
+	return r.Identity
+}
+
 // GetStartTime
 func (r BaseRecord) GetStartTime() uint64 {
 	return r.StartTime
 }
+
+// Implements NetworkRecord interface for the generated record objects
+
+// SetNetwork
+func (r *ApplicationFlowRecord) SetNetwork(v *string) {
+	r.Network = v
+}
+
+// SetNetwork
+func (r *ComponentRecord) SetNetwork(v *string) {
+	r.Network = v
+}
+
+// SetNetwork
+func (r *ConnectionRecord) SetNetwork(v *string) {
+	r.Network = v
+}
+
+// SetNetwork
+func (r *ConnectorRecord) SetNetwork(v *string) {
+	r.Network = v
+}
+
+// SetNetwork
+func (r *FlowAggregateRecord) SetNetwork(v *string) {
+	r.Network = v
+}
+
+// SetNetwork
+func (r *ListenerRecord) SetNetwork(v *string) {
+	r.Network = v
+}
+
+// SetNetwork
+func (r *ProcessRecord) SetNetwork(v *string) {
+	r.Network = v
+}
+
+// SetNetwork
+func (r *RouterAccessRecord) SetNetwork(v *string) {
+	r.Network = v
+}
+
+// SetNetwork
+func (r *RouterLinkRecord) SetNetwork(v *string) {
+	r.Network = v
+}
+
+// SetNetwork
+func (r *RouterRecord) SetNetwork(v *string) {
+	r.Network = v
+}
+
+// SetNetwork
+func (r *ServiceRecord) SetNetwork(v *string) {
+	r.Network = v
+}
+
+// SetNetwork
+func (r *SiteRecord) SetNetwork(v *string) {
+	r.Network = v
+}
+
+// SetNetwork
+func (r *BaseRecord) SetNetwork(v *string) {
+	r.Network = v
+}
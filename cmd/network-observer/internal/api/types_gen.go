@@ -71,16 +71,19 @@ type ApplicationFlowRecord struct {
 	EndTime uint64 `json:"endTime"`
 
 	// Identity The unique identifier for the record.
-	Identity          string `json:"identity"`
-	Method            string `json:"method"`
-	OctetCount        uint64 `json:"octetCount"`
-	OctetReverseCount uint64 `json:"octetReverseCount"`
-	Protocol          string `json:"protocol"`
-	RoutingKey        string `json:"routingKey"`
-	SourceProcessId   string `json:"sourceProcessId"`
-	SourceProcessName string `json:"sourceProcessName"`
-	SourceSiteId      string `json:"sourceSiteId"`
-	SourceSiteName    string `json:"sourceSiteName"`
+	Identity string `json:"identity"`
+	Method   string `json:"method"`
+
+	// Network The name of the application network the record was observed in.
+	Network           *string `json:"network,omitempty"`
+	OctetCount        uint64  `json:"octetCount"`
+	OctetReverseCount uint64  `json:"octetReverseCount"`
+	Protocol          string  `json:"protocol"`
+	RoutingKey        string  `json:"routingKey"`
+	SourceProcessId   string  `json:"sourceProcessId"`
+	SourceProcessName string  `json:"sourceProcessName"`
+	SourceSiteId      string  `json:"sourceSiteId"`
+	SourceSiteName    string  `json:"sourceSiteName"`
 
 	// StartTime The creation time in microseconds of the record in Unix timestamp format. The value 0 means that the record is not terminated
 	StartTime uint64 `json:"startTime"`
@@ -119,10 +122,13 @@ type ComponentRecord struct {
 	EndTime uint64 `json:"endTime"`
 
 	// Identity The unique identifier for the record.
-	Identity     string `json:"identity"`
-	Name         string `json:"name"`
-	ProcessCount int    `json:"processCount"`
-	Role         string `json:"role"`
+	Identity string `json:"identity"`
+	Name     string `json:"name"`
+
+	// Network The name of the application network the record was observed in.
+	Network      *string `json:"network,omitempty"`
+	ProcessCount int     `json:"processCount"`
+	Role         string  `json:"role"`
 
 	// StartTime The creation time in microseconds of the record in Unix timestamp format. The value 0 means that the record is not terminated
 	StartTime uint64 `json:"startTime"`
@@ -160,11 +166,14 @@ type ConnectionRecord struct {
 	EndTime uint64 `json:"endTime"`
 
 	// Identity The unique identifier for the record.
-	Identity          string  `json:"identity"`
-	Latency           uint64  `json:"latency"`
-	LatencyReverse    uint64  `json:"latencyReverse"`
-	ListenerError     *string `json:"listenerError"`
-	ListenerId        string  `json:"listenerId"`
+	Identity       string  `json:"identity"`
+	Latency        uint64  `json:"latency"`
+	LatencyReverse uint64  `json:"latencyReverse"`
+	ListenerError  *string `json:"listenerError"`
+	ListenerId     string  `json:"listenerId"`
+
+	// Network The name of the application network the record was observed in.
+	Network           *string `json:"network,omitempty"`
 	OctetCount        uint64  `json:"octetCount"`
 	OctetReverseCount uint64  `json:"octetReverseCount"`
 	ProcessPairId     *string `json:"processPairId"`
@@ -209,8 +218,11 @@ type ConnectorRecord struct {
 	EndTime uint64 `json:"endTime"`
 
 	// Identity The unique identifier for the record.
-	Identity   string  `json:"identity"`
-	Name       string  `json:"name"`
+	Identity string `json:"identity"`
+	Name     string `json:"name"`
+
+	// Network The name of the application network the record was observed in.
+	Network    *string `json:"network,omitempty"`
 	ProcessId  string  `json:"processId"`
 	Protocol   string  `json:"protocol"`
 	RouterId   string  `json:"routerId"`
@@ -256,7 +268,10 @@ type FlowAggregateRecord struct {
 	EndTime uint64 `json:"endTime"`
 
 	// Identity The unique identifier for the record.
-	Identity       string                `json:"identity"`
+	Identity string `json:"identity"`
+
+	// Network The name of the application network the record was observed in.
+	Network        *string               `json:"network,omitempty"`
 	PairType       FlowAggregatePairType `json:"pairType"`
 	Protocol       string                `json:"protocol"`
 	RecordCount    uint64                `json:"recordCount"`
@@ -293,8 +308,11 @@ type ListenerRecord struct {
 	EndTime uint64 `json:"endTime"`
 
 	// Identity The unique identifier for the record.
-	Identity   string  `json:"identity"`
-	Name       string  `json:"name"`
+	Identity string `json:"identity"`
+	Name     string `json:"name"`
+
+	// Network The name of the application network the record was observed in.
+	Network    *string `json:"network,omitempty"`
 	Protocol   string  `json:"protocol"`
 	RouterId   string  `json:"routerId"`
 	RoutingKey string  `json:"routingKey"`
@@ -341,6 +359,9 @@ type ProcessRecord struct {
 	ImageName *string `json:"imageName"`
 	Name      string  `json:"name"`
 
+	// Network The name of the application network the record was observed in.
+	Network *string `json:"network,omitempty"`
+
 	// Role Internal processes are processes related to Skupper. Remote processes are processes indirectly connected, such as a proxy
 	Role     ProcessRecordRole        `json:"role"`
 	Services *[]ServiceIdentifierType `json:"services"`
@@ -383,8 +404,11 @@ type RouterAccessRecord struct {
 	Identity  string `json:"identity"`
 	LinkCount uint64 `json:"linkCount"`
 	Name      string `json:"name"`
-	Role      string `json:"role"`
-	RouterId  string `json:"routerId"`
+
+	// Network The name of the application network the record was observed in.
+	Network  *string `json:"network,omitempty"`
+	Role     string  `json:"role"`
+	RouterId string  `json:"routerId"`
 
 	// StartTime The creation time in microseconds of the record in Unix timestamp format. The value 0 means that the record is not terminated
 	StartTime uint64 `json:"startTime"`
@@ -426,10 +450,13 @@ type RouterLinkRecord struct {
 	EndTime uint64 `json:"endTime"`
 
 	// Identity The unique identifier for the record.
-	Identity          string `json:"identity"`
-	Name              string `json:"name"`
-	OctetCount        uint64 `json:"octetCount"`
-	OctetReverseCount uint64 `json:"octetReverseCount"`
+	Identity string `json:"identity"`
+	Name     string `json:"name"`
+
+	// Network The name of the application network the record was observed in.
+	Network           *string `json:"network,omitempty"`
+	OctetCount        uint64  `json:"octetCount"`
+	OctetReverseCount uint64  `json:"octetReverseCount"`
 
 	// Role The class of skupper link
 	Role LinkRoleType `json:"role"`
@@ -476,7 +503,10 @@ type RouterRecord struct {
 	Mode         string  `json:"mode"`
 	Name         string  `json:"name"`
 	Namespace    *string `json:"namespace,omitempty"`
-	SiteId       string  `json:"siteId"`
+
+	// Network The name of the application network the record was observed in.
+	Network *string `json:"network,omitempty"`
+	SiteId  string  `json:"siteId"`
 
 	// StartTime The creation time in microseconds of the record in Unix timestamp format. The value 0 means that the record is not terminated
 	StartTime uint64 `json:"startTime"`
@@ -515,6 +545,9 @@ type ServiceRecord struct {
 	ListenerCount int    `json:"listenerCount"`
 	Name          string `json:"name"`
 
+	// Network The name of the application network the record was observed in.
+	Network *string `json:"network,omitempty"`
+
 	// ObservedApplicationProtocols Array of the observed application level protocols
 	ObservedApplicationProtocols []string `json:"observedApplicationProtocols"`
 	Protocol                     string   `json:"protocol"`
@@ -548,6 +581,9 @@ type SiteRecord struct {
 	Name      string  `json:"name"`
 	Namespace *string `json:"namespace"`
 
+	// Network The name of the application network the record was observed in.
+	Network *string `json:"network,omitempty"`
+
 	// Platform The platform used for the site.
 	Platform SitePlatformType `json:"platform"`
 
@@ -575,6 +611,9 @@ type BaseRecord struct {
 	// Identity The unique identifier for the record.
 	Identity string `json:"identity"`
 
+	// Network The name of the application network the record was observed in.
+	Network *string `json:"network,omitempty"`
+
 	// StartTime The creation time in microseconds of the record in Unix timestamp format. The value 0 means that the record is not terminated
 	StartTime uint64 `json:"startTime"`
 }
@@ -612,6 +651,9 @@ type PathID = string
 // ErrorBadRequest defines model for errorBadRequest.
 type ErrorBadRequest = ErrorResponse
 
+// ErrorForbidden defines model for errorForbidden.
+type ErrorForbidden = ErrorResponse
+
 // ErrorNotFound defines model for errorNotFound.
 type ErrorNotFound = ErrorResponse
 
@@ -0,0 +1,189 @@
+// Package auth authenticates network observer API requests and resolves the
+// scope of records visible to the requesting user.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type contextKey int
+
+const (
+	userKey contextKey = iota
+	scopeKey
+)
+
+// Authenticator authenticates requests using static tokens, htpasswd basic
+// authentication or OpenID Connect JWTs and binds users to roles.
+type Authenticator struct {
+	logger   *slog.Logger
+	tokens   tokenSet
+	htpasswd htpasswd
+	jwt      *jwtValidator
+	roles    map[string]Role
+	bindings []Binding
+}
+
+// New creates an Authenticator, loading the credentials referenced by cfg
+func New(logger *slog.Logger, cfg Config) (*Authenticator, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	a := &Authenticator{
+		logger:   logger,
+		roles:    make(map[string]Role, len(cfg.Roles)),
+		bindings: cfg.Bindings,
+	}
+	var err error
+	if cfg.TokenFile != "" {
+		if a.tokens, err = loadTokenFile(cfg.TokenFile); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.HtpasswdFile != "" {
+		if a.htpasswd, err = loadHtpasswdFile(cfg.HtpasswdFile); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.OIDC != nil {
+		if a.jwt, err = newJWTValidator(*cfg.OIDC); err != nil {
+			return nil, err
+		}
+	}
+	for _, role := range cfg.Roles {
+		a.roles[role.Name] = role
+	}
+	return a, nil
+}
+
+// Authenticate returns the user making the request. Returns false when the
+// request carries no valid credentials.
+func (a *Authenticator) Authenticate(r *http.Request) (User, bool) {
+	if name, password, ok := r.BasicAuth(); ok {
+		if a.htpasswd == nil {
+			return User{}, false
+		}
+		return a.htpasswd.authenticate(name, password)
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return User{}, false
+	}
+	if user, ok := a.tokens.authenticate(token); ok {
+		return user, true
+	}
+	if a.jwt != nil && strings.Count(token, ".") == 2 {
+		user, err := a.jwt.validate(token)
+		if err != nil {
+			a.logger.Debug("Rejected bearer token", slog.Any("error", err))
+			return User{}, false
+		}
+		return user, true
+	}
+	return User{}, false
+}
+
+// Scope returns the scope of records visible to the user based on the roles
+// bound to the user and their groups
+func (a *Authenticator) Scope(user User) Scope {
+	var scope Scope
+	bound := make(map[string]bool)
+	for _, binding := range a.bindings {
+		if bound[binding.Role] || !bindingMatches(binding, user) {
+			continue
+		}
+		bound[binding.Role] = true
+		scope.roles = append(scope.roles, a.roles[binding.Role])
+	}
+	return scope
+}
+
+func bindingMatches(binding Binding, user User) bool {
+	for _, name := range binding.Users {
+		if name == user.Name {
+			return true
+		}
+	}
+	for _, group := range binding.Groups {
+		for _, userGroup := range user.Groups {
+			if group == userGroup {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Middleware returns a handler that rejects requests from users that cannot
+// be authenticated or that have no roles, and otherwise adds the user and
+// their scope to the request context before calling next.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	challenge := `Bearer realm="network-observer"`
+	if a.htpasswd != nil {
+		challenge = `Basic realm="network-observer", ` + challenge
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := a.Authenticate(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", challenge)
+			writeError(w, http.StatusUnauthorized, "ErrUnauthorized", "authentication required")
+			return
+		}
+		scope := a.Scope(user)
+		if len(scope.roles) == 0 {
+			a.logger.Info("Denied request from user without roles",
+				slog.String("user", user.Name),
+				slog.String("endpoint", r.URL.Path),
+			)
+			writeError(w, http.StatusForbidden, "ErrForbidden", fmt.Sprintf("user %q is not bound to any role", user.Name))
+			return
+		}
+		ctx := context.WithValue(r.Context(), userKey, user)
+		next.ServeHTTP(w, r.WithContext(contextWithScope(ctx, scope)))
+	})
+}
+
+// RequireUnrestricted returns a handler that only calls next for requests
+// whose scope includes every record. It is used for endpoints that cannot
+// filter their responses by scope.
+func RequireUnrestricted(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if scope, ok := ScopeFromContext(r.Context()); ok && !scope.Unrestricted() {
+			writeError(w, http.StatusForbidden, "ErrForbidden", "endpoint requires a role without site, namespace or routing key restrictions")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UserFromContext returns the authenticated user added to a request context
+// by the Authenticator middleware
+func UserFromContext(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(userKey).(User)
+	return user, ok
+}
+
+func contextWithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeKey, scope)
+}
+
+// ScopeFromContext returns the scope added to a request context by the
+// Authenticator middleware
+func ScopeFromContext(ctx context.Context) (Scope, bool) {
+	scope, ok := ctx.Value(scopeKey).(Scope)
+	return scope, ok
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}{code, message})
+}
@@ -0,0 +1,267 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func writeFile(t *testing.T, name string, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	assert.NilError(t, os.WriteFile(path, []byte(contents), 0o600))
+	return path
+}
+
+func TestAuthenticator(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NilError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	jwksData, err := json.Marshal(jwks{Keys: []jwk{
+		{Kty: "RSA", Kid: "rsa-1", Use: "sig", N: b64(rsaKey.N.Bytes()), E: b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{Kty: "EC", Kid: "ec-1", Crv: "P-256", X: b64(ecKey.X.FillBytes(make([]byte, 32))), Y: b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+	}})
+	assert.NilError(t, err)
+
+	a, err := New(slog.Default(), Config{
+		TokenFile:    writeFile(t, "tokens.csv", "# token,user,groups\nadmin-token,admin\npayments-token,bob,\"payments, dev\"\nnobody-token,nobody\n"),
+		HtpasswdFile: writeFile(t, "htpasswd", "skupper:{PLAIN}secret\nsha:{SHA}qUqP5cyxm6YcTAhz05Hph5gvu9M=\n"),
+		OIDC: &OIDCConfig{
+			Issuer:        "https://idp.example.com",
+			Audience:      "network-observer",
+			JWKSFile:      writeFile(t, "jwks.json", string(jwksData)),
+			UsernameClaim: "email",
+		},
+		Roles: []Role{
+			{Name: "admin"},
+			{Name: "payments", Namespaces: []string{"payments-*"}, RoutingKeys: []string{"payments-*"}},
+		},
+		Bindings: []Binding{
+			{Role: "admin", Users: []string{"admin", "skupper"}},
+			{Role: "payments", Groups: []string{"payments"}},
+		},
+	})
+	assert.NilError(t, err)
+
+	now := time.Now()
+	claims := map[string]any{
+		"iss":    "https://idp.example.com",
+		"aud":    []string{"other", "network-observer"},
+		"exp":    now.Add(time.Hour).Unix(),
+		"email":  "carol@example.com",
+		"groups": []string{"payments"},
+	}
+	withClaims := func(changes map[string]any) map[string]any {
+		out := make(map[string]any, len(claims))
+		for k, v := range claims {
+			out[k] = v
+		}
+		for k, v := range changes {
+			out[k] = v
+		}
+		return out
+	}
+
+	srv := httptest.NewServer(a.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := UserFromContext(r.Context())
+		assert.Assert(t, ok)
+		scope, ok := ScopeFromContext(r.Context())
+		assert.Assert(t, ok)
+		json.NewEncoder(w).Encode(map[string]any{"user": user.Name, "roles": scope.Roles()})
+	})))
+	defer srv.Close()
+
+	testcases := []struct {
+		Name         string
+		Bearer       string
+		BasicUser    string
+		BasicPass    string
+		ExpectStatus int
+		ExpectUser   string
+		ExpectRoles  []any
+	}{
+		{Name: "no credentials", ExpectStatus: http.StatusUnauthorized},
+		{Name: "static token", Bearer: "admin-token", ExpectStatus: http.StatusOK, ExpectUser: "admin", ExpectRoles: []any{"admin"}},
+		{Name: "static token with groups", Bearer: "payments-token", ExpectStatus: http.StatusOK, ExpectUser: "bob", ExpectRoles: []any{"payments"}},
+		{Name: "unknown token", Bearer: "guess", ExpectStatus: http.StatusUnauthorized},
+		{Name: "user without roles", Bearer: "nobody-token", ExpectStatus: http.StatusForbidden},
+		{Name: "htpasswd plain", BasicUser: "skupper", BasicPass: "secret", ExpectStatus: http.StatusOK, ExpectUser: "skupper", ExpectRoles: []any{"admin"}},
+		{Name: "htpasswd sha", BasicUser: "sha", BasicPass: "test", ExpectStatus: http.StatusForbidden},
+		{Name: "htpasswd wrong password", BasicUser: "skupper", BasicPass: "guess", ExpectStatus: http.StatusUnauthorized},
+		{Name: "jwt rsa", Bearer: signRS256(t, rsaKey, "rsa-1", claims), ExpectStatus: http.StatusOK, ExpectUser: "carol@example.com", ExpectRoles: []any{"payments"}},
+		{Name: "jwt ec", Bearer: signES256(t, ecKey, "ec-1", claims), ExpectStatus: http.StatusOK, ExpectUser: "carol@example.com", ExpectRoles: []any{"payments"}},
+		{Name: "jwt expired", Bearer: signRS256(t, rsaKey, "rsa-1", withClaims(map[string]any{"exp": now.Add(-time.Hour).Unix()})), ExpectStatus: http.StatusUnauthorized},
+		{Name: "jwt wrong issuer", Bearer: signRS256(t, rsaKey, "rsa-1", withClaims(map[string]any{"iss": "https://evil.example.com"})), ExpectStatus: http.StatusUnauthorized},
+		{Name: "jwt wrong audience", Bearer: signRS256(t, rsaKey, "rsa-1", withClaims(map[string]any{"aud": "other"})), ExpectStatus: http.StatusUnauthorized},
+		{Name: "jwt wrong key", Bearer: signRS256(t, rsaKey, "ec-1", claims), ExpectStatus: http.StatusUnauthorized},
+		{Name: "jwt unsigned", Bearer: unsigned(claims), ExpectStatus: http.StatusUnauthorized},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+			assert.NilError(t, err)
+			if tc.Bearer != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.Bearer)
+			}
+			if tc.BasicUser != "" {
+				req.SetBasicAuth(tc.BasicUser, tc.BasicPass)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			assert.NilError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, resp.StatusCode, tc.ExpectStatus)
+			if tc.ExpectStatus == http.StatusUnauthorized {
+				assert.Equal(t, resp.Header.Get("WWW-Authenticate"), `Basic realm="network-observer", Bearer realm="network-observer"`)
+			}
+			if tc.ExpectStatus != http.StatusOK {
+				return
+			}
+			var body map[string]any
+			assert.NilError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, body["user"], tc.ExpectUser)
+			assert.DeepEqual(t, body["roles"], tc.ExpectRoles)
+		})
+	}
+}
+
+func TestScope(t *testing.T) {
+	scope := Scope{roles: []Role{
+		{Name: "west", Sites: []string{"west-*"}},
+		{Name: "payments", Namespaces: []string{"payments"}, RoutingKeys: []string{"payments-*"}},
+	}}
+	assert.Assert(t, !scope.Unrestricted())
+	assert.Assert(t, scope.AllowSite("site-1", "west-1", "default"))
+	assert.Assert(t, scope.AllowSite("site-2", "east-1", "payments"))
+	assert.Assert(t, !scope.AllowSite("site-3", "east-2", "default"))
+	// the west role does not restrict routing keys
+	assert.Assert(t, scope.AllowRoutingKey("inventory"))
+
+	roleScopes := scope.RoleScopes()
+	assert.Equal(t, len(roleScopes), 2)
+	assert.DeepEqual(t, roleScopes[0].Roles(), []string{"west"})
+	assert.Assert(t, roleScopes[0].AllowSite("site-1", "west-1", "default"))
+	assert.Assert(t, !roleScopes[1].AllowSite("site-1", "west-1", "default"))
+	assert.Assert(t, !roleScopes[1].AllowRoutingKey("inventory"))
+
+	scope = Scope{roles: scope.roles[1:]}
+	assert.Assert(t, scope.AllowRoutingKey("payments-api"))
+	assert.Assert(t, !scope.AllowRoutingKey("inventory"))
+
+	scope = Scope{roles: append(scope.roles, Role{Name: "admin"})}
+	assert.Assert(t, scope.Unrestricted())
+}
+
+func TestRequireUnrestricted(t *testing.T) {
+	handler := RequireUnrestricted(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, tc := range []struct {
+		Scope        *Scope
+		ExpectStatus int
+	}{
+		{ExpectStatus: http.StatusOK},
+		{Scope: &Scope{roles: []Role{{Name: "admin"}}}, ExpectStatus: http.StatusOK},
+		{Scope: &Scope{roles: []Role{{Name: "west", Sites: []string{"west"}}}}, ExpectStatus: http.StatusForbidden},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tc.Scope != nil {
+			req = req.WithContext(contextWithScope(req.Context(), *tc.Scope))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, rec.Code, tc.ExpectStatus)
+	}
+}
+
+func TestLoadCredentialsInvalid(t *testing.T) {
+	_, err := loadTokenFile(writeFile(t, "tokens.csv", "token-only\n"))
+	assert.ErrorContains(t, err, "line 1: expected token, user and optional groups")
+	_, err = loadTokenFile(writeFile(t, "tokens.csv", "a,alice\na,bob\n"))
+	assert.ErrorContains(t, err, "line 2: duplicate token")
+	_, err = loadHtpasswdFile(writeFile(t, "htpasswd", "alice:$2y$05$abcdefghijklmnopqrstuv\n"))
+	assert.ErrorContains(t, err, `unsupported password scheme for user "alice"`)
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeFile(t, "auth.yaml", `
+tokenFile: /etc/observer/tokens.csv
+roles:
+- name: payments
+  namespaces: ["payments-*"]
+bindings:
+- role: payments
+  groups: [payments]
+`)
+	cfg, err := LoadConfig(path)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cfg.Roles, []Role{{Name: "payments", Namespaces: []string{"payments-*"}}})
+
+	_, err = LoadConfig(writeFile(t, "auth.yaml", `
+oidc:
+  issuer: https://idp.example.com
+roles:
+- name: west
+  sites: ["[west"]
+- name: west
+bindings:
+- role: east
+`))
+	assert.ErrorContains(t, err, "oidc: audience is required")
+	assert.ErrorContains(t, err, "oidc: jwksFile is required")
+	assert.ErrorContains(t, err, `roles[0]: invalid pattern "[west"`)
+	assert.ErrorContains(t, err, `roles[1]: duplicate role name "west"`)
+	assert.ErrorContains(t, err, `bindings[0]: unknown role "east"`)
+	assert.ErrorContains(t, err, "bindings[0]: at least one user or group is required")
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func encodeToken(t *testing.T, alg, kid string, claims map[string]any) string {
+	t.Helper()
+	header, err := json.Marshal(jwtHeader{Alg: alg, Kid: kid})
+	assert.NilError(t, err)
+	payload, err := json.Marshal(claims)
+	assert.NilError(t, err)
+	return b64(header) + "." + b64(payload)
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+	signed := encodeToken(t, "RS256", kid, claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	assert.NilError(t, err)
+	return signed + "." + b64(signature)
+}
+
+func signES256(t *testing.T, key *ecdsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+	signed := encodeToken(t, "ES256", kid, claims)
+	digest := sha256.Sum256([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	assert.NilError(t, err)
+	signature := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	return signed + "." + b64(signature)
+}
+
+func unsigned(claims map[string]any) string {
+	header, _ := json.Marshal(jwtHeader{Alg: "none"})
+	payload, _ := json.Marshal(claims)
+	return b64(header) + "." + b64(payload) + "."
+}
@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	defaultUsernameClaim = "sub"
+	defaultGroupsClaim   = "groups"
+)
+
+// Config for authenticating and authorizing API requests
+type Config struct {
+	// TokenFile is the path to a csv file of static bearer tokens. Each
+	// line holds a token, a user name and optionally a quoted comma
+	// separated list of groups.
+	TokenFile string `json:"tokenFile,omitempty"`
+	// HtpasswdFile is the path to an htpasswd file used to authenticate
+	// requests with basic authentication
+	HtpasswdFile string `json:"htpasswdFile,omitempty"`
+	// OIDC configures validation of bearer tokens issued by an OpenID
+	// Connect provider
+	OIDC *OIDCConfig `json:"oidc,omitempty"`
+	// Roles define the records visible to the users bound to them
+	Roles []Role `json:"roles"`
+	// Bindings grant roles to users and groups
+	Bindings []Binding `json:"bindings"`
+}
+
+// OIDCConfig configures validation of JWT bearer tokens against a local
+// JSON Web Key Set
+type OIDCConfig struct {
+	// Issuer tokens must be issued by
+	Issuer string `json:"issuer"`
+	// Audience tokens must be issued for
+	Audience string `json:"audience"`
+	// JWKSFile is the path to a file containing the JSON Web Key Set used
+	// to verify token signatures
+	JWKSFile string `json:"jwksFile"`
+	// UsernameClaim is the claim holding the user name. Defaults to sub.
+	UsernameClaim string `json:"usernameClaim,omitempty"`
+	// GroupsClaim is the claim holding the user's groups. Defaults to
+	// groups.
+	GroupsClaim string `json:"groupsClaim,omitempty"`
+}
+
+// Role scopes the records visible to its users. Each selector is a list
+// of glob patterns. A record is visible when it belongs to a site matching
+// both Sites and Namespaces and, for records with a routing key, the key
+// matches RoutingKeys. An empty selector matches everything, so a role
+// without selectors can see every record.
+type Role struct {
+	// Name of the role. Must be unique.
+	Name string `json:"name"`
+	// Sites matched by site name or identity
+	Sites []string `json:"sites,omitempty"`
+	// Namespaces matched by site namespace
+	Namespaces []string `json:"namespaces,omitempty"`
+	// RoutingKeys matched by listener, connector and connection routing
+	// keys
+	RoutingKeys []string `json:"routingKeys,omitempty"`
+}
+
+// Binding grants a role to users and groups
+type Binding struct {
+	Role   string   `json:"role"`
+	Users  []string `json:"users,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+}
+
+// LoadConfig reads an authentication configuration from a yaml or json file
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read auth config: %w", err)
+	}
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse auth config %q: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid auth config %q: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate checks the configuration for errors
+func (c Config) Validate() error {
+	var errs []error
+	if c.TokenFile == "" && c.HtpasswdFile == "" && c.OIDC == nil {
+		errs = append(errs, fmt.Errorf("at least one of tokenFile, htpasswdFile or oidc is required"))
+	}
+	if c.OIDC != nil {
+		if c.OIDC.Issuer == "" {
+			errs = append(errs, fmt.Errorf("oidc: issuer is required"))
+		}
+		if c.OIDC.Audience == "" {
+			errs = append(errs, fmt.Errorf("oidc: audience is required"))
+		}
+		if c.OIDC.JWKSFile == "" {
+			errs = append(errs, fmt.Errorf("oidc: jwksFile is required"))
+		}
+	}
+	roles := make(map[string]bool, len(c.Roles))
+	for i, role := range c.Roles {
+		if role.Name == "" {
+			errs = append(errs, fmt.Errorf("roles[%d]: name is required", i))
+		} else if roles[role.Name] {
+			errs = append(errs, fmt.Errorf("roles[%d]: duplicate role name %q", i, role.Name))
+		}
+		roles[role.Name] = true
+		for _, patterns := range [][]string{role.Sites, role.Namespaces, role.RoutingKeys} {
+			for _, pattern := range patterns {
+				if _, err := path.Match(pattern, ""); err != nil {
+					errs = append(errs, fmt.Errorf("roles[%d]: invalid pattern %q", i, pattern))
+				}
+			}
+		}
+	}
+	for i, binding := range c.Bindings {
+		if !roles[binding.Role] {
+			errs = append(errs, fmt.Errorf("bindings[%d]: unknown role %q", i, binding.Role))
+		}
+		if len(binding.Users) == 0 && len(binding.Groups) == 0 {
+			errs = append(errs, fmt.Errorf("bindings[%d]: at least one user or group is required", i))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// User is an authenticated API user
+type User struct {
+	Name   string
+	Groups []string
+}
+
+// tokenSet authenticates static bearer tokens. Tokens are held as digests
+// and checked in constant time against every configured token, so that the
+// time taken does not reveal how much of a token matched.
+type tokenSet []staticToken
+
+type staticToken struct {
+	digest [sha256.Size]byte
+	user   User
+}
+
+// loadTokenFile reads a csv file of static tokens. Each record holds a
+// token, a user name and an optional comma separated list of groups.
+func loadTokenFile(path string) (tokenSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+	defer f.Close()
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	var tokens tokenSet
+	seen := make(map[[sha256.Size]byte]struct{})
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse token file %q: %w", path, err)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(fields) < 2 || len(fields) > 3 || fields[0] == "" || fields[1] == "" {
+			return nil, fmt.Errorf("token file %q line %d: expected token, user and optional groups", path, line)
+		}
+		digest := sha256.Sum256([]byte(fields[0]))
+		if _, ok := seen[digest]; ok {
+			return nil, fmt.Errorf("token file %q line %d: duplicate token", path, line)
+		}
+		seen[digest] = struct{}{}
+		user := User{Name: fields[1]}
+		if len(fields) == 3 && fields[2] != "" {
+			for _, group := range strings.Split(fields[2], ",") {
+				if group = strings.TrimSpace(group); group != "" {
+					user.Groups = append(user.Groups, group)
+				}
+			}
+		}
+		tokens = append(tokens, staticToken{digest: digest, user: user})
+	}
+	return tokens, nil
+}
+
+func (t tokenSet) authenticate(token string) (User, bool) {
+	digest := sha256.Sum256([]byte(token))
+	var (
+		user  User
+		found bool
+	)
+	for _, candidate := range t {
+		if subtle.ConstantTimeCompare(digest[:], candidate.digest[:]) == 1 {
+			user, found = candidate.user, true
+		}
+	}
+	return user, found
+}
+
+// htpasswd authenticates basic auth credentials against the contents of an
+// htpasswd file. Only the {PLAIN} and {SHA} password schemes are supported.
+type htpasswd map[string]string
+
+func loadHtpasswdFile(path string) (htpasswd, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read htpasswd file: %w", err)
+	}
+	users := make(htpasswd)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		name, hash, ok := strings.Cut(text, ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("htpasswd file %q line %d: expected user:password", path, line)
+		}
+		if !strings.HasPrefix(hash, "{PLAIN}") && !strings.HasPrefix(hash, "{SHA}") {
+			return nil, fmt.Errorf("htpasswd file %q line %d: unsupported password scheme for user %q", path, line, name)
+		}
+		users[name] = hash
+	}
+	return users, scanner.Err()
+}
+
+func (h htpasswd) authenticate(name, password string) (User, bool) {
+	hash, ok := h[name]
+	if !ok {
+		return User{}, false
+	}
+	var expected, actual []byte
+	switch {
+	case strings.HasPrefix(hash, "{PLAIN}"):
+		expected, actual = []byte(strings.TrimPrefix(hash, "{PLAIN}")), []byte(password)
+	case strings.HasPrefix(hash, "{SHA}"):
+		sum := sha1.Sum([]byte(password))
+		expected = []byte(strings.TrimPrefix(hash, "{SHA}"))
+		actual = []byte(base64.StdEncoding.EncodeToString(sum[:]))
+	}
+	if subtle.ConstantTimeCompare(expected, actual) != 1 {
+		return User{}, false
+	}
+	return User{Name: name}, true
+}
@@ -0,0 +1,284 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+// clockSkew is the leeway allowed when checking token expiry and not before
+// times
+const clockSkew = time.Minute
+
+// jwk is a JSON Web Key holding an RSA or EC public key
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+type verificationKey struct {
+	id  string
+	alg string
+	key crypto.PublicKey
+}
+
+// jwtValidator validates signed JWTs issued by an OpenID Connect provider
+type jwtValidator struct {
+	issuer        string
+	audience      string
+	usernameClaim string
+	groupsClaim   string
+	keys          []verificationKey
+	now           func() time.Time
+}
+
+func newJWTValidator(cfg OIDCConfig) (*jwtValidator, error) {
+	data, err := os.ReadFile(cfg.JWKSFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jwks file: %w", err)
+	}
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse jwks file %q: %w", cfg.JWKSFile, err)
+	}
+	v := &jwtValidator{
+		issuer:        cfg.Issuer,
+		audience:      cfg.Audience,
+		usernameClaim: cfg.UsernameClaim,
+		groupsClaim:   cfg.GroupsClaim,
+		now:           time.Now,
+	}
+	if v.usernameClaim == "" {
+		v.usernameClaim = defaultUsernameClaim
+	}
+	if v.groupsClaim == "" {
+		v.groupsClaim = defaultGroupsClaim
+	}
+	for i, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		pub, err := key.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwks file %q keys[%d]: %w", cfg.JWKSFile, i, err)
+		}
+		v.keys = append(v.keys, verificationKey{id: key.Kid, alg: key.Alg, key: pub})
+	}
+	if len(v.keys) == 0 {
+		return nil, fmt.Errorf("jwks file %q contains no signing keys", cfg.JWKSFile)
+	}
+	return v, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil || !e.IsInt64() {
+			return nil, fmt.Errorf("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid x coordinate: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid y coordinate: %w", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve %s", k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// validate checks the token's signature and claims and returns the user it
+// identifies
+func (v *jwtValidator) validate(token string) (User, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return User{}, errors.New("malformed token")
+	}
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return User{}, fmt.Errorf("malformed token header: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return User{}, fmt.Errorf("malformed token signature: %w", err)
+	}
+	if err := v.verify(header, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return User{}, err
+	}
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return User{}, fmt.Errorf("malformed token claims: %w", err)
+	}
+	if iss, _ := claims["iss"].(string); iss != v.issuer {
+		return User{}, fmt.Errorf("unexpected issuer %q", iss)
+	}
+	if !hasAudience(claims["aud"], v.audience) {
+		return User{}, errors.New("token not issued for this audience")
+	}
+	now := v.now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return User{}, errors.New("token has no expiry")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(clockSkew)) {
+		return User{}, errors.New("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(clockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return User{}, errors.New("token not yet valid")
+	}
+	name, _ := claims[v.usernameClaim].(string)
+	if name == "" {
+		return User{}, fmt.Errorf("token has no %s claim", v.usernameClaim)
+	}
+	user := User{Name: name}
+	switch groups := claims[v.groupsClaim].(type) {
+	case string:
+		user.Groups = []string{groups}
+	case []any:
+		for _, group := range groups {
+			if s, ok := group.(string); ok {
+				user.Groups = append(user.Groups, s)
+			}
+		}
+	}
+	return user, nil
+}
+
+func (v *jwtValidator) verify(header jwtHeader, signed, signature []byte) error {
+	hash, err := hashForAlg(header.Alg)
+	if err != nil {
+		return err
+	}
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+	for _, key := range v.keys {
+		if header.Kid != "" && key.id != "" && header.Kid != key.id {
+			continue
+		}
+		if key.alg != "" && key.alg != header.Alg {
+			continue
+		}
+		switch pub := key.key.(type) {
+		case *rsa.PublicKey:
+			switch header.Alg[:2] {
+			case "RS":
+				if rsa.VerifyPKCS1v15(pub, hash, digest, signature) == nil {
+					return nil
+				}
+			case "PS":
+				if rsa.VerifyPSS(pub, hash, digest, signature, nil) == nil {
+					return nil
+				}
+			}
+		case *ecdsa.PublicKey:
+			size := (pub.Curve.Params().BitSize + 7) / 8
+			if header.Alg[:2] != "ES" || len(signature) != 2*size {
+				continue
+			}
+			r := new(big.Int).SetBytes(signature[:size])
+			s := new(big.Int).SetBytes(signature[size:])
+			if ecdsa.Verify(pub, digest, r, s) {
+				return nil
+			}
+		}
+	}
+	return errors.New("invalid token signature")
+}
+
+func hashForAlg(alg string) (crypto.Hash, error) {
+	switch alg {
+	case "RS256", "PS256", "ES256":
+		return crypto.SHA256, nil
+	case "RS384", "PS384", "ES384":
+		return crypto.SHA384, nil
+	case "RS512", "PS512", "ES512":
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("unsupported token signing algorithm %q", alg)
+	}
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func hasAudience(aud any, audience string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == audience
+	case []any:
+		for _, a := range aud {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"path"
+)
+
+// Scope is the set of records visible to a user: the union of the records
+// visible to each of the user's roles.
+type Scope struct {
+	roles []Role
+}
+
+// Unrestricted returns true when the scope includes every record
+func (s Scope) Unrestricted() bool {
+	for _, role := range s.roles {
+		if len(role.Sites) == 0 && len(role.Namespaces) == 0 && len(role.RoutingKeys) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowSite returns true when records belonging to the site are visible
+// to one of the roles in the scope
+func (s Scope) AllowSite(id, name, namespace string) bool {
+	for _, role := range s.roles {
+		if (matchAny(role.Sites, id) || matchAny(role.Sites, name)) && matchAny(role.Namespaces, namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowRoutingKey returns true when records for the routing key are
+// visible to one of the roles in the scope
+func (s Scope) AllowRoutingKey(key string) bool {
+	for _, role := range s.roles {
+		if matchAny(role.RoutingKeys, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleScopes returns a scope for each of the roles making up the scope.
+// Records restricted by both site and routing key must be checked against
+// each of these in turn: checking AllowSite and AllowRoutingKey on the
+// combined scope would admit a site permitted by one role together with a
+// routing key permitted by another.
+func (s Scope) RoleScopes() []Scope {
+	scopes := make([]Scope, 0, len(s.roles))
+	for _, role := range s.roles {
+		scopes = append(scopes, Scope{roles: []Role{role}})
+	}
+	return scopes
+}
+
+// Roles returns the names of the roles making up the scope
+func (s Scope) Roles() []string {
+	names := make([]string, 0, len(s.roles))
+	for _, role := range s.roles {
+		names = append(names, role.Name)
+	}
+	return names
+}
+
+// matchAny returns true when patterns is empty or value matches one of
+// the patterns
+func matchAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
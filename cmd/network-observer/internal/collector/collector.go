@@ -9,13 +9,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"log/slog"
 	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	opmetrics "github.com/skupperproject/skupper/cmd/network-observer/internal/collector/metrics"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/otlp"
 	"github.com/skupperproject/skupper/pkg/vanflow"
 	"github.com/skupperproject/skupper/pkg/vanflow/eventsource"
 	"github.com/skupperproject/skupper/pkg/vanflow/session"
@@ -23,24 +29,55 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
-func New(logger *slog.Logger, factory session.ContainerFactory, reg *prometheus.Registry, flowRecordTTL time.Duration, flowLogger func(vanflow.RecordMessage)) *Collector {
+// Config for a Collector
+type Config struct {
+	// FlowRecordTTL is how long flow records are retained after they were
+	// last updated.
+	FlowRecordTTL time.Duration
+	// FlowLogger is called for each vanflow record message received.
+	FlowLogger func(vanflow.RecordMessage)
+	// StoreDir is the directory the collector persists records to so that
+	// they survive restarts. When empty, records are kept in memory only.
+	StoreDir string
+	// SpanExporter, when set, is called with a span describing each
+	// connection and application request once it has terminated.
+	SpanExporter func(otlp.Span)
+	// Network is the name of the application network the collector
+	// observes. Used to distinguish collectors when observing more than one
+	// network.
+	Network string
+	// Retention configures how long terminated records are kept and limits
+	// the memory used by collected records.
+	Retention RetentionConfig
+}
+
+func New(logger *slog.Logger, factory session.ContainerFactory, reg prometheus.Registerer, cfg Config) (*Collector, error) {
+	if err := cfg.Retention.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid retention config: %w", err)
+	}
 	sessionCtr := factory.Create()
 
 	collector := &Collector{
-		logger:         logger,
-		flowRecordTTL:  flowRecordTTL,
-		session:        sessionCtr,
-		discovery:      eventsource.NewDiscovery(sessionCtr, eventsource.DiscoveryOptions{}),
-		sources:        make(map[string]eventSource),
-		events:         make(chan changeEvent, 1024),
-		purgeQueue:     make(chan store.SourceRef, 8),
-		recordRouting:  make(eventsource.RecordStoreMap),
-		metrics:        register(reg),
-		metricsAdaptor: opmetrics.New(reg),
-		flowLogging:    flowLogger,
+		logger:          logger,
+		network:         cfg.Network,
+		flowRetention:   newFlowRetention(cfg.FlowRecordTTL, cfg.Retention),
+		retention:       cfg.Retention,
+		storeDir:        cfg.StoreDir,
+		session:         sessionCtr,
+		discovery:       eventsource.NewDiscovery(sessionCtr, eventsource.DiscoveryOptions{}),
+		sources:         make(map[string]eventSource),
+		restoredSources: make(map[string]store.SourceRef),
+		events:          make(chan changeEvent, 1024),
+		purgeQueue:      make(chan store.SourceRef, 8),
+		recordRouting:   make(eventsource.RecordStoreMap),
+		metrics:         register(reg),
+		metricsAdaptor:  opmetrics.New(reg),
+		flowLogging:     cfg.FlowLogger,
+		exportSpan:      cfg.SpanExporter,
+		watchers:        newRecordEventBroadcaster(),
 	}
 
-	collector.Records = store.NewSyncMapStore(store.SyncMapStoreConfig{
+	records, err := collector.newStore("records", store.SyncMapStoreConfig{
 		Handlers: store.EventHandlerFuncs{
 			OnAdd:    collector.handleStoreAdd,
 			OnChange: collector.handleStoreChange,
@@ -48,6 +85,17 @@ func New(logger *slog.Logger, factory session.ContainerFactory, reg *prometheus.
 		},
 		Indexers: RecordIndexers(),
 	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating collector record store: %w", err)
+	}
+	collector.Records = records
+	for _, e := range records.List() {
+		if e.Source.ID == "self" {
+			// records inferred by the collector itself
+			continue
+		}
+		collector.restoredSources[e.Source.ID] = e.Source
+	}
 	collector.graph = NewGraph(collector.Records).(*graph)
 	collector.processManager = newProcessManager(logger, collector.Records, collector.graph, newStableIdentityProvider(), collector.metrics)
 	collector.addressManager = newAddressManager(collector.logger, collector.Records)
@@ -56,19 +104,29 @@ func New(logger *slog.Logger, factory session.ContainerFactory, reg *prometheus.
 	for _, typ := range standardRecordTypes {
 		routerCfg[typ.String()] = collector.Records
 	}
-	return collector
+	if _, ok := cfg.Retention.Policies[vanflow.LogRecord{}.GetTypeMeta().Type]; ok {
+		routerCfg[vanflow.LogRecord{}.GetTypeMeta().String()] = collector.Records
+	}
+	return collector, nil
 }
 
 type Collector struct {
 	logger        *slog.Logger
-	flowRecordTTL time.Duration
+	network       string
+	flowRetention flowRetention
+	retention     RetentionConfig
 	flowLogging   func(vanflow.RecordMessage)
+	exportSpan    func(otlp.Span)
+	storeDir      string
 
 	session   session.Container
 	discovery *eventsource.Discovery
 
 	mu      sync.Mutex
 	sources map[string]eventSource
+	// restoredSources are the event sources records were restored from
+	// on startup
+	restoredSources map[string]store.SourceRef
 
 	Records       store.Interface
 	graph         *graph
@@ -81,11 +139,13 @@ type Collector struct {
 
 	events     chan changeEvent
 	purgeQueue chan store.SourceRef
+	watchers   *recordEventBroadcaster
 
 	metrics metrics
 }
 
 type eventSource struct {
+	source  store.SourceRef
 	client  *eventsource.Client
 	manager *connectionManager
 }
@@ -94,6 +154,12 @@ func (c *Collector) GetGraph() Graph {
 	return c.graph
 }
 
+// Network returns the name of the application network the collector
+// observes
+func (c *Collector) Network() string {
+	return c.network
+}
+
 func (c *Collector) Run(ctx context.Context) error {
 	c.session.Start(ctx)
 	g, ctx := errgroup.WithContext(ctx)
@@ -105,7 +171,27 @@ func (c *Collector) Run(ctx context.Context) error {
 	g.Go(c.processManager.run(ctx))
 	g.Go(c.addressManager.run(ctx))
 	g.Go(c.pairManager.run(ctx))
-	return g.Wait()
+	g.Go(c.purgeUnrecoveredSources(ctx))
+	err := g.Wait()
+	c.closeSources()
+	if closer, ok := c.Records.(io.Closer); ok {
+		if cErr := closer.Close(); cErr != nil {
+			c.logger.Error("error closing record store", slog.Any("error", cErr))
+		}
+	}
+	return err
+}
+
+// closeSources closes the client and flow store for each event source
+func (c *Collector) closeSources() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range c.sources {
+		s.client.Close()
+		if s.manager != nil {
+			s.manager.Close()
+		}
+	}
 }
 
 func (c *Collector) updateGraph(event changeEvent, stor readonly) {
@@ -183,6 +269,14 @@ func (c *Collector) runWorkQueue(ctx context.Context) func() error {
 		defer func() {
 			c.logger.Info("queue worker shutdown complete")
 		}()
+		// records restored from disk are added to the store without events.
+		// Replay them to the reactors before handling new events.
+		for _, e := range c.Records.List() {
+			event := addEvent{Record: e.Record}
+			for _, reactor := range reactors[event.GetTypeMeta()] {
+				reactor(event, c.Records)
+			}
+		}
 		for {
 			select {
 			case <-ctx.Done():
@@ -248,19 +342,12 @@ func (c *Collector) runRecordCleanup(ctx context.Context) func() error {
 		}()
 		ticker := time.NewTicker(10 * time.Second)
 		defer ticker.Stop()
-		terminatedExemplar := store.Entry{
-			Record: vanflow.SiteRecord{BaseRecord: vanflow.NewBase("", time.Unix(1, 0), time.Unix(2, 0))},
-		}
 		for {
 			select {
 			case <-ctx.Done():
 				return nil
 			case <-ticker.C:
-				terminated := c.Records.Index(IndexByLifecycleStatus, terminatedExemplar)
-				for _, e := range terminated {
-					c.Records.Delete(e.Record.Identity())
-				}
-				if ct := len(terminated); ct > 0 {
+				if ct := c.enforceRetention(time.Now()); ct > 0 {
 					c.logger.Info("purged terminated records",
 						slog.Int("count", ct),
 					)
@@ -276,12 +363,74 @@ func (c *Collector) runRecordCleanup(ctx context.Context) func() error {
 	}
 }
 
+// purgeUnrecoveredSources purges records restored from disk belonging to
+// event sources that have not been discovered again after startup.
+func (c *Collector) purgeUnrecoveredSources(ctx context.Context) func() error {
+	return func() error {
+		if len(c.restoredSources) == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(restoredSourceGracePeriod):
+		}
+		c.mu.Lock()
+		var unrecovered []store.SourceRef
+		for id, source := range c.restoredSources {
+			if s, ok := c.sources[id]; !ok || s.source != source {
+				unrecovered = append(unrecovered, source)
+			}
+		}
+		c.mu.Unlock()
+		for _, source := range unrecovered {
+			if c.storeDir != "" {
+				if err := os.Remove(c.flowStorePath(source)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					c.logger.Error("error removing flow store for unrecovered source",
+						slog.String("source", source.ID),
+						slog.Any("error", err))
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case c.purgeQueue <- source:
+			}
+		}
+		return nil
+	}
+}
+
+// newStore creates a record store. When the collector is configured with a
+// StoreDir the store is persisted to disk.
+func (c *Collector) newStore(name string, cfg store.SyncMapStoreConfig) (store.Interface, error) {
+	if c.storeDir == "" {
+		return store.NewSyncMapStore(cfg), nil
+	}
+	return store.NewDiskStore(store.DiskStoreConfig{
+		SyncMapStoreConfig: cfg,
+		Path:               filepath.Join(c.storeDir, name+".log"),
+		RecordTypes:        persistentRecordTypes,
+	})
+}
+
+func (c *Collector) flowStoreName(source store.SourceRef) string {
+	return "flows-" + url.PathEscape(source.ID)
+}
+
+func (c *Collector) flowStorePath(source store.SourceRef) string {
+	return filepath.Join(c.storeDir, c.flowStoreName(source)+".log")
+}
+
 func (c *Collector) handleStoreAdd(e store.Entry) {
+	c.watchers.publish(RecordEvent{Type: RecordAdded, Entry: e})
 	switch e.Record.(type) {
 	case RequestRecord:
 		return
 	case ConnectionRecord:
 		return
+	case vanflow.LogRecord:
+		return
 	}
 	select {
 	case c.events <- addEvent{Record: e.Record}:
@@ -291,11 +440,14 @@ func (c *Collector) handleStoreAdd(e store.Entry) {
 }
 
 func (c *Collector) handleStoreChange(p, e store.Entry) {
+	c.watchers.publish(RecordEvent{Type: RecordUpdated, Entry: e})
 	switch e.Record.(type) {
 	case RequestRecord:
 		return
 	case ConnectionRecord:
 		return
+	case vanflow.LogRecord:
+		return
 	}
 	select {
 	case c.events <- updateEvent{Prev: p.Record, Curr: e.Record}:
@@ -304,11 +456,14 @@ func (c *Collector) handleStoreChange(p, e store.Entry) {
 	}
 }
 func (c *Collector) handleStoreDelete(e store.Entry) {
+	c.watchers.publish(RecordEvent{Type: RecordDeleted, Entry: e})
 	switch e.Record.(type) {
 	case RequestRecord:
 		return
 	case ConnectionRecord:
 		return
+	case vanflow.LogRecord:
+		return
 	}
 	select {
 	case c.events <- deleteEvent{Record: e.Record}:
@@ -347,6 +502,7 @@ func (c *Collector) discoveryHandler(ctx context.Context) func(eventsource.Info)
 		}
 
 		sourceCtr := eventSource{
+			source: sourceRef(source),
 			client: client,
 		}
 
@@ -364,15 +520,27 @@ func (c *Collector) discoveryHandler(ctx context.Context) func(eventsource.Info)
 			addresses = append(addresses, eventsource.FromSourceAddressHeartbeats()) // listen to .heartbeats
 		case "ROUTER":
 			addresses = append(addresses, eventsource.FromSourceAddressFlows()) // listen to .flows
-			sourceCtr.manager = newConnectionmanager(
+			manager, err := newConnectionmanager(
 				ctx,
 				c.logger.With(slog.String("eventsource", fmt.Sprintf("%d/%s", source.Version, source.ID))),
-				sourceRef(source),
+				sourceCtr.source,
 				c.Records,
 				c.graph,
 				c.metrics,
-				c.flowRecordTTL,
+				c.flowRetention,
+				c.watchers,
+				c.exportSpan,
+				func(cfg store.SyncMapStoreConfig) (store.Interface, error) {
+					return c.newStore(c.flowStoreName(sourceCtr.source), cfg)
+				},
 			)
+			if err != nil {
+				c.logger.Error("error creating connection manager for discovered source", slog.Any("error", err))
+				client.Close()
+				c.discovery.Forget(source.ID)
+				return
+			}
+			sourceCtr.manager = manager
 
 			// route flow records to source-specific stores
 			router.Stores = maps.Clone(router.Stores)
@@ -419,6 +587,13 @@ func (c *Collector) handleForgotten(source eventsource.Info) {
 		s.client.Close()
 		if s.manager != nil {
 			s.manager.Stop()
+			if c.storeDir != "" {
+				if err := os.Remove(c.flowStorePath(s.source)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+					c.logger.Error("error removing flow store for forgotten source",
+						slog.String("source", source.ID),
+						slog.Any("error", err))
+				}
+			}
 		}
 		delete(c.sources, source.ID)
 	}
@@ -3,9 +3,12 @@ package collector
 import (
 	"container/list"
 	"context"
+	"fmt"
+	"io"
 	"log/slog"
 	"maps"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -13,6 +16,7 @@ import (
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/otlp"
 	"github.com/skupperproject/skupper/pkg/vanflow"
 	"github.com/skupperproject/skupper/pkg/vanflow/store"
 )
@@ -30,7 +34,9 @@ type connectionManager struct {
 	requestMetricsCache   map[labelSet]appMetrics
 	transportMetricsCache map[labelSet]transportMetrics
 
-	ttl time.Duration
+	retention  flowRetention
+	watchers   *recordEventBroadcaster
+	exportSpan func(otlp.Span)
 
 	transportProcessingTime prometheus.Observer
 	appProcessingTime       prometheus.Observer
@@ -47,7 +53,7 @@ type connectionManager struct {
 	routerCache     map[string]routerAttrs
 }
 
-func newConnectionmanager(ctx context.Context, log *slog.Logger, source store.SourceRef, records store.Interface, graph *graph, metrics metrics, ttl time.Duration) *connectionManager {
+func newConnectionmanager(ctx context.Context, log *slog.Logger, source store.SourceRef, records store.Interface, graph *graph, metrics metrics, retention flowRetention, watchers *recordEventBroadcaster, exportSpan func(otlp.Span), newStore func(store.SyncMapStoreConfig) (store.Interface, error)) (*connectionManager, error) {
 	m := &connectionManager{
 		logger:                  log,
 		records:                 records,
@@ -55,7 +61,9 @@ func newConnectionmanager(ctx context.Context, log *slog.Logger, source store.So
 		source:                  source,
 		idp:                     newStableIdentityProvider(),
 		metrics:                 metrics,
-		ttl:                     ttl,
+		retention:               retention,
+		watchers:                watchers,
+		exportSpan:              exportSpan,
 		transportProcessingTime: metrics.internal.flowProcessingTime.WithLabelValues(vanflow.TransportBiflowRecord{}.GetTypeMeta().String()),
 		appProcessingTime:       metrics.internal.flowProcessingTime.WithLabelValues(vanflow.AppBiflowRecord{}.GetTypeMeta().String()),
 		transportFlows: &keyedLRUCache[transportState, *transportState]{
@@ -72,7 +80,7 @@ func newConnectionmanager(ctx context.Context, log *slog.Logger, source store.So
 		routerCache:     make(map[string]routerAttrs),
 	}
 
-	m.flows = store.NewSyncMapStore(store.SyncMapStoreConfig{
+	flows, err := newStore(store.SyncMapStoreConfig{
 		Handlers: store.EventHandlerFuncs{
 			OnAdd:    m.handleAdd,
 			OnChange: m.handleChange,
@@ -82,9 +90,54 @@ func newConnectionmanager(ctx context.Context, log *slog.Logger, source store.So
 			store.TypeIndex: store.TypeIndexer,
 		},
 	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating flow store: %w", err)
+	}
+	m.flows = flows
+	m.restoreFlowState()
 
 	go m.run(ctx)
-	return m
+	return m, nil
+}
+
+// restoreFlowState seeds flow state for any flows already present in the
+// flow store when it was restored from disk. Restored flows are treated as
+// already counted so that metrics only reflect changes after the restart,
+// and spans for flows that had already ended are treated as exported.
+func (c *connectionManager) restoreFlowState() {
+	entries := c.flows.List()
+	if len(entries) == 0 {
+		return
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUpdate.Before(entries[j].LastUpdate)
+	})
+	for _, e := range entries {
+		switch record := e.Record.(type) {
+		case vanflow.TransportBiflowRecord:
+			c.transportFlows.Push(record.ID, transportState{
+				ID:            record.ID,
+				Opened:        true,
+				Terminated:    isTerminated(record.BaseRecord),
+				SpanExported:  isTerminated(record.BaseRecord),
+				BytesSent:     dref(record.Octets),
+				BytesReceived: dref(record.OctetsReverse),
+				LatencySet:    record.Latency != nil && record.LatencyReverse != nil,
+				FirstSeen:     e.LastUpdate,
+				LastSeen:      e.LastUpdate,
+			})
+		case vanflow.AppBiflowRecord:
+			c.appFlows.Push(record.ID, appState{
+				ID:           record.ID,
+				TransportID:  dref(record.Parent),
+				Terminated:   isTerminated(record.BaseRecord),
+				SpanExported: isTerminated(record.BaseRecord),
+				FirstSeen:    e.LastUpdate,
+				LastSeen:     e.LastUpdate,
+			})
+		}
+	}
+	c.logger.Info("restored flow state", slog.Int("count", len(entries)))
 }
 
 func (c *connectionManager) handleTransportFlow(record vanflow.TransportBiflowRecord) {
@@ -112,6 +165,9 @@ func (c *connectionManager) handleTransportFlow(record vanflow.TransportBiflowRe
 			metrics.closed.Inc()
 		}
 	}
+	if state.Terminated && !state.SpanExported {
+		state.SpanExported = c.exportConnectionSpan(record)
+	}
 	if !state.LatencySet && record.Latency != nil && record.LatencyReverse != nil {
 		delta := time.Microsecond * time.Duration(*record.Latency-*record.LatencyReverse)
 		state.LatencySet = true
@@ -155,6 +211,9 @@ func (c *connectionManager) handleAppFlow(record vanflow.AppBiflowRecord) {
 			}).Inc()
 		}
 	}
+	if state.Terminated && !state.SpanExported {
+		state.SpanExported = c.exportRequestSpan(record)
+	}
 	c.appFlows.Push(record.ID, state)
 }
 
@@ -174,6 +233,56 @@ func (c *connectionManager) handleChange(p, e store.Entry) {
 	default:
 		// ignore
 	}
+	c.publishFlowUpdate(e.Record.Identity())
+}
+
+// publishFlowUpdate notifies watchers of changes to the ConnectionRecord or
+// RequestRecord backed by a flow.
+func (c *connectionManager) publishFlowUpdate(id string) {
+	if !c.watchers.active() {
+		return
+	}
+	if entry, ok := c.records.Get(id); ok {
+		c.watchers.publish(RecordEvent{Type: RecordUpdated, Entry: entry})
+	}
+}
+
+// exportConnectionSpan exports a span for a transport flow that has ended,
+// returning false if it could not be because the flow has not yet been
+// reconciled with a ConnectionRecord.
+func (c *connectionManager) exportConnectionSpan(flow vanflow.TransportBiflowRecord) bool {
+	if c.exportSpan == nil {
+		return true
+	}
+	entry, ok := c.records.Get(flow.ID)
+	if !ok {
+		return false
+	}
+	connection, ok := entry.Record.(ConnectionRecord)
+	if !ok {
+		return false
+	}
+	c.exportSpan(connectionSpan(connection, flow))
+	return true
+}
+
+// exportRequestSpan exports a span for an app flow that has ended,
+// returning false if it could not be because the flow has not yet been
+// reconciled with a RequestRecord.
+func (c *connectionManager) exportRequestSpan(flow vanflow.AppBiflowRecord) bool {
+	if c.exportSpan == nil {
+		return true
+	}
+	entry, ok := c.records.Get(flow.ID)
+	if !ok {
+		return false
+	}
+	request, ok := entry.Record.(RequestRecord)
+	if !ok {
+		return false
+	}
+	c.exportSpan(requestSpan(request, flow))
+	return true
 }
 
 func normalizeHTTPMethod(method *string) string {
@@ -509,14 +618,18 @@ func (c *connectionManager) run(ctx context.Context) {
 				{
 					terminated := map[string]struct{}{}
 					stale := map[string]struct{}{}
-					cutoff := time.Now().Add(-1 * c.ttl)
+					now := time.Now()
+					terminatedCutoff := now.Add(-1 * c.retention.TerminatedConnections)
+					staleCutoff := now.Add(-1 * c.retention.Stale)
+					cutoff := latest(terminatedCutoff, staleCutoff)
 					c.transportFlows.All()(func(state transportState) bool {
 						if !state.LastSeen.Before(cutoff) {
 							return false
 						}
-						if state.Terminated {
+						switch {
+						case state.Terminated && state.LastSeen.Before(terminatedCutoff):
 							terminated[state.ID] = struct{}{}
-						} else {
+						case !state.Terminated && state.LastSeen.Before(staleCutoff):
 							stale[state.ID] = struct{}{}
 						}
 						return true
@@ -524,6 +637,7 @@ func (c *connectionManager) run(ctx context.Context) {
 
 					if ct := len(terminated); ct > 0 {
 						c.logger.Debug("purging terminated transport flows", slog.Int("count", ct))
+						c.metrics.internal.evictedRecords.WithLabelValues(ConnectionRecord{}.GetTypeMeta().Type, evictionReasonTTL).Add(float64(ct))
 						for id := range terminated {
 							c.flows.Delete(id)
 							c.records.Delete(id)
@@ -531,6 +645,7 @@ func (c *connectionManager) run(ctx context.Context) {
 					}
 					if ct := len(stale); ct > 0 {
 						c.logger.Info("purging stale transport flows", slog.Int("count", ct))
+						c.metrics.internal.evictedRecords.WithLabelValues(ConnectionRecord{}.GetTypeMeta().Type, evictionReasonStale).Add(float64(ct))
 						for id := range stale {
 							c.flows.Delete(id)
 							c.records.Delete(id)
@@ -540,26 +655,32 @@ func (c *connectionManager) run(ctx context.Context) {
 				{
 					terminated := map[string]struct{}{}
 					stale := map[string]struct{}{}
-					cutoff := time.Now().Add(-1 * c.ttl)
+					now := time.Now()
+					terminatedCutoff := now.Add(-1 * c.retention.TerminatedRequests)
+					staleCutoff := now.Add(-1 * c.retention.Stale)
+					cutoff := latest(terminatedCutoff, staleCutoff)
 					c.appFlows.All()(func(state appState) bool {
 						if !state.LastSeen.Before(cutoff) {
 							return false
 						}
-						if state.Terminated {
+						switch {
+						case state.Terminated && state.LastSeen.Before(terminatedCutoff):
 							terminated[state.ID] = struct{}{}
-						} else {
+						case !state.Terminated && state.LastSeen.Before(staleCutoff):
 							stale[state.ID] = struct{}{}
 						}
 						return true
 					})
 					if ct := len(terminated); ct > 0 {
 						c.logger.Debug("purging terminated app flows", slog.Int("count", ct))
+						c.metrics.internal.evictedRecords.WithLabelValues(RequestRecord{}.GetTypeMeta().Type, evictionReasonTTL).Add(float64(ct))
 						for id := range terminated {
 							c.flows.Delete(id)
 						}
 					}
 					if ct := len(stale); ct > 0 {
 						c.logger.Info("purging stale app flows", slog.Int("count", ct))
+						c.metrics.internal.evictedRecords.WithLabelValues(RequestRecord{}.GetTypeMeta().Type, evictionReasonStale).Add(float64(ct))
 						for id := range stale {
 							c.flows.Delete(id)
 						}
@@ -631,11 +752,18 @@ func (c *connectionManager) runAppReconcile() appReconcileResult {
 		case unreconciledTransport:
 			result.PendingTransportReconcileCount++
 		case success:
-			c.records.Add(request, c.source)
+			if !c.records.Add(request, c.source) {
+				// restored from disk: update to reference the flow store
+				c.records.Update(request)
+			}
 			push = true
 			metrics := request.metrics
 			state.metrics = &metrics
 			result.Reconciled = append(result.Reconciled, request)
+			// the flow may have ended before it could be reconciled
+			if flow, ok := request.GetFlow(); ok && isTerminated(flow.BaseRecord) && !state.SpanExported {
+				state.SpanExported = c.exportRequestSpan(flow)
+			}
 
 			c.pairMu.Lock()
 			p := pair{
@@ -749,10 +877,17 @@ func (c *connectionManager) runReconcile() reconcileResult {
 			result.PendingDestCount++
 		case success:
 			push = true
-			c.records.Add(connection, c.source)
+			if !c.records.Add(connection, c.source) {
+				// restored from disk: update to reference the flow store
+				c.records.Update(connection)
+			}
 			metrics := connection.metrics
 			state.metrics = &metrics
 			result.Reconciled = append(result.Reconciled, connection)
+			// the flow may have ended before it could be reconciled
+			if flow, ok := connection.GetFlow(); ok && isTerminated(flow.BaseRecord) && !state.SpanExported {
+				state.SpanExported = c.exportConnectionSpan(flow)
+			}
 
 			c.pairMu.Lock()
 			p := pair{
@@ -826,10 +961,22 @@ func (c *connectionManager) scheduleReconcile(ctx context.Context) {
 	}
 }
 
+// Stop discards the flows from the event source and closes the flow store
 func (c *connectionManager) Stop() {
 	for _, e := range c.flows.List() {
 		c.flows.Delete(e.Record.Identity())
 	}
+	c.Close()
+}
+
+// Close closes the flow store, leaving any flows it persisted to be restored
+// on restart
+func (c *connectionManager) Close() {
+	if closer, ok := c.flows.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			c.logger.Error("error closing flow store", slog.Any("error", err))
+		}
+	}
 }
 
 type transportMetrics struct {
@@ -846,10 +993,11 @@ type appMetrics struct {
 }
 
 type appState struct {
-	ID          string
-	TransportID string
-	Dirty       bool
-	Terminated  bool
+	ID           string
+	TransportID  string
+	Dirty        bool
+	Terminated   bool
+	SpanExported bool
 
 	metrics *appMetrics
 
@@ -867,6 +1015,7 @@ type transportState struct {
 
 	Opened        bool
 	Terminated    bool
+	SpanExported  bool
 	BytesSent     uint64
 	BytesReceived uint64
 	LatencySet    bool
@@ -1122,6 +1271,18 @@ func normalizeApplicationProtocol(result *string) (string, bool) {
 	}
 }
 
+func isTerminated(record vanflow.BaseRecord) bool {
+	return record.EndTime != nil && record.EndTime.Compare(dref(record.StartTime).Time) >= 0
+}
+
+// latest returns the later of two times
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
 func dref[T any](p *T) T {
 	var t T
 	if p != nil {
@@ -3,12 +3,18 @@ package collector
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"maps"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/otlp"
 	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/eventsource"
+	"github.com/skupperproject/skupper/pkg/vanflow/session"
 	"github.com/skupperproject/skupper/pkg/vanflow/store"
 	"gotest.tools/v3/assert"
 )
@@ -22,7 +28,10 @@ func TestConnectionManager(t *testing.T) {
 	// TODO(ck)  newConnectionmanager starts goroutines that can "steal" work
 	// from manually invoked manager methods (i.e. runReconcile). Write
 	// idempotent assertions.
-	manager := newConnectionmanager(tCtx, tlog, store.SourceRef{}, vanStor, graf, register(prometheus.NewRegistry()), time.Minute)
+	manager, err := newConnectionmanager(tCtx, tlog, store.SourceRef{}, vanStor, graf, register(prometheus.NewRegistry()), newFlowRetention(time.Minute, RetentionConfig{}), nil, nil, newMemoryStore)
+	if err != nil {
+		t.Fatal(err)
+	}
 	defer manager.Stop()
 	flowStor := manager.flows
 
@@ -87,13 +96,84 @@ func TestConnectionManager(t *testing.T) {
 	assert.Equal(t, requestRecord.Dest.Name, "server-east-06")
 }
 
+func TestConnectionManagerExportsFlowsEndedBeforeReconcile(t *testing.T) {
+	tCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tlog := slog.Default()
+	vanStor := store.NewSyncMapStore(store.SyncMapStoreConfig{Indexers: RecordIndexers()})
+	graf := NewGraph(vanStor).(*graph)
+	var (
+		mu    sync.Mutex
+		spans = map[string]int{}
+	)
+	exportSpan := func(span otlp.Span) {
+		mu.Lock()
+		defer mu.Unlock()
+		spans[span.Name]++
+	}
+	exported := func() map[string]int {
+		mu.Lock()
+		defer mu.Unlock()
+		return maps.Clone(spans)
+	}
+	manager, err := newConnectionmanager(tCtx, tlog, store.SourceRef{}, vanStor, graf, register(prometheus.NewRegistry()), newFlowRetention(time.Minute, RetentionConfig{}), nil, exportSpan, newMemoryStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Stop()
+	flowStor := manager.flows
+
+	vanStor.Replace(wrapRecords(van...))
+	graf.Reset()
+
+	// both flows end before the connector they reference is known, so
+	// neither can be reconciled when they end
+	start := time.Now()
+	end := start.Add(time.Second)
+	flowStor.Add(vanflow.TransportBiflowRecord{
+		BaseRecord: vanflow.NewBase("tflow-01", start, end),
+		Parent:     ptrTo("listener-backend"),
+		SourceHost: ptrTo("10.111.0.111"),
+	}, store.SourceRef{})
+	flowStor.Add(vanflow.AppBiflowRecord{
+		BaseRecord: vanflow.NewBase("appflow-01", start, end),
+		Parent:     ptrTo("tflow-01"),
+		Protocol:   ptrTo("HTTP/1.1"),
+		Method:     ptrTo("GET"),
+		Result:     ptrTo("200"),
+	}, store.SourceRef{})
+	manager.runReconcile()
+	manager.runAppReconcile()
+	assert.Equal(t, len(exported()), 0)
+
+	flowStor.Patch(vanflow.TransportBiflowRecord{
+		BaseRecord:  vanflow.NewBase("tflow-01", start),
+		ConnectorID: ptrTo("connector-backend-1-6"),
+	}, store.SourceRef{})
+	manager.runReconcile()
+	manager.runAppReconcile()
+	assert.DeepEqual(t, exported(), map[string]int{"backend": 1, "GET backend": 1})
+
+	// spans are only exported once, however often the flows are handled
+	if entry, ok := flowStor.Get("tflow-01"); ok {
+		manager.handleTransportFlow(entry.Record.(vanflow.TransportBiflowRecord))
+	}
+	if entry, ok := flowStor.Get("appflow-01"); ok {
+		manager.handleAppFlow(entry.Record.(vanflow.AppBiflowRecord))
+	}
+	assert.DeepEqual(t, exported(), map[string]int{"backend": 1, "GET backend": 1})
+}
+
 func benchmarkRunReconcile(b *testing.B, connections int) {
 	tCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	tlog := slog.Default()
 	vanStor := store.NewSyncMapStore(store.SyncMapStoreConfig{Indexers: RecordIndexers()})
 	graf := NewGraph(vanStor).(*graph)
-	manager := newConnectionmanager(tCtx, tlog, store.SourceRef{}, vanStor, graf, register(prometheus.NewRegistry()), time.Minute)
+	manager, err := newConnectionmanager(tCtx, tlog, store.SourceRef{}, vanStor, graf, register(prometheus.NewRegistry()), newFlowRetention(time.Minute, RetentionConfig{}), nil, nil, newMemoryStore)
+	if err != nil {
+		b.Fatal(err)
+	}
 	defer manager.Stop()
 	flowStor := manager.flows
 
@@ -132,7 +212,10 @@ func benchmarkRunAppReconcile(b *testing.B, connections int, requests int) {
 	tlog := slog.Default()
 	vanStor := store.NewSyncMapStore(store.SyncMapStoreConfig{Indexers: RecordIndexers()})
 	graf := NewGraph(vanStor).(*graph)
-	manager := newConnectionmanager(tCtx, tlog, store.SourceRef{}, vanStor, graf, register(prometheus.NewRegistry()), time.Minute)
+	manager, err := newConnectionmanager(tCtx, tlog, store.SourceRef{}, vanStor, graf, register(prometheus.NewRegistry()), newFlowRetention(time.Minute, RetentionConfig{}), nil, nil, newMemoryStore)
+	if err != nil {
+		b.Fatal(err)
+	}
 	defer manager.Stop()
 	flowStor := manager.flows
 
@@ -260,3 +343,41 @@ var van = []vanflow.Record{
 	ProcessGroupRecord{ID: "pg-01", Name: "clients"},
 	ProcessGroupRecord{ID: "pg-02", Name: "servers"},
 }
+
+func newMemoryStore(cfg store.SyncMapStoreConfig) (store.Interface, error) {
+	return store.NewSyncMapStore(cfg), nil
+}
+
+func TestCollectorClosesFlowStores(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(slog.Default(), session.NewMockContainerFactory(), prometheus.NewRegistry(), Config{StoreDir: dir})
+	assert.NilError(t, err)
+	source := store.SourceRef{Version: "1", ID: "router-1"}
+	manager, err := newConnectionmanager(context.Background(), slog.Default(), source, c.Records, c.graph, c.metrics, c.flowRetention, c.watchers, nil, func(cfg store.SyncMapStoreConfig) (store.Interface, error) {
+		return c.newStore(c.flowStoreName(source), cfg)
+	})
+	assert.NilError(t, err)
+	c.sources[source.ID] = eventSource{
+		source:  source,
+		client:  eventsource.NewClient(c.session, eventsource.ClientOptions{Source: eventsource.Info{ID: source.ID}}),
+		manager: manager,
+	}
+	manager.flows.Add(vanflow.TransportBiflowRecord{
+		BaseRecord: vanflow.NewBase("tflow-01", time.Now()),
+		Parent:     ptrTo("listener-backend"),
+	}, source)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, c.Run(ctx), context.Canceled)
+
+	// flows written to the closed store are restored, not discarded
+	restored, err := store.NewDiskStore(store.DiskStoreConfig{
+		Path:        c.flowStorePath(source),
+		RecordTypes: persistentRecordTypes,
+	})
+	assert.NilError(t, err)
+	defer restored.(io.Closer).Close()
+	_, ok := restored.Get("tflow-01")
+	assert.Assert(t, ok)
+}
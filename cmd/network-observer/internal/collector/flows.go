@@ -33,12 +33,15 @@ type ConnectionRecord struct {
 	// FlowStore is the backing store containing the Biflow records. This was
 	// split from the main record store to keep high volume flow producers from
 	// affecting the rest of the event sources.
-	FlowStore store.Interface
+	FlowStore store.Interface `json:"-"`
 	metrics   transportMetrics
 }
 
 func (cr *ConnectionRecord) GetFlow() (vanflow.TransportBiflowRecord, bool) {
 	var record vanflow.TransportBiflowRecord
+	if cr.FlowStore == nil {
+		return record, false
+	}
 	ent, ok := cr.FlowStore.Get(cr.ID)
 	if !ok {
 		return record, false
@@ -103,6 +106,9 @@ type RequestRecord struct {
 
 func (cr *RequestRecord) GetFlow() (vanflow.AppBiflowRecord, bool) {
 	var record vanflow.AppBiflowRecord
+	if cr.stor == nil {
+		return record, false
+	}
 	ent, ok := cr.stor.Get(cr.ID)
 	if !ok {
 		return record, false
@@ -112,6 +118,9 @@ func (cr *RequestRecord) GetFlow() (vanflow.AppBiflowRecord, bool) {
 }
 func (cr *RequestRecord) GetTransport() (vanflow.TransportBiflowRecord, bool) {
 	var record vanflow.TransportBiflowRecord
+	if cr.stor == nil {
+		return record, false
+	}
 	ent, ok := cr.stor.Get(cr.TransportID)
 	if !ok {
 		return record, false
@@ -23,6 +23,34 @@ var flowRecordTypes []vanflow.TypeMeta = []vanflow.TypeMeta{
 	vanflow.AppBiflowRecord{}.GetTypeMeta(),
 }
 
+// persistentRecordTypes are the record types the collector can persist to
+// disk when configured to do so.
+var persistentRecordTypes []vanflow.Record = []vanflow.Record{
+	vanflow.SiteRecord{},
+	vanflow.RouterRecord{},
+	vanflow.LinkRecord{},
+	vanflow.RouterAccessRecord{},
+	vanflow.ConnectorRecord{},
+	vanflow.ListenerRecord{},
+	vanflow.ProcessRecord{},
+	vanflow.LogRecord{},
+	vanflow.TransportBiflowRecord{},
+	vanflow.AppBiflowRecord{},
+	AddressRecord{},
+	ProcessGroupRecord{},
+	SitePairRecord{},
+	ProcGroupPairRecord{},
+	ProcPairRecord{},
+	FlowSourceRecord{},
+	ConnectionRecord{},
+	RequestRecord{},
+}
+
+// restoredSourceGracePeriod is how long the collector waits for event
+// sources that records were restored from to be discovered again before
+// purging their records.
+const restoredSourceGracePeriod = 2 * time.Minute
+
 const (
 	IndexByTypeParent      = "ByTypeAndParent"
 	IndexByAddress         = "ByAddress"
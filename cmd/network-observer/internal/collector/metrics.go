@@ -21,9 +21,11 @@ type metricsInternal struct {
 	reconcileTime      *prometheus.HistogramVec
 	queueUtilization   *prometheus.GaugeVec
 	pendingFlows       *prometheus.GaugeVec
+	evictedRecords     *prometheus.CounterVec
+	recordsMemory      prometheus.Gauge
 }
 
-func register(reg *prometheus.Registry) metrics {
+func register(reg prometheus.Registerer) metrics {
 	m := metrics{
 		flowOpenedCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: "skupper",
@@ -91,6 +93,18 @@ func register(reg *prometheus.Registry) metrics {
 				Subsystem: "internal",
 				Name:      "pending_flows",
 			}, []string{"type", "reason", "eventsource"}),
+			evictedRecords: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: "skupper",
+				Subsystem: "internal",
+				Name:      "evicted_records_total",
+				Help:      "Number of records evicted from the collector by record type and reason",
+			}, []string{"type", "reason"}),
+			recordsMemory: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: "skupper",
+				Subsystem: "internal",
+				Name:      "records_estimated_bytes",
+				Help:      "Estimated memory used by records in the collector. Only reported when a memory limit is configured",
+			}),
 		},
 	}
 
@@ -106,6 +120,8 @@ func register(reg *prometheus.Registry) metrics {
 		m.internal.queueUtilization,
 		m.internal.flowProcessingTime,
 		m.internal.pendingFlows,
+		m.internal.evictedRecords,
+		m.internal.recordsMemory,
 	)
 	return m
 }
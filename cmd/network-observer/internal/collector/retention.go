@@ -0,0 +1,323 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+)
+
+// RetentionPolicy configures how long the collector keeps records of a single
+// type once they have terminated.
+type RetentionPolicy struct {
+	// TerminatedTTL is how long records are kept after they terminate. Log
+	// records are considered terminated as soon as they are emitted. When
+	// unset, terminated connections and requests are kept for the
+	// FlowRecordTTL and other terminated records are kept indefinitely,
+	// subject to MaxRecords and the MemoryLimit.
+	TerminatedTTL time.Duration
+	// MaxRecords, when positive, limits the number of terminated records of
+	// the type that are kept. The oldest records are evicted first.
+	MaxRecords int
+}
+
+// RetentionConfig configures how long the collector keeps records
+type RetentionConfig struct {
+	// Policies by record type name, i.e. "ProcessRecord" or
+	// "ConnectionRecord". Terminated connections and requests without a
+	// policy are kept for the FlowRecordTTL and all other terminated records
+	// without a policy are removed promptly. LogRecords are only collected
+	// when they have a policy.
+	Policies map[string]RetentionPolicy
+	// MemoryLimit is the approximate number of bytes collected records may
+	// use. When exceeded, terminated records of any type are evicted oldest
+	// first. Zero disables the limit.
+	MemoryLimit int64
+}
+
+// Validate checks the configuration for errors
+func (c RetentionConfig) Validate() error {
+	var errs []error
+	if c.MemoryLimit < 0 {
+		errs = append(errs, fmt.Errorf("memoryLimit must not be negative"))
+	}
+	known := make(map[string]bool, len(retentionRecordTypes))
+	for _, record := range retentionRecordTypes {
+		known[record.GetTypeMeta().Type] = true
+	}
+	for typ, policy := range c.Policies {
+		if !known[typ] {
+			errs = append(errs, fmt.Errorf("policies[%s]: unknown record type", typ))
+		}
+		if policy.TerminatedTTL < 0 {
+			errs = append(errs, fmt.Errorf("policies[%s]: terminatedTTL must not be negative", typ))
+		}
+		if policy.MaxRecords < 0 {
+			errs = append(errs, fmt.Errorf("policies[%s]: maxRecords must not be negative", typ))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// retentionRecordTypes are the record types retention policies apply to
+var retentionRecordTypes = []vanflow.Record{
+	vanflow.SiteRecord{},
+	vanflow.RouterRecord{},
+	vanflow.LinkRecord{},
+	vanflow.RouterAccessRecord{},
+	vanflow.ConnectorRecord{},
+	vanflow.ListenerRecord{},
+	vanflow.ProcessRecord{},
+	vanflow.LogRecord{},
+	ConnectionRecord{},
+	RequestRecord{},
+}
+
+const (
+	evictionReasonTTL        = "ttl"
+	evictionReasonStale      = "stale"
+	evictionReasonMaxRecords = "max_records"
+	evictionReasonMemory     = "memory"
+)
+
+// flowRetention configures how long a connectionManager keeps flows
+type flowRetention struct {
+	// Stale is how long flows that have not terminated are kept after they
+	// were last updated
+	Stale time.Duration
+	// TerminatedConnections is how long transport flows are kept after they
+	// terminated
+	TerminatedConnections time.Duration
+	// TerminatedRequests is how long application flows are kept after they
+	// terminated
+	TerminatedRequests time.Duration
+}
+
+func newFlowRetention(ttl time.Duration, cfg RetentionConfig) flowRetention {
+	retention := flowRetention{
+		Stale:                 ttl,
+		TerminatedConnections: ttl,
+		TerminatedRequests:    ttl,
+	}
+	if policy, ok := cfg.Policies[ConnectionRecord{}.GetTypeMeta().Type]; ok && policy.TerminatedTTL > 0 {
+		retention.TerminatedConnections = policy.TerminatedTTL
+	}
+	if policy, ok := cfg.Policies[RequestRecord{}.GetTypeMeta().Type]; ok && policy.TerminatedTTL > 0 {
+		retention.TerminatedRequests = policy.TerminatedTTL
+	}
+	return retention
+}
+
+type terminatedEntry struct {
+	store.Entry
+	Terminated time.Time
+}
+
+// enforceRetention evicts terminated records according to the retention
+// policy for their type and then, when a memory limit is configured, evicts
+// the oldest terminated records until the collector is within the limit.
+// Returns the number of records evicted.
+func (c *Collector) enforceRetention(now time.Time) int {
+	var (
+		evicted    int
+		candidates []terminatedEntry
+	)
+	evict := func(entries []terminatedEntry, reason string) {
+		for _, e := range entries {
+			c.evict(e.Entry, reason)
+		}
+		evicted += len(entries)
+	}
+	for _, exemplar := range retentionRecordTypes {
+		policy, hasPolicy := c.retention.Policies[exemplar.GetTypeMeta().Type]
+		var terminated []terminatedEntry
+		for _, e := range c.Records.Index(store.TypeIndex, store.Entry{Record: exemplar}) {
+			if at, ok := terminatedAt(e); ok {
+				terminated = append(terminated, terminatedEntry{Entry: e, Terminated: at})
+			}
+		}
+		sort.Slice(terminated, func(i, j int) bool {
+			return terminated[i].Terminated.Before(terminated[j].Terminated)
+		})
+		switch exemplar.(type) {
+		case ConnectionRecord, RequestRecord:
+			// expired by the connection manager along with their flows
+		default:
+			if hasPolicy && policy.TerminatedTTL == 0 {
+				// no ttl, only limited by the number of records
+				break
+			}
+			cutoff := now.Add(-policy.TerminatedTTL)
+			expired := sort.Search(len(terminated), func(i int) bool {
+				return !terminated[i].Terminated.Before(cutoff)
+			})
+			evict(terminated[:expired], evictionReasonTTL)
+			terminated = terminated[expired:]
+		}
+		if excess := len(terminated) - policy.MaxRecords; policy.MaxRecords > 0 && excess > 0 {
+			evict(terminated[:excess], evictionReasonMaxRecords)
+			terminated = terminated[excess:]
+		}
+		candidates = append(candidates, terminated...)
+	}
+	if c.retention.MemoryLimit > 0 {
+		evicted += c.enforceMemoryLimit(candidates)
+	}
+	return evicted
+}
+
+// enforceMemoryLimit evicts the oldest of the terminated candidates until the
+// estimated size of all collected records is within the memory limit.
+func (c *Collector) enforceMemoryLimit(candidates []terminatedEntry) int {
+	var total int64
+	for _, e := range c.Records.List() {
+		total += estimateEntrySize(e)
+	}
+	defer func() {
+		c.metrics.internal.recordsMemory.Set(float64(total))
+	}()
+	if total <= c.retention.MemoryLimit {
+		return 0
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Terminated.Before(candidates[j].Terminated)
+	})
+	var evicted int
+	for _, e := range candidates {
+		if total <= c.retention.MemoryLimit {
+			break
+		}
+		total -= estimateEntrySize(e.Entry)
+		c.evict(e.Entry, evictionReasonMemory)
+		evicted++
+	}
+	if total > c.retention.MemoryLimit {
+		c.logger.Warn("memory limit exceeded by active records",
+			slog.Int64("limit", c.retention.MemoryLimit),
+			slog.Int64("estimate", total),
+		)
+	}
+	return evicted
+}
+
+// evict removes a record from the collector. Connections and requests are
+// removed from the flow store backing them as well.
+func (c *Collector) evict(e store.Entry, reason string) {
+	switch record := e.Record.(type) {
+	case ConnectionRecord:
+		if record.FlowStore != nil {
+			record.FlowStore.Delete(record.ID)
+		}
+	case RequestRecord:
+		if record.stor != nil {
+			record.stor.Delete(record.ID)
+		}
+	}
+	c.Records.Delete(e.Record.Identity())
+	c.metrics.internal.evictedRecords.WithLabelValues(e.Record.GetTypeMeta().Type, reason).Inc()
+}
+
+// terminatedAt returns the time a record terminated
+func terminatedAt(e store.Entry) (time.Time, bool) {
+	ended := func(b vanflow.BaseRecord) (time.Time, bool) {
+		if b.EndTime == nil || !b.EndTime.After(time.Unix(0, 0)) {
+			return time.Time{}, false
+		}
+		return b.EndTime.Time, true
+	}
+	switch record := e.Record.(type) {
+	case vanflow.SiteRecord:
+		return ended(record.BaseRecord)
+	case vanflow.RouterRecord:
+		return ended(record.BaseRecord)
+	case vanflow.LinkRecord:
+		return ended(record.BaseRecord)
+	case vanflow.RouterAccessRecord:
+		return ended(record.BaseRecord)
+	case vanflow.ConnectorRecord:
+		return ended(record.BaseRecord)
+	case vanflow.ListenerRecord:
+		return ended(record.BaseRecord)
+	case vanflow.ProcessRecord:
+		return ended(record.BaseRecord)
+	case vanflow.LogRecord:
+		if record.StartTime != nil {
+			return record.StartTime.Time, true
+		}
+		return e.LastUpdate, true
+	case ConnectionRecord:
+		return record.EndTime, !record.EndTime.IsZero()
+	case RequestRecord:
+		return record.EndTime, !record.EndTime.IsZero()
+	default:
+		return time.Time{}, false
+	}
+}
+
+// entryOverhead approximates the memory used to store and index an entry in
+// addition to the record itself
+const entryOverhead = 512
+
+// estimateEntrySize approximates the memory used by an entry. Connections
+// and requests include the flow record backing them.
+func estimateEntrySize(e store.Entry) int64 {
+	size := entryOverhead + estimateSize(reflect.ValueOf(e.Record))
+	switch record := e.Record.(type) {
+	case ConnectionRecord:
+		if flow, ok := record.GetFlow(); ok {
+			size += entryOverhead + estimateSize(reflect.ValueOf(flow))
+		}
+	case RequestRecord:
+		if flow, ok := record.GetFlow(); ok {
+			size += entryOverhead + estimateSize(reflect.ValueOf(flow))
+		}
+	}
+	return size
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// estimateSize approximates the number of bytes used by a value including
+// the strings, pointers and slices it references. Maps, interfaces and
+// functions are not followed.
+func estimateSize(v reflect.Value) int64 {
+	if !v.IsValid() {
+		return 0
+	}
+	return int64(v.Type().Size()) + estimateReferenced(v)
+}
+
+func estimateReferenced(v reflect.Value) int64 {
+	switch v.Kind() {
+	case reflect.String:
+		return int64(v.Len())
+	case reflect.Pointer:
+		if v.IsNil() {
+			return 0
+		}
+		return estimateSize(v.Elem())
+	case reflect.Slice:
+		size := int64(v.Cap()) * int64(v.Type().Elem().Size())
+		for i := 0; i < v.Len(); i++ {
+			size += estimateReferenced(v.Index(i))
+		}
+		return size
+	case reflect.Struct:
+		if v.Type() == timeType {
+			// location is shared
+			return 0
+		}
+		var size int64
+		for i := 0; i < v.NumField(); i++ {
+			size += estimateReferenced(v.Field(i))
+		}
+		return size
+	default:
+		return 0
+	}
+}
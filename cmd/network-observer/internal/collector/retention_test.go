@@ -0,0 +1,224 @@
+package collector
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+	"gotest.tools/v3/assert"
+)
+
+func TestRetention(t *testing.T) {
+	now := time.Now()
+	ago := func(d time.Duration) time.Time {
+		return now.Add(-d)
+	}
+	logs := func(ct int) []vanflow.Record {
+		var records []vanflow.Record
+		for i := 0; i < ct; i++ {
+			records = append(records, vanflow.LogRecord{
+				BaseRecord: vanflow.NewBase(fmt.Sprintf("log-%d", i), ago(time.Duration(ct-i)*time.Minute)),
+				LogText:    ptrTo("message"),
+			})
+		}
+		return records
+	}
+	testcases := []struct {
+		Name          string
+		Config        RetentionConfig
+		Records       []vanflow.Record
+		ExpectIDs     []string
+		ExpectEvicted map[string]float64
+	}{
+		{
+			Name: "default removes terminated",
+			Records: []vanflow.Record{
+				vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1", ago(time.Hour))},
+				vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-2", ago(time.Hour), ago(time.Second))},
+				ProcessGroupRecord{ID: "group-1", Name: "frontend", Start: ago(time.Hour)},
+			},
+			ExpectIDs:     []string{"group-1", "site-1"},
+			ExpectEvicted: map[string]float64{"SiteRecord/ttl": 1},
+		}, {
+			Name: "terminated ttl",
+			Config: RetentionConfig{Policies: map[string]RetentionPolicy{
+				"ProcessRecord": {TerminatedTTL: time.Hour},
+			}},
+			Records: []vanflow.Record{
+				vanflow.ProcessRecord{BaseRecord: vanflow.NewBase("process-1", ago(3*time.Hour))},
+				vanflow.ProcessRecord{BaseRecord: vanflow.NewBase("process-2", ago(3*time.Hour), ago(30*time.Minute))},
+				vanflow.ProcessRecord{BaseRecord: vanflow.NewBase("process-3", ago(3*time.Hour), ago(2*time.Hour))},
+				vanflow.RouterRecord{BaseRecord: vanflow.NewBase("router-1", ago(3*time.Hour), ago(30*time.Minute))},
+			},
+			ExpectIDs:     []string{"process-1", "process-2"},
+			ExpectEvicted: map[string]float64{"ProcessRecord/ttl": 1, "RouterRecord/ttl": 1},
+		}, {
+			Name: "max records",
+			Config: RetentionConfig{Policies: map[string]RetentionPolicy{
+				"LogRecord": {TerminatedTTL: time.Hour, MaxRecords: 2},
+			}},
+			Records:       logs(4),
+			ExpectIDs:     []string{"log-2", "log-3"},
+			ExpectEvicted: map[string]float64{"LogRecord/max_records": 2},
+		}, {
+			Name: "max records without ttl",
+			Config: RetentionConfig{Policies: map[string]RetentionPolicy{
+				"LogRecord":     {MaxRecords: 2},
+				"ProcessRecord": {MaxRecords: 5},
+			}},
+			Records: append(logs(3),
+				vanflow.ProcessRecord{BaseRecord: vanflow.NewBase("process-1", ago(3*time.Hour), ago(2*time.Hour))},
+			),
+			ExpectIDs:     []string{"log-1", "log-2", "process-1"},
+			ExpectEvicted: map[string]float64{"LogRecord/max_records": 1},
+		}, {
+			Name: "memory limit never evicts active",
+			Config: RetentionConfig{
+				MemoryLimit: 1,
+			},
+			Records: []vanflow.Record{
+				vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1", ago(time.Hour))},
+				vanflow.RouterRecord{BaseRecord: vanflow.NewBase("router-1", ago(time.Hour))},
+			},
+			ExpectIDs: []string{"router-1", "site-1"},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.NilError(t, tc.Config.Validate())
+			stor := store.NewSyncMapStore(store.SyncMapStoreConfig{Indexers: RecordIndexers()})
+			stor.Replace(wrapRecords(tc.Records...))
+			c := &Collector{
+				logger:    slog.Default(),
+				Records:   stor,
+				retention: tc.Config,
+				metrics:   register(prometheus.NewRegistry()),
+			}
+			var expected float64
+			for _, ct := range tc.ExpectEvicted {
+				expected += ct
+			}
+			assert.Equal(t, c.enforceRetention(now), int(expected))
+
+			var ids []string
+			for _, e := range stor.List() {
+				ids = append(ids, e.Record.Identity())
+			}
+			sort.Strings(ids)
+			assert.DeepEqual(t, ids, tc.ExpectIDs)
+			for key, ct := range tc.ExpectEvicted {
+				typ, reason, _ := strings.Cut(key, "/")
+				assert.Equal(t, testutil.ToFloat64(c.metrics.internal.evictedRecords.WithLabelValues(typ, reason)), ct, key)
+			}
+		})
+	}
+}
+
+func TestRetentionMemoryLimit(t *testing.T) {
+	now := time.Now()
+	var records []vanflow.Record
+	for i := 0; i < 4; i++ {
+		records = append(records, vanflow.LogRecord{
+			BaseRecord: vanflow.NewBase(fmt.Sprintf("log-%d", i), now.Add(-time.Duration(4-i)*time.Minute)),
+			LogText:    ptrTo("message"),
+		})
+	}
+	records = append(records,
+		vanflow.ProcessRecord{BaseRecord: vanflow.NewBase("process-1", now.Add(-3*time.Hour))},
+		vanflow.ProcessRecord{BaseRecord: vanflow.NewBase("process-2", now.Add(-3*time.Hour), now.Add(-30*time.Minute))},
+	)
+	entries := wrapRecords(records...)
+	// limit allows for the active process and the two most recent logs
+	var limit int64
+	for _, e := range entries {
+		switch e.Record.Identity() {
+		case "process-1", "log-2", "log-3":
+			limit += estimateEntrySize(e)
+		}
+	}
+	stor := store.NewSyncMapStore(store.SyncMapStoreConfig{Indexers: RecordIndexers()})
+	stor.Replace(entries)
+	c := &Collector{
+		logger:  slog.Default(),
+		Records: stor,
+		retention: RetentionConfig{
+			Policies: map[string]RetentionPolicy{
+				"ProcessRecord": {TerminatedTTL: time.Hour},
+				"LogRecord":     {TerminatedTTL: time.Hour},
+			},
+			MemoryLimit: limit,
+		},
+		metrics: register(prometheus.NewRegistry()),
+	}
+	assert.Equal(t, c.enforceRetention(now), 3)
+	var ids []string
+	for _, e := range stor.List() {
+		ids = append(ids, e.Record.Identity())
+	}
+	sort.Strings(ids)
+	assert.DeepEqual(t, ids, []string{"log-2", "log-3", "process-1"})
+	assert.Equal(t, testutil.ToFloat64(c.metrics.internal.evictedRecords.WithLabelValues("ProcessRecord", "memory")), 1.0)
+	assert.Equal(t, testutil.ToFloat64(c.metrics.internal.evictedRecords.WithLabelValues("LogRecord", "memory")), 2.0)
+	assert.Equal(t, testutil.ToFloat64(c.metrics.internal.recordsMemory), float64(limit))
+}
+
+func TestRetentionEvictsFlows(t *testing.T) {
+	now := time.Now()
+	stor := store.NewSyncMapStore(store.SyncMapStoreConfig{Indexers: RecordIndexers()})
+	flows := store.NewSyncMapStore(store.SyncMapStoreConfig{})
+	flows.Add(vanflow.TransportBiflowRecord{BaseRecord: vanflow.NewBase("flow-1", now.Add(-time.Hour), now.Add(-time.Minute))}, store.SourceRef{})
+	flows.Add(vanflow.TransportBiflowRecord{BaseRecord: vanflow.NewBase("flow-2", now.Add(-time.Hour))}, store.SourceRef{})
+	stor.Replace(wrapRecords(
+		ConnectionRecord{ID: "flow-1", StartTime: now.Add(-time.Hour), EndTime: now.Add(-time.Minute), FlowStore: flows},
+		ConnectionRecord{ID: "flow-2", StartTime: now.Add(-time.Hour), FlowStore: flows},
+	))
+	c := &Collector{
+		logger:    slog.Default(),
+		Records:   stor,
+		retention: RetentionConfig{MemoryLimit: 1},
+		metrics:   register(prometheus.NewRegistry()),
+	}
+	// terminated connections are left to the connection manager unless
+	// memory is constrained
+	assert.Equal(t, c.enforceRetention(now), 1)
+	_, ok := stor.Get("flow-1")
+	assert.Assert(t, !ok)
+	_, ok = flows.Get("flow-1")
+	assert.Assert(t, !ok, "expected flow record to be evicted with connection")
+	_, ok = stor.Get("flow-2")
+	assert.Assert(t, ok)
+	assert.Equal(t, testutil.ToFloat64(c.metrics.internal.evictedRecords.WithLabelValues("ConnectionRecord", "memory")), 1.0)
+}
+
+func TestFlowRetention(t *testing.T) {
+	retention := newFlowRetention(time.Hour, RetentionConfig{Policies: map[string]RetentionPolicy{
+		"ConnectionRecord": {MaxRecords: 100},
+		"RequestRecord":    {TerminatedTTL: time.Minute},
+	}})
+	assert.DeepEqual(t, retention, flowRetention{
+		Stale:                 time.Hour,
+		TerminatedConnections: time.Hour,
+		TerminatedRequests:    time.Minute,
+	})
+}
+
+func TestRetentionConfigValidate(t *testing.T) {
+	err := RetentionConfig{
+		Policies: map[string]RetentionPolicy{
+			"FlowRecord":    {},
+			"ProcessRecord": {TerminatedTTL: -time.Second, MaxRecords: -1},
+		},
+		MemoryLimit: -1,
+	}.Validate()
+	assert.ErrorContains(t, err, "memoryLimit must not be negative")
+	assert.ErrorContains(t, err, "policies[FlowRecord]: unknown record type")
+	assert.ErrorContains(t, err, "policies[ProcessRecord]: terminatedTTL must not be negative")
+	assert.ErrorContains(t, err, "policies[ProcessRecord]: maxRecords must not be negative")
+}
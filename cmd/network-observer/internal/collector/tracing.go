@@ -0,0 +1,123 @@
+package collector
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/otlp"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+)
+
+// flowSpanIDs derives stable trace and span identifiers from a flow ID so
+// that spans for application requests share the trace of the connection
+// carrying them.
+func flowSpanIDs(flowID string) (otlp.TraceID, otlp.SpanID) {
+	var (
+		traceID otlp.TraceID
+		spanID  otlp.SpanID
+	)
+	sum := sha256.Sum256([]byte(flowID))
+	copy(traceID[:], sum[:16])
+	copy(spanID[:], sum[16:24])
+	return traceID, spanID
+}
+
+// connectionSpan describes a terminated connection as a span.
+func connectionSpan(connection ConnectionRecord, flow vanflow.TransportBiflowRecord) otlp.Span {
+	traceID, spanID := flowSpanIDs(connection.ID)
+	span := otlp.Span{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Name:    connection.RoutingKey,
+		Kind:    otlp.SpanKindInternal,
+		Start:   dref(flow.StartTime).Time,
+		End:     dref(flow.EndTime).Time,
+		Attributes: append(
+			routingAttributes(connection.RoutingKey, connection.Protocol,
+				connection.SourceSite, connection.Source, connection.SourceGroup,
+				connection.DestSite, connection.Dest, connection.DestGroup),
+			otlp.String("skupper.connector.id", connection.Connector.ID),
+			otlp.String("skupper.listener.id", connection.Listener.ID),
+			otlp.String("server.address", connection.ConnectorHost),
+		),
+	}
+	if port, err := strconv.ParseInt(connection.ConnectorPort, 10, 64); err == nil {
+		span.Attributes = append(span.Attributes, otlp.Int("server.port", port))
+	}
+	if flow.SourceHost != nil {
+		span.Attributes = append(span.Attributes, otlp.String("client.address", *flow.SourceHost))
+	}
+	if port, err := strconv.ParseInt(dref(flow.SourcePort), 10, 64); err == nil {
+		span.Attributes = append(span.Attributes, otlp.Int("client.port", port))
+	}
+	span.Attributes = appendUintAttribute(span.Attributes, "skupper.octets", flow.Octets)
+	span.Attributes = appendUintAttribute(span.Attributes, "skupper.octets_reverse", flow.OctetsReverse)
+	span.Attributes = appendUintAttribute(span.Attributes, "skupper.latency_us", flow.Latency)
+	span.Attributes = appendUintAttribute(span.Attributes, "skupper.latency_reverse_us", flow.LatencyReverse)
+
+	switch {
+	case flow.ErrorListener != nil:
+		span.Status, span.Message = otlp.StatusCodeError, *flow.ErrorListener
+	case flow.ErrorConnector != nil:
+		span.Status, span.Message = otlp.StatusCodeError, *flow.ErrorConnector
+	}
+	return span
+}
+
+// requestSpan describes a terminated application request as a span that is
+// a child of the span for the connection carrying it.
+func requestSpan(request RequestRecord, flow vanflow.AppBiflowRecord) otlp.Span {
+	traceID, parentID := flowSpanIDs(request.TransportID)
+	_, spanID := flowSpanIDs(request.ID)
+	method := normalizeHTTPMethod(flow.Method)
+	span := otlp.Span{
+		TraceID:      traceID,
+		SpanID:       spanID,
+		ParentSpanID: parentID,
+		Name:         fmt.Sprintf("%s %s", method, request.RoutingKey),
+		Kind:         otlp.SpanKindInternal,
+		Start:        dref(flow.StartTime).Time,
+		End:          dref(flow.EndTime).Time,
+		Attributes: append(
+			routingAttributes(request.RoutingKey, request.Protocol,
+				request.SourceSite, request.Source, request.SourceGroup,
+				request.DestSite, request.Dest, request.DestGroup),
+			otlp.String("http.request.method", method),
+		),
+	}
+	if code, err := strconv.ParseInt(dref(flow.Result), 10, 64); err == nil {
+		span.Attributes = append(span.Attributes, otlp.Int("http.response.status_code", code))
+		if code >= 500 {
+			span.Status = otlp.StatusCodeError
+		}
+	}
+	span.Attributes = appendUintAttribute(span.Attributes, "skupper.octets", flow.Octets)
+	span.Attributes = appendUintAttribute(span.Attributes, "skupper.octets_reverse", flow.OctetsReverse)
+	span.Attributes = appendUintAttribute(span.Attributes, "skupper.latency_us", flow.Latency)
+	return span
+}
+
+func routingAttributes(routingKey, protocol string, sourceSite, source, sourceGroup, destSite, dest, destGroup NamedReference) []otlp.Attribute {
+	return []otlp.Attribute{
+		otlp.String("skupper.routing_key", routingKey),
+		otlp.String("network.protocol.name", protocol),
+		otlp.String("skupper.source.site.id", sourceSite.ID),
+		otlp.String("skupper.source.site.name", sourceSite.Name),
+		otlp.String("skupper.source.process.id", source.ID),
+		otlp.String("skupper.source.process.name", source.Name),
+		otlp.String("skupper.source.component.name", sourceGroup.Name),
+		otlp.String("skupper.dest.site.id", destSite.ID),
+		otlp.String("skupper.dest.site.name", destSite.Name),
+		otlp.String("skupper.dest.process.id", dest.ID),
+		otlp.String("skupper.dest.process.name", dest.Name),
+		otlp.String("skupper.dest.component.name", destGroup.Name),
+	}
+}
+
+func appendUintAttribute(attrs []otlp.Attribute, key string, value *uint64) []otlp.Attribute {
+	if value == nil {
+		return attrs
+	}
+	return append(attrs, otlp.Int(key, int64(*value)))
+}
@@ -0,0 +1,72 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/otlp"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"gotest.tools/v3/assert"
+)
+
+func TestFlowSpans(t *testing.T) {
+	start := time.Now().Truncate(time.Microsecond)
+	end := start.Add(time.Second)
+	transport := vanflow.TransportBiflowRecord{
+		BaseRecord:     vanflow.NewBase("transport-1", start, end),
+		SourceHost:     ptrTo("10.0.0.1"),
+		SourcePort:     ptrTo("53412"),
+		Octets:         ptrTo(uint64(512)),
+		ErrorConnector: ptrTo("connection refused"),
+	}
+	connection := ConnectionRecord{
+		ID:            "transport-1",
+		RoutingKey:    "backend:8080",
+		Protocol:      "tcp",
+		ConnectorHost: "10.0.1.1",
+		ConnectorPort: "8080",
+		SourceSite:    NamedReference{ID: "site-1", Name: "west"},
+		DestSite:      NamedReference{ID: "site-2", Name: "east"},
+	}
+	cspan := connectionSpan(connection, transport)
+	assert.Equal(t, cspan.Name, "backend:8080")
+	assert.Equal(t, cspan.Start, start)
+	assert.Equal(t, cspan.End, end)
+	assert.Equal(t, cspan.Status, otlp.StatusCodeError)
+	assert.Equal(t, cspan.Message, "connection refused")
+	assert.Assert(t, cspan.ParentSpanID.IsZero())
+	assert.Assert(t, hasAttribute(cspan, otlp.String("skupper.source.site.name", "west")))
+	assert.Assert(t, hasAttribute(cspan, otlp.Int("server.port", 8080)))
+	assert.Assert(t, hasAttribute(cspan, otlp.Int("client.port", 53412)))
+	assert.Assert(t, hasAttribute(cspan, otlp.Int("skupper.octets", 512)))
+
+	app := vanflow.AppBiflowRecord{
+		BaseRecord: vanflow.NewBase("app-1", start, end),
+		Parent:     ptrTo("transport-1"),
+		Method:     ptrTo("get"),
+		Result:     ptrTo("200"),
+	}
+	request := RequestRecord{
+		ID:          "app-1",
+		TransportID: "transport-1",
+		RoutingKey:  "backend:8080",
+		Protocol:    "http1",
+	}
+	rspan := requestSpan(request, app)
+	assert.Equal(t, rspan.Name, "GET backend:8080")
+	assert.Equal(t, rspan.TraceID, cspan.TraceID)
+	assert.Equal(t, rspan.ParentSpanID, cspan.SpanID)
+	assert.Assert(t, rspan.SpanID != cspan.SpanID)
+	assert.Equal(t, rspan.Status, otlp.StatusCodeUnset)
+	assert.Assert(t, hasAttribute(rspan, otlp.String("http.request.method", "GET")))
+	assert.Assert(t, hasAttribute(rspan, otlp.Int("http.response.status_code", 200)))
+}
+
+func hasAttribute(span otlp.Span, expected otlp.Attribute) bool {
+	for _, attr := range span.Attributes {
+		if attr == expected {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package collector
+
+// NewUnionGraph returns a Graph that looks up nodes in each of the graphs of
+// collectors for separate networks, returning the first node that is known.
+// When no graph knows a node the unknown node from the first graph is
+// returned.
+func NewUnionGraph(first Graph, rest ...Graph) Graph {
+	return unionGraph(append([]Graph{first}, rest...))
+}
+
+type unionGraph []Graph
+
+func (u unionGraph) Address(id string) Address {
+	return firstKnown(u, func(g Graph) Address { return g.Address(id) })
+}
+func (u unionGraph) Connector(id string) Connector {
+	return firstKnown(u, func(g Graph) Connector { return g.Connector(id) })
+}
+func (u unionGraph) SiteHost(id string) SiteHost {
+	return firstKnown(u, func(g Graph) SiteHost { return g.SiteHost(id) })
+}
+func (u unionGraph) Link(id string) Link {
+	return firstKnown(u, func(g Graph) Link { return g.Link(id) })
+}
+func (u unionGraph) Listener(id string) Listener {
+	return firstKnown(u, func(g Graph) Listener { return g.Listener(id) })
+}
+func (u unionGraph) Process(id string) Process {
+	return firstKnown(u, func(g Graph) Process { return g.Process(id) })
+}
+func (u unionGraph) RouterAccess(id string) RouterAccess {
+	return firstKnown(u, func(g Graph) RouterAccess { return g.RouterAccess(id) })
+}
+func (u unionGraph) Site(id string) Site {
+	return firstKnown(u, func(g Graph) Site { return g.Site(id) })
+}
+
+func firstKnown[T Node](graphs []Graph, lookup func(Graph) T) T {
+	first := lookup(graphs[0])
+	if first.IsKnown() {
+		return first
+	}
+	for _, g := range graphs[1:] {
+		if node := lookup(g); node.IsKnown() {
+			return node
+		}
+	}
+	return first
+}
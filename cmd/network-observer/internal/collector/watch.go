@@ -0,0 +1,94 @@
+package collector
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+)
+
+// RecordEventType describes the kind of change a RecordEvent represents
+type RecordEventType string
+
+const (
+	RecordAdded   RecordEventType = "add"
+	RecordUpdated RecordEventType = "update"
+	RecordDeleted RecordEventType = "delete"
+)
+
+// RecordEvent is a change to a record in the collector Records store.
+type RecordEvent struct {
+	Type  RecordEventType
+	Entry store.Entry
+}
+
+// Subscribe returns a channel that receives an event for each change made to
+// the collector's Records, and a function that cancels the subscription.
+// Updates to the flows backing ConnectionRecords and RequestRecords are
+// delivered as updates to those records. The channel is closed when the
+// subscription is cancelled, or when the subscriber falls more than buffer
+// events behind.
+func (c *Collector) Subscribe(buffer int) (<-chan RecordEvent, func()) {
+	return c.watchers.subscribe(buffer)
+}
+
+type recordEventBroadcaster struct {
+	mu          sync.Mutex
+	subscribers map[*recordSubscriber]struct{}
+	count       atomic.Int32
+}
+
+type recordSubscriber struct {
+	events chan RecordEvent
+}
+
+func newRecordEventBroadcaster() *recordEventBroadcaster {
+	return &recordEventBroadcaster{
+		subscribers: make(map[*recordSubscriber]struct{}),
+	}
+}
+
+func (b *recordEventBroadcaster) subscribe(buffer int) (<-chan RecordEvent, func()) {
+	sub := &recordSubscriber{events: make(chan RecordEvent, buffer)}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[sub] = struct{}{}
+	b.count.Add(1)
+	return sub.events, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(sub)
+	}
+}
+
+// remove a subscriber and close its channel. Must be called with mu held.
+func (b *recordEventBroadcaster) remove(sub *recordSubscriber) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	b.count.Add(-1)
+	close(sub.events)
+}
+
+// active returns true when there are any subscribers. Allows publishers to
+// skip work producing events nobody is listening for.
+func (b *recordEventBroadcaster) active() bool {
+	return b != nil && b.count.Load() > 0
+}
+
+func (b *recordEventBroadcaster) publish(event RecordEvent) {
+	if !b.active() {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			// subscriber is not keeping up
+			b.remove(sub)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package flowlog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"sigs.k8s.io/yaml"
+)
+
+// StrategyType identifies a SampleStrategy
+type StrategyType string
+
+const (
+	// StrategyUnlimited logs every matching record
+	StrategyUnlimited StrategyType = "unlimited"
+	// StrategyNever logs none of the matching records
+	StrategyNever StrategyType = "never"
+	// StrategyRateLimited logs matching records up to a rate
+	StrategyRateLimited StrategyType = "rateLimited"
+	// StrategyTransportFlowHash logs a percentage of transport flows and
+	// their application flows
+	StrategyTransportFlowHash StrategyType = "transportFlowHash"
+)
+
+// SinkType identifies where records are logged to
+type SinkType string
+
+const (
+	// SinkStdout writes records to standard output
+	SinkStdout SinkType = "stdout"
+	// SinkFile appends records to a file that is rotated by size
+	SinkFile SinkType = "file"
+	// SinkSyslog sends records to a syslog daemon
+	SinkSyslog SinkType = "syslog"
+)
+
+// Format of logged records
+type Format string
+
+const (
+	// FormatJSON writes each record as a line of json
+	FormatJSON Format = "json"
+	// FormatText writes each record as a line of key=value pairs
+	FormatText Format = "text"
+)
+
+// MatchAll is the record type matching records of all types in a RuleConfig
+const MatchAll = "*"
+
+// recordTypes are the vanflow record types rules can match by name
+var recordTypes = []vanflow.Record{
+	vanflow.SiteRecord{},
+	vanflow.RouterRecord{},
+	vanflow.LinkRecord{},
+	vanflow.ControllerRecord{},
+	vanflow.ListenerRecord{},
+	vanflow.ConnectorRecord{},
+	vanflow.FlowRecord{},
+	vanflow.ProcessRecord{},
+	vanflow.HostRecord{},
+	vanflow.LogRecord{},
+	vanflow.RouterAccessRecord{},
+	vanflow.TransportBiflowRecord{},
+	vanflow.AppBiflowRecord{},
+}
+
+// Config for flow loggers
+type Config struct {
+	// Loggers each log the records sampled by their rules to their own sink
+	Loggers []LoggerConfig `json:"loggers"`
+}
+
+// LoggerConfig defines a flow logger
+type LoggerConfig struct {
+	// Name of the logger used in operational logs
+	Name string `json:"name,omitempty"`
+	// Profile is the name of a built-in logging profile to sample records
+	// with. Mutually exclusive with Rules.
+	Profile string `json:"profile,omitempty"`
+	// Rules for sampling records
+	Rules []RuleConfig `json:"rules,omitempty"`
+	// Sink records are logged to
+	Sink SinkConfig `json:"sink"`
+}
+
+// RuleConfig defines a Rule
+type RuleConfig struct {
+	// Priority of the rule. Lowest matching a record type wins.
+	Priority int `json:"priority,omitempty"`
+	// Match is the list of record type names (i.e. SiteRecord) the rule
+	// applies to, or "*" for all record types.
+	Match []string `json:"match"`
+	// Strategy for sampling records
+	Strategy StrategyConfig `json:"strategy"`
+}
+
+// StrategyConfig defines a SampleStrategy
+type StrategyConfig struct {
+	Type StrategyType `json:"type"`
+	// Limit in records per second for rateLimited strategies
+	Limit float64 `json:"limit,omitempty"`
+	// Burst is the number of records logged in excess of the limit by
+	// rateLimited strategies. Defaults to the limit rounded up.
+	Burst int `json:"burst,omitempty"`
+	// Percent of transport flows sampled by transportFlowHash strategies.
+	// At least 0 and less than 1.
+	Percent float64 `json:"percent,omitempty"`
+	// Parent strategy applied to the flows sampled by a transportFlowHash
+	// strategy. Defaults to unlimited.
+	Parent *StrategyConfig `json:"parent,omitempty"`
+}
+
+// SinkConfig defines where a logger writes records to
+type SinkConfig struct {
+	Type SinkType `json:"type"`
+	// Format of logged records. Defaults to json.
+	Format Format `json:"format,omitempty"`
+
+	// Path to the file records are appended to by file sinks
+	Path string `json:"path,omitempty"`
+	// MaxSizeMB is the size in megabytes a file grows to before it is
+	// rotated. Defaults to 100.
+	MaxSizeMB int `json:"maxSizeMB,omitempty"`
+	// MaxBackups is the number of rotated files kept. When unset all rotated
+	// files are kept unless removed by MaxAge.
+	MaxBackups int `json:"maxBackups,omitempty"`
+	// MaxAge is how long rotated files are kept. When unset rotated files
+	// are kept unless removed by MaxBackups.
+	MaxAge Duration `json:"maxAge,omitempty"`
+
+	// Network and Address of the syslog daemon (i.e. udp and
+	// localhost:514). Defaults to the local syslog daemon.
+	Network string `json:"network,omitempty"`
+	Address string `json:"address,omitempty"`
+	// Tag of syslog messages. Defaults to skupper-network-observer.
+	Tag string `json:"tag,omitempty"`
+	// Facility of syslog messages (i.e. local0). Defaults to user.
+	Facility string `json:"facility,omitempty"`
+}
+
+// LoadConfig reads a flow logging configuration from a yaml or json file
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read flow logging config: %w", err)
+	}
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse flow logging config %q: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid flow logging config %q: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate checks the configuration for errors. Built-in profile names are
+// checked when the loggers are created.
+func (c Config) Validate() error {
+	var errs []error
+	for i, logger := range c.Loggers {
+		prefix := fmt.Sprintf("loggers[%d]", i)
+		switch {
+		case logger.Profile != "" && len(logger.Rules) > 0:
+			errs = append(errs, fmt.Errorf("%s: profile and rules are mutually exclusive", prefix))
+		case logger.Profile == "" && len(logger.Rules) == 0:
+			errs = append(errs, fmt.Errorf("%s: one of profile or rules is required", prefix))
+		}
+		for j, rule := range logger.Rules {
+			for _, err := range rule.validate() {
+				errs = append(errs, fmt.Errorf("%s.rules[%d]: %w", prefix, j, err))
+			}
+		}
+		for _, err := range logger.Sink.validate() {
+			errs = append(errs, fmt.Errorf("%s.sink: %w", prefix, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r RuleConfig) validate() []error {
+	var errs []error
+	if len(r.Match) == 0 {
+		errs = append(errs, fmt.Errorf("match is required"))
+	}
+	if _, err := recordTypeSet(r.Match); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.Strategy.validate(); err != nil {
+		errs = append(errs, fmt.Errorf("strategy: %w", err))
+	}
+	return errs
+}
+
+func (s StrategyConfig) validate() error {
+	switch s.Type {
+	case StrategyUnlimited, StrategyNever:
+	case StrategyRateLimited:
+		if s.Limit < 0 {
+			return fmt.Errorf("limit must not be negative")
+		}
+		if s.Burst < 0 {
+			return fmt.Errorf("burst must not be negative")
+		}
+		if s.Limit == 0 && s.Burst == 0 {
+			return fmt.Errorf("one of limit or burst is required")
+		}
+	case StrategyTransportFlowHash:
+		if s.Percent < 0 || s.Percent >= 1 {
+			return fmt.Errorf("percent must be at least 0 and less than 1")
+		}
+		if s.Parent != nil {
+			if err := s.Parent.validate(); err != nil {
+				return fmt.Errorf("parent: %w", err)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown strategy type %q", s.Type)
+	}
+	return nil
+}
+
+func (s SinkConfig) validate() []error {
+	var errs []error
+	switch s.Format {
+	case "", FormatJSON, FormatText:
+	default:
+		errs = append(errs, fmt.Errorf("unknown format %q", s.Format))
+	}
+	switch s.Type {
+	case SinkStdout:
+	case SinkFile:
+		if s.Path == "" {
+			errs = append(errs, fmt.Errorf("path is required"))
+		}
+		if s.MaxSizeMB < 0 {
+			errs = append(errs, fmt.Errorf("maxSizeMB must not be negative"))
+		}
+		if s.MaxBackups < 0 {
+			errs = append(errs, fmt.Errorf("maxBackups must not be negative"))
+		}
+		if s.MaxAge.Duration < 0 {
+			errs = append(errs, fmt.Errorf("maxAge must not be negative"))
+		}
+	case SinkSyslog:
+		if (s.Network == "") != (s.Address == "") {
+			errs = append(errs, fmt.Errorf("network and address must be set together"))
+		}
+		if _, err := syslogFacility(s.Facility); err != nil {
+			errs = append(errs, err)
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown sink type %q", s.Type))
+	}
+	return errs
+}
+
+// rules converts the rule configurations to Rules
+func rules(configs []RuleConfig) []Rule {
+	out := make([]Rule, 0, len(configs))
+	for _, cfg := range configs {
+		match, _ := recordTypeSet(cfg.Match)
+		out = append(out, Rule{
+			Priority: cfg.Priority,
+			Match:    match,
+			Strategy: cfg.Strategy.strategy(),
+		})
+	}
+	return out
+}
+
+func (s StrategyConfig) strategy() SampleStrategy {
+	switch s.Type {
+	case StrategyUnlimited:
+		return Unlimited()
+	case StrategyRateLimited:
+		burst := s.Burst
+		if burst == 0 {
+			burst = int(math.Ceil(s.Limit))
+		}
+		return RateLimited(s.Limit, burst)
+	case StrategyTransportFlowHash:
+		var parent SampleStrategy
+		if s.Parent != nil {
+			parent = s.Parent.strategy()
+		}
+		return TransportFlowHash(s.Percent, parent)
+	}
+	return doNotSample
+}
+
+// recordTypeSet returns the RecordTypeSet for a list of record type names
+func recordTypeSet(names []string) (RecordTypeSet, error) {
+	known := make(map[string]vanflow.Record, len(recordTypes))
+	for _, record := range recordTypes {
+		known[strings.ToLower(record.GetTypeMeta().Type)] = record
+	}
+	var records []vanflow.Record
+	for _, name := range names {
+		if name == MatchAll {
+			return NewRecordTypeSetAll(), nil
+		}
+		record, ok := known[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown record type %q", name)
+		}
+		records = append(records, record)
+	}
+	return NewRecordTypeSet(records...), nil
+}
+
+// Duration is a time.Duration encoded as a string such as "24h"
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"24h\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
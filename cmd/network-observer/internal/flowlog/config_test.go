@@ -0,0 +1,98 @@
+package flowlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"gotest.tools/v3/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flowlog.yaml")
+	assert.NilError(t, os.WriteFile(path, []byte(`
+loggers:
+- name: audit
+  rules:
+  - priority: 1
+    match: [TransportBiflowRecord, appbiflowrecord]
+    strategy:
+      type: transportFlowHash
+      percent: 0.5
+      parent:
+        type: rateLimited
+        limit: 10
+        burst: 100
+  - priority: 5
+    match: ["*"]
+    strategy:
+      type: never
+  sink:
+    type: file
+    path: /var/log/skupper/flows.log
+    maxSizeMB: 10
+    maxBackups: 3
+    maxAge: 24h
+- profile: minimal
+  sink:
+    type: syslog
+    format: text
+    facility: local0
+`), 0644))
+	cfg, err := LoadConfig(path)
+	assert.NilError(t, err)
+	assert.Equal(t, len(cfg.Loggers), 2)
+	assert.Equal(t, cfg.Loggers[0].Sink.MaxAge.Duration, 24*time.Hour)
+	assert.DeepEqual(t, *cfg.Loggers[0].Rules[0].Strategy.Parent, StrategyConfig{Type: StrategyRateLimited, Limit: 10, Burst: 100})
+
+	out := rules(cfg.Loggers[0].Rules)
+	assert.Equal(t, len(out), 2)
+	assert.DeepEqual(t, out[0].Match, NewRecordTypeSet(vanflow.TransportBiflowRecord{}, vanflow.AppBiflowRecord{}))
+	assert.Assert(t, out[1].Match.matchesAll())
+	assert.Equal(t, out[1].Strategy, doNotSample)
+
+	assert.NilError(t, os.WriteFile(path, []byte("loggers:\n- profile: all\n  sink:\n    type: stdout\n  unknown: true\n"), 0644))
+	_, err = LoadConfig(path)
+	assert.ErrorContains(t, err, "failed to parse flow logging config")
+}
+
+func TestConfigValidate(t *testing.T) {
+	err := Config{Loggers: []LoggerConfig{
+		{Sink: SinkConfig{Type: SinkStdout, Format: "xml"}},
+		{
+			Profile: "all",
+			Rules:   []RuleConfig{{Match: []string{"SiteRecord"}, Strategy: StrategyConfig{Type: StrategyUnlimited}}},
+			Sink:    SinkConfig{Type: SinkFile, MaxSizeMB: -1, MaxBackups: -1, MaxAge: Duration{-time.Second}},
+		},
+		{
+			Rules: []RuleConfig{
+				{Strategy: StrategyConfig{Type: StrategyRateLimited}},
+				{Match: []string{"Site"}, Strategy: StrategyConfig{Type: StrategyTransportFlowHash, Percent: 1}},
+				{Match: []string{"LogRecord"}, Strategy: StrategyConfig{Type: "sometimes"}},
+			},
+			Sink: SinkConfig{Type: SinkSyslog, Network: "udp", Facility: "local9"},
+		},
+		{Profile: "all", Sink: SinkConfig{Type: "kafka"}},
+	}}.Validate()
+	for _, expected := range []string{
+		"loggers[0]: one of profile or rules is required",
+		`loggers[0].sink: unknown format "xml"`,
+		"loggers[1]: profile and rules are mutually exclusive",
+		"loggers[1].sink: path is required",
+		"loggers[1].sink: maxSizeMB must not be negative",
+		"loggers[1].sink: maxBackups must not be negative",
+		"loggers[1].sink: maxAge must not be negative",
+		"loggers[2].rules[0]: match is required",
+		"loggers[2].rules[0]: strategy: one of limit or burst is required",
+		`loggers[2].rules[1]: unknown record type "Site"`,
+		"loggers[2].rules[1]: strategy: percent must be at least 0 and less than 1",
+		`loggers[2].rules[2]: strategy: unknown strategy type "sometimes"`,
+		"loggers[2].sink: network and address must be set together",
+		`loggers[2].sink: unknown syslog facility "local9"`,
+		`loggers[3].sink: unknown sink type "kafka"`,
+	} {
+		assert.ErrorContains(t, err, expected)
+	}
+}
@@ -0,0 +1,130 @@
+package flowlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+)
+
+const (
+	defaultMaxSizeMB = 100
+	defaultSyslogTag = "skupper-network-observer"
+)
+
+// Sink is a destination for flow logs
+type Sink struct {
+	// Logger writing to the sink in its configured format
+	Logger *slog.Logger
+	closer io.Closer
+}
+
+// NewSink opens the destination configured by cfg
+func NewSink(cfg SinkConfig) (*Sink, error) {
+	if err := errors.Join(cfg.validate()...); err != nil {
+		return nil, err
+	}
+	var (
+		out    io.Writer
+		closer io.Closer
+	)
+	switch cfg.Type {
+	case SinkStdout:
+		out = os.Stdout
+	case SinkFile:
+		maxSizeMB := cfg.MaxSizeMB
+		if maxSizeMB == 0 {
+			maxSizeMB = defaultMaxSizeMB
+		}
+		file, err := newRotatingFile(cfg.Path, int64(maxSizeMB)<<20, cfg.MaxBackups, cfg.MaxAge.Duration)
+		if err != nil {
+			return nil, err
+		}
+		out, closer = file, file
+	case SinkSyslog:
+		tag := cfg.Tag
+		if tag == "" {
+			tag = defaultSyslogTag
+		}
+		w, err := newSyslogWriter(cfg.Network, cfg.Address, cfg.Facility, tag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+		}
+		out, closer = w, w
+	}
+	var handler slog.Handler
+	switch cfg.Format {
+	case FormatText:
+		handler = slog.NewTextHandler(out, nil)
+	default:
+		handler = slog.NewJSONHandler(out, nil)
+	}
+	return &Sink{Logger: slog.New(handler), closer: closer}, nil
+}
+
+// Close the sink
+func (s *Sink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	return s.closer.Close()
+}
+
+// Loggers logs records to the sinks of a set of configured flow loggers
+type Loggers struct {
+	handlers []MessageHandler
+	sinks    []*Sink
+}
+
+// NewLoggers creates the loggers in cfg. Loggers configured with a profile
+// sample records with the rules returned by the profile of the same name in
+// profiles.
+func NewLoggers(ctx context.Context, cfg Config, profiles map[string]func() []Rule) (*Loggers, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	loggers := &Loggers{}
+	for i, loggerCfg := range cfg.Loggers {
+		name := loggerCfg.Name
+		if name == "" {
+			name = fmt.Sprintf("loggers[%d]", i)
+		}
+		loggerRules := rules(loggerCfg.Rules)
+		if loggerCfg.Profile != "" {
+			profile, ok := profiles[loggerCfg.Profile]
+			if !ok {
+				loggers.Close()
+				return nil, fmt.Errorf("%s: unknown logging profile %q", name, loggerCfg.Profile)
+			}
+			loggerRules = profile()
+		}
+		sink, err := NewSink(loggerCfg.Sink)
+		if err != nil {
+			loggers.Close()
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		loggers.sinks = append(loggers.sinks, sink)
+		loggers.handlers = append(loggers.handlers, New(ctx, sink.Logger.Info, loggerRules))
+	}
+	return loggers, nil
+}
+
+// Handle logs the records in a message with each logger
+func (l *Loggers) Handle(msg vanflow.RecordMessage) {
+	for _, handler := range l.handlers {
+		handler(msg)
+	}
+}
+
+// Close the sinks of all loggers
+func (l *Loggers) Close() error {
+	var errs []error
+	for _, sink := range l.sinks {
+		errs = append(errs, sink.Close())
+	}
+	return errors.Join(errs...)
+}
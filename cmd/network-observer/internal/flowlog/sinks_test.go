@@ -0,0 +1,149 @@
+package flowlog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"gotest.tools/v3/assert"
+)
+
+func TestLoggers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dir := t.TempDir()
+	auditPath := filepath.Join(dir, "audit", "flows.log")
+	textPath := filepath.Join(dir, "sites.log")
+	loggers, err := NewLoggers(ctx, Config{Loggers: []LoggerConfig{
+		{
+			Name: "audit",
+			Rules: []RuleConfig{{
+				Match:    []string{"TransportBiflowRecord"},
+				Strategy: StrategyConfig{Type: StrategyUnlimited},
+			}},
+			Sink: SinkConfig{Type: SinkFile, Path: auditPath},
+		}, {
+			Profile: "sites",
+			Sink:    SinkConfig{Type: SinkFile, Format: FormatText, Path: textPath},
+		},
+	}}, map[string]func() []Rule{
+		"sites": func() []Rule {
+			return []Rule{{Match: NewRecordTypeSet(vanflow.SiteRecord{}), Strategy: Unlimited()}}
+		},
+	})
+	assert.NilError(t, err)
+	for i := 0; i < 3; i++ {
+		loggers.Handle(vanflow.RecordMessage{
+			MessageProps: vanflow.MessageProps{To: "mc/sfe.router-1.flows", Subject: "RECORD"},
+			Records: []vanflow.Record{
+				vanflow.TransportBiflowRecord{BaseRecord: vanflow.NewBase("flow-1"), SourceHost: ptrTo("10.0.0.1")},
+				vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1")},
+			},
+		})
+	}
+	assert.NilError(t, loggers.Close())
+
+	lines := readLines(t, auditPath)
+	assert.Equal(t, len(lines), 3)
+	var entry struct {
+		Msg     string
+		Record  map[string]any
+		Message map[string]any
+	}
+	assert.NilError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, entry.Msg, "flow/v1/TransportBiflowRecord")
+	assert.Equal(t, entry.Record["ID"], "flow-1")
+	assert.Equal(t, entry.Record["SourceHost"], "10.0.0.1")
+	assert.Equal(t, entry.Message["to"], "mc/sfe.router-1.flows")
+
+	lines = readLines(t, textPath)
+	assert.Equal(t, len(lines), 3)
+	assert.Assert(t, strings.Contains(lines[0], "msg=flow/v1/SiteRecord record.ID=site-1"), lines[0])
+
+	_, err = NewLoggers(ctx, Config{Loggers: []LoggerConfig{
+		{Profile: "unknown", Sink: SinkConfig{Type: SinkStdout}},
+	}}, nil)
+	assert.ErrorContains(t, err, `loggers[0]: unknown logging profile "unknown"`)
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "flows.log")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	f, err := newRotatingFile(path, 10, 2, 0)
+	assert.NilError(t, err)
+	f.now = func() time.Time { return now }
+
+	for i, line := range []string{"aaaaaa\n", "bbbbbb\n", "cccccc\n", "dddddd\n", "eeeeee\n"} {
+		now = start.Add(time.Duration(i) * time.Minute)
+		n, err := f.Write([]byte(line))
+		assert.NilError(t, err)
+		assert.Equal(t, n, len(line))
+	}
+	assert.NilError(t, f.Close())
+	_, err = f.Write([]byte("closed"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+
+	current, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, string(current), "eeeeee\n")
+	// only the two most recent backups are kept
+	assert.DeepEqual(t, listDir(t, dir), []string{
+		"flows.log",
+		"flows.log.2024-01-01T00-03-00.000",
+		"flows.log.2024-01-01T00-04-00.000",
+	})
+	backup, err := os.ReadFile(filepath.Join(dir, "flows.log.2024-01-01T00-04-00.000"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(backup), "dddddd\n")
+
+	// reopening appends to the existing file and backups older than maxAge
+	// are removed on rotation
+	f, err = newRotatingFile(path, 10, 0, 90*time.Second)
+	assert.NilError(t, err)
+	f.now = func() time.Time { return start.Add(5 * time.Minute) }
+	_, err = f.Write([]byte("ffffff\n"))
+	assert.NilError(t, err)
+	assert.NilError(t, f.Close())
+	assert.DeepEqual(t, listDir(t, dir), []string{
+		"flows.log",
+		"flows.log.2024-01-01T00-04-00.000",
+		"flows.log.2024-01-01T00-05-00.000",
+	})
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	file, err := os.Open(path)
+	assert.NilError(t, err)
+	defer file.Close()
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	assert.NilError(t, scanner.Err())
+	return lines
+}
+
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	assert.NilError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func ptrTo[T any](obj T) *T { return &obj }
@@ -0,0 +1,16 @@
+//go:build windows || plan9
+
+package flowlog
+
+import (
+	"fmt"
+	"io"
+)
+
+func syslogFacility(string) (int, error) {
+	return 0, fmt.Errorf("syslog sinks are not supported on this platform")
+}
+
+func newSyslogWriter(network, address, facility, tag string) (io.WriteCloser, error) {
+	return nil, fmt.Errorf("syslog sinks are not supported on this platform")
+}
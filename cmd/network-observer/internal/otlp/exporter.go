@@ -0,0 +1,222 @@
+// Package otlp implements a minimal OpenTelemetry protocol exporter for
+// spans and metrics using the OTLP/HTTP transport with json encoding.
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	tracesPath  = "v1/traces"
+	metricsPath = "v1/metrics"
+
+	defaultBatchInterval   = 5 * time.Second
+	defaultMetricsInterval = 30 * time.Second
+	defaultExportTimeout   = 10 * time.Second
+	maxBatchSize           = 512
+	spanQueueSize          = 4096
+)
+
+type Config struct {
+	// Endpoint is the base URL of the OTLP/HTTP receiver, i.e.
+	// http://otel-collector:4318
+	Endpoint string
+	// Client is used to make export requests. Defaults to a client with a
+	// request timeout.
+	Client *http.Client
+	// Resource attributes describing the exporting service
+	Resource []Attribute
+	// Scope is the name of the instrumentation scope spans and metrics are
+	// reported under
+	Scope string
+	// BatchInterval is the longest spans will be held before export.
+	BatchInterval time.Duration
+
+	// Gatherer is the source of metrics to export. Metrics are not
+	// exported when nil.
+	Gatherer prometheus.Gatherer
+	// MetricsInterval is how often metrics are exported.
+	MetricsInterval time.Duration
+}
+
+// Exporter batches spans and periodically gathers metrics to send to an
+// OTLP/HTTP receiver.
+type Exporter struct {
+	logger   *slog.Logger
+	endpoint *url.URL
+	client   *http.Client
+	resource resource
+	scope    instrumentationScope
+
+	batchInterval   time.Duration
+	gatherer        prometheus.Gatherer
+	metricsInterval time.Duration
+	start           time.Time
+
+	spans   chan Span
+	dropped atomic.Uint64
+}
+
+func New(logger *slog.Logger, cfg Config) (*Exporter, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid otlp endpoint: %w", err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid otlp endpoint %q: scheme must be http or https", cfg.Endpoint)
+	}
+	e := &Exporter{
+		logger:          logger,
+		endpoint:        endpoint,
+		client:          cfg.Client,
+		resource:        resource{Attributes: cfg.Resource},
+		scope:           instrumentationScope{Name: cfg.Scope},
+		batchInterval:   cfg.BatchInterval,
+		gatherer:        cfg.Gatherer,
+		metricsInterval: cfg.MetricsInterval,
+		start:           time.Now(),
+		spans:           make(chan Span, spanQueueSize),
+	}
+	if e.client == nil {
+		e.client = &http.Client{Timeout: defaultExportTimeout}
+	}
+	if e.batchInterval <= 0 {
+		e.batchInterval = defaultBatchInterval
+	}
+	if e.metricsInterval <= 0 {
+		e.metricsInterval = defaultMetricsInterval
+	}
+	return e, nil
+}
+
+// ExportSpan queues a span for export. Does not block: spans are dropped
+// when the export queue is full.
+func (e *Exporter) ExportSpan(span Span) {
+	select {
+	case e.spans <- span:
+	default:
+		e.dropped.Add(1)
+	}
+}
+
+// Run exports queued spans and metrics until the context is cancelled.
+func (e *Exporter) Run(ctx context.Context) error {
+	flush := time.NewTicker(e.batchInterval)
+	defer flush.Stop()
+	var metricsC <-chan time.Time
+	if e.gatherer != nil {
+		metricsTicker := time.NewTicker(e.metricsInterval)
+		defer metricsTicker.Stop()
+		metricsC = metricsTicker.C
+	}
+
+	batch := make([]Span, 0, maxBatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			// make a best effort to export any remaining spans
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+		drain:
+			for {
+				select {
+				case span := <-e.spans:
+					batch = append(batch, span)
+				default:
+					break drain
+				}
+			}
+			e.exportSpans(shutdownCtx, batch)
+			return nil
+		case span := <-e.spans:
+			batch = append(batch, span)
+			if len(batch) >= maxBatchSize {
+				e.exportSpans(ctx, batch)
+				batch = batch[:0]
+			}
+		case <-flush.C:
+			if dropped := e.dropped.Swap(0); dropped > 0 {
+				e.logger.Warn("dropped spans while export queue was full", slog.Uint64("count", dropped))
+			}
+			e.exportSpans(ctx, batch)
+			batch = batch[:0]
+		case <-metricsC:
+			e.exportMetrics(ctx)
+		}
+	}
+}
+
+func (e *Exporter) exportSpans(ctx context.Context, batch []Span) {
+	if len(batch) == 0 {
+		return
+	}
+	spans := make([]span, 0, len(batch))
+	for _, s := range batch {
+		spans = append(spans, encodeSpan(s))
+	}
+	req := exportTraceServiceRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource:   e.resource,
+			ScopeSpans: []scopeSpans{{Scope: e.scope, Spans: spans}},
+		}},
+	}
+	if err := e.post(ctx, tracesPath, req); err != nil {
+		e.logger.Error("failed to export spans", slog.Int("count", len(batch)), slog.Any("error", err))
+	}
+}
+
+func (e *Exporter) exportMetrics(ctx context.Context) {
+	families, err := e.gatherer.Gather()
+	if err != nil {
+		e.logger.Error("error gathering metrics for export", slog.Any("error", err))
+		if len(families) == 0 {
+			return
+		}
+	}
+	metrics := convertMetrics(families, e.start, time.Now())
+	if len(metrics) == 0 {
+		return
+	}
+	req := exportMetricsServiceRequest{
+		ResourceMetrics: []resourceMetrics{{
+			Resource:     e.resource,
+			ScopeMetrics: []scopeMetrics{{Scope: e.scope, Metrics: metrics}},
+		}},
+	}
+	if err := e.post(ctx, metricsPath, req); err != nil {
+		e.logger.Error("failed to export metrics", slog.Any("error", err))
+	}
+}
+
+func (e *Exporter) post(ctx context.Context, path string, msg any) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("json encoding error: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint.JoinPath(path).String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response from otlp receiver: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/poll"
+)
+
+// receiverStub is a minimal OTLP/HTTP receiver that decodes json encoded
+// export requests.
+type receiverStub struct {
+	traces  chan exportTraceServiceRequest
+	metrics chan exportMetricsServiceRequest
+}
+
+func newReceiverStub() *receiverStub {
+	return &receiverStub{
+		traces:  make(chan exportTraceServiceRequest, 8),
+		metrics: make(chan exportMetricsServiceRequest, 8),
+	}
+}
+
+func (s *receiverStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	dec := json.NewDecoder(r.Body)
+	switch r.URL.Path {
+	case "/otlp/v1/traces":
+		var req exportTraceServiceRequest
+		if err := dec.Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.traces <- req
+	case "/otlp/v1/metrics":
+		var req exportMetricsServiceRequest
+		if err := dec.Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.metrics <- req
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte("{}"))
+}
+
+func (v *Value) UnmarshalJSON(data []byte) error {
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return err
+	}
+	for _, value := range out {
+		v.v = value
+	}
+	return nil
+}
+
+// cmpValue compares attribute values by their decoded representation
+var cmpValue = cmp.Comparer(func(a, b Value) bool {
+	return fmt.Sprint(a.v) == fmt.Sprint(b.v)
+})
+
+func TestExporter(t *testing.T) {
+	receiver := newReceiverStub()
+	srv := httptest.NewServer(receiver)
+	defer srv.Close()
+
+	reg := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "test_requests_total",
+		Help: "test counter",
+	}, []string{"code"})
+	hist := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "test_latency_seconds",
+		Buckets: []float64{0.1, 1},
+	})
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_not_a_number"})
+	reg.MustRegister(counter, hist, gauge)
+	counter.WithLabelValues("2xx").Add(3)
+	hist.Observe(0.05)
+	hist.Observe(0.5)
+	hist.Observe(0.6)
+	hist.Observe(5)
+	gauge.Set(math.NaN())
+
+	exporter, err := New(slog.Default(), Config{
+		Endpoint:        srv.URL + "/otlp",
+		Resource:        []Attribute{String("service.name", "test")},
+		Scope:           "test",
+		BatchInterval:   10 * time.Millisecond,
+		Gatherer:        reg,
+		MetricsInterval: 10 * time.Millisecond,
+	})
+	assert.NilError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go exporter.Run(ctx)
+
+	start := time.Unix(1700000000, 0)
+	exporter.ExportSpan(Span{
+		TraceID:      TraceID{0: 1, 15: 2},
+		SpanID:       SpanID{0: 3},
+		ParentSpanID: SpanID{7: 4},
+		Name:         "GET backend",
+		Kind:         SpanKindInternal,
+		Start:        start,
+		End:          start.Add(time.Second),
+		Attributes: []Attribute{
+			String("http.request.method", "GET"),
+			Int("http.response.status_code", 503),
+		},
+		Status: StatusCodeError,
+	})
+
+	var traces exportTraceServiceRequest
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		select {
+		case traces = <-receiver.traces:
+			return poll.Success()
+		default:
+			return poll.Continue("waiting for spans")
+		}
+	}, poll.WithTimeout(5*time.Second), poll.WithDelay(5*time.Millisecond))
+
+	assert.Equal(t, len(traces.ResourceSpans), 1)
+	assert.Equal(t, traces.ResourceSpans[0].Resource.Attributes[0].Key, "service.name")
+	assert.Equal(t, traces.ResourceSpans[0].ScopeSpans[0].Scope.Name, "test")
+	spans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+	assert.Equal(t, len(spans), 1)
+	assert.DeepEqual(t, spans[0], span{
+		TraceID:           "01000000000000000000000000000002",
+		SpanID:            "0300000000000000",
+		ParentSpanID:      "0000000000000004",
+		Name:              "GET backend",
+		Kind:              SpanKindInternal,
+		StartTimeUnixNano: "1700000000000000000",
+		EndTimeUnixNano:   "1700000001000000000",
+		Attributes: []Attribute{
+			{Key: "http.request.method", Value: Value{v: "GET"}},
+			{Key: "http.response.status_code", Value: Value{v: "503"}},
+		},
+		Status: &status{Code: StatusCodeError},
+	}, cmpValue)
+
+	var metrics exportMetricsServiceRequest
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		select {
+		case metrics = <-receiver.metrics:
+			return poll.Success()
+		default:
+			return poll.Continue("waiting for metrics")
+		}
+	}, poll.WithTimeout(5*time.Second), poll.WithDelay(5*time.Millisecond))
+
+	byName := make(map[string]metric)
+	for _, m := range metrics.ResourceMetrics[0].ScopeMetrics[0].Metrics {
+		byName[m.Name] = m
+	}
+	_, ok := byName["test_not_a_number"]
+	assert.Assert(t, !ok, "expected NaN gauge to be skipped")
+
+	requests := byName["test_requests_total"]
+	assert.Assert(t, requests.Sum != nil)
+	assert.Equal(t, requests.Description, "test counter")
+	assert.Equal(t, requests.Sum.IsMonotonic, true)
+	assert.Equal(t, requests.Sum.AggregationTemporality, aggregationTemporalityCumulative)
+	assert.Equal(t, len(requests.Sum.DataPoints), 1)
+	assert.Equal(t, requests.Sum.DataPoints[0].AsDouble, 3.0)
+	assert.DeepEqual(t, requests.Sum.DataPoints[0].Attributes, []Attribute{{Key: "code", Value: Value{v: "2xx"}}}, cmpValue)
+
+	latency := byName["test_latency_seconds"]
+	assert.Assert(t, latency.Histogram != nil)
+	assert.Equal(t, len(latency.Histogram.DataPoints), 1)
+	dp := latency.Histogram.DataPoints[0]
+	assert.Equal(t, dp.Count, "4")
+	assert.DeepEqual(t, dp.ExplicitBounds, []float64{0.1, 1})
+	assert.DeepEqual(t, dp.BucketCounts, []string{"1", "2", "1"})
+}
+
+func TestNewInvalidEndpoint(t *testing.T) {
+	_, err := New(slog.Default(), Config{Endpoint: "grpc://localhost:4317"})
+	assert.ErrorContains(t, err, "scheme must be http or https")
+}
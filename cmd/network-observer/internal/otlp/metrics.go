@@ -0,0 +1,160 @@
+package otlp
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// convertMetrics translates gathered prometheus metric families to otlp
+// metrics. Counters become cumulative monotonic sums, gauges and untyped
+// metrics become gauges, and histograms and summaries are mapped to their
+// otlp equivalents. Samples with values that cannot be represented in json
+// (NaN and infinities) are skipped.
+func convertMetrics(families []*dto.MetricFamily, start, now time.Time) []metric {
+	var out []metric
+	ts := unixNano(now)
+	for _, family := range families {
+		m := metric{
+			Name:        family.GetName(),
+			Description: family.GetHelp(),
+		}
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			m.Sum = &sum{
+				AggregationTemporality: aggregationTemporalityCumulative,
+				IsMonotonic:            true,
+			}
+			for _, sample := range family.GetMetric() {
+				v := sample.GetCounter().GetValue()
+				if !isFinite(v) {
+					continue
+				}
+				startTime := start
+				if created := sample.GetCounter().GetCreatedTimestamp(); created != nil {
+					startTime = created.AsTime()
+				}
+				m.Sum.DataPoints = append(m.Sum.DataPoints, numberDataPoint{
+					Attributes:        labelAttributes(sample.GetLabel()),
+					StartTimeUnixNano: unixNano(startTime),
+					TimeUnixNano:      ts,
+					AsDouble:          v,
+				})
+			}
+			if len(m.Sum.DataPoints) == 0 {
+				continue
+			}
+		case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+			m.Gauge = &gauge{}
+			for _, sample := range family.GetMetric() {
+				v := sample.GetGauge().GetValue()
+				if family.GetType() == dto.MetricType_UNTYPED {
+					v = sample.GetUntyped().GetValue()
+				}
+				if !isFinite(v) {
+					continue
+				}
+				m.Gauge.DataPoints = append(m.Gauge.DataPoints, numberDataPoint{
+					Attributes:   labelAttributes(sample.GetLabel()),
+					TimeUnixNano: ts,
+					AsDouble:     v,
+				})
+			}
+			if len(m.Gauge.DataPoints) == 0 {
+				continue
+			}
+		case dto.MetricType_HISTOGRAM:
+			m.Histogram = &histogram{
+				AggregationTemporality: aggregationTemporalityCumulative,
+			}
+			for _, sample := range family.GetMetric() {
+				h := sample.GetHistogram()
+				if !isFinite(h.GetSampleSum()) {
+					continue
+				}
+				startTime := start
+				if created := h.GetCreatedTimestamp(); created != nil {
+					startTime = created.AsTime()
+				}
+				dp := histogramDataPoint{
+					Attributes:        labelAttributes(sample.GetLabel()),
+					StartTimeUnixNano: unixNano(startTime),
+					TimeUnixNano:      ts,
+					Count:             strconv.FormatUint(h.GetSampleCount(), 10),
+					Sum:               h.GetSampleSum(),
+					ExplicitBounds:    []float64{},
+				}
+				// prometheus bucket counts are cumulative where otlp
+				// counts are per bucket with an implicit +Inf bucket
+				var prev uint64
+				for _, bucket := range h.GetBucket() {
+					if math.IsInf(bucket.GetUpperBound(), 1) {
+						continue
+					}
+					dp.ExplicitBounds = append(dp.ExplicitBounds, bucket.GetUpperBound())
+					dp.BucketCounts = append(dp.BucketCounts, strconv.FormatUint(bucket.GetCumulativeCount()-prev, 10))
+					prev = bucket.GetCumulativeCount()
+				}
+				dp.BucketCounts = append(dp.BucketCounts, strconv.FormatUint(h.GetSampleCount()-prev, 10))
+				m.Histogram.DataPoints = append(m.Histogram.DataPoints, dp)
+			}
+			if len(m.Histogram.DataPoints) == 0 {
+				continue
+			}
+		case dto.MetricType_SUMMARY:
+			m.Summary = &summary{}
+			for _, sample := range family.GetMetric() {
+				s := sample.GetSummary()
+				if !isFinite(s.GetSampleSum()) {
+					continue
+				}
+				startTime := start
+				if created := s.GetCreatedTimestamp(); created != nil {
+					startTime = created.AsTime()
+				}
+				dp := summaryDataPoint{
+					Attributes:        labelAttributes(sample.GetLabel()),
+					StartTimeUnixNano: unixNano(startTime),
+					TimeUnixNano:      ts,
+					Count:             strconv.FormatUint(s.GetSampleCount(), 10),
+					Sum:               s.GetSampleSum(),
+					QuantileValues:    []quantileValue{},
+				}
+				for _, q := range s.GetQuantile() {
+					if !isFinite(q.GetValue()) {
+						continue
+					}
+					dp.QuantileValues = append(dp.QuantileValues, quantileValue{
+						Quantile: q.GetQuantile(),
+						Value:    q.GetValue(),
+					})
+				}
+				m.Summary.DataPoints = append(m.Summary.DataPoints, dp)
+			}
+			if len(m.Summary.DataPoints) == 0 {
+				continue
+			}
+		default:
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func labelAttributes(labels []*dto.LabelPair) []Attribute {
+	if len(labels) == 0 {
+		return nil
+	}
+	attrs := make([]Attribute, 0, len(labels))
+	for _, label := range labels {
+		attrs = append(attrs, String(label.GetName(), label.GetValue()))
+	}
+	return attrs
+}
+
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
@@ -0,0 +1,127 @@
+package probes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	defaultInterval = 30 * time.Second
+	defaultTimeout  = 5 * time.Second
+)
+
+// Config for the reachability prober
+type Config struct {
+	// Interval between probes of each target. Defaults to 30s.
+	Interval Duration `json:"interval,omitempty"`
+	// Timeout for establishing a connection. Defaults to 5s.
+	Timeout Duration `json:"timeout,omitempty"`
+	// Site is the name or ID of the site whose router is reached at
+	// RouterHost
+	Site string `json:"site,omitempty"`
+	// RouterHost is the host on which the router of Site accepts
+	// connections. Listeners of that site bound to all interfaces, as on
+	// kubernetes where the router listens on a port allocated by the
+	// controller, are probed at this host and the port the router listens
+	// on. Listeners bound to all interfaces in other sites are not probed.
+	RouterHost string `json:"routerHost,omitempty"`
+	// Targets to probe
+	Targets []TargetConfig `json:"targets"`
+}
+
+// TargetConfig defines a service to probe
+type TargetConfig struct {
+	// Name of the target. Must be unique.
+	Name string `json:"name"`
+	// RoutingKey of the listeners to probe
+	RoutingKey string `json:"routingKey,omitempty"`
+	// Address (host:port) to connect to. When unset the address of each
+	// listener with the routing key is probed.
+	Address string `json:"address,omitempty"`
+	// Interval between probes overriding the default interval
+	Interval Duration `json:"interval,omitempty"`
+	// Timeout for establishing a connection overriding the default timeout
+	Timeout Duration `json:"timeout,omitempty"`
+}
+
+// LoadConfig reads a probe configuration from a yaml or json file
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read probes config: %w", err)
+	}
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse probes config %q: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid probes config %q: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate checks the configuration for errors
+func (c Config) Validate() error {
+	var errs []error
+	if c.Interval.Duration < 0 {
+		errs = append(errs, fmt.Errorf("interval must not be negative"))
+	}
+	if c.Timeout.Duration < 0 {
+		errs = append(errs, fmt.Errorf("timeout must not be negative"))
+	}
+	if (c.Site == "") != (c.RouterHost == "") {
+		errs = append(errs, fmt.Errorf("site and routerHost must be set together"))
+	}
+	names := make(map[string]bool, len(c.Targets))
+	for i, target := range c.Targets {
+		if target.Name == "" {
+			errs = append(errs, fmt.Errorf("targets[%d]: name is required", i))
+		} else if names[target.Name] {
+			errs = append(errs, fmt.Errorf("targets[%d]: duplicate target name %q", i, target.Name))
+		}
+		names[target.Name] = true
+		if target.RoutingKey == "" && target.Address == "" {
+			errs = append(errs, fmt.Errorf("targets[%d]: one of routingKey or address is required", i))
+		}
+		if target.Address != "" {
+			if _, _, err := net.SplitHostPort(target.Address); err != nil {
+				errs = append(errs, fmt.Errorf("targets[%d]: address must be a host:port: %s", i, err))
+			}
+		}
+		if target.Interval.Duration < 0 {
+			errs = append(errs, fmt.Errorf("targets[%d]: interval must not be negative", i))
+		}
+		if target.Timeout.Duration < 0 {
+			errs = append(errs, fmt.Errorf("targets[%d]: timeout must not be negative", i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Duration is a time.Duration encoded as a string such as "30s"
+type Duration struct {
+	time.Duration
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\": %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
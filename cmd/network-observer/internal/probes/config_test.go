@@ -0,0 +1,72 @@
+package probes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(t *testing.T, contents string) string {
+		t.Helper()
+		path := filepath.Join(dir, t.Name()+".yaml")
+		assert.NilError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NilError(t, os.WriteFile(path, []byte(contents), 0o644))
+		return path
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		cfg, err := LoadConfig(write(t, `
+interval: 10s
+site: west
+routerHost: localhost
+targets:
+- name: backend
+  routingKey: backend
+- name: db
+  address: db.example.com:5432
+  timeout: 1s
+`))
+		assert.NilError(t, err)
+		assert.Equal(t, cfg.Interval.Duration, 10*time.Second)
+		assert.Equal(t, len(cfg.Targets), 2)
+		assert.Equal(t, cfg.Site, "west")
+		assert.Equal(t, cfg.RouterHost, "localhost")
+		assert.Equal(t, cfg.Targets[1].Timeout.Duration, time.Second)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := LoadConfig(write(t, `
+targets:
+- name: backend
+  routing: backend
+`))
+		assert.ErrorContains(t, err, `unknown field "routing"`)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := LoadConfig(write(t, `
+timeout: -1s
+site: west
+targets:
+- name: backend
+  routingKey: backend
+- name: backend
+  address: backend
+- routingKey: db
+  interval: -5s
+- name: empty
+`))
+		assert.ErrorContains(t, err, "timeout must not be negative")
+		assert.ErrorContains(t, err, "site and routerHost must be set together")
+		assert.ErrorContains(t, err, `targets[1]: duplicate target name "backend"`)
+		assert.ErrorContains(t, err, "targets[1]: address must be a host:port")
+		assert.ErrorContains(t, err, "targets[2]: name is required")
+		assert.ErrorContains(t, err, "targets[2]: interval must not be negative")
+		assert.ErrorContains(t, err, "targets[3]: one of routingKey or address is required")
+	})
+}
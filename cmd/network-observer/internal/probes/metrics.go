@@ -0,0 +1,70 @@
+package probes
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metrics struct {
+	attempts       *prometheus.CounterVec
+	connectLatency *prometheus.HistogramVec
+	up             *prometheus.GaugeVec
+}
+
+var (
+	probeMetricLabels = []string{"target", "routing_key", "address", "site_id"}
+	histBucketsProbe  = []float64{0.001, 0.002, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5}
+)
+
+func register(reg prometheus.Registerer) metrics {
+	m := metrics{
+		attempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "skupper",
+			Name:      "probe_attempts_total",
+			Help:      "Number of reachability probes by result. The result is success or the reason the probe failed",
+		}, append(probeMetricLabels, "result")),
+		connectLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "skupper",
+			Name:      "probe_connect_latency_seconds",
+			Help:      "Time taken by successful reachability probes to establish a connection",
+			Buckets:   histBucketsProbe,
+		}, probeMetricLabels),
+		up: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "skupper",
+			Name:      "probe_up",
+			Help:      "Whether the most recent reachability probe succeeded (1) or failed (0)",
+		}, probeMetricLabels),
+	}
+	if reg != nil {
+		reg.MustRegister(m.attempts, m.connectLatency, m.up)
+	}
+	return m
+}
+
+func (m metrics) observe(result Result) {
+	labels := prometheus.Labels{
+		"target":      result.Target,
+		"routing_key": result.RoutingKey,
+		"address":     result.Address,
+		"site_id":     result.SiteID,
+	}
+	up := 0.0
+	outcome := result.FailureReason
+	if result.Success {
+		up, outcome = 1, "success"
+		latency := time.Duration(result.ConnectLatency) * time.Microsecond
+		m.connectLatency.With(labels).Observe(latency.Seconds())
+	}
+	m.up.With(labels).Set(up)
+	labels["result"] = outcome
+	m.attempts.With(labels).Inc()
+}
+
+// forget removes the metrics of an endpoint that is no longer probed
+func (m metrics) forget(result Result) {
+	labels := prometheus.Labels{"target": result.Target, "address": result.Address}
+	m.attempts.DeletePartialMatch(labels)
+	m.connectLatency.DeletePartialMatch(labels)
+	m.up.DeletePartialMatch(labels)
+}
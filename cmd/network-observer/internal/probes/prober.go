@@ -0,0 +1,355 @@
+package probes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+)
+
+// Failure reasons reported for unsuccessful probes
+const (
+	ReasonTimeout     = "timeout"
+	ReasonRefused     = "refused"
+	ReasonReset       = "reset"
+	ReasonUnreachable = "unreachable"
+	ReasonDNS         = "dns"
+	ReasonNoListeners = "no_listeners"
+	ReasonError       = "error"
+)
+
+// Result of the most recent probe of an endpoint
+type Result struct {
+	Target     string `json:"target"`
+	RoutingKey string `json:"routingKey,omitempty"`
+	// Address connected to
+	Address string `json:"address,omitempty"`
+	// ListenerID and SiteID of the listener the address was resolved from
+	ListenerID string `json:"listenerId,omitempty"`
+	SiteID     string `json:"siteId,omitempty"`
+	Success    bool   `json:"success"`
+	// ConnectLatency is the time taken to establish the connection in
+	// microseconds
+	ConnectLatency uint64 `json:"connectLatency,omitempty"`
+	// FailureReason categorizes the error of an unsuccessful probe
+	FailureReason string `json:"failureReason,omitempty"`
+	Error         string `json:"error,omitempty"`
+	// Timestamp of the probe
+	Timestamp time.Time `json:"timestamp"`
+	// LastSuccess is when the endpoint was last probed successfully
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+	// ConsecutiveFailures since the last successful probe
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+}
+
+// endpoint is an address probed for a target
+type endpoint struct {
+	address    string
+	listenerID string
+	siteID     string
+}
+
+type target struct {
+	TargetConfig
+	interval time.Duration
+	timeout  time.Duration
+}
+
+type resultKey struct {
+	target  string
+	address string
+}
+
+// Prober periodically opens TCP connections to the configured targets and
+// records the outcome.
+type Prober struct {
+	logger     *slog.Logger
+	records    store.Interface
+	site       string
+	routerHost string
+	targets    []target
+	metrics    metrics
+	dial       func(ctx context.Context, network, address string) (net.Conn, error)
+	now        func() time.Time
+
+	mu      sync.Mutex
+	results map[resultKey]Result
+}
+
+// New creates a Prober from a validated configuration. Listener addresses
+// are resolved from records.
+func New(logger *slog.Logger, records store.Interface, reg prometheus.Registerer, cfg Config) (*Prober, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	interval, timeout := cfg.Interval.Duration, cfg.Timeout.Duration
+	if interval == 0 {
+		interval = defaultInterval
+	}
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
+	p := &Prober{
+		logger:     logger,
+		records:    records,
+		site:       cfg.Site,
+		routerHost: cfg.RouterHost,
+		metrics:    register(reg),
+		dial:       (&net.Dialer{}).DialContext,
+		now:        time.Now,
+		results:    make(map[resultKey]Result),
+	}
+	for _, targetCfg := range cfg.Targets {
+		t := target{TargetConfig: targetCfg, interval: interval, timeout: timeout}
+		if targetCfg.Interval.Duration > 0 {
+			t.interval = targetCfg.Interval.Duration
+		}
+		if targetCfg.Timeout.Duration > 0 {
+			t.timeout = targetCfg.Timeout.Duration
+		}
+		p.targets = append(p.targets, t)
+	}
+	return p, nil
+}
+
+// Run probes each target at its interval until the context is cancelled
+func (p *Prober) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, t := range p.targets {
+		wg.Add(1)
+		go func(t target) {
+			defer wg.Done()
+			ticker := time.NewTicker(t.interval)
+			defer ticker.Stop()
+			for {
+				p.probeTarget(ctx, t)
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(t)
+	}
+	wg.Wait()
+	return nil
+}
+
+// Results returns the most recent result for each probed endpoint ordered
+// by target and address
+func (p *Prober) Results() []Result {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]Result, 0, len(p.results))
+	for _, result := range p.results {
+		out = append(out, result)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Target != out[j].Target {
+			return out[i].Target < out[j].Target
+		}
+		return out[i].Address < out[j].Address
+	})
+	return out
+}
+
+// probeTarget probes each endpoint of a target concurrently and forgets the
+// results of endpoints that no longer exist
+func (p *Prober) probeTarget(ctx context.Context, t target) {
+	endpoints := p.endpoints(t)
+	results := make([]Result, len(endpoints))
+	var wg sync.WaitGroup
+	for i, ep := range endpoints {
+		wg.Add(1)
+		go func(i int, ep endpoint) {
+			defer wg.Done()
+			results[i] = p.probe(ctx, t, ep)
+		}(i, ep)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+	if len(endpoints) == 0 {
+		results = append(results, Result{
+			Target:        t.Name,
+			RoutingKey:    t.RoutingKey,
+			FailureReason: ReasonNoListeners,
+			Error:         fmt.Sprintf("no reachable listeners found with routing key %q", t.RoutingKey),
+			Timestamp:     p.now(),
+		})
+	}
+	p.record(t, results)
+}
+
+func (p *Prober) probe(ctx context.Context, t target, ep endpoint) Result {
+	result := Result{
+		Target:     t.Name,
+		RoutingKey: t.RoutingKey,
+		Address:    ep.address,
+		ListenerID: ep.listenerID,
+		SiteID:     ep.siteID,
+		Timestamp:  p.now(),
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	start := time.Now()
+	conn, err := p.dial(dialCtx, "tcp", ep.address)
+	latency := time.Since(start)
+	if err != nil {
+		result.FailureReason = failureReason(err)
+		result.Error = err.Error()
+		return result
+	}
+	conn.Close()
+	result.Success = true
+	result.ConnectLatency = uint64(latency.Microseconds())
+	return result
+}
+
+// record stores the results of a round of probes for a target and updates
+// metrics
+func (p *Prober) record(t target, results []Result) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	current := make(map[resultKey]bool, len(results))
+	for _, result := range results {
+		key := resultKey{target: result.Target, address: result.Address}
+		current[key] = true
+		prev, ok := p.results[key]
+		if ok {
+			result.LastSuccess = prev.LastSuccess
+			result.ConsecutiveFailures = prev.ConsecutiveFailures
+		}
+		if result.Success {
+			ts := result.Timestamp
+			result.LastSuccess = &ts
+			result.ConsecutiveFailures = 0
+		} else {
+			result.ConsecutiveFailures++
+		}
+		p.results[key] = result
+		p.metrics.observe(result)
+		if !result.Success {
+			p.logger.Debug("probe failed",
+				slog.String("target", result.Target),
+				slog.String("address", result.Address),
+				slog.String("reason", result.FailureReason),
+				slog.String("error", result.Error))
+		}
+	}
+	for key, result := range p.results {
+		if key.target == t.Name && !current[key] {
+			delete(p.results, key)
+			p.metrics.forget(result)
+		}
+	}
+}
+
+// endpoints returns the addresses to probe for a target
+func (p *Prober) endpoints(t target) []endpoint {
+	if t.Address != "" {
+		return []endpoint{{address: t.Address}}
+	}
+	var endpoints []endpoint
+	seen := make(map[string]bool)
+	entries := p.records.Index(store.TypeIndex, store.Entry{Record: vanflow.ListenerRecord{}})
+	for _, entry := range entries {
+		listener, ok := entry.Record.(vanflow.ListenerRecord)
+		if !ok || listener.Address == nil || *listener.Address != t.RoutingKey {
+			continue
+		}
+		if listener.DestPort == nil || listener.EndTime != nil {
+			continue
+		}
+		site, siteName := p.listenerSite(listener)
+		host := p.listenerHost(listener, site, siteName)
+		if host == "" {
+			continue
+		}
+		address := net.JoinHostPort(host, *listener.DestPort)
+		if seen[address] {
+			continue
+		}
+		seen[address] = true
+		endpoints = append(endpoints, endpoint{
+			address:    address,
+			listenerID: listener.ID,
+			siteID:     site,
+		})
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].address < endpoints[j].address
+	})
+	return endpoints
+}
+
+// listenerSite returns the ID and name of the site a listener belongs to
+func (p *Prober) listenerSite(listener vanflow.ListenerRecord) (id string, name string) {
+	if listener.Parent == nil {
+		return "", ""
+	}
+	entry, ok := p.records.Get(*listener.Parent)
+	if !ok {
+		return "", ""
+	}
+	router, ok := entry.Record.(vanflow.RouterRecord)
+	if !ok || router.Parent == nil {
+		return "", ""
+	}
+	id = *router.Parent
+	if entry, ok := p.records.Get(id); ok {
+		if site, ok := entry.Record.(vanflow.SiteRecord); ok && site.Name != nil {
+			name = *site.Name
+		}
+	}
+	return id, name
+}
+
+// listenerHost returns the host a listener accepts connections on, or an
+// empty string when it cannot be reached. Listeners bound to all interfaces
+// are only reachable through the host of their router, which is known for
+// the configured site alone. On kubernetes the service exposing a listener
+// is not part of its record, and listens on a different port than the
+// router.
+func (p *Prober) listenerHost(listener vanflow.ListenerRecord, siteID string, siteName string) string {
+	if listener.DestHost != nil {
+		switch host := *listener.DestHost; host {
+		case "", "0.0.0.0", "::", "[::]":
+		default:
+			return host
+		}
+	}
+	if p.routerHost != "" && (p.site == siteID || p.site == siteName) {
+		return p.routerHost
+	}
+	return ""
+}
+
+// failureReason categorizes a dial error
+func failureReason(err error) string {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &dnsErr):
+		return ReasonDNS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ReasonTimeout
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ReasonRefused
+	case errors.Is(err, syscall.ECONNRESET):
+		return ReasonReset
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return ReasonUnreachable
+	}
+	return ReasonError
+}
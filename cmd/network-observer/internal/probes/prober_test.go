@@ -0,0 +1,159 @@
+package probes
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+	"gotest.tools/v3/assert"
+)
+
+func TestProber(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	// an address nothing is listening on
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	refusedAddr := closed.Addr().String()
+	closed.Close()
+
+	records := store.NewSyncMapStore(store.SyncMapStoreConfig{Indexers: collector.RecordIndexers()})
+	reg := prometheus.NewRegistry()
+	prober, err := New(slog.Default(), records, reg, Config{
+		Targets: []TargetConfig{
+			{Name: "up", Address: ln.Addr().String()},
+			{Name: "down", Address: refusedAddr},
+		},
+	})
+	assert.NilError(t, err)
+
+	ctx := context.Background()
+	for _, target := range prober.targets {
+		prober.probeTarget(ctx, target)
+	}
+	prober.probeTarget(ctx, prober.targets[1])
+	results := prober.Results()
+	assert.Equal(t, len(results), 2)
+
+	down, up := results[0], results[1]
+	assert.Equal(t, up.Target, "up")
+	assert.Assert(t, up.Success)
+	assert.Assert(t, up.LastSuccess != nil)
+	assert.Equal(t, up.ConsecutiveFailures, 0)
+
+	assert.Equal(t, down.Target, "down")
+	assert.Assert(t, !down.Success)
+	assert.Equal(t, down.FailureReason, ReasonRefused)
+	assert.Equal(t, down.ConsecutiveFailures, 2)
+	assert.Assert(t, down.LastSuccess == nil)
+
+	assert.Equal(t, testutil.ToFloat64(prober.metrics.up.WithLabelValues("up", "", ln.Addr().String(), "")), 1.0)
+	assert.Equal(t, testutil.ToFloat64(prober.metrics.attempts.WithLabelValues("down", "", refusedAddr, "", ReasonRefused)), 2.0)
+}
+
+func TestProberListeners(t *testing.T) {
+	records := store.NewSyncMapStore(store.SyncMapStoreConfig{Indexers: collector.RecordIndexers()})
+	source := store.SourceRef{ID: "test"}
+	records.Add(vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1"), Name: ptrTo("west")}, source)
+	records.Add(vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-2"), Name: ptrTo("east")}, source)
+	records.Add(vanflow.RouterRecord{BaseRecord: vanflow.NewBase("router-1"), Parent: ptrTo("site-1")}, source)
+	records.Add(vanflow.RouterRecord{BaseRecord: vanflow.NewBase("router-2"), Parent: ptrTo("site-2")}, source)
+	// kubernetes routers listen on all interfaces at a port allocated by
+	// the controller
+	records.Add(vanflow.ListenerRecord{BaseRecord: vanflow.NewBase("listener-1"), Parent: ptrTo("router-1"),
+		Name: ptrTo("backend"), DestHost: ptrTo(""), DestPort: ptrTo("1024"), Address: ptrTo("backend")}, source)
+	records.Add(vanflow.ListenerRecord{BaseRecord: vanflow.NewBase("listener-2"), Parent: ptrTo("router-2"),
+		Name: ptrTo("backend"), DestHost: ptrTo("10.0.0.2"), DestPort: ptrTo("8080"), Address: ptrTo("backend")}, source)
+	records.Add(vanflow.ListenerRecord{BaseRecord: vanflow.NewBase("listener-3"), Parent: ptrTo("router-2"),
+		Name: ptrTo("db"), DestPort: ptrTo("5432"), Address: ptrTo("db")}, source)
+	records.Add(vanflow.ListenerRecord{BaseRecord: vanflow.NewBase("listener-4"), Parent: ptrTo("router-2"),
+		Name: ptrTo("backend-2"), DestHost: ptrTo(""), DestPort: ptrTo("1025"), Address: ptrTo("backend")}, source)
+
+	prober, err := New(slog.Default(), records, prometheus.NewRegistry(), Config{
+		Site:       "west",
+		RouterHost: "10.0.0.1",
+		Targets: []TargetConfig{
+			{Name: "backend", RoutingKey: "backend"},
+			{Name: "missing", RoutingKey: "missing"},
+			{Name: "db", RoutingKey: "db"},
+		},
+	})
+	assert.NilError(t, err)
+	var (
+		mu     sync.Mutex
+		dialed []string
+	)
+	prober.dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		dialed = append(dialed, address)
+		return nil, &net.OpError{Op: "dial", Net: network, Err: syscall.ECONNREFUSED}
+	}
+
+	// listeners bound to all interfaces are probed through the router of
+	// the configured site, and not at all in other sites
+	assert.DeepEqual(t, prober.endpoints(prober.targets[0]), []endpoint{
+		{address: "10.0.0.1:1024", listenerID: "listener-1", siteID: "site-1"},
+		{address: "10.0.0.2:8080", listenerID: "listener-2", siteID: "site-2"},
+	}, cmpEndpoint)
+	assert.Equal(t, len(prober.endpoints(prober.targets[2])), 0)
+	prober.routerHost, prober.site = "", ""
+	assert.DeepEqual(t, prober.endpoints(prober.targets[0]), []endpoint{
+		{address: "10.0.0.2:8080", listenerID: "listener-2", siteID: "site-2"},
+	}, cmpEndpoint)
+	prober.routerHost, prober.site = "10.0.0.1", "site-1"
+
+	prober.probeTarget(context.Background(), prober.targets[1])
+	results := prober.Results()
+	assert.Equal(t, len(results), 1)
+	assert.Equal(t, results[0].FailureReason, ReasonNoListeners)
+	assert.Equal(t, len(dialed), 0)
+
+	// results for listeners that have gone away are dropped
+	prober.probeTarget(context.Background(), prober.targets[0])
+	assert.Equal(t, len(prober.Results()), 3)
+	records.Delete("listener-1")
+	prober.probeTarget(context.Background(), prober.targets[0])
+	assert.Equal(t, len(prober.Results()), 2)
+}
+
+func TestFailureReason(t *testing.T) {
+	testcases := []struct {
+		Err    error
+		Reason string
+	}{
+		{Err: &net.DNSError{Err: "no such host", Name: "backend"}, Reason: ReasonDNS},
+		{Err: context.DeadlineExceeded, Reason: ReasonTimeout},
+		{Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, Reason: ReasonRefused},
+		{Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}, Reason: ReasonReset},
+		{Err: &net.OpError{Op: "dial", Err: syscall.EHOSTUNREACH}, Reason: ReasonUnreachable},
+		{Err: errors.New("unexpected"), Reason: ReasonError},
+	}
+	for _, tc := range testcases {
+		assert.Equal(t, failureReason(tc.Err), tc.Reason, tc.Err.Error())
+	}
+}
+
+var cmpEndpoint = cmp.AllowUnexported(endpoint{})
+
+func ptrTo[T any](obj T) *T { return &obj }
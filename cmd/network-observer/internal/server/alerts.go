@@ -0,0 +1,71 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/alerts"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
+)
+
+// AlertSource provides the state of the alerting engine
+type AlertSource interface {
+	Alerts() []alerts.Alert
+	Rules() []alerts.RuleStatus
+}
+
+type alertsResponse struct {
+	Rules  []alerts.RuleStatus `json:"rules"`
+	Alerts []alerts.Alert      `json:"alerts"`
+}
+
+// NewAlertsHandler returns a handler that reports the configured alerting
+// rules and their pending and firing alerts. The state and rule query
+// parameters filter the alerts returned.
+//
+// (GET /api/v2alpha1/alerts)
+func NewAlertsHandler(logger *slog.Logger, source AlertSource) http.Handler {
+	return &alertsHandler{
+		logger: logger,
+		source: source,
+	}
+}
+
+type alertsHandler struct {
+	logger *slog.Logger
+	source AlertSource
+}
+
+func (h *alertsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	state := alerts.AlertState(query.Get("state"))
+	switch state {
+	case "", alerts.StatePending, alerts.StateFiring:
+	default:
+		if err := encodeResponse(w, http.StatusBadRequest, api.ErrorBadRequest{
+			Message: fmt.Sprintf("invalid state %q: must be one of pending or firing", state),
+		}); err != nil {
+			requestLogger(h.logger, r).Error("failed to write response", slog.Any("error", err))
+		}
+		return
+	}
+	rule := query.Get("rule")
+
+	response := alertsResponse{
+		Rules:  h.source.Rules(),
+		Alerts: []alerts.Alert{},
+	}
+	for _, alert := range h.source.Alerts() {
+		if state != "" && alert.State != state {
+			continue
+		}
+		if rule != "" && alert.Rule != rule {
+			continue
+		}
+		response.Alerts = append(response.Alerts, alert)
+	}
+	if err := encodeResponse(w, http.StatusOK, response); err != nil {
+		requestLogger(h.logger, r).Error("failed to write response", slog.Any("error", err))
+	}
+}
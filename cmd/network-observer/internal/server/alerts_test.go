@@ -0,0 +1,65 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/alerts"
+	"gotest.tools/v3/assert"
+)
+
+type staticAlerts struct {
+	alerts []alerts.Alert
+	rules  []alerts.RuleStatus
+}
+
+func (s staticAlerts) Alerts() []alerts.Alert     { return s.alerts }
+func (s staticAlerts) Rules() []alerts.RuleStatus { return s.rules }
+
+func TestAlerts(t *testing.T) {
+	srv := httptest.NewServer(NewAlertsHandler(slog.Default(), staticAlerts{
+		rules: []alerts.RuleStatus{
+			{Name: "link-down", Type: alerts.RuleLinkDown, Firing: 1},
+			{Name: "site-missing", Type: alerts.RuleSiteMissing, Pending: 1},
+		},
+		alerts: []alerts.Alert{
+			{Fingerprint: "a", Rule: "link-down", State: alerts.StateFiring},
+			{Fingerprint: "b", Rule: "site-missing", State: alerts.StatePending},
+		},
+	}))
+	defer srv.Close()
+
+	testcases := []struct {
+		Query        string
+		ExpectStatus int
+		ExpectAlerts []string
+	}{
+		{ExpectStatus: http.StatusOK, ExpectAlerts: []string{"a", "b"}},
+		{Query: "?state=firing", ExpectStatus: http.StatusOK, ExpectAlerts: []string{"a"}},
+		{Query: "?rule=site-missing", ExpectStatus: http.StatusOK, ExpectAlerts: []string{"b"}},
+		{Query: "?rule=unknown", ExpectStatus: http.StatusOK, ExpectAlerts: nil},
+		{Query: "?state=resolved", ExpectStatus: http.StatusBadRequest},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.Query, func(t *testing.T) {
+			resp, err := http.Get(srv.URL + tc.Query)
+			assert.NilError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, resp.StatusCode, tc.ExpectStatus)
+			if tc.ExpectStatus != http.StatusOK {
+				return
+			}
+			var body alertsResponse
+			assert.NilError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.Equal(t, len(body.Rules), 2)
+			var actual []string
+			for _, alert := range body.Alerts {
+				actual = append(actual, alert.Fingerprint)
+			}
+			assert.DeepEqual(t, actual, tc.ExpectAlerts)
+		})
+	}
+}
@@ -141,6 +141,27 @@ func TestConnections(t *testing.T) {
 			ExpectCount:          0,
 			ExpectTimeRangeCount: 3,
 		},
+		{
+			Records: wrapRecords(
+				collector.ConnectionRecord{ID: "flow:1", RoutingKey: "backend-v1", FlowStore: flowStor},
+				collector.ConnectionRecord{ID: "flow:2", RoutingKey: "backend-v2", FlowStore: flowStor},
+				collector.ConnectionRecord{ID: "flow:3", RoutingKey: "frontend", FlowStore: flowStor},
+			),
+			Flows: wrapRecords(
+				vanflow.TransportBiflowRecord{BaseRecord: vanflow.NewBase("flow:1", timeline[0]), Octets: ptrTo(uint64(2e6))},
+				vanflow.TransportBiflowRecord{BaseRecord: vanflow.NewBase("flow:2", timeline[0]), Octets: ptrTo(uint64(10))},
+				vanflow.TransportBiflowRecord{BaseRecord: vanflow.NewBase("flow:3", timeline[0]), Octets: ptrTo(uint64(2e6))},
+			),
+			Parameters: map[string][]string{
+				"filter":         {`routingKey ^= "backend-" AND octetCount > 1e6`},
+				"timeRangeStart": {fmt.Sprint(t0.UnixMicro())},
+			},
+			ExpectOK:    true,
+			ExpectCount: 1,
+			ExpectResults: func(t *testing.T, results []api.ConnectionRecord) {
+				assert.Equal(t, results[0].Identity, "flow:1")
+			},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -0,0 +1,227 @@
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/server/views"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+)
+
+const (
+	exportNDJSON = "ndjson"
+	exportCSV    = "csv"
+)
+
+// exportFlushInterval is the number of records written between flushes of
+// the response
+const exportFlushInterval = 1000
+
+// NewExportHandler returns a handler that streams connections or requests
+// as newline delimited json or csv. The filter and time range query
+// parameters of the corresponding list endpoint are honored while sorting
+// and pagination are not: records are written in identity order.
+//
+// (GET /api/v2alpha1/connections/export)
+// (GET /api/v2alpha1/applicationflows/export)
+func NewExportHandler(logger *slog.Logger, records store.Interface) http.Handler {
+	return &exportHandler{
+		logger:  logger,
+		records: records,
+	}
+}
+
+type exportHandler struct {
+	logger  *slog.Logger
+	records store.Interface
+}
+
+func (h *exportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var err error
+	switch recordType := mux.Vars(r)["recordType"]; recordType {
+	case "connections":
+		err = exportConnections(w, r, h.records, exportNDJSON)
+	case "applicationflows":
+		err = exportRequests(w, r, h.records, exportNDJSON)
+	default:
+		err = encodeResponse(w, http.StatusNotFound, api.ErrorNotFound{
+			Code:    "ErrNotFound",
+			Message: fmt.Sprintf("cannot export %q", recordType),
+		})
+	}
+	if err != nil {
+		requestLogger(h.logger, r).Error("failed to write export", slog.Any("error", err))
+	}
+}
+
+func exportConnections(w http.ResponseWriter, r *http.Request, records store.Interface, defaultFormat string) error {
+	provider := views.NewConnectionsProvider(records)
+	return streamExport(w, r, "connections", defaultFormat, listByType[collector.ConnectionRecord](records), func(entry store.Entry) (api.ConnectionRecord, bool) {
+		record, ok := entry.Record.(collector.ConnectionRecord)
+		if !ok {
+			return api.ConnectionRecord{}, false
+		}
+		return provider(record)
+	})
+}
+
+func exportRequests(w http.ResponseWriter, r *http.Request, records store.Interface, defaultFormat string) error {
+	provider := views.NewRequestProvider(records)
+	return streamExport(w, r, "applicationflows", defaultFormat, listByType[collector.RequestRecord](records), func(entry store.Entry) (api.ApplicationFlowRecord, bool) {
+		record, ok := entry.Record.(collector.RequestRecord)
+		if !ok {
+			return api.ApplicationFlowRecord{}, false
+		}
+		return provider(record)
+	})
+}
+
+// streamExport converts, filters and writes records one at a time so that
+// the full result set is never held in memory.
+func streamExport[T api.Record](w http.ResponseWriter, r *http.Request, name string, defaultFormat string, entries []store.Entry, convert func(store.Entry) (T, bool)) error {
+	qp := getQueryParams(r)
+	format := qp.Format
+	if format == "" {
+		format = defaultFormat
+	}
+	filter, err := newRecordFilter[T](qp)
+	if err != nil {
+		return encodeResponse(w, http.StatusBadRequest, api.ErrorBadRequest{Message: err.Error()})
+	}
+	if _, ok := r.URL.Query()["sortBy"]; ok {
+		return encodeResponse(w, http.StatusBadRequest, api.ErrorBadRequest{Message: "sortBy is not supported by exports"})
+	}
+	var newWriter func(io.Writer) (func(T) error, error)
+	switch format {
+	case exportNDJSON:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		newWriter = newNDJSONRecordWriter[T]
+	case exportCSV:
+		w.Header().Set("Content-Type", "text/csv")
+		newWriter = newCSVRecordWriter[T]
+	default:
+		return encodeResponse(w, http.StatusBadRequest, api.ErrorBadRequest{
+			Message: fmt.Sprintf("unsupported export format %q: expected %s or %s", format, exportNDJSON, exportCSV),
+		})
+	}
+	rc := http.NewResponseController(w)
+	// the export outlives any write timeout configured on the server
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+"."+format))
+	w.WriteHeader(http.StatusOK)
+	write, err := newWriter(w)
+	if err != nil {
+		return err
+	}
+
+	outsideTimeRange := timeRangeFilter(qp.State, qp.TimeRangeOperation, qp.TimeRangeStart, qp.TimeRangeEnd)
+	var written int
+	for _, entry := range entries {
+		if err := r.Context().Err(); err != nil {
+			return err
+		}
+		record, ok := convert(entry)
+		if !ok || !isVisible(r, record) || !filter.Matches(record) || outsideTimeRange(record) {
+			continue
+		}
+		tagNetwork(r, &record)
+		if err := write(record); err != nil {
+			return err
+		}
+		written++
+		if written%exportFlushInterval == 0 {
+			if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func newNDJSONRecordWriter[T any](w io.Writer) (func(T) error, error) {
+	enc := json.NewEncoder(w)
+	return func(record T) error { return enc.Encode(record) }, nil
+}
+
+// newCSVRecordWriter writes a header row of json field names and returns a
+// function writing records of type T as csv rows. Optional fields that are
+// unset are written as empty strings and lists are joined with semicolons.
+func newCSVRecordWriter[T any](w io.Writer) (func(T) error, error) {
+	var (
+		out    = csv.NewWriter(w)
+		typ    = reflect.TypeOf((*T)(nil)).Elem()
+		fields []int
+		header []string
+	)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, i)
+		header = append(header, name)
+	}
+	if err := out.Write(header); err != nil {
+		return nil, err
+	}
+	out.Flush()
+	row := make([]string, len(fields))
+	return func(record T) error {
+		val := reflect.ValueOf(record)
+		for i, field := range fields {
+			row[i] = csvValue(val.Field(field))
+		}
+		if err := out.Write(row); err != nil {
+			return err
+		}
+		out.Flush()
+		return out.Error()
+	}, out.Error()
+}
+
+func csvValue(val reflect.Value) string {
+	if val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return ""
+		}
+		val = val.Elem()
+	}
+	switch val.Kind() {
+	case reflect.String:
+		return val.String()
+	case reflect.Bool:
+		return strconv.FormatBool(val.Bool())
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(val.Int(), 10)
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(val.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(val.Float(), 'g', -1, 64)
+	case reflect.Slice:
+		parts := make([]string, val.Len())
+		for i := range parts {
+			parts[i] = csvValue(val.Index(i))
+		}
+		return strings.Join(parts, ";")
+	default:
+		return fmt.Sprint(val.Interface())
+	}
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+	"gotest.tools/v3/assert"
+)
+
+func TestExport(t *testing.T) {
+	stor := store.NewSyncMapStore(store.SyncMapStoreConfig{Indexers: collector.RecordIndexers()})
+	flowStor := store.NewSyncMapStore(store.SyncMapStoreConfig{Indexers: collector.RecordIndexers()})
+	graph := collector.NewGraph(stor)
+
+	var records, flows []vanflow.Record
+	for i, key := range []string{"payments", "inventory", "payments"} {
+		id := fmt.Sprintf("flow:%d", i)
+		records = append(records, collector.ConnectionRecord{
+			ID:         id,
+			Source:     collector.NamedReference{ID: "p1", Name: "frontend"},
+			Dest:       collector.NamedReference{ID: "p2", Name: "backend, primary"},
+			Protocol:   "tcp",
+			RoutingKey: key,
+			FlowStore:  flowStor,
+		})
+		flows = append(flows, vanflow.TransportBiflowRecord{
+			BaseRecord: vanflow.NewBase(id),
+			Octets:     ptrTo(uint64(i * 100)),
+		})
+	}
+	stor.Replace(wrapRecords(records...))
+	flowStor.Replace(wrapRecords(flows...))
+	graph.(reset).Reset()
+
+	router := mux.NewRouter()
+	api.HandlerWithOptions(New(slog.Default(), stor, graph), api.GorillaServerOptions{BaseRouter: router})
+	router.Path("/api/v2alpha1/{recordType:connections|applicationflows}/export").Handler(NewExportHandler(slog.Default(), stor))
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	get := func(t *testing.T, path string) *http.Response {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		assert.NilError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("ndjson", func(t *testing.T) {
+		resp := get(t, "/api/v2alpha1/connections/export?routingKey=payments")
+		assert.Equal(t, resp.StatusCode, http.StatusOK)
+		assert.Equal(t, resp.Header.Get("Content-Type"), "application/x-ndjson")
+		assert.Equal(t, resp.Header.Get("Content-Disposition"), `attachment; filename="connections.ndjson"`)
+		var ids []string
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			var record api.ConnectionRecord
+			assert.NilError(t, json.Unmarshal(scanner.Bytes(), &record))
+			assert.Equal(t, record.RoutingKey, "payments")
+			ids = append(ids, record.Identity)
+		}
+		assert.NilError(t, scanner.Err())
+		assert.DeepEqual(t, ids, []string{"flow:0", "flow:2"})
+	})
+
+	t.Run("csv from list endpoint", func(t *testing.T) {
+		resp := get(t, "/api/v2alpha1/connections?format=csv&filter=octetCount>0")
+		assert.Equal(t, resp.StatusCode, http.StatusOK)
+		assert.Equal(t, resp.Header.Get("Content-Type"), "text/csv")
+		rows, err := csv.NewReader(resp.Body).ReadAll()
+		assert.NilError(t, err)
+		assert.Equal(t, len(rows), 3)
+		column := func(name string) int {
+			for i, col := range rows[0] {
+				if col == name {
+					return i
+				}
+			}
+			t.Fatalf("missing column %q in %v", name, rows[0])
+			return -1
+		}
+		assert.Equal(t, rows[1][column("identity")], "flow:1")
+		assert.Equal(t, rows[1][column("octetCount")], "100")
+		assert.Equal(t, rows[1][column("destProcessName")], "backend, primary")
+		assert.Equal(t, rows[2][column("connectorError")], "")
+	})
+
+	t.Run("empty csv has header", func(t *testing.T) {
+		resp := get(t, "/api/v2alpha1/applicationflows/export?format=csv")
+		assert.Equal(t, resp.StatusCode, http.StatusOK)
+		rows, err := csv.NewReader(resp.Body).ReadAll()
+		assert.NilError(t, err)
+		assert.Equal(t, len(rows), 1)
+		assert.Equal(t, rows[0][0], "connectionId")
+	})
+
+	t.Run("csv values", func(t *testing.T) {
+		assert.Equal(t, csvValue(reflect.ValueOf([]string{"router-a", "router-b"})), "router-a;router-b")
+		assert.Equal(t, csvValue(reflect.ValueOf(ptrTo(uint64(7)))), "7")
+		assert.Equal(t, csvValue(reflect.ValueOf((*string)(nil))), "")
+	})
+
+	for _, path := range []string{
+		"/api/v2alpha1/connections/export?format=xml",
+		"/api/v2alpha1/connections/export?sortBy=octetCount.desc",
+		"/api/v2alpha1/connections/export?filter=octetCount>>1",
+		"/api/v2alpha1/connections?format=xml",
+	} {
+		t.Run(path, func(t *testing.T) {
+			resp := get(t, path)
+			assert.Equal(t, resp.StatusCode, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestExportWriteTimeout(t *testing.T) {
+	stor := store.NewSyncMapStore(store.SyncMapStoreConfig{Indexers: collector.RecordIndexers()})
+	flowStor := store.NewSyncMapStore(store.SyncMapStoreConfig{Indexers: collector.RecordIndexers()})
+	var records, flows []vanflow.Record
+	for i := 0; i < 3; i++ {
+		id := fmt.Sprintf("flow:%d", i)
+		records = append(records, collector.ConnectionRecord{ID: id, Protocol: "tcp", FlowStore: flowStor})
+		flows = append(flows, vanflow.TransportBiflowRecord{BaseRecord: vanflow.NewBase(id)})
+	}
+	stor.Replace(wrapRecords(records...))
+	flowStor.Replace(wrapRecords(flows...))
+
+	router := mux.NewRouter()
+	export := NewExportHandler(slog.Default(), stor)
+	// stands in for an export that takes longer than the write timeout
+	router.Path("/api/v2alpha1/{recordType:connections|applicationflows}/export").Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		export.ServeHTTP(w, r)
+	}))
+	srv := httptest.NewUnstartedServer(router)
+	srv.Config.WriteTimeout = 20 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/v2alpha1/connections/export")
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	var ids []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var record api.ConnectionRecord
+		assert.NilError(t, json.Unmarshal(scanner.Bytes(), &record))
+		ids = append(ids, record.Identity)
+	}
+	assert.NilError(t, scanner.Err())
+	assert.DeepEqual(t, ids, []string{"flow:0", "flow:1", "flow:2"})
+}
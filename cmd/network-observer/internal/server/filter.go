@@ -0,0 +1,573 @@
+package server
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
+)
+
+// maxFilterDepth limits the nesting of filter expressions
+const maxFilterDepth = 32
+
+// filterExpr is a parsed filter expression that can be evaluated against
+// records.
+//
+// The filter expression grammar:
+//
+//	expr       = and { "OR" and }
+//	and        = unary { "AND" unary }
+//	unary      = "NOT" unary | "(" expr ")" | comparison
+//	comparison = field op value
+//	           | field [ "NOT" ] "IN" "(" value { "," value } ")"
+//	op         = "=" | "==" | "!=" | "<" | "<=" | ">" | ">="
+//	           | "=~" | "!~" (regular expression match)
+//	           | "^=" (prefix match)
+//
+// Fields are record fields as named in the api, with nested fields separated
+// by dots. Values are either quoted strings or bare words. Values compared
+// to numeric fields may be numbers (1e6), durations (200ms) which are
+// converted to microseconds, or times relative to the current time (now-1h)
+// as microseconds since the unix epoch. The value null matches fields that
+// are not set.
+type filterExpr[T any] interface {
+	Eval(item T) bool
+}
+
+type andExpr[T any] []filterExpr[T]
+
+func (e andExpr[T]) Eval(item T) bool {
+	for _, term := range e {
+		if !term.Eval(item) {
+			return false
+		}
+	}
+	return true
+}
+
+type orExpr[T any] []filterExpr[T]
+
+func (e orExpr[T]) Eval(item T) bool {
+	for _, term := range e {
+		if term.Eval(item) {
+			return true
+		}
+	}
+	return false
+}
+
+type notExpr[T any] struct {
+	expr filterExpr[T]
+}
+
+func (e notExpr[T]) Eval(item T) bool {
+	return !e.expr.Eval(item)
+}
+
+type comparisonOp int
+
+const (
+	opEq comparisonOp = iota
+	opLt
+	opLe
+	opGt
+	opGe
+	opRegexp
+	opPrefix
+	opIn
+	opIsNull
+)
+
+// comparisonExpr compares a record field to one or more values. Negated
+// operators (!=, !~, NOT IN) are evaluated as the negation of their
+// positive counterpart.
+type comparisonExpr[T any] struct {
+	field   fieldIndex[T]
+	op      comparisonOp
+	negate  bool
+	strings []string
+	numbers []float64
+	bools   []bool
+	re      *regexp.Regexp
+}
+
+func (e comparisonExpr[T]) Eval(item T) bool {
+	val, ok := e.field.value(item)
+	if !ok {
+		return e.negate != (e.op == opIsNull)
+	}
+	return e.negate != e.matches(val)
+}
+
+func (e comparisonExpr[T]) matches(val reflect.Value) bool {
+	if e.op == opIsNull {
+		switch val.Kind() {
+		case reflect.Pointer, reflect.Slice:
+			return val.IsNil()
+		}
+		return false
+	}
+	if val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return false
+		}
+		val = val.Elem()
+	}
+	switch val.Kind() {
+	case reflect.Slice:
+		for i := 0; i < val.Len(); i++ {
+			if e.matches(val.Index(i)) {
+				return true
+			}
+		}
+		return false
+	case reflect.String:
+		if val.Type() == atmarkSplitStringTyp {
+			for _, part := range val.Interface().(api.AtmarkDelimitedString).Parts() {
+				if e.matchString(part, true) {
+					return true
+				}
+			}
+			return false
+		}
+		return e.matchString(val.String(), false)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return e.matchNumber(float64(val.Uint()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return e.matchNumber(float64(val.Int()))
+	case reflect.Float32, reflect.Float64:
+		return e.matchNumber(val.Float())
+	case reflect.Bool:
+		for _, b := range e.bools {
+			if val.Bool() == b {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (e comparisonExpr[T]) matchString(s string, foldCase bool) bool {
+	switch e.op {
+	case opRegexp:
+		return e.re.MatchString(s)
+	case opPrefix:
+		return strings.HasPrefix(s, e.strings[0])
+	case opEq, opIn:
+		for _, value := range e.strings {
+			if s == value || foldCase && strings.EqualFold(s, value) {
+				return true
+			}
+		}
+		return false
+	}
+	return compareOp(e.op, strings.Compare(s, e.strings[0]))
+}
+
+func (e comparisonExpr[T]) matchNumber(x float64) bool {
+	switch e.op {
+	case opEq, opIn:
+		for _, value := range e.numbers {
+			if x == value {
+				return true
+			}
+		}
+		return false
+	}
+	y := e.numbers[0]
+	switch {
+	case x < y:
+		return compareOp(e.op, -1)
+	case x > y:
+		return compareOp(e.op, 1)
+	default:
+		return compareOp(e.op, 0)
+	}
+}
+
+func compareOp(op comparisonOp, d int) bool {
+	switch op {
+	case opLt:
+		return d < 0
+	case opLe:
+		return d <= 0
+	case opGt:
+		return d > 0
+	case opGe:
+		return d >= 0
+	}
+	return false
+}
+
+// parseFilterExpression parses a filter expression for records of type T
+func parseFilterExpression[T any](expr string) (filterExpr[T], error) {
+	tokens, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser[T]{tokens: tokens, now: time.Now()}
+	out, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at offset %d", tok, tok.pos)
+	}
+	return out, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type filterToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t filterToken) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+// keyword returns true when the token is the bare keyword kw
+func (t filterToken) keyword(kw string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.text, kw)
+}
+
+var filterOperators = []string{"==", "!=", ">=", "<=", "=~", "!~", "^=", "=", ">", "<"}
+
+func lexFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	for pos := 0; pos < len(expr); {
+		c := expr[pos]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			pos++
+			continue
+		case c == '(':
+			tokens = append(tokens, filterToken{kind: tokenLParen, text: "(", pos: pos})
+			pos++
+			continue
+		case c == ')':
+			tokens = append(tokens, filterToken{kind: tokenRParen, text: ")", pos: pos})
+			pos++
+			continue
+		case c == ',':
+			tokens = append(tokens, filterToken{kind: tokenComma, text: ",", pos: pos})
+			pos++
+			continue
+		case c == '"' || c == '\'':
+			end := pos + 1
+			var sb strings.Builder
+			for ; end < len(expr) && expr[end] != c; end++ {
+				if expr[end] == '\\' && end+1 < len(expr) {
+					end++
+				}
+				sb.WriteByte(expr[end])
+			}
+			if end >= len(expr) {
+				return nil, fmt.Errorf("unterminated string at offset %d", pos)
+			}
+			tokens = append(tokens, filterToken{kind: tokenString, text: sb.String(), pos: pos})
+			pos = end + 1
+			continue
+		}
+		if op, ok := matchOperator(expr[pos:]); ok {
+			tokens = append(tokens, filterToken{kind: tokenOperator, text: op, pos: pos})
+			pos += len(op)
+			continue
+		}
+		end := pos
+		for end < len(expr) && !isFilterDelimiter(expr[end]) {
+			end++
+		}
+		if end == pos {
+			return nil, fmt.Errorf("unexpected character %q at offset %d", c, pos)
+		}
+		tokens = append(tokens, filterToken{kind: tokenWord, text: expr[pos:end], pos: pos})
+		pos = end
+	}
+	return append(tokens, filterToken{kind: tokenEOF, pos: len(expr)}), nil
+}
+
+func matchOperator(s string) (string, bool) {
+	for _, op := range filterOperators {
+		if strings.HasPrefix(s, op) {
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func isFilterDelimiter(c byte) bool {
+	return unicode.IsSpace(rune(c)) || strings.IndexByte("()=!<>~^,'\"", c) >= 0
+}
+
+type filterParser[T any] struct {
+	tokens []filterToken
+	pos    int
+	now    time.Time
+}
+
+func (p *filterParser[T]) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser[T]) next() filterToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *filterParser[T]) parseOr(depth int) (filterExpr[T], error) {
+	if depth > maxFilterDepth {
+		return nil, fmt.Errorf("expression nested too deeply")
+	}
+	var terms orExpr[T]
+	for {
+		term, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		if !p.peek().keyword("OR") {
+			break
+		}
+		p.next()
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *filterParser[T]) parseAnd(depth int) (filterExpr[T], error) {
+	var terms andExpr[T]
+	for {
+		term, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+		if !p.peek().keyword("AND") {
+			break
+		}
+		p.next()
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return terms, nil
+}
+
+func (p *filterParser[T]) parseUnary(depth int) (filterExpr[T], error) {
+	if depth > maxFilterDepth {
+		return nil, fmt.Errorf("expression nested too deeply")
+	}
+	tok := p.peek()
+	switch {
+	case tok.keyword("NOT"):
+		p.next()
+		expr, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return notExpr[T]{expr: expr}, nil
+	case tok.kind == tokenLParen:
+		p.next()
+		expr, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokenRParen {
+			return nil, fmt.Errorf("expected \")\" but got %s at offset %d", tok, tok.pos)
+		}
+		return expr, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser[T]) parseComparison() (filterExpr[T], error) {
+	fieldTok := p.next()
+	if fieldTok.kind != tokenWord {
+		return nil, fmt.Errorf("expected field name but got %s at offset %d", fieldTok, fieldTok.pos)
+	}
+	field, err := indexerForField[T](fieldTok.text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid field %q: %s", fieldTok.text, err)
+	}
+	expr := comparisonExpr[T]{field: field}
+
+	opTok := p.next()
+	switch {
+	case opTok.keyword("NOT"):
+		if tok := p.next(); !tok.keyword("IN") {
+			return nil, fmt.Errorf("expected IN but got %s at offset %d", tok, tok.pos)
+		}
+		expr.negate = true
+		fallthrough
+	case opTok.keyword("IN"):
+		expr.op = opIn
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		if err := expr.setValues(values, p.now); err != nil {
+			return nil, fmt.Errorf("invalid value for field %q: %s", fieldTok.text, err)
+		}
+		return expr, nil
+	case opTok.kind != tokenOperator:
+		return nil, fmt.Errorf("expected comparison operator but got %s at offset %d", opTok, opTok.pos)
+	}
+
+	switch opTok.text {
+	case "=", "==":
+		expr.op = opEq
+	case "!=":
+		expr.op, expr.negate = opEq, true
+	case "<":
+		expr.op = opLt
+	case "<=":
+		expr.op = opLe
+	case ">":
+		expr.op = opGt
+	case ">=":
+		expr.op = opGe
+	case "=~":
+		expr.op = opRegexp
+	case "!~":
+		expr.op, expr.negate = opRegexp, true
+	case "^=":
+		expr.op = opPrefix
+	}
+
+	valueTok := p.next()
+	if valueTok.kind != tokenWord && valueTok.kind != tokenString {
+		return nil, fmt.Errorf("expected value but got %s at offset %d", valueTok, valueTok.pos)
+	}
+	if valueTok.keyword("null") {
+		if expr.op != opEq {
+			return nil, fmt.Errorf("null can only be compared with = or != at offset %d", valueTok.pos)
+		}
+		expr.op = opIsNull
+		return expr, nil
+	}
+	if err := expr.setValues([]filterToken{valueTok}, p.now); err != nil {
+		return nil, fmt.Errorf("invalid value for field %q: %s", fieldTok.text, err)
+	}
+	return expr, nil
+}
+
+func (p *filterParser[T]) parseList() ([]filterToken, error) {
+	if tok := p.next(); tok.kind != tokenLParen {
+		return nil, fmt.Errorf("expected \"(\" but got %s at offset %d", tok, tok.pos)
+	}
+	var values []filterToken
+	for {
+		tok := p.next()
+		if tok.kind != tokenWord && tok.kind != tokenString {
+			return nil, fmt.Errorf("expected value but got %s at offset %d", tok, tok.pos)
+		}
+		values = append(values, tok)
+		switch tok := p.next(); tok.kind {
+		case tokenComma:
+			continue
+		case tokenRParen:
+			return values, nil
+		default:
+			return nil, fmt.Errorf("expected \",\" or \")\" but got %s at offset %d", tok, tok.pos)
+		}
+	}
+}
+
+// setValues converts the value tokens to the type of the field being
+// compared.
+func (e *comparisonExpr[T]) setValues(values []filterToken, now time.Time) error {
+	typ := e.field.typ
+	for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice {
+		typ = typ.Elem()
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		for _, v := range values {
+			e.strings = append(e.strings, v.text)
+		}
+		if e.op == opRegexp {
+			re, err := regexp.Compile(values[0].text)
+			if err != nil {
+				return err
+			}
+			e.re = re
+		}
+		return nil
+	case reflect.Bool:
+		if e.op != opEq && e.op != opIn {
+			return fmt.Errorf("boolean fields can only be compared for equality")
+		}
+		for _, v := range values {
+			b, err := strconv.ParseBool(v.text)
+			if err != nil {
+				return fmt.Errorf("%q is not a boolean", v.text)
+			}
+			e.bools = append(e.bools, b)
+		}
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Float32, reflect.Float64:
+		if e.op == opRegexp || e.op == opPrefix {
+			return fmt.Errorf("numeric fields do not support pattern matching")
+		}
+		for _, v := range values {
+			n, err := parseFilterNumber(v.text, now)
+			if err != nil {
+				return err
+			}
+			e.numbers = append(e.numbers, n)
+		}
+		return nil
+	}
+	return fmt.Errorf("fields of type %s cannot be filtered", e.field.typ)
+}
+
+// parseFilterNumber parses numbers, durations as microseconds, and times
+// relative to now as microseconds since the unix epoch.
+func parseFilterNumber(s string, now time.Time) (float64, error) {
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return n, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return float64(d.Microseconds()), nil
+	}
+	if rel, ok := strings.CutPrefix(strings.ToLower(s), "now"); ok {
+		t := now
+		if rel != "" {
+			d, err := time.ParseDuration(rel)
+			if err != nil {
+				return 0, fmt.Errorf("%q is not a valid relative time", s)
+			}
+			t = t.Add(d)
+		}
+		return float64(t.UnixMicro()), nil
+	}
+	return 0, fmt.Errorf("%q is not a number, duration or relative time", s)
+}
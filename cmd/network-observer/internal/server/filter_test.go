@@ -0,0 +1,138 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
+	"gotest.tools/v3/assert"
+)
+
+func TestFilterExpression(t *testing.T) {
+	now := uint64(time.Now().UnixMicro())
+	records := []api.ConnectionRecord{
+		{
+			Identity:      "conn-1",
+			RoutingKey:    "backend-v1",
+			Protocol:      "tcp",
+			OctetCount:    2_000_000,
+			Latency:       250_000,
+			StartTime:     now - uint64(30*time.Minute/time.Microsecond),
+			ListenerError: ptrTo("connection reset"),
+			TraceSites:    []string{"west", "east"},
+		},
+		{
+			Identity:   "conn-2",
+			RoutingKey: "backend-v2",
+			Protocol:   "tcp",
+			OctetCount: 512,
+			Latency:    10_000,
+			StartTime:  now - uint64(2*time.Hour/time.Microsecond),
+			TraceSites: []string{"west"},
+		},
+		{
+			Identity:       "conn-3",
+			RoutingKey:     "frontend",
+			Protocol:       "http1",
+			OctetCount:     1_000_001,
+			StartTime:      now - uint64(time.Minute/time.Microsecond),
+			ConnectorError: ptrTo("connection refused"),
+		},
+	}
+
+	testcases := []struct {
+		Expr        string
+		ExpectIDs   []string
+		ExpectError string
+	}{
+		{Expr: `protocol = tcp`, ExpectIDs: []string{"conn-1", "conn-2"}},
+		{Expr: `protocol == "http1"`, ExpectIDs: []string{"conn-3"}},
+		{Expr: `protocol != tcp`, ExpectIDs: []string{"conn-3"}},
+		{Expr: `octetCount > 1e6`, ExpectIDs: []string{"conn-1", "conn-3"}},
+		{Expr: `octetCount <= 512`, ExpectIDs: []string{"conn-2"}},
+		{Expr: `latency >= 200ms`, ExpectIDs: []string{"conn-1"}},
+		{Expr: `startTime > now-1h`, ExpectIDs: []string{"conn-1", "conn-3"}},
+		{Expr: `routingKey ^= backend-`, ExpectIDs: []string{"conn-1", "conn-2"}},
+		{Expr: `routingKey =~ "v[0-9]$"`, ExpectIDs: []string{"conn-1", "conn-2"}},
+		{Expr: `routingKey !~ '^back'`, ExpectIDs: []string{"conn-3"}},
+		{Expr: `routingKey IN (frontend, "backend-v2")`, ExpectIDs: []string{"conn-2", "conn-3"}},
+		{Expr: `routingKey not in (frontend)`, ExpectIDs: []string{"conn-1", "conn-2"}},
+		{Expr: `traceSites = east`, ExpectIDs: []string{"conn-1"}},
+		{Expr: `traceSites = null`, ExpectIDs: []string{"conn-3"}},
+		{Expr: `listenerError != null OR connectorError != null`, ExpectIDs: []string{"conn-1", "conn-3"}},
+		{Expr: `connectorError =~ refused`, ExpectIDs: []string{"conn-3"}},
+		{
+			Expr:      `routingKey ^= "backend-" AND (listenerError != null OR connectorError != null) AND startTime > now-1h`,
+			ExpectIDs: []string{"conn-1"},
+		},
+		{Expr: `NOT (protocol = tcp AND octetCount < 1000)`, ExpectIDs: []string{"conn-1", "conn-3"}},
+		{Expr: `protocol = tcp OR protocol = http1 AND octetCount < 10`, ExpectIDs: []string{"conn-1", "conn-2"}},
+		{Expr: ``, ExpectError: "expected field name but got end of expression"},
+		{Expr: `unknownField = 1`, ExpectError: `invalid field "unknownField"`},
+		{Expr: `octetCount > lots`, ExpectError: `"lots" is not a number`},
+		{Expr: `octetCount ^= 10`, ExpectError: "numeric fields do not support pattern matching"},
+		{Expr: `routingKey =~ "["`, ExpectError: "missing closing ]"},
+		{Expr: `routingKey > null`, ExpectError: "null can only be compared with = or !="},
+		{Expr: `(protocol = tcp`, ExpectError: `expected ")" but got end of expression`},
+		{Expr: `protocol = tcp extra`, ExpectError: `unexpected "extra" at offset 15`},
+		{Expr: `routingKey = "unterminated`, ExpectError: "unterminated string at offset 13"},
+		{Expr: `routingKey IN (a b)`, ExpectError: `expected "," or ")" but got "b"`},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.Expr, func(t *testing.T) {
+			expr, err := parseFilterExpression[api.ConnectionRecord](tc.Expr)
+			if tc.ExpectError != "" {
+				assert.ErrorContains(t, err, tc.ExpectError)
+				return
+			}
+			assert.NilError(t, err)
+			var actual []string
+			for _, record := range records {
+				if expr.Eval(record) {
+					actual = append(actual, record.Identity)
+				}
+			}
+			assert.DeepEqual(t, actual, tc.ExpectIDs)
+		})
+	}
+}
+
+func TestFilterExpressionNested(t *testing.T) {
+	type inner struct {
+		Name  string
+		Count *int
+	}
+	type outer struct {
+		Inner   *inner
+		Enabled bool
+	}
+	records := []outer{
+		{Inner: &inner{Name: "a", Count: ptrTo(3)}, Enabled: true},
+		{Inner: &inner{Name: "b"}},
+		{},
+	}
+	testcases := []struct {
+		Expr   string
+		Expect []bool
+	}{
+		{Expr: `inner.name = a`, Expect: []bool{true, false, false}},
+		{Expr: `inner.name != a`, Expect: []bool{false, true, true}},
+		{Expr: `inner.count > 1`, Expect: []bool{true, false, false}},
+		{Expr: `inner.count = null`, Expect: []bool{false, true, true}},
+		{Expr: `enabled = true`, Expect: []bool{true, false, false}},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.Expr, func(t *testing.T) {
+			expr, err := parseFilterExpression[outer](tc.Expr)
+			assert.NilError(t, err)
+			actual := make([]bool, len(records))
+			for i, record := range records {
+				actual[i] = expr.Eval(record)
+			}
+			assert.DeepEqual(t, actual, tc.Expect)
+		})
+	}
+
+	_, err := parseFilterExpression[outer](`enabled > true`)
+	assert.ErrorContains(t, err, "boolean fields can only be compared for equality")
+}
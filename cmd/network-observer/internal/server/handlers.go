@@ -14,6 +14,12 @@ var _ api.ServerInterface = (*server)(nil)
 
 // (GET /api/v2alpha1/connections)
 func (s *server) Connections(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Has("format") {
+		if err := exportConnections(w, r, s.records, exportNDJSON); err != nil {
+			s.logWriteError(r, err)
+		}
+		return
+	}
 	results := views.NewConnectionsSliceProvider(s.records)(listByType[collector.ConnectionRecord](s.records))
 	if err := handleCollection(w, r, &api.ConnectionListResponse{}, results); err != nil {
 		s.logWriteError(r, err)
@@ -32,7 +38,14 @@ func (s *server) ConnectionsByService(w http.ResponseWriter, r *http.Request, id
 	}
 }
 
+// (GET /api/v2alpha1/applicationflows)
 func (s *server) Applicationflows(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Has("format") {
+		if err := exportRequests(w, r, s.records, exportNDJSON); err != nil {
+			s.logWriteError(r, err)
+		}
+		return
+	}
 	results := views.NewRequestSliceProvider(s.records)(listByType[collector.RequestRecord](s.records))
 	if err := handleCollection(w, r, &api.ApplicationFlowResponse{}, results); err != nil {
 		s.logWriteError(r, err)
@@ -0,0 +1,262 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+)
+
+// networkQueryParam is the query parameter that scopes a request to a single
+// network
+const networkQueryParam = "network"
+
+// Network is an application network observed by a collector
+type Network struct {
+	Name    string
+	Records store.Interface
+	Graph   collector.Graph
+	Events  RecordEventSource
+}
+
+// NetworkRouter serves the API for one or more networks. Requests with the
+// network query parameter are served from that network's records. Requests
+// without it are served from the records of every network.
+type NetworkRouter struct {
+	logger    *slog.Logger
+	networks  []Network
+	byNetwork map[string]http.Handler
+	all       *mux.Router
+}
+
+// NewNetworkRouter creates a NetworkRouter, using newRouter to build the API
+// routes for each network and for the union of all networks.
+func NewNetworkRouter(logger *slog.Logger, networks []Network, newRouter func(Network) *mux.Router) (*NetworkRouter, error) {
+	if len(networks) == 0 {
+		return nil, fmt.Errorf("at least one network is required")
+	}
+	r := &NetworkRouter{
+		logger:    logger,
+		networks:  networks,
+		byNetwork: make(map[string]http.Handler, len(networks)),
+	}
+	for _, network := range networks {
+		if _, ok := r.byNetwork[network.Name]; ok {
+			return nil, fmt.Errorf("duplicate network %q", network.Name)
+		}
+		router := newRouter(network)
+		r.byNetwork[network.Name] = router
+		if len(networks) == 1 {
+			r.all = router
+		}
+	}
+	if r.all == nil {
+		r.all = newRouter(unionNetwork(networks))
+	}
+	return r, nil
+}
+
+// Match is a gorilla mux MatcherFunc that matches requests for any of the
+// API routes
+func (r *NetworkRouter) Match(req *http.Request, _ *mux.RouteMatch) bool {
+	var match mux.RouteMatch
+	return r.all.Match(req, &match)
+}
+
+func (r *NetworkRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	names, ok := query[networkQueryParam]
+	if !ok {
+		r.all.ServeHTTP(w, withNetworkResolver(req, r.networkOf))
+		return
+	}
+	if len(names) != 1 {
+		if err := encodeResponse(w, http.StatusBadRequest, api.ErrorBadRequest{
+			Message: "only one network query parameter may be specified",
+		}); err != nil {
+			requestLogger(r.logger, req).Error("failed to write response", slog.Any("error", err))
+		}
+		return
+	}
+	handler, ok := r.byNetwork[names[0]]
+	if !ok {
+		if err := encodeResponse(w, http.StatusNotFound, api.ErrorNotFound{
+			Code:    "ErrNotFound",
+			Message: fmt.Sprintf("unknown network %q", names[0]),
+		}); err != nil {
+			requestLogger(r.logger, req).Error("failed to write response", slog.Any("error", err))
+		}
+		return
+	}
+	query.Del(networkQueryParam)
+	name := names[0]
+	scoped := withNetworkResolver(req, func(string) (string, bool) { return name, true })
+	scoped.URL.RawQuery = query.Encode()
+	handler.ServeHTTP(w, scoped)
+}
+
+// networkOf returns the name of the network that observed the record with
+// the given identity
+func (r *NetworkRouter) networkOf(identity string) (string, bool) {
+	if len(r.networks) == 1 {
+		return r.networks[0].Name, true
+	}
+	for _, network := range r.networks {
+		if _, ok := network.Records.Get(identity); ok {
+			return network.Name, true
+		}
+	}
+	return "", false
+}
+
+type networkKey struct{}
+
+// networkResolver returns the name of the network that observed the record
+// with the given identity
+type networkResolver func(identity string) (string, bool)
+
+// withNetworkResolver returns a copy of the request carrying the resolver
+// used to tag the records in its response with their network
+func withNetworkResolver(req *http.Request, resolve networkResolver) *http.Request {
+	return req.Clone(context.WithValue(req.Context(), networkKey{}, resolve))
+}
+
+// tagNetworks sets the network of each of the records when it can be
+// determined
+func tagNetworks[T any](r *http.Request, records []T) {
+	for i := range records {
+		tagNetwork(r, &records[i])
+	}
+}
+
+// tagNetwork sets the network of the record pointed to when it can be
+// determined
+func tagNetwork(r *http.Request, record any) {
+	resolve, ok := r.Context().Value(networkKey{}).(networkResolver)
+	if !ok {
+		return
+	}
+	tagged, ok := record.(api.NetworkRecord)
+	if !ok {
+		return
+	}
+	if name, ok := resolve(tagged.GetIdentity()); ok {
+		tagged.SetNetwork(&name)
+	}
+}
+
+type networkSummary struct {
+	Name        string `json:"name"`
+	SiteCount   int    `json:"siteCount"`
+	RouterCount int    `json:"routerCount"`
+}
+
+type networkListResponse struct {
+	Results []networkSummary `json:"results"`
+	Count   int              `json:"count"`
+}
+
+// NetworksHandler returns a handler that lists the networks served by the
+// router.
+//
+// (GET /api/v2alpha1/networks)
+func (r *NetworkRouter) NetworksHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		response := networkListResponse{Results: make([]networkSummary, 0, len(r.networks))}
+		for _, network := range r.networks {
+			response.Results = append(response.Results, networkSummary{
+				Name:        network.Name,
+				SiteCount:   len(listByType[vanflow.SiteRecord](network.Records)),
+				RouterCount: len(listByType[vanflow.RouterRecord](network.Records)),
+			})
+		}
+		response.Count = len(response.Results)
+		if err := encodeResponse(w, http.StatusOK, response); err != nil {
+			requestLogger(r.logger, req).Error("failed to write response", slog.Any("error", err))
+		}
+	})
+}
+
+// unionNetwork combines the records, graphs and events of several networks
+func unionNetwork(networks []Network) Network {
+	var (
+		stores []store.Interface
+		graphs []collector.Graph
+		events unionEvents
+	)
+	for _, network := range networks {
+		stores = append(stores, network.Records)
+		graphs = append(graphs, network.Graph)
+		events = append(events, network.Events)
+	}
+	return Network{
+		Records: store.NewUnion(stores...),
+		Graph:   collector.NewUnionGraph(graphs[0], graphs[1:]...),
+		Events:  events,
+	}
+}
+
+// unionEvents merges the record events of several networks into a single
+// subscription. The subscription ends when any of the underlying
+// subscriptions end or when the subscriber falls more than buffer events
+// behind.
+type unionEvents []RecordEventSource
+
+func (u unionEvents) Subscribe(buffer int) (<-chan collector.RecordEvent, func()) {
+	var (
+		out     = make(chan collector.RecordEvent, buffer)
+		done    = make(chan struct{})
+		once    sync.Once
+		wg      sync.WaitGroup
+		cancels = make([]func(), 0, len(u))
+		sources = make([]<-chan collector.RecordEvent, 0, len(u))
+	)
+	for _, source := range u {
+		events, cancel := source.Subscribe(buffer)
+		sources = append(sources, events)
+		cancels = append(cancels, cancel)
+	}
+	stop := func() {
+		once.Do(func() {
+			close(done)
+			for _, cancel := range cancels {
+				cancel()
+			}
+		})
+	}
+	for _, events := range sources {
+		wg.Add(1)
+		go func(events <-chan collector.RecordEvent) {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				case event, ok := <-events:
+					if !ok {
+						stop()
+						return
+					}
+					select {
+					case out <- event:
+					default:
+						stop()
+						return
+					}
+				}
+			}
+		}(events)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out, stop
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+	"gotest.tools/v3/assert"
+)
+
+func TestNetworkRouter(t *testing.T) {
+	var networks []Network
+	for name, sites := range map[string][]string{
+		"east": {"site-east-1", "site-east-2"},
+		"west": {"site-west-1"},
+	} {
+		stor := store.NewSyncMapStore(store.SyncMapStoreConfig{})
+		var records []vanflow.Record
+		for _, site := range sites {
+			records = append(records, vanflow.SiteRecord{BaseRecord: vanflow.NewBase(site), Name: ptrTo(site)})
+		}
+		stor.Replace(wrapRecords(records...))
+		graph := collector.NewGraph(stor)
+		graph.(reset).Reset()
+		networks = append(networks, Network{Name: name, Records: stor, Graph: graph})
+	}
+	sort.Slice(networks, func(i, j int) bool { return networks[i].Name < networks[j].Name })
+
+	router, err := NewNetworkRouter(slog.Default(), networks, func(network Network) *mux.Router {
+		r := mux.NewRouter()
+		api.HandlerWithOptions(New(slog.Default(), network.Records, network.Graph), api.GorillaServerOptions{BaseRouter: r})
+		return r
+	})
+	assert.NilError(t, err)
+	root := mux.NewRouter()
+	root.Path("/api/v2alpha1/networks").Handler(router.NetworksHandler())
+	root.MatcherFunc(router.Match).Handler(router)
+	srv := httptest.NewServer(root)
+	defer srv.Close()
+
+	testcases := []struct {
+		Query       string
+		ExpectCode  int
+		ExpectSites []string
+	}{
+		{ExpectCode: http.StatusOK, ExpectSites: []string{"east/site-east-1", "east/site-east-2", "west/site-west-1"}},
+		{Query: "?network=east", ExpectCode: http.StatusOK, ExpectSites: []string{"east/site-east-1", "east/site-east-2"}},
+		{Query: "?network=west&sortBy=name.desc", ExpectCode: http.StatusOK, ExpectSites: []string{"west/site-west-1"}},
+		{Query: "?network=north", ExpectCode: http.StatusNotFound},
+		{Query: "?network=east&network=west", ExpectCode: http.StatusBadRequest},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.Query, func(t *testing.T) {
+			resp, err := http.Get(srv.URL + "/api/v2alpha1/sites" + tc.Query)
+			assert.NilError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, resp.StatusCode, tc.ExpectCode)
+			if tc.ExpectCode != http.StatusOK {
+				return
+			}
+			var body api.SiteListResponse
+			assert.NilError(t, json.NewDecoder(resp.Body).Decode(&body))
+			var actual []string
+			for _, site := range body.Results {
+				assert.Assert(t, site.Network != nil)
+				actual = append(actual, *site.Network+"/"+site.Identity)
+			}
+			sort.Strings(actual)
+			assert.DeepEqual(t, actual, tc.ExpectSites)
+		})
+	}
+
+	t.Run("single record", func(t *testing.T) {
+		for _, query := range []string{"", "?network=west"} {
+			resp, err := http.Get(srv.URL + "/api/v2alpha1/sites/site-west-1" + query)
+			assert.NilError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, resp.StatusCode, http.StatusOK)
+			var body api.SiteResponse
+			assert.NilError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.DeepEqual(t, body.Results.Network, ptrTo("west"))
+		}
+	})
+
+	t.Run("unknown route", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/api/v2alpha1/unknown?network=east")
+		assert.NilError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, resp.StatusCode, http.StatusNotFound)
+	})
+
+	t.Run("networks", func(t *testing.T) {
+		resp, err := http.Get(srv.URL + "/api/v2alpha1/networks")
+		assert.NilError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, resp.StatusCode, http.StatusOK)
+		var body networkListResponse
+		assert.NilError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.DeepEqual(t, body, networkListResponse{
+			Count: 2,
+			Results: []networkSummary{
+				{Name: "east", SiteCount: 2},
+				{Name: "west", SiteCount: 1},
+			},
+		})
+	})
+}
+
+func TestNetworkRouterInvalid(t *testing.T) {
+	newRouter := func(Network) *mux.Router { return mux.NewRouter() }
+	_, err := NewNetworkRouter(slog.Default(), nil, newRouter)
+	assert.ErrorContains(t, err, "at least one network")
+	stor := store.NewSyncMapStore(store.SyncMapStoreConfig{})
+	_, err = NewNetworkRouter(slog.Default(), []Network{
+		{Name: "east", Records: stor, Graph: collector.NewGraph(stor)},
+		{Name: "east", Records: stor, Graph: collector.NewGraph(stor)},
+	}, newRouter)
+	assert.ErrorContains(t, err, "duplicate network")
+}
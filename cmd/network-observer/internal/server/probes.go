@@ -0,0 +1,69 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/probes"
+)
+
+// ProbeSource provides the results of reachability probes
+type ProbeSource interface {
+	Results() []probes.Result
+}
+
+type probesResponse struct {
+	Results []probes.Result `json:"results"`
+}
+
+// NewProbesHandler returns a handler that reports the most recent result of
+// each reachability probe. The target and success query parameters filter
+// the results returned.
+//
+// (GET /api/v2alpha1/probes)
+func NewProbesHandler(logger *slog.Logger, source ProbeSource) http.Handler {
+	return &probesHandler{
+		logger: logger,
+		source: source,
+	}
+}
+
+type probesHandler struct {
+	logger *slog.Logger
+	source ProbeSource
+}
+
+func (h *probesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var success *bool
+	if raw := query.Get("success"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			if err := encodeResponse(w, http.StatusBadRequest, api.ErrorBadRequest{
+				Message: fmt.Sprintf("invalid success %q: must be true or false", raw),
+			}); err != nil {
+				requestLogger(h.logger, r).Error("failed to write response", slog.Any("error", err))
+			}
+			return
+		}
+		success = &parsed
+	}
+	target := query.Get("target")
+
+	response := probesResponse{Results: []probes.Result{}}
+	for _, result := range h.source.Results() {
+		if target != "" && result.Target != target {
+			continue
+		}
+		if success != nil && result.Success != *success {
+			continue
+		}
+		response.Results = append(response.Results, result)
+	}
+	if err := encodeResponse(w, http.StatusOK, response); err != nil {
+		requestLogger(h.logger, r).Error("failed to write response", slog.Any("error", err))
+	}
+}
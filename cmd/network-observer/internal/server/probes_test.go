@@ -0,0 +1,55 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/probes"
+	"gotest.tools/v3/assert"
+)
+
+type staticProbes []probes.Result
+
+func (s staticProbes) Results() []probes.Result { return s }
+
+func TestProbes(t *testing.T) {
+	srv := httptest.NewServer(NewProbesHandler(slog.Default(), staticProbes{
+		{Target: "backend", Address: "backend:8080", Success: true},
+		{Target: "backend", Address: "10.0.0.1:8080", FailureReason: probes.ReasonRefused},
+		{Target: "db", Address: "db:5432", FailureReason: probes.ReasonTimeout},
+	}))
+	defer srv.Close()
+
+	testcases := []struct {
+		Query         string
+		ExpectStatus  int
+		ExpectResults []string
+	}{
+		{ExpectStatus: http.StatusOK, ExpectResults: []string{"backend:8080", "10.0.0.1:8080", "db:5432"}},
+		{Query: "?target=backend", ExpectStatus: http.StatusOK, ExpectResults: []string{"backend:8080", "10.0.0.1:8080"}},
+		{Query: "?success=false", ExpectStatus: http.StatusOK, ExpectResults: []string{"10.0.0.1:8080", "db:5432"}},
+		{Query: "?target=db&success=true", ExpectStatus: http.StatusOK, ExpectResults: nil},
+		{Query: "?success=maybe", ExpectStatus: http.StatusBadRequest},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.Query, func(t *testing.T) {
+			resp, err := http.Get(srv.URL + tc.Query)
+			assert.NilError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, resp.StatusCode, tc.ExpectStatus)
+			if tc.ExpectStatus != http.StatusOK {
+				return
+			}
+			var body probesResponse
+			assert.NilError(t, json.NewDecoder(resp.Body).Decode(&body))
+			var actual []string
+			for _, result := range body.Results {
+				actual = append(actual, result.Address)
+			}
+			assert.DeepEqual(t, actual, tc.ExpectResults)
+		})
+	}
+}
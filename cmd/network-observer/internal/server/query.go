@@ -23,23 +23,13 @@ func filterAndOrderResults[T api.Record](r *http.Request, results []T) ([]T, int
 
 	qp := getQueryParams(r)
 
-	filterFields := make(map[string]fieldIndex[T], len(qp.FilterFields))
-	for path := range qp.FilterFields {
-		m, err := indexerForField[T](path)
-		if err != nil {
-			return nil, 0, fmt.Errorf("invalid filter parameter %q for record type %T", path, results)
-		}
-		filterFields[path] = m
+	filter, err := newRecordFilter[T](qp)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	for i, item := range results {
-		matches := true
-		for path, values := range qp.FilterFields {
-			if !filterFields[path].MatchesFilter(item, values) {
-				matches = false
-				break
-			}
-		}
+		matches := filter.Matches(item)
 		switch {
 		case matches && !isCopy:
 			continue
@@ -82,12 +72,75 @@ func filterAndOrderResults[T api.Record](r *http.Request, results []T) ([]T, int
 	return out, timeRangeCount, nil
 }
 
+// recordFilter matches records against the field filters and filter
+// expression in a request's query parameters.
+type recordFilter[T any] struct {
+	fields map[string]fieldIndex[T]
+	values map[string][]string
+	expr   filterExpr[T]
+}
+
+func newRecordFilter[T any](qp queryParams) (recordFilter[T], error) {
+	filter := recordFilter[T]{
+		fields: make(map[string]fieldIndex[T], len(qp.FilterFields)),
+		values: qp.FilterFields,
+	}
+	for path := range qp.FilterFields {
+		m, err := indexerForField[T](path)
+		if err != nil {
+			return filter, fmt.Errorf("invalid filter parameter %q for record type %T", path, []T(nil))
+		}
+		filter.fields[path] = m
+	}
+	if qp.Filter != "" {
+		expr, err := parseFilterExpression[T](qp.Filter)
+		if err != nil {
+			return filter, fmt.Errorf("invalid filter expression: %s", err)
+		}
+		filter.expr = expr
+	}
+	return filter, nil
+}
+
+func (f recordFilter[T]) Matches(item T) bool {
+	for path, values := range f.values {
+		if !f.fields[path].MatchesFilter(item, values) {
+			return false
+		}
+	}
+	if f.expr != nil && !f.expr.Eval(item) {
+		return false
+	}
+	return true
+}
+
 func filterTime[T api.Record](all []T, state timeRangeState, op timeRangeRelation, rangeStart, rangeEnd uint64) []T {
 	var (
-		out    = all
-		isCopy bool
+		out          = all
+		isCopy       bool
+		shouldFilter = timeRangeFilter(state, op, rangeStart, rangeEnd)
 	)
 
+	for i, record := range all {
+		toRemove := shouldFilter(record)
+		switch {
+		case !toRemove && !isCopy:
+			continue
+		case isCopy && !toRemove:
+			out = append(out, record)
+		case toRemove && !isCopy:
+			isCopy = true
+			out = make([]T, 0, len(all)-1)
+			out = append(out, all[:i]...)
+		}
+	}
+
+	return out
+}
+
+// timeRangeFilter returns a function that returns true for records outside
+// of the time range and state
+func timeRangeFilter(state timeRangeState, op timeRangeRelation, rangeStart, rangeEnd uint64) func(api.Record) bool {
 	shouldFilterOp := func(t api.Record) bool { return false }
 	switch op {
 	case intersects:
@@ -123,31 +176,35 @@ func filterTime[T api.Record](all []T, state timeRangeState, op timeRangeRelatio
 			return shouldFilterOp(record)
 		}
 	}
-
-	for i, record := range all {
-		toRemove := shouldFilter(record)
-		switch {
-		case !toRemove && !isCopy:
-			continue
-		case isCopy && !toRemove:
-			out = append(out, record)
-		case toRemove && !isCopy:
-			isCopy = true
-			out = make([]T, 0, len(all)-1)
-			out = append(out, all[:i]...)
-		}
-	}
-
-	return out
+	return shouldFilter
 }
 
 type fieldIndex[T any] struct {
 	index []int
+	typ   reflect.Type
+}
+
+// value returns the indexed field of item. Returns false when the field is
+// nested within a nil pointer.
+func (m fieldIndex[T]) value(item T) (reflect.Value, bool) {
+	val, err := reflect.ValueOf(item).FieldByIndexErr(m.index)
+	if err != nil {
+		return val, false
+	}
+	return val, true
 }
 
 func (m fieldIndex[T]) Compare(x, y T) int {
-	vx := reflect.ValueOf(x).FieldByIndex(m.index)
-	vy := reflect.ValueOf(y).FieldByIndex(m.index)
+	vx, okx := m.value(x)
+	vy, oky := m.value(y)
+	switch {
+	case !okx && !oky:
+		return 0
+	case okx && !oky:
+		return 1
+	case !okx && oky:
+		return -1
+	}
 	if vx.Kind() == reflect.Pointer {
 		switch {
 		case vx.IsNil() && vy.IsNil():
@@ -236,7 +293,10 @@ func (m fieldIndex[T]) matches(val reflect.Value, values []string) bool {
 }
 
 func (m fieldIndex[T]) MatchesFilter(e T, values []string) bool {
-	val := reflect.ValueOf(e).FieldByIndex(m.index)
+	val, ok := m.value(e)
+	if !ok {
+		return false
+	}
 	// pre-checks
 	if val.Kind() == reflect.Pointer {
 		if val.IsNil() {
@@ -270,6 +330,9 @@ func indexerForField[T any](fieldPath string) (fieldIndex[T], error) {
 	example := (*T)(nil)
 	typ := reflect.TypeOf(example).Elem()
 	for fieldNames := parts; len(fieldNames) > 0; fieldNames = fieldNames[1:] {
+		if typ.Kind() == reflect.Pointer && len(indexer.index) > 0 {
+			typ = typ.Elem()
+		}
 		if typ.Kind() != reflect.Struct {
 			return indexer, fmt.Errorf("cannot reference field %q on type %s: not a struct", fieldNames[0], typ.String())
 		}
@@ -280,6 +343,7 @@ func indexerForField[T any](fieldPath string) (fieldIndex[T], error) {
 		indexer.index = append(indexer.index, sf.Index...)
 		typ = sf.Type
 	}
+	indexer.typ = typ
 	return indexer, nil
 }
 
@@ -289,6 +353,8 @@ type queryParams struct {
 	SortField          string
 	SortDescending     bool
 	FilterFields       map[string][]string
+	Filter             string
+	Format             string
 	TimeRangeStart     uint64
 	TimeRangeEnd       uint64
 	TimeRangeOperation timeRangeRelation
@@ -370,6 +436,11 @@ func getQueryParams(r *http.Request) queryParams {
 			default:
 				qp.State = all
 			}
+		case "filter":
+			qp.Filter = v[0]
+		case "format":
+			// selects the export format rather than filtering
+			qp.Format = v[0]
 		default:
 			qp.FilterFields[cases.Title(language.Und, cases.NoLower).String(k)] = v
 		}
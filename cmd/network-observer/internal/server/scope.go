@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/auth"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+)
+
+type visibilityKey struct{}
+
+// NewScopeMiddleware returns middleware that limits the records returned by
+// the list and by-id endpoints to those within the auth.Scope of the
+// request. Requests without a scope or with an unrestricted scope are not
+// filtered.
+func NewScopeMiddleware(records store.Interface) api.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope, ok := auth.ScopeFromContext(r.Context())
+			if !ok || scope.Unrestricted() {
+				next.ServeHTTP(w, r)
+				return
+			}
+			v := &visibility{}
+			for _, roleScope := range scope.RoleScopes() {
+				v.roles = append(v.roles, &roleVisibility{scope: roleScope, records: records})
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), visibilityKey{}, v)))
+		})
+	}
+}
+
+// visibleRecords returns the records visible to the request
+func visibleRecords[T any](r *http.Request, records []T) []T {
+	v, ok := r.Context().Value(visibilityKey{}).(*visibility)
+	if !ok {
+		return records
+	}
+	out := make([]T, 0, len(records))
+	for _, record := range records {
+		if v.allows(record) {
+			out = append(out, record)
+		}
+	}
+	return out
+}
+
+// isVisible returns true when the record is visible to the request
+func isVisible(r *http.Request, record any) bool {
+	v, ok := r.Context().Value(visibilityKey{}).(*visibility)
+	return !ok || v.allows(record)
+}
+
+// visibility resolves whether api records are within a scope. A record is
+// within the scope when it is within the scope of one of its roles taken
+// on its own, so that the site restrictions of one role are never paired
+// with the routing key restrictions of another.
+type visibility struct {
+	roles []*roleVisibility
+}
+
+func (v *visibility) allows(record any) bool {
+	for _, role := range v.roles {
+		if role.allows(record) {
+			return true
+		}
+	}
+	return false
+}
+
+// roleVisibility resolves whether api records are within the scope of a
+// single role. Sites and components are resolved against the store once
+// per request as they are needed.
+type roleVisibility struct {
+	scope      auth.Scope
+	records    store.Interface
+	sites      map[string]bool
+	components map[string]bool
+}
+
+func (v *roleVisibility) allows(record any) bool {
+	switch record := record.(type) {
+	case api.SiteRecord:
+		return v.site(record.Identity)
+	case api.RouterRecord:
+		return v.site(record.SiteId)
+	case api.ProcessRecord:
+		return v.site(record.SiteId)
+	case api.ListenerRecord:
+		return v.site(record.SiteId) && v.scope.AllowRoutingKey(record.RoutingKey)
+	case api.ConnectorRecord:
+		return v.site(record.SiteId) && v.scope.AllowRoutingKey(record.RoutingKey)
+	case api.ConnectionRecord:
+		return (v.site(record.SourceSiteId) || v.site(record.DestSiteId)) && v.scope.AllowRoutingKey(record.RoutingKey)
+	case api.ApplicationFlowRecord:
+		return (v.site(record.SourceSiteId) || v.site(record.DestSiteId)) && v.scope.AllowRoutingKey(record.RoutingKey)
+	case api.ServiceRecord:
+		return v.scope.AllowRoutingKey(record.Name)
+	case api.RouterLinkRecord:
+		return v.site(record.SourceSiteId) || (record.DestinationSiteId != nil && v.site(*record.DestinationSiteId))
+	case api.RouterAccessRecord:
+		return v.router(record.RouterId)
+	case api.ComponentRecord:
+		return v.component(record.Name)
+	case api.FlowAggregateRecord:
+		switch record.PairType {
+		case api.SITE:
+			return v.site(record.SourceId) || v.site(record.DestinationId)
+		case api.PROCESSGROUP:
+			return v.component(record.SourceName) || v.component(record.DestinationName)
+		default:
+			return (record.SourceSiteId != nil && v.site(*record.SourceSiteId)) ||
+				(record.DestinationSiteId != nil && v.site(*record.DestinationSiteId))
+		}
+	default:
+		return false
+	}
+}
+
+func (v *roleVisibility) site(id string) bool {
+	if v.sites == nil {
+		v.sites = make(map[string]bool)
+		for _, entry := range v.records.Index(store.TypeIndex, store.Entry{Record: vanflow.SiteRecord{}}) {
+			site := entry.Record.(vanflow.SiteRecord)
+			v.sites[site.ID] = v.scope.AllowSite(site.ID, deref(site.Name), deref(site.Namespace))
+		}
+	}
+	return v.sites[id]
+}
+
+func (v *roleVisibility) router(id string) bool {
+	entry, ok := v.records.Get(id)
+	if !ok {
+		return false
+	}
+	router, ok := entry.Record.(vanflow.RouterRecord)
+	return ok && router.Parent != nil && v.site(*router.Parent)
+}
+
+// component returns true when any process in the named component belongs
+// to a visible site
+func (v *roleVisibility) component(name string) bool {
+	if v.components == nil {
+		v.components = make(map[string]bool)
+		for _, entry := range v.records.Index(store.TypeIndex, store.Entry{Record: vanflow.ProcessRecord{}}) {
+			process := entry.Record.(vanflow.ProcessRecord)
+			if process.Group == nil || process.Parent == nil {
+				continue
+			}
+			if v.site(*process.Parent) {
+				v.components[*process.Group] = true
+			}
+		}
+	}
+	return v.components[name]
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/auth"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+	"gotest.tools/v3/assert"
+)
+
+func TestScopeMiddleware(t *testing.T) {
+	stor := store.NewSyncMapStore(store.SyncMapStoreConfig{})
+	graph := collector.NewGraph(stor)
+	stor.Replace(wrapRecords(
+		vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1"), Name: ptrTo("west"), Namespace: ptrTo("payments")},
+		vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-2"), Name: ptrTo("east"), Namespace: ptrTo("inventory")},
+		vanflow.RouterRecord{BaseRecord: vanflow.NewBase("router-1"), Parent: ptrTo("site-1")},
+		vanflow.RouterRecord{BaseRecord: vanflow.NewBase("router-2"), Parent: ptrTo("site-2")},
+		vanflow.RouterAccessRecord{BaseRecord: vanflow.NewBase("access-1"), Parent: ptrTo("router-1")},
+		vanflow.RouterAccessRecord{BaseRecord: vanflow.NewBase("access-2"), Parent: ptrTo("router-2")},
+		vanflow.ProcessRecord{BaseRecord: vanflow.NewBase("process-1"), Parent: ptrTo("site-1"), Group: ptrTo("frontend")},
+		vanflow.ProcessRecord{BaseRecord: vanflow.NewBase("process-2"), Parent: ptrTo("site-2"), Group: ptrTo("backend")},
+		vanflow.ListenerRecord{BaseRecord: vanflow.NewBase("listener-1"), Parent: ptrTo("router-1"), Address: ptrTo("payments-api")},
+		vanflow.ListenerRecord{BaseRecord: vanflow.NewBase("listener-2"), Parent: ptrTo("router-1"), Address: ptrTo("inventory-api")},
+		vanflow.ListenerRecord{BaseRecord: vanflow.NewBase("listener-3"), Parent: ptrTo("router-2"), Address: ptrTo("payments-api")},
+		vanflow.ListenerRecord{BaseRecord: vanflow.NewBase("listener-4"), Parent: ptrTo("router-2"), Address: ptrTo("inventory-api")},
+		collector.ProcessGroupRecord{ID: "component-1", Name: "frontend", Start: time.Now()},
+		collector.ProcessGroupRecord{ID: "component-2", Name: "backend", Start: time.Now()},
+	))
+	graph.(reset).Reset()
+
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "tokens.csv")
+	assert.NilError(t, os.WriteFile(tokenFile, []byte("admin-token,admin\npayments-token,bob\nsplit-token,carol\n"), 0o600))
+	authenticator, err := auth.New(slog.Default(), auth.Config{
+		TokenFile: tokenFile,
+		Roles: []auth.Role{
+			{Name: "admin"},
+			{Name: "payments", Namespaces: []string{"payments"}, RoutingKeys: []string{"payments-*"}},
+			{Name: "west-payments", Sites: []string{"west"}, RoutingKeys: []string{"payments-*"}},
+			{Name: "east-inventory", Sites: []string{"east"}, RoutingKeys: []string{"inventory-*"}},
+		},
+		Bindings: []auth.Binding{
+			{Role: "admin", Users: []string{"admin"}},
+			{Role: "payments", Users: []string{"bob"}},
+			{Role: "west-payments", Users: []string{"carol"}},
+			{Role: "east-inventory", Users: []string{"carol"}},
+		},
+	})
+	assert.NilError(t, err)
+	router := mux.NewRouter()
+	api.HandlerWithOptions(New(slog.Default(), stor, graph), api.GorillaServerOptions{
+		BaseRouter:  router,
+		Middlewares: []api.MiddlewareFunc{NewScopeMiddleware(stor)},
+	})
+	srv := httptest.NewServer(authenticator.Middleware(router))
+	defer srv.Close()
+
+	get := func(t *testing.T, token string, path string) (int, []string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		assert.NilError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		assert.NilError(t, err)
+		defer resp.Body.Close()
+		var body struct {
+			Results json.RawMessage `json:"results"`
+		}
+		assert.NilError(t, json.NewDecoder(resp.Body).Decode(&body))
+		if len(body.Results) == 0 {
+			return resp.StatusCode, nil
+		}
+		var results []api.BaseRecord
+		if err := json.Unmarshal(body.Results, &results); err != nil {
+			var result api.BaseRecord
+			assert.NilError(t, json.Unmarshal(body.Results, &result))
+			results = append(results, result)
+		}
+		var ids []string
+		for _, result := range results {
+			ids = append(ids, result.Identity)
+		}
+		sort.Strings(ids)
+		return resp.StatusCode, ids
+	}
+
+	testcases := []struct {
+		Path      string
+		Token     string
+		ExpectIDs []string
+	}{
+		{Path: "/api/v2alpha1/sites", Token: "admin-token", ExpectIDs: []string{"site-1", "site-2"}},
+		{Path: "/api/v2alpha1/sites", Token: "payments-token", ExpectIDs: []string{"site-1"}},
+		{Path: "/api/v2alpha1/routers", Token: "payments-token", ExpectIDs: []string{"router-1"}},
+		{Path: "/api/v2alpha1/routeraccess", Token: "payments-token", ExpectIDs: []string{"access-1"}},
+		{Path: "/api/v2alpha1/processes", Token: "payments-token", ExpectIDs: []string{"process-1"}},
+		{Path: "/api/v2alpha1/components", Token: "payments-token", ExpectIDs: []string{"component-1"}},
+		{Path: "/api/v2alpha1/listeners", Token: "admin-token", ExpectIDs: []string{"listener-1", "listener-2", "listener-3", "listener-4"}},
+		{Path: "/api/v2alpha1/listeners", Token: "payments-token", ExpectIDs: []string{"listener-1"}},
+		// each role grants one routing key on one site; the routing key of
+		// one role must not be visible on the site of the other
+		{Path: "/api/v2alpha1/sites", Token: "split-token", ExpectIDs: []string{"site-1", "site-2"}},
+		{Path: "/api/v2alpha1/listeners", Token: "split-token", ExpectIDs: []string{"listener-1", "listener-4"}},
+		{Path: "/api/v2alpha1/sites/site-2/processes", Token: "payments-token", ExpectIDs: nil},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.Token+tc.Path, func(t *testing.T) {
+			status, ids := get(t, tc.Token, tc.Path)
+			assert.Equal(t, status, http.StatusOK)
+			assert.DeepEqual(t, ids, tc.ExpectIDs)
+		})
+	}
+
+	t.Run("by id", func(t *testing.T) {
+		status, ids := get(t, "payments-token", "/api/v2alpha1/sites/site-1")
+		assert.Equal(t, status, http.StatusOK)
+		assert.DeepEqual(t, ids, []string{"site-1"})
+		status, _ = get(t, "payments-token", "/api/v2alpha1/sites/site-2")
+		assert.Equal(t, status, http.StatusNotFound)
+		status, _ = get(t, "payments-token", "/api/v2alpha1/listeners/listener-2")
+		assert.Equal(t, status, http.StatusNotFound)
+		status, _ = get(t, "split-token", "/api/v2alpha1/listeners/listener-2")
+		assert.Equal(t, status, http.StatusNotFound)
+		status, _ = get(t, "split-token", "/api/v2alpha1/listeners/listener-3")
+		assert.Equal(t, status, http.StatusNotFound)
+		status, _ = get(t, "admin-token", "/api/v2alpha1/sites/site-2")
+		assert.Equal(t, status, http.StatusOK)
+	})
+}
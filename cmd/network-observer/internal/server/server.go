@@ -35,13 +35,14 @@ func handleCollection[T api.Record](w http.ResponseWriter, r *http.Request, resp
 		out    any = response
 		status     = http.StatusOK
 	)
-	records, count, err := filterAndOrderResults(r, records)
+	records, count, err := filterAndOrderResults(r, visibleRecords(r, records))
 	if err != nil {
 		status = http.StatusBadRequest
 		out = api.ErrorBadRequest{
 			Message: err.Error(),
 		}
 	}
+	tagNetworks(r, records)
 	response.SetResults(records)
 	response.SetCount(int64(len(records)))
 	response.SetTimeRangeCount(count)
@@ -58,7 +59,7 @@ func handleSubCollection[T api.Record](w http.ResponseWriter, r *http.Request, r
 	)
 
 	if item, ok := getExemplar(); ok {
-		records := indexFunc(item)
+		records := visibleRecords(r, indexFunc(item))
 		records, count, err := filterAndOrderResults(r, records)
 		if err != nil {
 			status = http.StatusBadRequest
@@ -66,6 +67,7 @@ func handleSubCollection[T api.Record](w http.ResponseWriter, r *http.Request, r
 				Message: err.Error(),
 			}
 		}
+		tagNetworks(r, records)
 		response.SetResults(records)
 		response.SetCount(int64(len(records)))
 		response.SetTimeRangeCount(count)
@@ -80,13 +82,14 @@ func handleSubCollection[T api.Record](w http.ResponseWriter, r *http.Request, r
 	}
 	return nil
 }
-func handleSingle[T any](w http.ResponseWriter, _ *http.Request, response api.ResponseSetter[T], getter func() (T, bool)) error {
+func handleSingle[T any](w http.ResponseWriter, r *http.Request, response api.ResponseSetter[T], getter func() (T, bool)) error {
 	var (
 		out    any = response
 		status     = http.StatusOK
 	)
 
-	if record, ok := getter(); ok {
+	if record, ok := getter(); ok && isVisible(r, record) {
+		tagNetwork(r, &record)
 		response.SetResults(record)
 	} else {
 		status = http.StatusNotFound
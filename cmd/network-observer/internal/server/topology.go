@@ -0,0 +1,555 @@
+package server
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+)
+
+const (
+	topologyFormatJSON    = "json"
+	topologyFormatDOT     = "dot"
+	topologyFormatGraphML = "graphml"
+
+	unknownStr = "unknown"
+)
+
+// NewTopologyHandler returns a handler that exports the application network
+// topology as a graph of nodes and edges. The format query parameter
+// selects between json (the default), dot and graphml encodings.
+//
+// (GET /api/v2alpha1/topology)
+func NewTopologyHandler(logger *slog.Logger, records store.Interface, graph collector.Graph) http.Handler {
+	return &topologyHandler{
+		logger:  logger,
+		records: records,
+		graph:   graph,
+	}
+}
+
+type topologyHandler struct {
+	logger  *slog.Logger
+	records store.Interface
+	graph   collector.Graph
+}
+
+func (h *topologyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = topologyFormatJSON
+	}
+	var err error
+	switch format {
+	case topologyFormatJSON:
+		err = encodeResponse(w, http.StatusOK, buildTopology(h.records, h.graph))
+	case topologyFormatDOT:
+		w.Header().Set("Content-Type", "text/vnd.graphviz")
+		w.WriteHeader(http.StatusOK)
+		err = writeTopologyDOT(w, buildTopology(h.records, h.graph))
+	case topologyFormatGraphML:
+		w.Header().Set("Content-Type", "application/graphml+xml")
+		w.WriteHeader(http.StatusOK)
+		err = writeTopologyGraphML(w, buildTopology(h.records, h.graph))
+	default:
+		err = encodeResponse(w, http.StatusBadRequest, api.ErrorBadRequest{
+			Message: fmt.Sprintf("unsupported topology format %q: must be one of json, dot or graphml", format),
+		})
+	}
+	if err != nil {
+		requestLogger(h.logger, r).Error("failed to write response", slog.Any("error", err))
+	}
+}
+
+const (
+	topologyNodeSite         = "site"
+	topologyNodeRouter       = "router"
+	topologyNodeRouterAccess = "routeraccess"
+	topologyNodeListener     = "listener"
+	topologyNodeConnector    = "connector"
+	topologyNodeProcess      = "process"
+
+	// topologyEdgeContains relates a parent to its child, i.e. a site to
+	// its routers and processes
+	topologyEdgeContains = "contains"
+	// topologyEdgeLink relates the two routers joined by a router link
+	topologyEdgeLink = "link"
+	// topologyEdgeTarget relates a connector to the process it targets
+	topologyEdgeTarget = "target"
+	// topologyEdgeProcessPair relates processes that have communicated
+	topologyEdgeProcessPair = "processpair"
+)
+
+type topology struct {
+	Nodes []topologyNode `json:"nodes"`
+	Edges []topologyEdge `json:"edges"`
+}
+
+type topologyNode struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	Name       string         `json:"name"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+type topologyEdge struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	Source     string         `json:"source"`
+	Target     string         `json:"target"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+type topologyBuilder struct {
+	nodes map[string]topologyNode
+	edges map[string]topologyEdge
+}
+
+func (b *topologyBuilder) addNode(id, typ string, name *string, attrs map[string]any) {
+	node := topologyNode{
+		ID:         id,
+		Type:       typ,
+		Name:       unknownStr,
+		Attributes: make(map[string]any),
+	}
+	if name != nil {
+		node.Name = *name
+	}
+	for k, v := range attrs {
+		switch v := v.(type) {
+		case *string:
+			if v != nil {
+				node.Attributes[k] = *v
+			}
+		case *uint64:
+			if v != nil {
+				node.Attributes[k] = *v
+			}
+		default:
+			node.Attributes[k] = v
+		}
+	}
+	b.nodes[id] = node
+}
+
+func (b *topologyBuilder) addEdge(id, typ string, source, target string, attrs map[string]any) {
+	if id == "" {
+		id = fmt.Sprintf("%s:%s:%s", typ, source, target)
+	}
+	b.edges[id] = topologyEdge{
+		ID:         id,
+		Type:       typ,
+		Source:     source,
+		Target:     target,
+		Attributes: attrs,
+	}
+}
+
+func (b *topologyBuilder) addParentEdge(parent *string, child string) {
+	if parent == nil {
+		return
+	}
+	b.addEdge("", topologyEdgeContains, *parent, child, nil)
+}
+
+// buildTopology assembles the topology from the collector records, using the
+// graph to resolve relationships between them.
+func buildTopology(records store.Interface, graph collector.Graph) topology {
+	b := topologyBuilder{
+		nodes: make(map[string]topologyNode),
+		edges: make(map[string]topologyEdge),
+	}
+	for _, e := range listByType[vanflow.SiteRecord](records) {
+		site := e.Record.(vanflow.SiteRecord)
+		b.addNode(site.ID, topologyNodeSite, site.Name, map[string]any{
+			"namespace": site.Namespace,
+			"platform":  site.Platform,
+			"provider":  site.Provider,
+			"location":  site.Location,
+			"version":   site.Version,
+		})
+	}
+	for _, e := range listByType[vanflow.RouterRecord](records) {
+		router := e.Record.(vanflow.RouterRecord)
+		b.addNode(router.ID, topologyNodeRouter, router.Name, map[string]any{
+			"namespace":    router.Namespace,
+			"mode":         router.Mode,
+			"hostname":     router.Hostname,
+			"imageName":    router.ImageName,
+			"imageVersion": router.ImageVersion,
+		})
+		b.addParentEdge(router.Parent, router.ID)
+	}
+	for _, e := range listByType[vanflow.RouterAccessRecord](records) {
+		access := e.Record.(vanflow.RouterAccessRecord)
+		b.addNode(access.ID, topologyNodeRouterAccess, access.Name, map[string]any{
+			"role":      access.Role,
+			"linkCount": access.LinkCount,
+		})
+		b.addParentEdge(access.Parent, access.ID)
+	}
+	for _, e := range listByType[vanflow.ListenerRecord](records) {
+		listener := e.Record.(vanflow.ListenerRecord)
+		b.addNode(listener.ID, topologyNodeListener, listener.Name, map[string]any{
+			"address":  listener.Address,
+			"protocol": listener.Protocol,
+			"host":     listener.DestHost,
+			"port":     listener.DestPort,
+		})
+		b.addParentEdge(listener.Parent, listener.ID)
+	}
+	for _, e := range listByType[vanflow.ConnectorRecord](records) {
+		connector := e.Record.(vanflow.ConnectorRecord)
+		b.addNode(connector.ID, topologyNodeConnector, connector.Name, map[string]any{
+			"address":  connector.Address,
+			"protocol": connector.Protocol,
+			"host":     connector.DestHost,
+			"port":     connector.DestPort,
+		})
+		b.addParentEdge(connector.Parent, connector.ID)
+		if target := graph.Connector(connector.ID).Target(); target.IsKnown() {
+			b.addEdge("", topologyEdgeTarget, connector.ID, target.ID(), nil)
+		}
+	}
+	for _, e := range listByType[vanflow.ProcessRecord](records) {
+		process := e.Record.(vanflow.ProcessRecord)
+		b.addNode(process.ID, topologyNodeProcess, process.Name, map[string]any{
+			"host":         process.SourceHost,
+			"hostname":     process.Hostname,
+			"component":    process.Group,
+			"imageName":    process.ImageName,
+			"imageVersion": process.ImageVersion,
+			"mode":         process.Mode,
+		})
+		b.addParentEdge(process.Parent, process.ID)
+	}
+	for _, e := range listByType[vanflow.LinkRecord](records) {
+		link := e.Record.(vanflow.LinkRecord)
+		if link.Parent == nil || link.Peer == nil {
+			continue
+		}
+		attrs := map[string]any{
+			"name":   unknownStr,
+			"role":   unknownStr,
+			"status": string(api.Down),
+		}
+		if link.Name != nil {
+			attrs["name"] = *link.Name
+		}
+		if link.Role != nil {
+			attrs["role"] = *link.Role
+		}
+		if link.Status != nil && strings.EqualFold(*link.Status, string(api.Up)) {
+			attrs["status"] = string(api.Up)
+		}
+		if link.LinkCost != nil {
+			attrs["cost"] = *link.LinkCost
+		}
+		// links are between routers, with the destination router
+		// identified by the router access the link is peered with. When
+		// that router is not yet known link to the router access itself.
+		target := *link.Peer
+		peerRouter := graph.RouterAccess(*link.Peer).Parent()
+		if peerRouter.IsKnown() {
+			target = peerRouter.ID()
+		}
+		sourceSite := graph.Link(link.ID).Parent().Parent()
+		destSite := peerRouter.Parent()
+		if sourceSite.IsKnown() && destSite.IsKnown() {
+			attrs["sourceSiteId"] = sourceSite.ID()
+			attrs["destinationSiteId"] = destSite.ID()
+			attrs["interSite"] = sourceSite.ID() != destSite.ID()
+		}
+		b.addEdge(link.ID, topologyEdgeLink, *link.Parent, target, attrs)
+	}
+
+	pairTraffic := processPairTraffic(records)
+	for _, e := range listByType[collector.ProcPairRecord](records) {
+		pair := e.Record.(collector.ProcPairRecord)
+		traffic := pairTraffic[processPairKey{Source: pair.Source, Dest: pair.Dest, Protocol: pair.Protocol}]
+		b.addEdge(pair.ID, topologyEdgeProcessPair, pair.Source, pair.Dest, map[string]any{
+			"protocol":      pair.Protocol,
+			"connections":   traffic.Connections,
+			"octets":        traffic.Octets,
+			"octetsReverse": traffic.OctetsReverse,
+		})
+	}
+
+	var out topology
+	out.Nodes = make([]topologyNode, 0, len(b.nodes))
+	for _, node := range b.nodes {
+		out.Nodes = append(out.Nodes, node)
+	}
+	out.Edges = make([]topologyEdge, 0, len(b.edges))
+	for _, edge := range b.edges {
+		// omit edges to records that are not (yet) known
+		if _, ok := b.nodes[edge.Source]; !ok {
+			continue
+		}
+		if _, ok := b.nodes[edge.Target]; !ok {
+			continue
+		}
+		out.Edges = append(out.Edges, edge)
+	}
+	sort.Slice(out.Nodes, func(i, j int) bool {
+		if out.Nodes[i].Type != out.Nodes[j].Type {
+			return out.Nodes[i].Type < out.Nodes[j].Type
+		}
+		return out.Nodes[i].ID < out.Nodes[j].ID
+	})
+	sort.Slice(out.Edges, func(i, j int) bool {
+		if out.Edges[i].Type != out.Edges[j].Type {
+			return out.Edges[i].Type < out.Edges[j].Type
+		}
+		return out.Edges[i].ID < out.Edges[j].ID
+	})
+	return out
+}
+
+type processPairKey struct {
+	Source   string
+	Dest     string
+	Protocol string
+}
+
+type processPairTrafficTotals struct {
+	Connections   uint64
+	Octets        uint64
+	OctetsReverse uint64
+}
+
+// processPairTraffic totals the connections and bytes transferred between
+// pairs of processes for the connections retained by the collector.
+func processPairTraffic(records store.Interface) map[processPairKey]processPairTrafficTotals {
+	totals := make(map[processPairKey]processPairTrafficTotals)
+	for _, e := range listByType[collector.ConnectionRecord](records) {
+		connection := e.Record.(collector.ConnectionRecord)
+		key := processPairKey{
+			Source:   connection.Source.ID,
+			Dest:     connection.Dest.ID,
+			Protocol: connection.Protocol,
+		}
+		total := totals[key]
+		total.Connections++
+		if flow, ok := connection.GetFlow(); ok {
+			if flow.Octets != nil {
+				total.Octets += *flow.Octets
+			}
+			if flow.OctetsReverse != nil {
+				total.OctetsReverse += *flow.OctetsReverse
+			}
+		}
+		totals[key] = total
+	}
+	return totals
+}
+
+var topologyDOTShapes = map[string]string{
+	topologyNodeSite:         "box3d",
+	topologyNodeRouter:       "circle",
+	topologyNodeRouterAccess: "diamond",
+	topologyNodeListener:     "cds",
+	topologyNodeConnector:    "rarrow",
+	topologyNodeProcess:      "box",
+}
+
+var topologyDOTEdgeStyles = map[string]string{
+	topologyEdgeContains:    "dotted",
+	topologyEdgeLink:        "bold",
+	topologyEdgeTarget:      "dashed",
+	topologyEdgeProcessPair: "solid",
+}
+
+func writeTopologyDOT(w io.Writer, t topology) error {
+	var sb strings.Builder
+	sb.WriteString("digraph skupper {\n")
+	for _, node := range t.Nodes {
+		attrs := map[string]any{
+			"label": node.Name,
+			"type":  node.Type,
+			"shape": topologyDOTShapes[node.Type],
+		}
+		for k, v := range node.Attributes {
+			attrs[k] = v
+		}
+		fmt.Fprintf(&sb, "\t%s [%s];\n", dotQuote(node.ID), dotAttributes(attrs))
+	}
+	for _, edge := range t.Edges {
+		attrs := map[string]any{
+			"id":    edge.ID,
+			"type":  edge.Type,
+			"style": topologyDOTEdgeStyles[edge.Type],
+		}
+		switch edge.Type {
+		case topologyEdgeLink:
+			if cost, ok := edge.Attributes["cost"]; ok {
+				attrs["label"] = fmt.Sprintf("cost %v", cost)
+			}
+		case topologyEdgeProcessPair:
+			attrs["label"] = edge.Attributes["protocol"]
+		}
+		for k, v := range edge.Attributes {
+			attrs[k] = v
+		}
+		fmt.Fprintf(&sb, "\t%s -> %s [%s];\n", dotQuote(edge.Source), dotQuote(edge.Target), dotAttributes(attrs))
+	}
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func dotAttributes(attrs map[string]any) string {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%s", k, dotQuote(fmt.Sprint(attrs[k]))))
+	}
+	return strings.Join(parts, ", ")
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// graphMLKeys declares graphml data keys for the attributes of either nodes
+// or edges.
+type graphMLKeys struct {
+	domain string
+	types  map[string]string
+}
+
+func (k *graphMLKeys) data(attrs map[string]any) []graphMLData {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := make([]graphMLData, 0, len(names))
+	for _, name := range names {
+		value := attrs[name]
+		typ := "string"
+		switch value.(type) {
+		case bool:
+			typ = "boolean"
+		case int, int64, uint64:
+			typ = "long"
+		}
+		k.types[name] = typ
+		out = append(out, graphMLData{Key: k.id(name), Value: fmt.Sprint(value)})
+	}
+	return out
+}
+
+func (k *graphMLKeys) id(name string) string {
+	return k.domain + "_" + name
+}
+
+func (k *graphMLKeys) keys() []graphMLKey {
+	out := make([]graphMLKey, 0, len(k.types))
+	for name, typ := range k.types {
+		out = append(out, graphMLKey{ID: k.id(name), For: k.domain, AttrName: name, AttrType: typ})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func writeTopologyGraphML(w io.Writer, t topology) error {
+	nodeKeys := graphMLKeys{domain: "node", types: make(map[string]string)}
+	edgeKeys := graphMLKeys{domain: "edge", types: make(map[string]string)}
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Graph: graphMLGraph{
+			ID:          "skupper",
+			EdgeDefault: "directed",
+			Nodes:       make([]graphMLNode, 0, len(t.Nodes)),
+			Edges:       make([]graphMLEdge, 0, len(t.Edges)),
+		},
+	}
+	for _, node := range t.Nodes {
+		attrs := map[string]any{
+			"type": node.Type,
+			"name": node.Name,
+		}
+		for k, v := range node.Attributes {
+			attrs[k] = v
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: node.ID, Data: nodeKeys.data(attrs)})
+	}
+	for _, edge := range t.Edges {
+		attrs := map[string]any{
+			"type": edge.Type,
+		}
+		for k, v := range edge.Attributes {
+			attrs[k] = v
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     edge.ID,
+			Source: edge.Source,
+			Target: edge.Target,
+			Data:   edgeKeys.data(attrs),
+		})
+	}
+	doc.Keys = append(nodeKeys.keys(), edgeKeys.keys()...)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("xml encoding error: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+	"gotest.tools/v3/assert"
+)
+
+func TestTopology(t *testing.T) {
+	stor := store.NewSyncMapStore(store.SyncMapStoreConfig{})
+	flows := store.NewSyncMapStore(store.SyncMapStoreConfig{})
+	graph := collector.NewGraph(stor)
+	srv := httptest.NewServer(NewTopologyHandler(slog.Default(), stor, graph))
+	defer srv.Close()
+
+	flows.Add(vanflow.TransportBiflowRecord{
+		BaseRecord:    vanflow.NewBase("flow-1"),
+		Octets:        ptrTo(uint64(100)),
+		OctetsReverse: ptrTo(uint64(2000)),
+	}, store.SourceRef{})
+	stor.Replace(wrapRecords(
+		vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1"), Name: ptrTo("west")},
+		vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-2"), Name: ptrTo("east")},
+		vanflow.RouterRecord{BaseRecord: vanflow.NewBase("router-1"), Parent: ptrTo("site-1"), Name: ptrTo("west-router")},
+		vanflow.RouterRecord{BaseRecord: vanflow.NewBase("router-2"), Parent: ptrTo("site-2"), Name: ptrTo("east-router")},
+		vanflow.RouterAccessRecord{BaseRecord: vanflow.NewBase("access-2"), Parent: ptrTo("router-2"), Role: ptrTo("inter-router")},
+		vanflow.LinkRecord{BaseRecord: vanflow.NewBase("link-1"), Parent: ptrTo("router-1"), Peer: ptrTo("access-2"), LinkCost: ptrTo(uint64(4)), Status: ptrTo("up"), Name: ptrTo("west-to-east")},
+		vanflow.ListenerRecord{BaseRecord: vanflow.NewBase("listener-1"), Parent: ptrTo("router-1"), Address: ptrTo("backend"), Protocol: ptrTo("tcp")},
+		vanflow.ConnectorRecord{BaseRecord: vanflow.NewBase("connector-2"), Parent: ptrTo("router-2"), ProcessID: ptrTo("process-2"), Address: ptrTo("backend"), Protocol: ptrTo("tcp")},
+		vanflow.ProcessRecord{BaseRecord: vanflow.NewBase("process-1"), Parent: ptrTo("site-1"), Name: ptrTo("frontend")},
+		vanflow.ProcessRecord{BaseRecord: vanflow.NewBase("process-2"), Parent: ptrTo("site-2"), Name: ptrTo("backend")},
+		collector.ProcPairRecord{ID: "pair-1", Source: "process-1", Dest: "process-2", Protocol: "tcp"},
+		collector.ConnectionRecord{
+			ID:        "flow-1",
+			Protocol:  "tcp",
+			Source:    collector.NamedReference{ID: "process-1"},
+			Dest:      collector.NamedReference{ID: "process-2"},
+			FlowStore: flows,
+		},
+		// link to a router that is not known
+		vanflow.LinkRecord{BaseRecord: vanflow.NewBase("link-2"), Parent: ptrTo("router-1"), Peer: ptrTo("access-unknown")},
+	))
+	graph.(reset).Reset()
+
+	get := func(t *testing.T, format string) (*http.Response, []byte) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + "?format=" + format)
+		assert.NilError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		assert.NilError(t, err)
+		return resp, body
+	}
+
+	t.Run("json", func(t *testing.T) {
+		resp, body := get(t, "json")
+		assert.Equal(t, resp.StatusCode, http.StatusOK)
+		var actual topology
+		assert.NilError(t, json.Unmarshal(body, &actual))
+		assert.Equal(t, len(actual.Nodes), 9)
+
+		edges := make(map[string]topologyEdge)
+		for _, edge := range actual.Edges {
+			edges[edge.ID] = edge
+		}
+		assert.Equal(t, len(edges), 10)
+		_, ok := edges["link-2"]
+		assert.Assert(t, !ok, "expected link to unknown router access to be omitted")
+
+		link := edges["link-1"]
+		assert.Equal(t, link.Type, topologyEdgeLink)
+		assert.Equal(t, link.Source, "router-1")
+		assert.Equal(t, link.Target, "router-2")
+		assert.Equal(t, link.Attributes["cost"], float64(4))
+		assert.Equal(t, link.Attributes["status"], "up")
+		assert.Equal(t, link.Attributes["interSite"], true)
+
+		pair := edges["pair-1"]
+		assert.Equal(t, pair.Type, topologyEdgeProcessPair)
+		assert.Equal(t, pair.Attributes["connections"], float64(1))
+		assert.Equal(t, pair.Attributes["octets"], float64(100))
+		assert.Equal(t, pair.Attributes["octetsReverse"], float64(2000))
+
+		target := edges["target:connector-2:process-2"]
+		assert.Equal(t, target.Type, topologyEdgeTarget)
+		contains := edges["contains:site-1:router-1"]
+		assert.Equal(t, contains.Type, topologyEdgeContains)
+	})
+
+	t.Run("dot", func(t *testing.T) {
+		resp, body := get(t, "dot")
+		assert.Equal(t, resp.StatusCode, http.StatusOK)
+		assert.Equal(t, resp.Header.Get("Content-Type"), "text/vnd.graphviz")
+		dot := string(body)
+		assert.Assert(t, strings.HasPrefix(dot, "digraph skupper {\n"))
+		assert.Assert(t, strings.Contains(dot, `"site-1" [label="west", shape="box3d", type="site"];`), dot)
+		assert.Assert(t, strings.Contains(dot, `"router-1" -> "router-2" [cost="4", destinationSiteId="site-2", id="link-1", interSite="true", label="cost 4", name="west-to-east", role="unknown", sourceSiteId="site-1", status="up", style="bold", type="link"];`), dot)
+	})
+
+	t.Run("graphml", func(t *testing.T) {
+		resp, body := get(t, "graphml")
+		assert.Equal(t, resp.StatusCode, http.StatusOK)
+		var doc graphML
+		assert.NilError(t, xml.Unmarshal(body, &doc))
+		assert.Equal(t, len(doc.Graph.Nodes), 9)
+		assert.Equal(t, len(doc.Graph.Edges), 10)
+		keyTypes := make(map[string]string)
+		for _, key := range doc.Keys {
+			keyTypes[key.ID] = key.AttrType
+		}
+		assert.Equal(t, keyTypes["edge_cost"], "long")
+		assert.Equal(t, keyTypes["edge_interSite"], "boolean")
+		assert.Equal(t, keyTypes["node_name"], "string")
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		resp, _ := get(t, "svg")
+		assert.Equal(t, resp.StatusCode, http.StatusBadRequest)
+	})
+}
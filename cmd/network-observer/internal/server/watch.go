@@ -0,0 +1,266 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/server/views"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+)
+
+const (
+	watchBufferSize        = 256
+	watchKeepAliveInterval = 15 * time.Second
+)
+
+// RecordEventSource is implemented by the collector to notify subscribers of
+// changes to records.
+type RecordEventSource interface {
+	Subscribe(buffer int) (<-chan collector.RecordEvent, func())
+}
+
+// NewWatchHandler returns a handler that streams changes to records of the
+// type named by the recordType path variable as server-sent events. Clients
+// are first sent an add event for each existing record, and then add, update
+// and delete events as records change. Records are sent in the same shape as
+// the corresponding list endpoint, and can be filtered using the same field
+// filter and state query parameters.
+//
+// (GET /api/v2alpha1/watch/{recordType})
+func NewWatchHandler(logger *slog.Logger, records store.Interface, graph collector.Graph, events RecordEventSource) http.Handler {
+	return &watchHandler{
+		logger:  logger,
+		records: records,
+		graph:   graph,
+		events:  events,
+	}
+}
+
+type watchHandler struct {
+	logger  *slog.Logger
+	records store.Interface
+	graph   collector.Graph
+	events  RecordEventSource
+}
+
+func (h *watchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	kind, ok := h.watchKinds()[mux.Vars(r)["recordType"]]
+	if !ok {
+		if err := encodeResponse(w, http.StatusNotFound, api.ErrorNotFound{Code: "ErrNotFound"}); err != nil {
+			h.logWriteError(r, err)
+		}
+		return
+	}
+	stream, err := kind.newStream(r)
+	if err != nil {
+		if err := encodeResponse(w, http.StatusBadRequest, api.ErrorBadRequest{Message: err.Error()}); err != nil {
+			h.logWriteError(r, err)
+		}
+		return
+	}
+
+	events, cancel := h.events.Subscribe(watchBufferSize)
+	defer cancel()
+
+	rc := http.NewResponseController(w)
+	// the stream outlives any write timeout configured on the server
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		requestLogger(h.logger, r).Error("failed to clear write deadline", slog.Any("error", err))
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	var seq uint64
+	send := func(event collector.RecordEvent) error {
+		out, ok := stream.Handle(event)
+		if !ok {
+			return nil
+		}
+		seq++
+		return writeServerSentEvent(w, seq, out)
+	}
+
+	for _, entry := range ordered(h.records.Index(store.TypeIndex, store.Entry{Record: kind.record})) {
+		if err := send(collector.RecordEvent{Type: collector.RecordAdded, Entry: entry}); err != nil {
+			h.logWriteError(r, err)
+			return
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		h.logWriteError(r, err)
+		return
+	}
+
+	keepAlive := time.NewTicker(watchKeepAliveInterval)
+	defer keepAlive.Stop()
+	typ := kind.record.GetTypeMeta()
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			_, err = io.WriteString(w, ": keepalive\n\n")
+		case event, ok := <-events:
+			if !ok {
+				requestLogger(h.logger, r).Info("closing watch that fell behind")
+				return
+			}
+			if event.Entry.Record.GetTypeMeta() != typ {
+				continue
+			}
+			err = send(event)
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			h.logWriteError(r, err)
+			return
+		}
+	}
+}
+
+func (h *watchHandler) logWriteError(r *http.Request, err error) {
+	requestLogger(h.logger, r).Error("failed to write response", slog.Any("error", err))
+}
+
+// watchKinds returns the watchable record types by their path name. The
+// views used to map records are not safe for concurrent use, so are created
+// for each request.
+func (h *watchHandler) watchKinds() map[string]watchKind {
+	return map[string]watchKind{
+		"applicationflows": newWatchKind(collector.RequestRecord{}, views.NewRequestSliceProvider(h.records)),
+		"componentpairs":   newWatchKind(collector.ProcGroupPairRecord{}, views.NewComponentPairSliceProvider()),
+		"components":       newWatchKind(collector.ProcessGroupRecord{}, views.NewComponentSliceProvider(h.records)),
+		"connections":      newWatchKind(collector.ConnectionRecord{}, views.NewConnectionsSliceProvider(h.records)),
+		"connectors":       newWatchKind(vanflow.ConnectorRecord{}, views.NewConnectorSliceProvider(h.graph)),
+		"listeners":        newWatchKind(vanflow.ListenerRecord{}, views.NewListenerSliceProvider(h.graph)),
+		"processes":        newWatchKind(vanflow.ProcessRecord{}, views.NewProcessSliceProvider(h.records, h.graph)),
+		"processpairs":     newWatchKind(collector.ProcPairRecord{}, views.NewProcessPairSliceProvider(h.graph)),
+		"routeraccess":     newWatchKind(vanflow.RouterAccessRecord{}, views.RouterAccessList),
+		"routerlinks":      newWatchKind(vanflow.LinkRecord{}, views.NewRotuerLinkSliceProvider(h.graph)),
+		"routers":          newWatchKind(vanflow.RouterRecord{}, views.Routers),
+		"services":         newWatchKind(collector.AddressRecord{}, views.NewServiceSliceProvider(h.records, h.graph)),
+		"sitepairs":        newWatchKind(collector.SitePairRecord{}, views.NewSitePairSliceProvider(h.graph)),
+		"sites":            newWatchKind(vanflow.SiteRecord{}, views.NewSiteSliceProvider(h.graph)),
+	}
+}
+
+type watchKind struct {
+	record    vanflow.Record
+	newStream func(r *http.Request) (watchStream, error)
+}
+
+func newWatchKind[T api.Record](record vanflow.Record, provider func([]store.Entry) []T) watchKind {
+	return watchKind{
+		record: record,
+		newStream: func(r *http.Request) (watchStream, error) {
+			qp := getQueryParams(r)
+			filter, err := newRecordFilter[T](qp)
+			if err != nil {
+				return nil, err
+			}
+			return &typedWatchStream[T]{
+				request:  r,
+				provider: provider,
+				filter:   filter,
+				state:    qp.State,
+				sent:     make(map[string]struct{}),
+			}, nil
+		},
+	}
+}
+
+type watchStream interface {
+	// Handle returns the event to send to the client for a record change
+	Handle(collector.RecordEvent) (watchEvent, bool)
+}
+
+type watchEvent struct {
+	Type   collector.RecordEventType
+	Record any
+}
+
+// deletedRecord is sent for deleted records that can no longer be mapped to
+// their api representation.
+type deletedRecord struct {
+	Identity string `json:"identity"`
+}
+
+// typedWatchStream tracks the records a client has been sent so that
+// records changing to no longer match the client's filter are sent as
+// deletes, and deletes are only sent for records the client has seen.
+type typedWatchStream[T api.Record] struct {
+	request  *http.Request
+	provider func([]store.Entry) []T
+	filter   recordFilter[T]
+	state    timeRangeState
+	sent     map[string]struct{}
+}
+
+func (s *typedWatchStream[T]) Handle(event collector.RecordEvent) (watchEvent, bool) {
+	id := event.Entry.Record.Identity()
+	_, sent := s.sent[id]
+
+	var (
+		record T
+		mapped bool
+	)
+	if results := s.provider([]store.Entry{event.Entry}); len(results) == 1 {
+		record, mapped = results[0], true
+		tagNetwork(s.request, &record)
+	}
+
+	if event.Type == collector.RecordDeleted || !mapped || !s.matches(record) {
+		if !sent {
+			return watchEvent{}, false
+		}
+		delete(s.sent, id)
+		out := watchEvent{Type: collector.RecordDeleted, Record: deletedRecord{Identity: id}}
+		if mapped {
+			out.Record = record
+		}
+		return out, true
+	}
+
+	s.sent[id] = struct{}{}
+	if sent {
+		return watchEvent{Type: collector.RecordUpdated, Record: record}, true
+	}
+	return watchEvent{Type: collector.RecordAdded, Record: record}, true
+}
+
+func (s *typedWatchStream[T]) matches(record T) bool {
+	switch s.state {
+	case active:
+		if record.GetEndTime() != 0 {
+			return false
+		}
+	case terminated:
+		if record.GetEndTime() == 0 {
+			return false
+		}
+	}
+	return s.filter.Matches(record)
+}
+
+func writeServerSentEvent(w io.Writer, id uint64, event watchEvent) error {
+	data, err := json.Marshal(event.Record)
+	if err != nil {
+		return fmt.Errorf("json encoding error: %s", err)
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", id, event.Type, data)
+	return err
+}
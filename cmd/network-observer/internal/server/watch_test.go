@@ -0,0 +1,114 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+	"gotest.tools/v3/assert"
+)
+
+type testEventSource chan collector.RecordEvent
+
+func (s testEventSource) Subscribe(int) (<-chan collector.RecordEvent, func()) {
+	return s, func() {}
+}
+
+type testServerSentEvent struct {
+	Event string
+	Data  string
+}
+
+func readServerSentEvent(t *testing.T, r *bufio.Reader) testServerSentEvent {
+	t.Helper()
+	var out testServerSentEvent
+	for {
+		line, err := r.ReadString('\n')
+		assert.NilError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return out
+		case strings.HasPrefix(line, "event: "):
+			out.Event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			out.Data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestWatch(t *testing.T) {
+	stor := store.NewSyncMapStore(store.SyncMapStoreConfig{})
+	graph := collector.NewGraph(stor)
+	events := make(testEventSource, 8)
+
+	router := mux.NewRouter()
+	router.Path("/api/v2alpha1/watch/{recordType}").Handler(NewWatchHandler(slog.Default(), stor, graph, events))
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	stor.Replace(wrapRecords(
+		vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1"), Namespace: ptrTo("testns")},
+		vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-2"), Namespace: ptrTo("other")},
+		vanflow.RouterRecord{BaseRecord: vanflow.NewBase("router-1"), Parent: ptrTo("site-1")},
+	))
+
+	resp, err := http.Get(srv.URL + "/api/v2alpha1/watch/fizz")
+	assert.NilError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusNotFound)
+
+	resp, err = http.Get(srv.URL + "/api/v2alpha1/watch/sites?fizz=buzz")
+	assert.NilError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusBadRequest)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v2alpha1/watch/sites?namespace=testns", nil)
+	assert.NilError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Equal(t, resp.Header.Get("Content-Type"), "text/event-stream")
+	body := bufio.NewReader(resp.Body)
+
+	expectSite := func(event string, id string) {
+		t.Helper()
+		actual := readServerSentEvent(t, body)
+		assert.Equal(t, actual.Event, event)
+		var site api.SiteRecord
+		assert.NilError(t, json.Unmarshal([]byte(actual.Data), &site))
+		assert.Equal(t, site.Identity, id)
+	}
+	expectSite("add", "site-1")
+
+	site := func(id, namespace string) store.Entry {
+		return store.Entry{Record: vanflow.SiteRecord{BaseRecord: vanflow.NewBase(id), Namespace: ptrTo(namespace)}}
+	}
+	// events for other record types are ignored
+	events <- collector.RecordEvent{Type: collector.RecordAdded, Entry: wrapRecords(vanflow.RouterRecord{BaseRecord: vanflow.NewBase("router-2")})[0]}
+	// records not matching the filter are ignored
+	events <- collector.RecordEvent{Type: collector.RecordAdded, Entry: site("site-3", "other")}
+	events <- collector.RecordEvent{Type: collector.RecordUpdated, Entry: site("site-1", "testns")}
+	expectSite("update", "site-1")
+	events <- collector.RecordEvent{Type: collector.RecordUpdated, Entry: site("site-2", "testns")}
+	expectSite("add", "site-2")
+	// records that stop matching are sent as deletes
+	events <- collector.RecordEvent{Type: collector.RecordUpdated, Entry: site("site-1", "other")}
+	expectSite("delete", "site-1")
+	events <- collector.RecordEvent{Type: collector.RecordDeleted, Entry: site("site-1", "other")}
+	events <- collector.RecordEvent{Type: collector.RecordDeleted, Entry: site("site-2", "testns")}
+	expectSite("delete", "site-2")
+}
@@ -10,6 +10,7 @@ import (
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"time"
 
 	"github.com/gorilla/handlers"
@@ -17,14 +18,20 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/alerts"
 	"github.com/skupperproject/skupper/cmd/network-observer/internal/api"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/auth"
 	"github.com/skupperproject/skupper/cmd/network-observer/internal/cmd"
 	"github.com/skupperproject/skupper/cmd/network-observer/internal/collector"
 	"github.com/skupperproject/skupper/cmd/network-observer/internal/flowlog"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/otlp"
+	"github.com/skupperproject/skupper/cmd/network-observer/internal/probes"
 	"github.com/skupperproject/skupper/cmd/network-observer/internal/server"
 	"github.com/skupperproject/skupper/internal/version"
 	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/capture"
 	"github.com/skupperproject/skupper/pkg/vanflow/session"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
 )
 
 func run(cfg Config) error {
@@ -42,38 +49,242 @@ func run(cfg Config) error {
 		return fmt.Errorf("could not load spec filesystem: %s", err)
 	}
 
-	sessionConfig, err := configureSession(cfg.RouterTLS)
-	if err != nil {
-		return fmt.Errorf("failed to load router tls configuration: %s", err)
+	networks := []NetworkSpec{{Name: defaultNetworkName, RouterURL: cfg.RouterURL, RouterTLS: cfg.RouterTLS}}
+	multiNetwork := cfg.NetworksFile != ""
+	if multiNetwork {
+		if cfg.CaptureFile != "" || cfg.ReplayFile != "" {
+			return fmt.Errorf("capture-file and replay-file cannot be used with networks-file")
+		}
+		networks, err = loadNetworks(cfg.NetworksFile)
+		if err != nil {
+			return fmt.Errorf("failed to load networks file %q: %s", cfg.NetworksFile, err)
+		}
+	}
+
+	var (
+		wrapContainerFactory = func(f session.ContainerFactory) session.ContainerFactory { return f }
+		captureWriter        *capture.Writer
+	)
+	switch {
+	case cfg.ReplayFile != "" && cfg.CaptureFile != "":
+		return fmt.Errorf("capture-file and replay-file cannot be used together")
+	case cfg.ReplayFile != "":
+		replayFile, err := os.Open(cfg.ReplayFile)
+		if err != nil {
+			return fmt.Errorf("failed to open replay file: %s", err)
+		}
+		defer replayFile.Close()
+		reader, err := capture.NewReader(replayFile)
+		if err != nil {
+			return fmt.Errorf("failed to read replay file %q: %s", cfg.ReplayFile, err)
+		}
+		replayFactory := capture.NewReplayContainerFactory(reader, capture.ReplayOptions{
+			Speed:  cfg.ReplaySpeed,
+			Logger: logger.With(slog.String("component", "replay")),
+		})
+		wrapContainerFactory = func(session.ContainerFactory) session.ContainerFactory { return replayFactory }
+	case cfg.CaptureFile != "":
+		captureFile, err := os.Create(cfg.CaptureFile)
+		if err != nil {
+			return fmt.Errorf("failed to create capture file: %s", err)
+		}
+		defer captureFile.Close()
+		captureWriter = capture.NewWriter(captureFile)
+		defer func() {
+			if err := captureWriter.Close(); err != nil {
+				logger.Error("failed to write capture file", slog.Any("error", err))
+			}
+			logger.Info("Capture complete", slog.String("file", cfg.CaptureFile), slog.Int("messages", captureWriter.Count()))
+		}()
+		wrapContainerFactory = func(f session.ContainerFactory) session.ContainerFactory {
+			return capture.NewCaptureContainerFactory(f, captureWriter, logger.With(slog.String("component", "capture")))
+		}
 	}
 
 	flowLogger := func(vanflow.RecordMessage) {}
 	vanflowSLog := logger.With(slog.String("component", "vanflow"))
-	switch cfg.VanflowLoggingProfile {
-	case "silent":
-	case "minimal":
-		flowLogger = flowlog.New(ctx, vanflowSLog.Info, loggingProfileMinimal)
-	case "moderate":
-		flowLogger = flowlog.New(ctx, vanflowSLog.Info, loggingProfileModerate)
-	case "all":
-		flowLogger = flowlog.New(ctx, vanflowSLog.Info, loggingProfileAll)
-	default:
-		return fmt.Errorf("unknown logging profile: %s", cfg.VanflowLoggingProfile)
-	}
-
-	collector := collector.New(
-		logger.With(slog.String("component", "collector")),
-		session.NewContainerFactory(cfg.RouterURL, sessionConfig),
-		reg,
-		cfg.FlowRecordTTL,
-		flowLogger,
-	)
+	if cfg.VanflowLoggingProfile != "silent" {
+		profile, ok := loggingProfiles[cfg.VanflowLoggingProfile]
+		if !ok {
+			return fmt.Errorf("unknown logging profile: %s", cfg.VanflowLoggingProfile)
+		}
+		flowLogger = flowlog.New(ctx, vanflowSLog.Info, profile())
+	}
+	if cfg.FlowLoggingConfigFile != "" {
+		flowLoggingConfig, err := flowlog.LoadConfig(cfg.FlowLoggingConfigFile)
+		if err != nil {
+			return err
+		}
+		flowLoggers, err := flowlog.NewLoggers(ctx, flowLoggingConfig, loggingProfiles)
+		if err != nil {
+			return fmt.Errorf("failed to create flow loggers: %s", err)
+		}
+		defer func() {
+			if err := flowLoggers.Close(); err != nil {
+				logger.Error("error closing flow logging sinks", slog.Any("error", err))
+			}
+		}()
+		profileLogger := flowLogger
+		flowLogger = func(msg vanflow.RecordMessage) {
+			profileLogger(msg)
+			flowLoggers.Handle(msg)
+		}
+	}
 
-	collectorAPI := server.New(
+	var otlpExporter *otlp.Exporter
+	var spanExporter func(otlp.Span)
+	if cfg.OTLPEndpoint != "" {
+		otlpExporter, err = otlp.New(logger.With(slog.String("component", "otlp")), otlp.Config{
+			Endpoint: cfg.OTLPEndpoint,
+			Resource: []otlp.Attribute{
+				otlp.String("service.name", "skupper-network-observer"),
+				otlp.String("service.version", version.Version),
+			},
+			Scope:           "github.com/skupperproject/skupper/cmd/network-observer",
+			Gatherer:        reg,
+			MetricsInterval: cfg.OTLPMetricsInterval,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to configure otlp exporter: %s", err)
+		}
+		spanExporter = otlpExporter.ExportSpan
+	}
+
+	var retention collector.RetentionConfig
+	if cfg.RetentionConfigFile != "" {
+		retention, err = loadRetentionConfig(cfg.RetentionConfigFile)
+		if err != nil {
+			return fmt.Errorf("failed to load retention config %q: %s", cfg.RetentionConfigFile, err)
+		}
+	}
+
+	collectors := make([]*collector.Collector, 0, len(networks))
+	for _, network := range networks {
+		sessionConfig, err := network.RouterTLS.ContainerConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load router tls configuration for network %q: %s", network.Name, err)
+		}
+		var (
+			collectorLogger = logger.With(slog.String("component", "collector"))
+			collectorReg    = prometheus.Registerer(reg)
+			storeDir        = cfg.StoreDir
+		)
+		if multiNetwork {
+			collectorLogger = collectorLogger.With(slog.String("network", network.Name))
+			collectorReg = prometheus.WrapRegistererWith(prometheus.Labels{"network": network.Name}, reg)
+			if storeDir != "" {
+				storeDir = filepath.Join(storeDir, network.Name)
+			}
+		}
+		c, err := collector.New(
+			collectorLogger,
+			wrapContainerFactory(session.NewContainerFactory(network.RouterURL, sessionConfig)),
+			collectorReg,
+			collector.Config{
+				FlowRecordTTL: cfg.FlowRecordTTL,
+				FlowLogger:    flowLogger,
+				StoreDir:      storeDir,
+				SpanExporter:  spanExporter,
+				Network:       network.Name,
+				Retention:     retention,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create collector for network %q: %s", network.Name, err)
+		}
+		collectors = append(collectors, c)
+	}
+
+	apiNetworks := make([]server.Network, 0, len(collectors))
+	for _, c := range collectors {
+		apiNetworks = append(apiNetworks, server.Network{
+			Name:    c.Network(),
+			Records: c.Records,
+			Graph:   c.GetGraph(),
+			Events:  c,
+		})
+	}
+	authenticate := func(next http.Handler) http.Handler { return next }
+	if cfg.AuthConfigFile != "" {
+		authConfig, err := auth.LoadConfig(cfg.AuthConfigFile)
+		if err != nil {
+			return err
+		}
+		authenticator, err := auth.New(logger.With(slog.String("component", "auth")), authConfig)
+		if err != nil {
+			return fmt.Errorf("failed to configure api authentication: %s", err)
+		}
+		authenticate = authenticator.Middleware
+	}
+
+	networkRouter, err := server.NewNetworkRouter(
 		logger.With(slog.String("component", "api")),
-		collector.Records,
-		collector.GetGraph(),
+		apiNetworks,
+		func(network server.Network) *mux.Router {
+			router := mux.NewRouter().StrictSlash(true)
+			api.HandlerWithOptions(server.New(
+				logger.With(slog.String("component", "api")),
+				network.Records,
+				network.Graph,
+			), api.GorillaServerOptions{
+				BaseRouter:  router,
+				Middlewares: []api.MiddlewareFunc{server.NewScopeMiddleware(network.Records)},
+			})
+			router.Path("/api/v2alpha1/watch/{recordType}").Handler(auth.RequireUnrestricted(server.NewWatchHandler(
+				logger.With(slog.String("component", "api.watch")),
+				network.Records,
+				network.Graph,
+				network.Events,
+			)))
+			router.Path("/api/v2alpha1/{recordType:connections|applicationflows}/export").Handler(server.NewScopeMiddleware(network.Records)(server.NewExportHandler(
+				logger.With(slog.String("component", "api.export")),
+				network.Records,
+			)))
+			router.Path("/api/v2alpha1/topology").Handler(auth.RequireUnrestricted(server.NewTopologyHandler(
+				logger.With(slog.String("component", "api.topology")),
+				network.Records,
+				network.Graph,
+			)))
+			return router
+		},
 	)
+	if err != nil {
+		return fmt.Errorf("failed to configure api: %s", err)
+	}
+
+	// records from all networks for components that are not network scoped
+	allRecords := collectors[0].Records
+	if multiNetwork {
+		stores := make([]store.Interface, 0, len(collectors))
+		for _, c := range collectors {
+			stores = append(stores, c.Records)
+		}
+		allRecords = store.NewUnion(stores...)
+	}
+
+	var alertEngine *alerts.Engine
+	if cfg.AlertRulesFile != "" {
+		alertsConfig, err := alerts.LoadConfig(cfg.AlertRulesFile)
+		if err != nil {
+			return err
+		}
+		alertEngine, err = alerts.New(logger.With(slog.String("component", "alerts")), allRecords, alertsConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create alerting engine: %s", err)
+		}
+	}
+
+	var prober *probes.Prober
+	if cfg.ProbesConfigFile != "" {
+		probesConfig, err := probes.LoadConfig(cfg.ProbesConfigFile)
+		if err != nil {
+			return err
+		}
+		prober, err = probes.New(logger.With(slog.String("component", "probes")), allRecords, reg, probesConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create reachability prober: %s", err)
+		}
+	}
 
 	var mux = mux.NewRouter().StrictSlash(true)
 	promSubrouter := mux.PathPrefix("/api/v2alpha1/internal/prom")
@@ -83,9 +294,21 @@ func run(cfg Config) error {
 	if cfg.CORSAllowAll {
 		apiMux.Use(handlers.CORS())
 	}
-	api.HandlerWithOptions(collectorAPI, api.GorillaServerOptions{
-		BaseRouter: apiMux,
-	})
+	apiMux.Use(authenticate)
+	apiMux.Path("/api/v2alpha1/networks").Handler(auth.RequireUnrestricted(networkRouter.NetworksHandler()))
+	apiMux.MatcherFunc(networkRouter.Match).Handler(networkRouter)
+	if alertEngine != nil {
+		apiMux.Path("/api/v2alpha1/alerts").Handler(auth.RequireUnrestricted(server.NewAlertsHandler(
+			logger.With(slog.String("component", "api.alerts")),
+			alertEngine,
+		)))
+	}
+	if prober != nil {
+		apiMux.Path("/api/v2alpha1/probes").Handler(auth.RequireUnrestricted(server.NewProbesHandler(
+			logger.With(slog.String("component", "api.probes")),
+			prober,
+		)))
+	}
 
 	if cfg.EnableConsole {
 		promAPI, err := parsePrometheusAPI(cfg.PrometheusAPI)
@@ -95,7 +318,7 @@ func run(cfg Config) error {
 		// add unspec'd api routes
 		apiMux.Path("/api/v2alpha1/user").Handler(handleGetUser())
 		apiMux.Path("/api/v2alpha1/logout").Handler(handleUserLogout())
-		promSubrouter.Handler(handleProxyPrometheusAPI("/api/v2alpha1/internal/prom", promAPI))
+		promSubrouter.Handler(authenticate(auth.RequireUnrestricted(handleProxyPrometheusAPI("/api/v2alpha1/internal/prom", promAPI))))
 
 		apiMux.PathPrefix("/").Handler(handleSecuredConsoleAssets(cfg.ConsoleLocation))
 	}
@@ -112,9 +335,9 @@ func run(cfg Config) error {
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 	}
-	tlsEnabled := cfg.APITLS.hasCert()
+	tlsEnabled := cfg.APITLS.HasCert()
 	if tlsEnabled {
-		s.TLSConfig, err = cfg.APITLS.config()
+		s.TLSConfig, err = cfg.APITLS.TLSConfig()
 		if err != nil {
 			return fmt.Errorf("could not set up certs for api server: %s", err)
 		}
@@ -178,13 +401,56 @@ func run(cfg Config) error {
 		})
 	}
 
-	g.Go(func() error {
-		logger.Debug("Starting Network Observer Collector")
-		if err := collector.Run(runCtx); err != nil {
-			return fmt.Errorf("collector error: %w", err)
-		}
-		return nil
-	})
+	if otlpExporter != nil {
+		g.Go(func() error {
+			logger.Info("Starting OTLP Exporter", slog.String("endpoint", cfg.OTLPEndpoint))
+			return otlpExporter.Run(runCtx)
+		})
+	}
+
+	if alertEngine != nil {
+		g.Go(func() error {
+			logger.Info("Starting Alerting Engine", slog.String("rules", cfg.AlertRulesFile))
+			return alertEngine.Run(runCtx)
+		})
+	}
+
+	if prober != nil {
+		g.Go(func() error {
+			logger.Info("Starting Reachability Prober", slog.String("config", cfg.ProbesConfigFile))
+			return prober.Run(runCtx)
+		})
+	}
+
+	if captureWriter != nil {
+		g.Go(func() error {
+			logger.Info("Capturing vanflow messages", slog.String("file", cfg.CaptureFile))
+			// flush periodically so that the capture is usable should the
+			// process not exit cleanly
+			ticker := time.NewTicker(5 * time.Second)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-runCtx.Done():
+					return nil
+				case <-ticker.C:
+					if err := captureWriter.Flush(); err != nil {
+						return fmt.Errorf("failed to write capture file: %w", err)
+					}
+				}
+			}
+		})
+	}
+
+	for _, c := range collectors {
+		g.Go(func() error {
+			logger.Debug("Starting Network Observer Collector", slog.String("network", c.Network()))
+			if err := c.Run(runCtx); err != nil {
+				return fmt.Errorf("collector error: %w", err)
+			}
+			return nil
+		})
+	}
 
 	if err := g.Wait(); err != nil && !errors.Is(err, ctx.Err()) {
 		return err
@@ -203,6 +469,7 @@ func main() {
 	flags.StringVar(&cfg.RouterTLS.Key, "router-tls-key", "", "Path to the client key for the router endpoint")
 	flags.StringVar(&cfg.RouterTLS.CA, "router-tls-ca", "", "Path to the CA certificate file for the router endpoint")
 	flags.BoolVar(&cfg.RouterTLS.SkipVerify, "router-tls-insecure", false, "Set to skip verification of the router certificate and host name")
+	flags.StringVar(&cfg.NetworksFile, "networks-file", "", "Path to a yaml file listing the name, router endpoint and router tls configuration of each application network to observe. When set the router-endpoint and router-tls flags are ignored")
 
 	flags.StringVar(&cfg.APIListenAddress, "listen", ":8080", "The address that the API Server will listen on")
 	flags.BoolVar(&cfg.APIEnableAccessLogs, "enable-access-logs", false, "Enable access logging for the API Server")
@@ -214,10 +481,21 @@ func main() {
 	flags.StringVar(&cfg.PrometheusAPI, "prometheus-api", "http://127.0.0.1:9090", "Prometheus API HTTP endpoint for console")
 
 	flags.DurationVar(&cfg.FlowRecordTTL, "flow-record-ttl", 15*time.Minute, "How long to retain flow records in memory")
+	flags.StringVar(&cfg.RetentionConfigFile, "retention-config", "", "Path to a yaml file configuring how long terminated records are retained by type and an approximate memory limit for collected records")
+	flags.StringVar(&cfg.StoreDir, "store-dir", "", "Directory to persist collected records to so that they are retained across restarts. When unset records are kept in memory only")
+	flags.StringVar(&cfg.CaptureFile, "capture-file", "", "Path to a file to write every vanflow message received from the router to, for later use with replay-file")
+	flags.StringVar(&cfg.ReplayFile, "replay-file", "", "Path to a capture file to replay instead of connecting to a router")
+	flags.Float64Var(&cfg.ReplaySpeed, "replay-speed", 1, "Speed at which replay-file is replayed relative to real time. Zero replays the capture as quickly as possible")
+	flags.StringVar(&cfg.OTLPEndpoint, "otlp-endpoint", "", "Base URL of an OTLP/HTTP receiver (i.e. http://otel-collector:4318) to export connections and application flows as spans and metrics to. Export is disabled when unset")
+	flags.DurationVar(&cfg.OTLPMetricsInterval, "otlp-metrics-interval", 30*time.Second, "How often metrics are exported to the OTLP endpoint")
+	flags.StringVar(&cfg.AlertRulesFile, "alert-rules", "", "Path to a yaml file containing alerting rules and notification sinks. Alerting is disabled when unset")
+	flags.StringVar(&cfg.ProbesConfigFile, "probes-config", "", "Path to a yaml file containing targets for synthetic TCP reachability probes. Probing is disabled when unset")
+	flags.StringVar(&cfg.AuthConfigFile, "auth-config", "", "Path to a yaml file configuring authentication of API requests with static tokens, htpasswd or OIDC JWTs and the roles scoping the records each user can see. When unset the API is not authenticated")
 	flags.BoolVar(&cfg.CORSAllowAll, "cors-allow-all", false, "Development option to allow all origins")
 	flags.BoolVar(&cfg.EnableProfile, "profile", false, "Exposes the runtime profiling facilities from net/http/pprof on http://localhost:9970")
 
 	flags.StringVar(&cfg.VanflowLoggingProfile, "vanflow-logging-profile", "silent", "Controls low level vanflow record logging. Options are silent, minimal, moderate and all")
+	flags.StringVar(&cfg.FlowLoggingConfigFile, "flow-logging-config", "", "Path to a yaml file configuring flow loggers that write sampled vanflow records to dedicated stdout, rotating file or syslog sinks, separate from the operational logs")
 
 	flags.Parse(os.Args[1:])
 	if *isVersion {
@@ -241,15 +519,3 @@ func main() {
 		os.Exit(1)
 	}
 }
-
-func configureSession(tlsCfg TLSSpec) (ctrCfg session.ContainerConfig, err error) {
-	ctrCfg.TLSConfig, err = tlsCfg.config()
-	if err != nil {
-		return
-	}
-	if tlsCfg.hasCert() {
-		ctrCfg.SASLType = session.SASLTypeExternal
-	}
-
-	return ctrCfg, err
-}
@@ -5,11 +5,20 @@ import (
 	"github.com/skupperproject/skupper/pkg/vanflow"
 )
 
-var (
-	// loggingProfileMinimal logs 1 vanflow event per second (with bursts up to 32)
-	// reduces Link Record noise to 1 every ~20s.
-	// excludes network flow records
-	loggingProfileMinimal = []flowlog.Rule{
+// loggingProfiles are the built-in logging profiles by name. Each call returns
+// rules with their own rate limits so that profiles can be used by more than
+// one logger.
+var loggingProfiles = map[string]func() []flowlog.Rule{
+	"minimal":  loggingProfileMinimal,
+	"moderate": loggingProfileModerate,
+	"all":      loggingProfileAll,
+}
+
+// loggingProfileMinimal logs 1 vanflow event per second (with bursts up to 32)
+// reduces Link Record noise to 1 every ~20s.
+// excludes network flow records
+func loggingProfileMinimal() []flowlog.Rule {
+	return []flowlog.Rule{
 		{
 			Priority: 5,
 			Match: flowlog.NewRecordTypeSet(
@@ -25,9 +34,12 @@ var (
 			Strategy: flowlog.RateLimited(0.05, 32),
 		},
 	}
-	// loggingProfileModerate is similar to minimal but doubles rate and burst
-	// limits. Also samples 1 in every 10 network flows up to 2 events per second.
-	loggingProfileModerate = []flowlog.Rule{
+}
+
+// loggingProfileModerate is similar to minimal but doubles rate and burst
+// limits. Also samples 1 in every 10 network flows up to 2 events per second.
+func loggingProfileModerate() []flowlog.Rule {
+	return []flowlog.Rule{
 		{
 			Priority: 5,
 			Match:    flowlog.NewRecordTypeSetAll(),
@@ -45,11 +57,14 @@ var (
 			Strategy: flowlog.TransportFlowHash(0.1, flowlog.RateLimited(2.0, 64)),
 		},
 	}
-	// loggingProfileAll logs all vanflow events.
-	loggingProfileAll = []flowlog.Rule{
+}
+
+// loggingProfileAll logs all vanflow events.
+func loggingProfileAll() []flowlog.Rule {
+	return []flowlog.Rule{
 		{
 			Match:    flowlog.NewRecordTypeSetAll(),
 			Strategy: flowlog.Unlimited(),
 		},
 	}
-)
+}
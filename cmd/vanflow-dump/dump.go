@@ -0,0 +1,429 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/eventsource"
+	"github.com/skupperproject/skupper/pkg/vanflow/session"
+)
+
+// beaconAddress is the address all event sources send beacons to
+const beaconAddress = "mc/sfe.all"
+
+// recordTypes are the record types sent by event sources
+var recordTypes = []vanflow.Record{
+	vanflow.SiteRecord{},
+	vanflow.RouterRecord{},
+	vanflow.LinkRecord{},
+	vanflow.ControllerRecord{},
+	vanflow.ListenerRecord{},
+	vanflow.ConnectorRecord{},
+	vanflow.FlowRecord{},
+	vanflow.ProcessRecord{},
+	vanflow.HostRecord{},
+	vanflow.LogRecord{},
+	vanflow.RouterAccessRecord{},
+	vanflow.TransportBiflowRecord{},
+	vanflow.AppBiflowRecord{},
+}
+
+// filter selects the messages that are printed
+type filter struct {
+	// Sources are the IDs of the event sources to print messages from. All
+	// sources when empty.
+	Sources map[string]bool
+	// Types are the record types to print. All types when empty.
+	Types      map[string]bool
+	Beacons    bool
+	Heartbeats bool
+}
+
+func newFilter(sources []string, types []string) (filter, error) {
+	f := filter{Beacons: true, Heartbeats: true}
+	if len(sources) > 0 {
+		f.Sources = make(map[string]bool, len(sources))
+		for _, id := range sources {
+			f.Sources[id] = true
+		}
+	}
+	if len(types) > 0 {
+		known := make(map[string]string, len(recordTypes))
+		for _, record := range recordTypes {
+			typ := record.GetTypeMeta().Type
+			known[strings.ToLower(typ)] = typ
+		}
+		f.Types = make(map[string]bool, len(types))
+		for _, typ := range types {
+			name, ok := known[strings.ToLower(typ)]
+			if !ok {
+				return f, fmt.Errorf("unknown record type %q", typ)
+			}
+			f.Types[name] = true
+		}
+	}
+	return f, nil
+}
+
+func (f filter) source(id string) bool {
+	return len(f.Sources) == 0 || f.Sources[id]
+}
+
+func (f filter) recordType(typ string) bool {
+	return len(f.Types) == 0 || f.Types[typ]
+}
+
+type outputFormat string
+
+const (
+	formatText outputFormat = "text"
+	formatJSON outputFormat = "json"
+)
+
+func parseFormat(s string) (outputFormat, error) {
+	switch format := outputFormat(s); format {
+	case formatText, formatJSON:
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown output format %q: expected text or json", s)
+}
+
+// event is a single printed message or record
+type event struct {
+	Time       time.Time      `json:"time"`
+	Kind       string         `json:"kind"`
+	Source     string         `json:"source"`
+	Type       string         `json:"type,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+
+	// keys in the order attributes were set
+	keys []string
+}
+
+func (e *event) set(key string, value any) {
+	if e.Attributes == nil {
+		e.Attributes = make(map[string]any)
+	}
+	e.keys = append(e.keys, key)
+	e.Attributes[key] = value
+}
+
+// printer writes events to an output as lines of text or json
+type printer struct {
+	mu     sync.Mutex
+	out    io.Writer
+	format outputFormat
+	now    func() time.Time
+}
+
+func newPrinter(out io.Writer, format outputFormat) *printer {
+	return &printer{
+		out:    out,
+		format: format,
+		now:    time.Now,
+	}
+}
+
+func (p *printer) Beacon(beacon vanflow.BeaconMessage) {
+	ev := event{Kind: "BEACON", Source: beacon.Identity, Type: beacon.SourceType}
+	ev.set("version", beacon.Version)
+	ev.set("address", beacon.Address)
+	ev.set("direct", beacon.Direct)
+	p.print(ev)
+}
+
+func (p *printer) Heartbeat(source eventsource.Info, heartbeat vanflow.HeartbeatMessage) {
+	ev := event{Kind: "HEARTBEAT", Source: source.ID, Type: source.Type}
+	ev.set("version", heartbeat.Version)
+	ev.set("now", time.UnixMicro(int64(heartbeat.Now)))
+	p.print(ev)
+}
+
+func (p *printer) Record(source eventsource.Info, record vanflow.Record) {
+	ev := event{Kind: "RECORD", Source: source.ID, Type: record.GetTypeMeta().Type}
+	v := reflect.ValueOf(record)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		setRecordAttributes(&ev, v)
+	}
+	p.print(ev)
+}
+
+// setRecordAttributes sets an attribute for each field of a record that is
+// set, including those of embedded structs
+func setRecordAttributes(ev *event, v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field, fv := v.Type().Field(i), v.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && fv.Kind() == reflect.Struct {
+			setRecordAttributes(ev, fv)
+			continue
+		}
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		value := fv.Interface()
+		if t, ok := value.(vanflow.Time); ok {
+			value = t.Time
+		}
+		ev.set(field.Name, value)
+	}
+}
+
+func (p *printer) print(ev event) {
+	ev.Time = p.now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var err error
+	switch p.format {
+	case formatJSON:
+		err = json.NewEncoder(p.out).Encode(ev)
+	default:
+		_, err = fmt.Fprintln(p.out, textLine(ev))
+	}
+	if err != nil {
+		slog.Error("error writing output", slog.Any("error", err))
+	}
+}
+
+func textLine(ev event) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %-9s %s", ev.Time.Format("15:04:05.000000"), ev.Kind, ev.Source)
+	if ev.Type != "" {
+		fmt.Fprintf(&sb, " %s", ev.Type)
+	}
+	for _, key := range ev.keys {
+		fmt.Fprintf(&sb, " %s=%s", key, formatValue(ev.Attributes[key]))
+	}
+	return sb.String()
+}
+
+func formatValue(value any) string {
+	switch value := value.(type) {
+	case time.Time:
+		return value.UTC().Format(time.RFC3339Nano)
+	case string:
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			return fmt.Sprintf("%q", value)
+		}
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// dumper subscribes to event sources and prints the messages they emit
+type dumper struct {
+	factory session.ContainerFactory
+	printer *printer
+	filter  filter
+	// Flush requests the full set of records from each event source when
+	// subscribing to it
+	Flush bool
+
+	logger  *slog.Logger
+	mu      sync.Mutex
+	clients map[string]*eventsource.Client
+}
+
+func newDumper(factory session.ContainerFactory, p *printer, f filter) *dumper {
+	return &dumper{
+		factory: factory,
+		printer: p,
+		filter:  f,
+		logger:  slog.Default().With(slog.String("component", "vanflow-dump")),
+		clients: make(map[string]*eventsource.Client),
+	}
+}
+
+// start creates and starts a container that cancels ctx with an
+// unrecoverable session error
+func (d *dumper) start(ctx context.Context, cancel context.CancelCauseFunc) session.Container {
+	container := d.factory.Create()
+	container.OnSessionError(func(err error) {
+		if _, retryable := err.(session.RetryableError); retryable {
+			d.logger.Error("amqp session error", slog.Any("error", err))
+			return
+		}
+		cancel(fmt.Errorf("unrecoverable session error: %w", err))
+	})
+	container.Start(ctx)
+	return container
+}
+
+// Run subscribes to the event sources matching the filter as they are
+// discovered and prints their messages until ctx is cancelled
+func (d *dumper) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	container := d.start(ctx, cancel)
+	discovery := eventsource.NewDiscovery(container, eventsource.DiscoveryOptions{})
+	if d.filter.Beacons {
+		go d.printBeacons(ctx, container)
+	}
+	err := discovery.Run(ctx, eventsource.DiscoveryHandlers{
+		Discovered: func(source eventsource.Info) {
+			if !d.filter.source(source.ID) {
+				return
+			}
+			d.subscribe(ctx, container, discovery, source)
+		},
+		Forgotten: d.unsubscribe,
+	})
+	if cause := context.Cause(ctx); cause != nil && !errors.Is(cause, context.Canceled) {
+		return cause
+	}
+	return err
+}
+
+// List prints a table of the event sources matching the filter that are
+// discovered before ctx is done
+func (d *dumper) List(ctx context.Context, out io.Writer) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	container := d.start(ctx, cancel)
+	discovery := eventsource.NewDiscovery(container, eventsource.DiscoveryOptions{})
+	discovery.Run(ctx, eventsource.DiscoveryHandlers{})
+	if cause := context.Cause(ctx); cause != nil && !errors.Is(cause, context.Canceled) && !errors.Is(cause, context.DeadlineExceeded) {
+		return cause
+	}
+
+	var sources []eventsource.Info
+	for _, source := range discovery.List() {
+		if d.filter.source(source.ID) {
+			sources = append(sources, source)
+		}
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].ID < sources[j].ID
+	})
+	if d.printer.format == formatJSON {
+		enc := json.NewEncoder(out)
+		for _, source := range sources {
+			if err := enc.Encode(source); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTYPE\tVERSION\tADDRESS\tDIRECT")
+	for _, source := range sources {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", source.ID, source.Type, source.Version, source.Address, source.Direct)
+	}
+	return tw.Flush()
+}
+
+func (d *dumper) printBeacons(ctx context.Context, container session.Container) {
+	receiver := container.NewReceiver(beaconAddress, session.ReceiverOptions{})
+	defer receiver.Close(context.Background())
+	for {
+		msg, err := receiver.Next(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				d.logger.Error("error receiving beacon messages", slog.Any("error", err))
+			}
+			return
+		}
+		if err := receiver.Accept(ctx, msg); err != nil {
+			d.logger.Error("error accepting beacon message", slog.Any("error", err))
+		}
+		decoded, err := vanflow.Decode(msg)
+		if err != nil {
+			d.logger.Error("skipping beacon that could not be decoded", slog.Any("error", err))
+			continue
+		}
+		if beacon, ok := decoded.(vanflow.BeaconMessage); ok && d.filter.source(beacon.Identity) {
+			d.printer.Beacon(beacon)
+		}
+	}
+}
+
+func (d *dumper) subscribe(ctx context.Context, container session.Container, discovery *eventsource.Discovery, source eventsource.Info) {
+	d.logger.Info("subscribing to event source", slog.String("id", source.ID), slog.String("type", source.Type))
+	client := eventsource.NewClient(container, eventsource.ClientOptions{Source: source})
+	err := discovery.NewWatchClient(ctx, eventsource.WatchConfig{
+		Client:      client,
+		ID:          source.ID,
+		Timeout:     time.Second * 30,
+		GracePeriod: time.Second * 30,
+	})
+	if err != nil {
+		d.logger.Error("error creating watcher for discovered source", slog.Any("error", err))
+		return
+	}
+	client.OnRecord(func(msg vanflow.RecordMessage) {
+		for _, record := range msg.Records {
+			if d.filter.recordType(record.GetTypeMeta().Type) {
+				d.printer.Record(source, record)
+			}
+		}
+	})
+	if d.filter.Heartbeats {
+		client.OnHeartbeat(func(msg vanflow.HeartbeatMessage) {
+			d.printer.Heartbeat(source, msg)
+		})
+	}
+
+	addresses := []eventsource.ListenerConfigProvider{
+		eventsource.FromSourceAddress(),
+	}
+	switch source.Type {
+	case "CONTROLLER":
+		addresses = append(addresses, eventsource.FromSourceAddressHeartbeats())
+	case "ROUTER":
+		addresses = append(addresses, eventsource.FromSourceAddressFlows())
+	}
+	for _, address := range addresses {
+		client.Listen(ctx, address)
+	}
+
+	d.mu.Lock()
+	d.clients[source.ID] = client
+	d.mu.Unlock()
+
+	if !d.Flush {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+		defer cancel()
+		if err := eventsource.FlushOnFirstMessage(ctx, client); err != nil {
+			if errors.Is(err, ctx.Err()) {
+				err = client.SendFlush(ctx)
+			}
+			if err != nil {
+				d.logger.Error("error sending flush", slog.String("id", source.ID), slog.Any("error", err))
+			}
+		}
+	}()
+}
+
+func (d *dumper) unsubscribe(source eventsource.Info) {
+	d.mu.Lock()
+	client, ok := d.clients[source.ID]
+	delete(d.clients, source.ID)
+	d.mu.Unlock()
+	if ok {
+		d.logger.Info("event source forgotten", slog.String("id", source.ID))
+		client.Close()
+	}
+}
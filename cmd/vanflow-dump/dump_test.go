@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/eventsource"
+	"github.com/skupperproject/skupper/pkg/vanflow/publisher"
+	"github.com/skupperproject/skupper/pkg/vanflow/session"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/poll"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func runPublisher(t *testing.T, ctx context.Context, factory session.ContainerFactory, id string, records ...vanflow.Record) {
+	t.Helper()
+	pub, err := publisher.New(factory, publisher.Config{
+		ID:                id,
+		HeartbeatInterval: 20 * time.Millisecond,
+		BeaconInterval:    20 * time.Millisecond,
+		UpdateBufferTime:  10 * time.Millisecond,
+	})
+	assert.NilError(t, err)
+	for _, record := range records {
+		assert.NilError(t, pub.Publish(record))
+	}
+	go pub.Run(ctx)
+}
+
+func TestDumper(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	factory := session.NewMockContainerFactory()
+	runPublisher(t, ctx, factory, "agent-1",
+		vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1"), Name: ptrTo("west")},
+		vanflow.ProcessRecord{BaseRecord: vanflow.NewBase("process-1"), Parent: ptrTo("site-1"), Name: ptrTo("backend")},
+	)
+	runPublisher(t, ctx, factory, "agent-2",
+		vanflow.ProcessRecord{BaseRecord: vanflow.NewBase("process-2"), Parent: ptrTo("site-1")},
+	)
+
+	var out syncBuffer
+	f, err := newFilter([]string{"agent-1"}, []string{"processrecord"})
+	assert.NilError(t, err)
+	d := newDumper(factory, newPrinter(&out, formatJSON), f)
+	d.Flush = true
+	done := make(chan error, 1)
+	go func() { done <- d.Run(ctx) }()
+
+	seen := func() map[string]bool {
+		kinds := make(map[string]bool)
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var ev event
+			assert.NilError(t, json.Unmarshal([]byte(line), &ev))
+			assert.Equal(t, ev.Source, "agent-1")
+			key := ev.Kind
+			if ev.Kind == "RECORD" {
+				assert.Equal(t, ev.Type, "ProcessRecord")
+				key += "/" + ev.Attributes["ID"].(string)
+				assert.Equal(t, ev.Attributes["Name"], "backend")
+			}
+			kinds[key] = true
+		}
+		return kinds
+	}
+	poll.WaitOn(t, func(poll.LogT) poll.Result {
+		kinds := seen()
+		for _, expected := range []string{"BEACON", "HEARTBEAT", "RECORD/process-1"} {
+			if !kinds[expected] {
+				return poll.Continue("waiting for %s", expected)
+			}
+		}
+		return poll.Success()
+	}, poll.WithDelay(10*time.Millisecond), poll.WithTimeout(5*time.Second))
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestList(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	factory := session.NewMockContainerFactory()
+	runPublisher(t, ctx, factory, "agent-2")
+	runPublisher(t, ctx, factory, "agent-1")
+
+	var out bytes.Buffer
+	f, err := newFilter(nil, nil)
+	assert.NilError(t, err)
+	d := newDumper(factory, newPrinter(&out, formatText), f)
+	listCtx, listCancel := context.WithTimeout(ctx, 500*time.Millisecond)
+	defer listCancel()
+	assert.NilError(t, d.List(listCtx, &out))
+	assert.Equal(t, out.String(), strings.Join([]string{
+		"ID       TYPE        VERSION  ADDRESS         DIRECT",
+		"agent-1  CONTROLLER  1        mc/sfe.agent-1  sfe.agent-1",
+		"agent-2  CONTROLLER  1        mc/sfe.agent-2  sfe.agent-2",
+		"",
+	}, "\n"))
+}
+
+func TestPrinterText(t *testing.T) {
+	var out bytes.Buffer
+	p := newPrinter(&out, formatText)
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+	p.now = func() time.Time { return ts }
+	source := eventsource.Info{ID: "router-1", Type: "ROUTER"}
+
+	p.Beacon(vanflow.BeaconMessage{Identity: "router-1", SourceType: "ROUTER", Version: 1, Address: "mc/sfe.router-1", Direct: "sfe.router-1"})
+	p.Heartbeat(source, vanflow.HeartbeatMessage{Version: 1, Now: uint64(ts.UnixMicro())})
+	p.Record(source, vanflow.SiteRecord{
+		BaseRecord: vanflow.NewBase("site-1", ts),
+		Name:       ptrTo("west"),
+		Location:   ptrTo("data center"),
+	})
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.DeepEqual(t, lines, []string{
+		"03:04:05.000006 BEACON    router-1 ROUTER version=1 address=mc/sfe.router-1 direct=sfe.router-1",
+		"03:04:05.000006 HEARTBEAT router-1 ROUTER version=1 now=2024-01-02T03:04:05.000006Z",
+		`03:04:05.000006 RECORD    router-1 SiteRecord ID=site-1 StartTime=2024-01-02T03:04:05.000006Z Location="data center" Name=west`,
+	})
+}
+
+func TestNewFilter(t *testing.T) {
+	f, err := newFilter([]string{"a"}, []string{"siterecord", "LogRecord"})
+	assert.NilError(t, err)
+	assert.Assert(t, f.source("a"))
+	assert.Assert(t, !f.source("b"))
+	assert.Assert(t, f.recordType("SiteRecord"))
+	assert.Assert(t, f.recordType("LogRecord"))
+	assert.Assert(t, !f.recordType("ProcessRecord"))
+
+	_, err = newFilter(nil, []string{"Site"})
+	assert.ErrorContains(t, err, `unknown record type "Site"`)
+	_, err = parseFormat("yaml")
+	assert.ErrorContains(t, err, `unknown output format "yaml"`)
+}
+
+func ptrTo[T any](obj T) *T { return &obj }
@@ -0,0 +1,147 @@
+// vanflow-dump connects to a skupper router and prints the vanflow messages
+// emitted by the event sources it discovers.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/skupperproject/skupper/internal/version"
+	"github.com/skupperproject/skupper/pkg/vanflow/capture"
+	"github.com/skupperproject/skupper/pkg/vanflow/session"
+)
+
+type Config struct {
+	RouterURL        string
+	RouterTLS        session.TLSSpec
+	CaptureFile      string
+	ReplayFile       string
+	ReplaySpeed      float64
+	List             bool
+	DiscoveryTimeout time.Duration
+	Sources          string
+	Types            string
+	Output           string
+	Beacons          bool
+	Heartbeats       bool
+	Flush            bool
+}
+
+func run(cfg Config) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	if cfg.CaptureFile != "" && cfg.ReplayFile != "" {
+		return fmt.Errorf("capture-file and replay-file cannot be used together")
+	}
+	format, err := parseFormat(cfg.Output)
+	if err != nil {
+		return err
+	}
+	filter, err := newFilter(splitList(cfg.Sources), splitList(cfg.Types))
+	if err != nil {
+		return err
+	}
+	filter.Beacons = cfg.Beacons
+	filter.Heartbeats = cfg.Heartbeats
+
+	var factory session.ContainerFactory
+	switch {
+	case cfg.ReplayFile != "":
+		replayFile, err := os.Open(cfg.ReplayFile)
+		if err != nil {
+			return fmt.Errorf("failed to open replay file: %s", err)
+		}
+		defer replayFile.Close()
+		reader, err := capture.NewReader(replayFile)
+		if err != nil {
+			return fmt.Errorf("failed to read replay file: %s", err)
+		}
+		factory = capture.NewReplayContainerFactory(reader, capture.ReplayOptions{
+			Speed: cfg.ReplaySpeed,
+		})
+	default:
+		sessionConfig, err := cfg.RouterTLS.ContainerConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load router tls configuration: %s", err)
+		}
+		factory = session.NewContainerFactory(cfg.RouterURL, sessionConfig)
+	}
+	if cfg.CaptureFile != "" {
+		captureFile, err := os.Create(cfg.CaptureFile)
+		if err != nil {
+			return fmt.Errorf("failed to create capture file: %s", err)
+		}
+		defer captureFile.Close()
+		writer := capture.NewWriter(captureFile)
+		defer func() {
+			if err := writer.Close(); err != nil {
+				slog.Error("error closing capture file", slog.Any("error", err))
+			}
+		}()
+		factory = capture.NewCaptureContainerFactory(factory, writer, nil)
+	}
+
+	d := newDumper(factory, newPrinter(os.Stdout, format), filter)
+	if cfg.List {
+		listCtx, listCancel := context.WithTimeout(ctx, cfg.DiscoveryTimeout)
+		defer listCancel()
+		return d.List(listCtx, os.Stdout)
+	}
+	d.Flush = cfg.Flush
+	if err := d.Run(ctx); err != nil && !errors.Is(err, ctx.Err()) {
+		return err
+	}
+	return nil
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func main() {
+	var cfg Config
+	flags := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	isVersion := flags.Bool("version", false, "Report the version of skupper vanflow-dump was built against")
+
+	flags.StringVar(&cfg.RouterURL, "router-endpoint", "amqps://skupper-router-local", "URL to the skupper router amqp(s) endpoint")
+	flags.StringVar(&cfg.RouterTLS.Cert, "router-tls-cert", "", "Path to the client certificate for the router endpoint")
+	flags.StringVar(&cfg.RouterTLS.Key, "router-tls-key", "", "Path to the client key for the router endpoint")
+	flags.StringVar(&cfg.RouterTLS.CA, "router-tls-ca", "", "Path to the CA certificate file for the router endpoint")
+	flags.BoolVar(&cfg.RouterTLS.SkipVerify, "router-tls-insecure", false, "Set to skip verification of the router certificate and host name")
+	flags.StringVar(&cfg.CaptureFile, "capture-file", "", "Path to a file to write every vanflow message received to, for later use with replay-file")
+	flags.StringVar(&cfg.ReplayFile, "replay-file", "", "Path to a capture file to read messages from instead of connecting to a router")
+	flags.Float64Var(&cfg.ReplaySpeed, "replay-speed", 0, "Speed at which replay-file is replayed relative to real time. Zero replays the capture as quickly as possible")
+
+	flags.BoolVar(&cfg.List, "list", false, "List the event sources discovered within discovery-timeout and exit")
+	flags.DurationVar(&cfg.DiscoveryTimeout, "discovery-timeout", 15*time.Second, "How long to wait for event source beacons when listing event sources")
+	flags.StringVar(&cfg.Sources, "source", "", "Comma separated list of event source IDs to subscribe to. Subscribes to all discovered sources when unset")
+	flags.StringVar(&cfg.Types, "type", "", "Comma separated list of record types to print (i.e. SiteRecord,ProcessRecord). Prints all record types when unset")
+	flags.StringVar(&cfg.Output, "output", "text", "Output format. One of text or json")
+	flags.BoolVar(&cfg.Beacons, "beacons", true, "Print beacon messages")
+	flags.BoolVar(&cfg.Heartbeats, "heartbeats", true, "Print heartbeat messages")
+	flags.BoolVar(&cfg.Flush, "flush", true, "Send a flush request to each event source subscribed to so that its full set of records is printed")
+
+	flags.Parse(os.Args[1:])
+	if *isVersion {
+		fmt.Println(version.Version)
+		os.Exit(0)
+	}
+	if err := run(cfg); err != nil {
+		slog.Error("vanflow-dump run error", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
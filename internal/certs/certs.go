@@ -137,7 +137,7 @@ func GenerateSecret(name string, subject string, hosts string, expiration time.D
 	if caCert == nil {
 		// self signed
 		template.IsCA = true
-		template.KeyUsage |= x509.KeyUsageCertSign
+		template.KeyUsage |= x509.KeyUsageCertSign | x509.KeyUsageCRLSign
 		parent = &template
 		cakey = priv
 	} else {
@@ -0,0 +1,193 @@
+package certs
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// RenewalConfig determines when generated certificates are renewed
+// and for how long a replaced CA continues to be trusted.
+type RenewalConfig struct {
+	// Duration is the validity period of generated (non-CA) certificates
+	Duration time.Duration
+	// RenewBefore is how long before expiry a certificate is renewed
+	RenewBefore time.Duration
+	// CaRenewBefore is how long before expiry a CA is rotated
+	CaRenewBefore time.Duration
+	// CaRotationOverlap is how long a replaced CA remains trusted
+	// alongside its replacement
+	CaRotationOverlap time.Duration
+}
+
+// The certificate and key of a replaced CA are retained in these
+// entries of the CA's secret until the rotation overlap has ended.
+const (
+	PreviousCaCertificate = "previous-ca.crt"
+	PreviousCaKey         = "previous-ca.key"
+)
+
+const (
+	defaultDuration          = 5 * 365 * 24 * time.Hour
+	defaultRenewBefore       = 30 * 24 * time.Hour
+	defaultCaRenewBefore     = 90 * 24 * time.Hour
+	defaultCaRotationOverlap = 7 * 24 * time.Hour
+)
+
+func DefaultRenewalConfig() RenewalConfig {
+	return RenewalConfig{
+		Duration:          defaultDuration,
+		RenewBefore:       defaultRenewBefore,
+		CaRenewBefore:     defaultCaRenewBefore,
+		CaRotationOverlap: defaultCaRotationOverlap,
+	}
+}
+
+// RenewalConfigFromSettings returns the default configuration
+// overridden by any of the certificate-duration,
+// certificate-renew-before, ca-renew-before or ca-rotation-overlap
+// keys present in the supplied settings.
+func RenewalConfigFromSettings(settings map[string]string) (RenewalConfig, error) {
+	config := DefaultRenewalConfig()
+	for key, field := range map[string]*time.Duration{
+		"certificate-duration":     &config.Duration,
+		"certificate-renew-before": &config.RenewBefore,
+		"ca-renew-before":          &config.CaRenewBefore,
+		"ca-rotation-overlap":      &config.CaRotationOverlap,
+	} {
+		if value, ok := settings[key]; ok {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return config, fmt.Errorf("invalid value for %s: %s", key, err)
+			}
+			*field = d
+		}
+	}
+	return config, config.Verify()
+}
+
+func (c RenewalConfig) Verify() error {
+	if c.Duration <= 0 || c.RenewBefore < 0 || c.CaRenewBefore < 0 || c.CaRotationOverlap < 0 {
+		return fmt.Errorf("certificate durations must not be negative and certificate-duration must be positive")
+	}
+	if c.RenewBefore >= c.Duration {
+		return fmt.Errorf("certificate-renew-before (%s) must be less than certificate-duration (%s)", c.RenewBefore, c.Duration)
+	}
+	if c.CaRotationOverlap > c.CaRenewBefore {
+		return fmt.Errorf("ca-rotation-overlap (%s) must not exceed ca-renew-before (%s), as the replaced CA would expire during the overlap", c.CaRotationOverlap, c.CaRenewBefore)
+	}
+	return nil
+}
+
+// RenewalTime returns the point at which a certificate should be
+// renewed.
+func RenewalTime(cert *x509.Certificate, renewBefore time.Duration) time.Time {
+	return cert.NotAfter.Add(-renewBefore)
+}
+
+// RenewalDue returns true if the certificate expires within the
+// renewal window.
+func RenewalDue(cert *x509.Certificate, renewBefore time.Duration, now time.Time) bool {
+	return !now.Before(RenewalTime(cert, renewBefore))
+}
+
+// DecodeCertificates returns all the certificates that can be
+// decoded from a PEM bundle.
+func DecodeCertificates(data []byte) []*x509.Certificate {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return certs
+		}
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			certs = append(certs, cert)
+		}
+	}
+}
+
+// TrustBundle returns the CA certificates that certificates issued by
+// the supplied CA should trust. While a CA rotation is in progress,
+// this includes the replaced CA as well as the current one.
+func TrustBundle(ca *corev1.Secret) []byte {
+	current := ca.Data["tls.crt"]
+	if bundle, ok := ca.Data["ca.crt"]; ok && len(current) > 0 && bytes.Contains(bundle, current) {
+		return bundle
+	}
+	return current
+}
+
+// IssuedBy returns true if the certificate was signed by one of the
+// CA certificates in the bundle.
+func IssuedBy(cert *x509.Certificate, bundle []byte) bool {
+	for _, ca := range DecodeCertificates(bundle) {
+		if cert.CheckSignatureFrom(ca) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// RotateCA generates a new CA to replace that in the supplied
+// secret. Unless it has already expired, the replaced CA is retained
+// in the ca.crt bundle of the returned secret, and continues to be
+// used by SigningCA, until EndCARotation is called.
+func RotateCA(name string, subject string, current *corev1.Secret) corev1.Secret {
+	secret := GenerateSecret(name, subject, "", 0, nil)
+	bundle := append([]byte{}, secret.Data["tls.crt"]...)
+	if previous, err := DecodeCertificate(current.Data["tls.crt"]); err == nil && time.Now().Before(previous.NotAfter) {
+		bundle = append(bundle, current.Data["tls.crt"]...)
+		secret.Data[PreviousCaCertificate] = current.Data["tls.crt"]
+		secret.Data[PreviousCaKey] = current.Data["tls.key"]
+	}
+	secret.Data["ca.crt"] = bundle
+	return secret
+}
+
+// SigningCA returns the CA that new certificates should be issued
+// by. While a rotation is in progress that is the replaced CA, as
+// not everything yet trusts its replacement.
+func SigningCA(ca *corev1.Secret, overlap time.Duration, now time.Time) *corev1.Secret {
+	end, ok := RotationEnd(ca, overlap)
+	if !ok || !now.Before(end) || len(ca.Data[PreviousCaCertificate]) == 0 || len(ca.Data[PreviousCaKey]) == 0 {
+		return ca
+	}
+	signer := ca.DeepCopy()
+	signer.Data["tls.crt"] = ca.Data[PreviousCaCertificate]
+	signer.Data["tls.key"] = ca.Data[PreviousCaKey]
+	return signer
+}
+
+// RotationEnd returns the time at which the CA in the supplied secret
+// should stop trusting the CA it replaced, or false if no rotation is
+// in progress.
+func RotationEnd(ca *corev1.Secret, overlap time.Duration) (time.Time, bool) {
+	current := ca.Data["tls.crt"]
+	bundle, ok := ca.Data["ca.crt"]
+	if !ok || bytes.Equal(bundle, current) || !bytes.HasPrefix(bundle, current) {
+		return time.Time{}, false
+	}
+	cert, err := DecodeCertificate(current)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return cert.NotBefore.Add(overlap), true
+}
+
+// EndCARotation drops the replaced CA from the ca.crt bundle once the
+// overlap has elapsed, returning true if the secret was changed.
+func EndCARotation(ca *corev1.Secret, overlap time.Duration, now time.Time) bool {
+	end, ok := RotationEnd(ca, overlap)
+	if !ok || now.Before(end) {
+		return false
+	}
+	ca.Data["ca.crt"] = ca.Data["tls.crt"]
+	delete(ca.Data, PreviousCaCertificate)
+	delete(ca.Data, PreviousCaKey)
+	return true
+}
@@ -0,0 +1,99 @@
+package certs
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRenewalConfigFromSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]string
+		expected RenewalConfig
+		err      string
+	}{
+		{
+			name:     "defaults",
+			expected: DefaultRenewalConfig(),
+		},
+		{
+			name: "overrides",
+			settings: map[string]string{
+				"certificate-duration":     "2160h",
+				"certificate-renew-before": "240h",
+				"ca-renew-before":          "720h",
+				"ca-rotation-overlap":      "48h",
+			},
+			expected: RenewalConfig{
+				Duration:          2160 * time.Hour,
+				RenewBefore:       240 * time.Hour,
+				CaRenewBefore:     720 * time.Hour,
+				CaRotationOverlap: 48 * time.Hour,
+			},
+		},
+		{
+			name: "bad duration",
+			settings: map[string]string{
+				"ca-renew-before": "a month",
+			},
+			err: "invalid value for ca-renew-before",
+		},
+		{
+			name: "renewal window too long",
+			settings: map[string]string{
+				"certificate-duration": "24h",
+			},
+			err: "certificate-renew-before (720h0m0s) must be less than certificate-duration (24h0m0s)",
+		},
+		{
+			name: "overlap too long",
+			settings: map[string]string{
+				"ca-rotation-overlap": "2400h",
+			},
+			err: "ca-rotation-overlap (2400h0m0s) must not exceed ca-renew-before (2160h0m0s)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := RenewalConfigFromSettings(tt.settings)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, config, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRotateCA(t *testing.T) {
+	previous := GenerateSecret("my-ca", "my-ca", "", time.Hour, nil)
+	leaf := GenerateSecret("leaf", "leaf", "leaf", 0, &previous)
+	leafCert, err := DecodeCertificate(leaf.Data["tls.crt"])
+	assert.NilError(t, err)
+	now := time.Now()
+	assert.Assert(t, RenewalDue(leafCert, 24*time.Hour, now.Add(5*365*24*time.Hour)))
+	assert.Assert(t, !RenewalDue(leafCert, 24*time.Hour, now))
+
+	rotated := RotateCA("my-ca", "my-ca", &previous)
+	assert.Equal(t, len(DecodeCertificates(rotated.Data["ca.crt"])), 2)
+	assert.DeepEqual(t, TrustBundle(&rotated), rotated.Data["ca.crt"])
+	assert.Assert(t, IssuedBy(leafCert, TrustBundle(&rotated)))
+	assert.Assert(t, !IssuedBy(leafCert, rotated.Data["tls.crt"]))
+
+	signer := SigningCA(&rotated, time.Hour, now)
+	assert.DeepEqual(t, signer.Data["tls.crt"], previous.Data["tls.crt"])
+	assert.DeepEqual(t, SigningCA(&rotated, time.Hour, now.Add(2*time.Hour)).Data["tls.crt"], rotated.Data["tls.crt"])
+
+	end, ok := RotationEnd(&rotated, time.Hour)
+	assert.Assert(t, ok)
+	assert.Assert(t, !EndCARotation(&rotated, time.Hour, end.Add(-time.Minute)))
+	assert.Assert(t, EndCARotation(&rotated, time.Hour, end))
+	assert.DeepEqual(t, rotated.Data["ca.crt"], rotated.Data["tls.crt"])
+	_, ok = rotated.Data[PreviousCaKey]
+	assert.Assert(t, !ok)
+	_, ok = RotationEnd(&rotated, time.Hour)
+	assert.Assert(t, !ok)
+}
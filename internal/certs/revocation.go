@@ -0,0 +1,117 @@
+package certs
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// RevocationList returns PEM encoded certificate revocation lists,
+// signed by the CA in the supplied secret, that revoke the
+// certificates with the supplied (hexadecimal) serial numbers as of
+// the times given for them. While a CA rotation is in progress, a
+// list signed by the replaced CA is included as well, as certificates
+// issued during the rotation were signed by it. The lists remain
+// valid until the CA that signed them expires.
+func RevocationList(ca *corev1.Secret, revoked map[string]time.Time, now time.Time) ([]byte, error) {
+	entries, err := revocationEntries(revoked)
+	if err != nil {
+		return nil, err
+	}
+	var crls []byte
+	for _, keys := range [][2]string{{"tls.crt", "tls.key"}, {PreviousCaCertificate, PreviousCaKey}} {
+		if len(ca.Data[keys[0]]) == 0 || len(ca.Data[keys[1]]) == 0 {
+			continue
+		}
+		crl, err := signRevocationList(ca.Data[keys[0]], ca.Data[keys[1]], entries, now)
+		if err != nil {
+			return nil, err
+		}
+		crls = append(crls, crl...)
+	}
+	if len(crls) == 0 {
+		return nil, errors.New("No CA certificate and key found")
+	}
+	return crls, nil
+}
+
+// RevokedSerialNumbers returns the (hexadecimal) serial numbers of the
+// certificates revoked by the supplied PEM encoded revocation lists
+// that were signed by the CA in the supplied secret. It returns false
+// if there are no such lists.
+func RevokedSerialNumbers(data []byte, ca *corev1.Secret) ([]string, bool) {
+	cert, err := DecodeCertificate(ca.Data["tls.crt"])
+	if err != nil {
+		return nil, false
+	}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, false
+		}
+		crl, err := x509.ParseRevocationList(block.Bytes)
+		if err != nil || crl.CheckSignatureFrom(cert) != nil {
+			continue
+		}
+		var serials []string
+		for _, entry := range crl.RevokedCertificateEntries {
+			serials = append(serials, entry.SerialNumber.Text(16))
+		}
+		sort.Strings(serials)
+		return serials, true
+	}
+}
+
+func revocationEntries(revoked map[string]time.Time) ([]x509.RevocationListEntry, error) {
+	var serials []string
+	for serial := range revoked {
+		serials = append(serials, serial)
+	}
+	sort.Strings(serials)
+	var entries []x509.RevocationListEntry
+	for _, serial := range serials {
+		number, ok := new(big.Int).SetString(serial, 16)
+		if !ok {
+			return nil, fmt.Errorf("Invalid serial number %q", serial)
+		}
+		entries = append(entries, x509.RevocationListEntry{
+			SerialNumber:   number,
+			RevocationTime: revoked[serial].UTC(),
+		})
+	}
+	return entries, nil
+}
+
+func signRevocationList(certData []byte, keyData []byte, entries []x509.RevocationListEntry, now time.Time) ([]byte, error) {
+	cert, err := DecodeCertificate(certData)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(keyData)
+	if block == nil {
+		return nil, errors.New("Could not decode CA key")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.RevocationList{
+		RevokedCertificateEntries: entries,
+		Number:                    big.NewInt(now.Unix()),
+		ThisUpdate:                now.UTC(),
+		NextUpdate:                cert.NotAfter,
+	}
+	der, err := x509.CreateRevocationList(rand.Reader, template, cert, key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), nil
+}
@@ -0,0 +1,72 @@
+package certs
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRevocationList(t *testing.T) {
+	now := time.Now()
+	ca := GenerateSecret("ca", "ca", "", 0, nil)
+	issued := GenerateSecret("link", "remote-site", "", 0, &ca)
+	other := GenerateSecret("other", "remote-site", "", 0, &ca)
+	cert, err := DecodeCertificate(issued.Data["tls.crt"])
+	assert.NilError(t, err)
+	serial := cert.SerialNumber.Text(16)
+
+	data, err := RevocationList(&ca, map[string]time.Time{serial: now}, now)
+	assert.NilError(t, err)
+	block, _ := pem.Decode(data)
+	assert.Assert(t, block != nil)
+	assert.Equal(t, block.Type, "X509 CRL")
+	crl, err := x509.ParseRevocationList(block.Bytes)
+	assert.NilError(t, err)
+	caCert, err := DecodeCertificate(ca.Data["tls.crt"])
+	assert.NilError(t, err)
+	assert.NilError(t, crl.CheckSignatureFrom(caCert))
+	assert.Equal(t, crl.NextUpdate.Unix(), caCert.NotAfter.Unix())
+	assert.Equal(t, len(crl.RevokedCertificateEntries), 1)
+	// only the revoked certificate is listed, not others with the same subject
+	assert.Equal(t, crl.RevokedCertificateEntries[0].SerialNumber.Cmp(cert.SerialNumber), 0)
+	otherCert, err := DecodeCertificate(other.Data["tls.crt"])
+	assert.NilError(t, err)
+	assert.Assert(t, crl.RevokedCertificateEntries[0].SerialNumber.Cmp(otherCert.SerialNumber) != 0)
+
+	serials, ok := RevokedSerialNumbers(data, &ca)
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, serials, []string{serial})
+
+	empty, err := RevocationList(&ca, nil, now)
+	assert.NilError(t, err)
+	serials, ok = RevokedSerialNumbers(empty, &ca)
+	assert.Assert(t, ok)
+	assert.Equal(t, len(serials), 0)
+
+	// a list from a CA that has since been replaced is not recognised
+	rotated := RotateCA("ca", "ca", &ca)
+	_, ok = RevokedSerialNumbers(data, &rotated)
+	assert.Assert(t, !ok)
+	// but one is generated for the replaced CA during the rotation
+	data, err = RevocationList(&rotated, map[string]time.Time{serial: now}, now)
+	assert.NilError(t, err)
+	var signers int
+	for rest := data; ; {
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		crl, err := x509.ParseRevocationList(block.Bytes)
+		assert.NilError(t, err)
+		if crl.CheckSignatureFrom(caCert) == nil {
+			signers++
+		}
+	}
+	assert.Equal(t, signers, 1)
+
+	_, err = RevocationList(&ca, map[string]time.Time{"not-hex": now}, now)
+	assert.ErrorContains(t, err, "Invalid serial number")
+}
@@ -61,18 +61,22 @@ for other Kubernetes flavors, loadbalancer is the default.`
 	FlagNameWorkload            = "workload"
 	FlagDescWorkload            = "A Kubernetes resource name that identifies a workload expressed like resource-type/resource-name. Expected resource types: service, daemonset, deployment, and statefulset."
 
-	FlagNameConnectorPort = "port"
-	FlagDescConnectorPort = "The port of the local connector"
+	FlagNameConnectorPort  = "port"
+	FlagDescConnectorPort  = "The port of the local connector"
+	FlagNameConnectorPorts = "ports"
+	FlagDescConnectorPorts = "The named ports of a multi-port connector, used instead of the port argument. Each is expressed as name:port or name:port:routing-key-suffix."
 
 	FlagNameConnectorStatusOutput = "output"
 	FlagDescConnectorStatusOutput = "print status of connectors Choices: json, yaml"
 
-	FlagNameListenerType = "type"
-	FlagDescListenerType = "The listener type. Choices: [tcp]."
-	FlagNameListenerPort = "port"
-	FlagDescListenerPort = "The port of the local listener"
-	FlagNameListenerHost = "host"
-	FlagDescListenerHost = "The hostname or IP address of the local listener. Clients at this site use the listener host and port to establish connections to the remote service."
+	FlagNameListenerType  = "type"
+	FlagDescListenerType  = "The listener type. Choices: [tcp]."
+	FlagNameListenerPort  = "port"
+	FlagDescListenerPort  = "The port of the local listener"
+	FlagNameListenerPorts = "ports"
+	FlagDescListenerPorts = "The named ports of a multi-port listener, used instead of the port argument. Each is expressed as name:port or name:port:routing-key-suffix."
+	FlagNameListenerHost  = "host"
+	FlagDescListenerHost  = "The hostname or IP address of the local listener. Clients at this site use the listener host and port to establish connections to the remote service."
 
 	FlagNamePath     = "path"
 	FlagDescPath     = "Custom resources location on the file system"
@@ -180,6 +184,7 @@ type CommandConnectorCreateFlags struct {
 	ConnectorType       string
 	IncludeNotReadyPods bool
 	Workload            string
+	Ports               []string
 	Timeout             time.Duration
 	Wait                string
 }
@@ -222,6 +227,7 @@ type CommandListenerCreateFlags struct {
 	Host           string
 	TlsCredentials string
 	ListenerType   string
+	Ports          []string
 	Timeout        time.Duration
 	Wait           string
 }
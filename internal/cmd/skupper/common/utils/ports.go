@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+// ParseBindingPorts parses the ports of a multi-port listener or
+// connector, each expressed as name:port or name:port:routing-key-suffix
+func ParseBindingPorts(values []string) ([]v2alpha1.BindingPort, error) {
+	var ports []v2alpha1.BindingPort
+	var errs []error
+	names := map[string]bool{}
+	for _, value := range values {
+		parts := strings.Split(value, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+			errs = append(errs, fmt.Errorf("%q is not of the form name:port or name:port:routing-key-suffix", value))
+			continue
+		}
+		port, err := strconv.Atoi(parts[1])
+		if err != nil || port < 1 || port > 65535 {
+			errs = append(errs, fmt.Errorf("%q does not have a valid port number", value))
+			continue
+		}
+		if names[parts[0]] {
+			errs = append(errs, fmt.Errorf("port name %q is used more than once", parts[0]))
+			continue
+		}
+		names[parts[0]] = true
+		bindingPort := v2alpha1.BindingPort{
+			Name: parts[0],
+			Port: port,
+		}
+		if len(parts) == 3 {
+			bindingPort.RoutingKeySuffix = parts[2]
+		}
+		ports = append(ports, bindingPort)
+	}
+	return ports, errors.Join(errs...)
+}
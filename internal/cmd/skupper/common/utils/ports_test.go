@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"gotest.tools/v3/assert"
+)
+
+func TestParseBindingPorts(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected []v2alpha1.BindingPort
+		err      string
+	}{
+		{
+			name:   "name and port",
+			values: []string{"amqp:5672", "console:15672"},
+			expected: []v2alpha1.BindingPort{
+				{Name: "amqp", Port: 5672},
+				{Name: "console", Port: 15672},
+			},
+		},
+		{
+			name:   "routing key suffix",
+			values: []string{"http:80:web", "https:443"},
+			expected: []v2alpha1.BindingPort{
+				{Name: "http", Port: 80, RoutingKeySuffix: "web"},
+				{Name: "https", Port: 443},
+			},
+		},
+		{
+			name:   "missing port",
+			values: []string{"amqp"},
+			err:    "\"amqp\" is not of the form name:port or name:port:routing-key-suffix",
+		},
+		{
+			name:   "missing name",
+			values: []string{":5672"},
+			err:    "\":5672\" is not of the form name:port or name:port:routing-key-suffix",
+		},
+		{
+			name:   "invalid port",
+			values: []string{"amqp:abc", "http:70000"},
+			err:    "\"amqp:abc\" does not have a valid port number\n\"http:70000\" does not have a valid port number",
+		},
+		{
+			name:   "duplicate name",
+			values: []string{"amqp:5672", "amqp:5671"},
+			err:    "port name \"amqp\" is used more than once",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ports, err := ParseBindingPorts(tt.values)
+			if tt.err != "" {
+				assert.Error(t, err, tt.err)
+			} else {
+				assert.NilError(t, err)
+				assert.DeepEqual(t, ports, tt.expected)
+			}
+		})
+	}
+}
@@ -35,11 +35,12 @@ func CmdConnectorCreateFactory(configuredPlatform common.Platform) *cobra.Comman
 	nonKubeCommand := nonkube.NewCmdConnectorCreate()
 
 	cmdConnectorCreateDesc := common.SkupperCmdDescription{
-		Use:   "create <name> <port>",
+		Use:   "create <name> [port]",
 		Short: "create a connector",
 		Long:  "Clients at this site use the connector host and port to establish connections to the remote service.",
 		Example: `skupper connector create database 5432
-skupper connector create backend 8080 --workload deployment/backend`,
+skupper connector create backend 8080 --workload deployment/backend
+skupper connector create broker --ports amqp:5672,console:15672 --workload deployment/broker`,
 	}
 
 	cmd := common.ConfigureCobraCommand(configuredPlatform, cmdConnectorCreateDesc, kubeCommand, nonKubeCommand)
@@ -50,6 +51,7 @@ skupper connector create backend 8080 --workload deployment/backend`,
 	cmd.Flags().StringVar(&cmdFlags.Host, common.FlagNameHost, "", common.FlagDescHost)
 	cmd.Flags().StringVar(&cmdFlags.TlsCredentials, common.FlagNameTlsCredentials, "", common.FlagDescTlsCredentials)
 	cmd.Flags().StringVar(&cmdFlags.ConnectorType, common.FlagNameConnectorType, "tcp", common.FlagDescConnectorType)
+	cmd.Flags().StringSliceVar(&cmdFlags.Ports, common.FlagNameConnectorPorts, []string{}, common.FlagDescConnectorPorts)
 	if configuredPlatform == common.PlatformKubernetes {
 		cmd.Flags().BoolVar(&cmdFlags.IncludeNotReadyPods, common.FlagNameIncludeNotReadyPods, false, common.FlagDescIncludeNotRead)
 		cmd.Flags().StringVar(&cmdFlags.Selector, common.FlagNameSelector, "", common.FlagDescSelector)
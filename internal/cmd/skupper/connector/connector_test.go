@@ -29,6 +29,7 @@ func TestCmdConnectorFactory(t *testing.T) {
 				common.FlagNameIncludeNotReadyPods: "false",
 				common.FlagNameSelector:            "",
 				common.FlagNameWorkload:            "",
+				common.FlagNameConnectorPorts:      "[]",
 				common.FlagNameTimeout:             "1m0s",
 				common.FlagNameWait:                "configured",
 			},
@@ -31,6 +31,7 @@ type CmdConnectorCreate struct {
 	namespace           string
 	name                string
 	port                int
+	ports               []v2alpha1.BindingPort
 	host                string
 	selector            string
 	tlsCredentials      string
@@ -74,14 +75,17 @@ func (cmd *CmdConnectorCreate) ValidateInput(args []string) error {
 		return errors.Join(validationErrors...)
 	}
 
-	// Validate arguments name and port
-	if len(args) < 2 {
+	// Validate arguments name and port, unless ports are configured instead
+	hasPorts := cmd.Flags != nil && len(cmd.Flags.Ports) > 0
+	if len(args) < 1 || (len(args) < 2 && !hasPorts) {
 		validationErrors = append(validationErrors, fmt.Errorf("connector name and port must be configured"))
 	} else if len(args) > 2 {
 		validationErrors = append(validationErrors, fmt.Errorf("only two arguments are allowed for this command"))
 	} else if args[0] == "" {
 		validationErrors = append(validationErrors, fmt.Errorf("connector name must not be empty"))
-	} else if args[1] == "" {
+	} else if len(args) == 2 && hasPorts {
+		validationErrors = append(validationErrors, fmt.Errorf("connector port must not be configured when ports are configured"))
+	} else if !hasPorts && args[1] == "" {
 		validationErrors = append(validationErrors, fmt.Errorf("connector port must not be empty"))
 	} else {
 		ok, err := resourceStringValidator.Evaluate(args[0])
@@ -91,14 +95,23 @@ func (cmd *CmdConnectorCreate) ValidateInput(args []string) error {
 			cmd.name = args[0]
 		}
 
-		cmd.port, err = strconv.Atoi(args[1])
-		if err != nil {
-			validationErrors = append(validationErrors, fmt.Errorf("connector port is not valid: %s", err))
+		if !hasPorts {
+			cmd.port, err = strconv.Atoi(args[1])
+			if err != nil {
+				validationErrors = append(validationErrors, fmt.Errorf("connector port is not valid: %s", err))
+			}
+			ok, err = numberValidator.Evaluate(cmd.port)
+			if !ok {
+				validationErrors = append(validationErrors, fmt.Errorf("connector port is not valid: %s", err))
+			}
 		}
-		ok, err = numberValidator.Evaluate(cmd.port)
-		if !ok {
-			validationErrors = append(validationErrors, fmt.Errorf("connector port is not valid: %s", err))
+	}
+	if hasPorts {
+		ports, err := utils.ParseBindingPorts(cmd.Flags.Ports)
+		if err != nil {
+			validationErrors = append(validationErrors, fmt.Errorf("ports are not valid: %s", err))
 		}
+		cmd.ports = ports
 	}
 
 	// Validate if there is already a Connector with this name in the namespace
@@ -264,6 +277,7 @@ func (cmd *CmdConnectorCreate) Run() error {
 		Spec: v2alpha1.ConnectorSpec{
 			Host:                cmd.host,
 			Port:                cmd.port,
+			Ports:               cmd.ports,
 			RoutingKey:          cmd.routingKey,
 			TlsCredentials:      cmd.tlsCredentials,
 			Type:                cmd.connectorType,
@@ -122,6 +122,26 @@ func TestCmdConnectorCreate_ValidateInput(t *testing.T) {
 			},
 			expectedError: "connector name and port must be configured",
 		},
+		{
+			name: "ports replace port argument",
+			args: []string{"my-broker"},
+			flags: common.CommandConnectorCreateFlags{
+				Selector: "backend",
+				Ports:    []string{"amqp:5672", "console:15672"},
+				Timeout:  1 * time.Minute,
+			},
+			expectedError: "",
+		},
+		{
+			name: "port argument with ports",
+			args: []string{"my-broker", "5672"},
+			flags: common.CommandConnectorCreateFlags{
+				Selector: "backend",
+				Ports:    []string{"amqp:5672"},
+				Timeout:  1 * time.Minute,
+			},
+			expectedError: "connector port must not be configured when ports are configured",
+		},
 		{
 			name: "more than two arguments are specified",
 			args: []string{"my", "connector", "8080"},
@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/skupperproject/skupper/internal/cmd/skupper/common"
+	"github.com/skupperproject/skupper/internal/cmd/skupper/common/utils"
 	"github.com/skupperproject/skupper/internal/nonkube/client/fs"
 	"github.com/skupperproject/skupper/internal/utils/validator"
 	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
@@ -21,6 +22,7 @@ type CmdConnectorCreate struct {
 	namespace        string
 	connectorName    string
 	port             int
+	ports            []v2alpha1.BindingPort
 	host             string
 	routingKey       string
 	connectorType    string
@@ -55,14 +57,17 @@ func (cmd *CmdConnectorCreate) ValidateInput(args []string) error {
 	connectorTypeValidator := validator.NewOptionValidator(common.ConnectorTypes)
 	hostStringValidator := validator.NewHostStringValidator()
 
-	// Validate arguments name and port
-	if len(args) < 2 {
+	// Validate arguments name and port, unless ports are configured instead
+	hasPorts := cmd.Flags != nil && len(cmd.Flags.Ports) > 0
+	if len(args) < 1 || (len(args) < 2 && !hasPorts) {
 		validationErrors = append(validationErrors, fmt.Errorf("connector name and port must be configured"))
 	} else if len(args) > 2 {
 		validationErrors = append(validationErrors, fmt.Errorf("only two arguments are allowed for this command"))
 	} else if args[0] == "" {
 		validationErrors = append(validationErrors, fmt.Errorf("connector name must not be empty"))
-	} else if args[1] == "" {
+	} else if len(args) == 2 && hasPorts {
+		validationErrors = append(validationErrors, fmt.Errorf("connector port must not be configured when ports are configured"))
+	} else if !hasPorts && args[1] == "" {
 		validationErrors = append(validationErrors, fmt.Errorf("connector port must not be empty"))
 	} else {
 		ok, err := resourceStringValidator.Evaluate(args[0])
@@ -71,14 +76,23 @@ func (cmd *CmdConnectorCreate) ValidateInput(args []string) error {
 		} else {
 			cmd.connectorName = args[0]
 		}
-		cmd.port, err = strconv.Atoi(args[1])
-		if err != nil {
-			validationErrors = append(validationErrors, fmt.Errorf("connector port is not valid: %s", err))
+		if !hasPorts {
+			cmd.port, err = strconv.Atoi(args[1])
+			if err != nil {
+				validationErrors = append(validationErrors, fmt.Errorf("connector port is not valid: %s", err))
+			}
+			ok, err = numberValidator.Evaluate(cmd.port)
+			if !ok {
+				validationErrors = append(validationErrors, fmt.Errorf("connector port is not valid: %s", err))
+			}
 		}
-		ok, err = numberValidator.Evaluate(cmd.port)
-		if !ok {
-			validationErrors = append(validationErrors, fmt.Errorf("connector port is not valid: %s", err))
+	}
+	if hasPorts {
+		ports, err := utils.ParseBindingPorts(cmd.Flags.Ports)
+		if err != nil {
+			validationErrors = append(validationErrors, fmt.Errorf("ports are not valid: %s", err))
 		}
+		cmd.ports = ports
 	}
 
 	// Validate flags
@@ -143,6 +157,7 @@ func (cmd *CmdConnectorCreate) Run() error {
 		Spec: v2alpha1.ConnectorSpec{
 			Host:           cmd.host,
 			Port:           cmd.port,
+			Ports:          cmd.ports,
 			RoutingKey:     cmd.routingKey,
 			TlsCredentials: cmd.tlsCredentials,
 			Type:           cmd.connectorType,
@@ -29,6 +29,7 @@ type CmdListenerCreate struct {
 	namespace      string
 	name           string
 	port           int
+	ports          []v2alpha1.BindingPort
 	host           string
 	tlsCredentials string
 	listenerType   string
@@ -68,14 +69,17 @@ func (cmd *CmdListenerCreate) ValidateInput(args []string) error {
 		return errors.Join(validationErrors...)
 	}
 
-	// Validate arguments name and port
-	if len(args) < 2 {
+	// Validate arguments name and port, unless ports are configured instead
+	hasPorts := cmd.Flags != nil && len(cmd.Flags.Ports) > 0
+	if len(args) < 1 || (len(args) < 2 && !hasPorts) {
 		validationErrors = append(validationErrors, fmt.Errorf("listener name and port must be configured"))
 	} else if len(args) > 2 {
 		validationErrors = append(validationErrors, fmt.Errorf("only two arguments are allowed for this command"))
 	} else if args[0] == "" {
 		validationErrors = append(validationErrors, fmt.Errorf("listener name must not be empty"))
-	} else if args[1] == "" {
+	} else if len(args) == 2 && hasPorts {
+		validationErrors = append(validationErrors, fmt.Errorf("listener port must not be configured when ports are configured"))
+	} else if !hasPorts && args[1] == "" {
 		validationErrors = append(validationErrors, fmt.Errorf("listener port must not be empty"))
 	} else {
 		ok, err := resourceStringValidator.Evaluate(args[0])
@@ -85,14 +89,23 @@ func (cmd *CmdListenerCreate) ValidateInput(args []string) error {
 			cmd.name = args[0]
 		}
 
-		cmd.port, err = strconv.Atoi(args[1])
-		if err != nil {
-			validationErrors = append(validationErrors, fmt.Errorf("listener port is not valid: %s", err))
+		if !hasPorts {
+			cmd.port, err = strconv.Atoi(args[1])
+			if err != nil {
+				validationErrors = append(validationErrors, fmt.Errorf("listener port is not valid: %s", err))
+			}
+			ok, err = numberValidator.Evaluate(cmd.port)
+			if !ok {
+				validationErrors = append(validationErrors, fmt.Errorf("listener port is not valid: %s", err))
+			}
 		}
-		ok, err = numberValidator.Evaluate(cmd.port)
-		if !ok {
-			validationErrors = append(validationErrors, fmt.Errorf("listener port is not valid: %s", err))
+	}
+	if hasPorts {
+		ports, err := utils.ParseBindingPorts(cmd.Flags.Ports)
+		if err != nil {
+			validationErrors = append(validationErrors, fmt.Errorf("ports are not valid: %s", err))
 		}
+		cmd.ports = ports
 	}
 
 	// Validate if there is already a listener with this name in the namespace
@@ -175,6 +188,7 @@ func (cmd *CmdListenerCreate) Run() error {
 		Spec: v2alpha1.ListenerSpec{
 			Host:           cmd.host,
 			Port:           cmd.port,
+			Ports:          cmd.ports,
 			RoutingKey:     cmd.routingKey,
 			TlsCredentials: cmd.tlsCredentials,
 			Type:           cmd.listenerType,
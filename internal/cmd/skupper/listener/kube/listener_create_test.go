@@ -183,6 +183,24 @@ func TestCmdListenerCreate_ValidateInput(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:          "ports replace port argument",
+			args:          []string{"my-broker"},
+			flags:         common.CommandListenerCreateFlags{Timeout: 1 * time.Minute, Ports: []string{"amqp:5672", "console:15672:mgmt"}},
+			expectedError: "",
+		},
+		{
+			name:          "port argument with ports",
+			args:          []string{"my-broker", "5672"},
+			flags:         common.CommandListenerCreateFlags{Timeout: 1 * time.Minute, Ports: []string{"amqp:5672"}},
+			expectedError: "listener port must not be configured when ports are configured",
+		},
+		{
+			name:          "ports are not valid",
+			args:          []string{"my-broker"},
+			flags:         common.CommandListenerCreateFlags{Timeout: 1 * time.Minute, Ports: []string{"amqp"}},
+			expectedError: "ports are not valid: \"amqp\" is not of the form name:port or name:port:routing-key-suffix",
+		},
 		{
 			name:          "wait status is not valid",
 			args:          []string{"my-listener-tls", "8080"},
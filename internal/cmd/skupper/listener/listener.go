@@ -35,10 +35,11 @@ func CmdListenerCreateFactory(configuredPlatform common.Platform) *cobra.Command
 	nonKubeCommand := nonkube.NewCmdListenerCreate()
 
 	cmdListenerCreateDesc := common.SkupperCmdDescription{
-		Use:     "create <name> <port>",
-		Short:   "create a listener",
-		Long:    "Clients at this site use the listener host and port to establish connections to the remote service.",
-		Example: "skupper listener create database 5432",
+		Use:   "create <name> [port]",
+		Short: "create a listener",
+		Long:  "Clients at this site use the listener host and port to establish connections to the remote service.",
+		Example: `skupper listener create database 5432
+skupper listener create broker --ports amqp:5672,console:15672`,
 	}
 
 	cmd := common.ConfigureCobraCommand(configuredPlatform, cmdListenerCreateDesc, kubeCommand, nonKubeCommand)
@@ -49,6 +50,7 @@ func CmdListenerCreateFactory(configuredPlatform common.Platform) *cobra.Command
 	cmd.Flags().StringVar(&cmdFlags.Host, common.FlagNameListenerHost, "", common.FlagDescListenerHost)
 	cmd.Flags().StringVar(&cmdFlags.TlsCredentials, common.FlagNameTlsCredentials, "", common.FlagDescTlsCredentials)
 	cmd.Flags().StringVar(&cmdFlags.ListenerType, common.FlagNameListenerType, "tcp", common.FlagDescListenerType)
+	cmd.Flags().StringSliceVar(&cmdFlags.Ports, common.FlagNameListenerPorts, []string{}, common.FlagDescListenerPorts)
 
 	if configuredPlatform == common.PlatformKubernetes {
 		cmd.Flags().DurationVar(&cmdFlags.Timeout, common.FlagNameTimeout, 60*time.Second, common.FlagDescTimeout)
@@ -26,6 +26,7 @@ func TestCmdListenerFactory(t *testing.T) {
 				common.FlagNameListenerHost:   "",
 				common.FlagNameTlsCredentials: "",
 				common.FlagNameListenerType:   "tcp",
+				common.FlagNameListenerPorts:  "[]",
 				common.FlagNameTimeout:        "1m0s",
 				common.FlagNameWait:           "configured",
 			},
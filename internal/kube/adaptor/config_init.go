@@ -2,17 +2,20 @@ package adaptor
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"os"
 	paths "path"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/skupperproject/skupper/internal/qdr"
+	skupperclient "github.com/skupperproject/skupper/pkg/generated/client/clientset/versioned"
 )
 
-func InitialiseConfig(client kubernetes.Interface, namespace string, path string, routerConfigMap string) error {
+func InitialiseConfig(client kubernetes.Interface, skupperClient skupperclient.Interface, namespace string, path string, routerConfigMap string) error {
 	ctxt := context.Background()
 	current, err := client.CoreV1().ConfigMaps(namespace).Get(ctxt, routerConfigMap, metav1.GetOptions{})
 	if err != nil {
@@ -46,6 +49,30 @@ func InitialiseConfig(client kubernetes.Interface, namespace string, path string
 			return err
 		}
 		log.Printf("Resources for SslProfile %s written to %s", profile.Name, target.path)
+		if profile.CrlFile != "" {
+			if err := initialiseRevocationList(client, skupperClient, namespace, paths.Join(target.path, qdr.RevocationListFile)); err != nil {
+				return err
+			}
+			log.Printf("Revocation list for SslProfile %s written to %s", profile.Name, target.path)
+		}
 	}
 	return nil
 }
+
+func initialiseRevocationList(client kubernetes.Interface, skupperClient skupperclient.Interface, namespace string, file string) error {
+	ctxt := context.Background()
+	sites, err := skupperClient.SkupperV2alpha1().Sites(namespace).List(ctxt, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	if len(sites.Items) == 0 {
+		return fmt.Errorf("No site found in %s to determine revoked certificates", namespace)
+	}
+	site := &sites.Items[0]
+	ca, err := client.CoreV1().Secrets(namespace).Get(ctxt, site.DefaultIssuer(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	_, err = writeRevocationList(file, site, ca, time.Now())
+	return err
+}
@@ -21,6 +21,8 @@ type ConfigSync struct {
 	profileSyncer   *SslProfileSyncer
 	config          *watchers.ConfigMapWatcher
 	secrets         *watchers.SecretWatcher
+	sites           *watchers.SiteWatcher
+	revocations     revocations
 	path            string
 	routerConfigMap string
 }
@@ -31,6 +33,7 @@ func NewConfigSync(cli internalclient.Clients, namespace string, path string, ro
 		controller:      watchers.NewEventProcessor("config-sync", cli),
 		namespace:       namespace,
 		profileSyncer:   newSslProfileSyncer(path),
+		revocations:     newRevocations(),
 		path:            path,
 		routerConfigMap: routerConfigMap,
 	}
@@ -43,6 +46,7 @@ func (c *ConfigSync) Start(stopCh <-chan struct{}) error {
 	}
 	c.config = c.controller.WatchConfigMaps(watchers.ByName(c.routerConfigMap), c.namespace, c.configEvent)
 	c.secrets = c.controller.WatchAllSecrets(c.namespace, c.secretEvent)
+	c.sites = c.controller.WatchSites(c.namespace, c.siteEvent)
 	c.controller.StartWatchers(stopCh)
 	log.Printf("CONFIG_SYNC: Waiting for informers to sync...")
 	if ok := c.controller.WaitForCacheSync(stopCh); !ok {
@@ -96,6 +100,12 @@ func (c *ConfigSync) secretEvent(key string, secret *corev1.Secret) error {
 	if secret == nil {
 		return nil
 	}
+	if secret.Name == c.revocations.issuer() {
+		if err := c.updateRevocationLists(); err != nil {
+			log.Printf("CONFIG_SYNC: Error updating revocation lists: %s", err)
+			return err
+		}
+	}
 	if current, ok := c.profileSyncer.bySecretName(secret.Name); ok {
 		if current.secret != nil && reflect.DeepEqual(current.secret.Data, secret.Data) {
 			log.Printf("CONFIG_SYNC: Secret %q already up to date", secret.Name)
@@ -132,9 +142,20 @@ func (c *ConfigSync) configEvent(key string, configmap *corev1.ConfigMap) error
 	if err := c.syncSslProfileCredentialsToDisk(desired.SslProfiles); err != nil {
 		return err
 	}
+	c.revocations.track(desired.SslProfiles)
+	changed, err := c.writeRevocationLists()
+	if err != nil {
+		return err
+	}
 	if err := c.syncSslProfilesToRouter(desired.SslProfiles); err != nil {
 		return err
 	}
+	if err := c.reloadRevocationLists(changed); err != nil {
+		return err
+	}
+	if err := c.enforceRevocations(); err != nil {
+		return err
+	}
 	if err := c.syncBridgeConfig(&desired.Bridges); err != nil {
 		log.Printf("sync failed: %s", err)
 		return err
@@ -203,6 +224,23 @@ func syncRouterConfig(agent *qdr.Agent, desired *qdr.RouterConfig) error {
 	if err := syncListeners(agent, desired); err != nil {
 		return err
 	}
+	if err := syncAddresses(agent, desired); err != nil {
+		return err
+	}
+	return nil
+}
+
+func syncAddresses(agent *qdr.Agent, desired *qdr.RouterConfig) error {
+	actual, err := agent.GetLocalAddresses()
+	if err != nil {
+		return fmt.Errorf("Error retrieving local addresses: %s", err)
+	}
+
+	if differences := qdr.AddressesDifference(actual, desired.Addresses); !differences.Empty() {
+		if err := agent.UpdateAddressConfig(differences); err != nil {
+			return fmt.Errorf("Error syncing addresses: %s", err)
+		}
+	}
 	return nil
 }
 
@@ -253,12 +291,32 @@ func (c *ConfigSync) syncSslProfilesToRouter(desired map[string]qdr.SslProfile)
 		return err
 	}
 
+	revocationLists := false
 	for _, profile := range desired {
-		if _, ok := actual[profile.Name]; !ok {
+		if profile.CrlFile != "" {
+			revocationLists = true
+		}
+		if current, ok := actual[profile.Name]; !ok {
 			if err := agent.CreateSslProfile(profile); err != nil {
 				return err
 			}
+		} else if profile.CrlFile != "" && current.CrlFile != profile.CrlFile {
+			// a revocation list is only ever added to an existing
+			// profile; removing one takes effect on router restart
+			current.CrlFile = profile.CrlFile
+			if err := agent.Update("io.skupper.router.sslProfile", current.Name, current); err != nil {
+				return err
+			}
+		}
+	}
+	if revocationLists {
+		// a router that does not support crlFile may accept and
+		// ignore it, so check what it reports as configured
+		applied, err := agent.GetSslProfiles()
+		if err != nil {
+			return err
 		}
+		c.verifyRevocationLists(desired, applied)
 	}
 	for _, profile := range actual {
 		if _, ok := desired[profile.Name]; !ok {
@@ -297,5 +355,6 @@ func (c *ConfigSync) recoverTracking() error {
 	for _, profile := range current.SslProfiles {
 		c.trackSslProfile(profile.Name)
 	}
+	c.revocations.track(current.SslProfiles)
 	return nil
 }
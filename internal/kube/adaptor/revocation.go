@@ -0,0 +1,367 @@
+package adaptor
+
+import (
+	"fmt"
+	"log"
+	"os"
+	paths "path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/skupperproject/skupper/internal/certs"
+	"github.com/skupperproject/skupper/internal/qdr"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+// How often connections from sites with revoked certificates are
+// closed again when the router cannot refuse those certificates
+const revocationRecheckInterval = 30 * time.Second
+
+// Tracks the certificates revoked for remote sites, as recorded in
+// the status of the Site, and keeps the revocation lists configured
+// for SslProfiles up to date with them. The router then refuses any
+// revoked certificate during the TLS handshake.
+//
+// This relies on the router accepting the crlFile attribute of
+// io.skupper.router.sslProfile. Where the router does not report that
+// attribute back as configured, the profile is recorded as
+// unenforced and connections from any site with a revoked certificate
+// are instead closed periodically.
+type revocations struct {
+	site     *skupperv2alpha1.Site
+	profiles map[string]bool
+	// the serial numbers revoked by the lists currently in use,
+	// keyed by the id of the site each was issued to
+	applied map[string]string
+	// the profiles for which the router did not apply the
+	// revocation list
+	unenforced map[string]bool
+	rechecking bool
+}
+
+func newRevocations() revocations {
+	return revocations{
+		profiles:   map[string]bool{},
+		applied:    map[string]string{},
+		unenforced: map[string]bool{},
+	}
+}
+
+// Records the SslProfiles configured with a revocation list.
+func (r *revocations) track(profiles map[string]qdr.SslProfile) {
+	r.profiles = map[string]bool{}
+	for _, profile := range profiles {
+		if profile.CrlFile != "" {
+			r.profiles[profile.Name] = true
+		}
+	}
+}
+
+// Records which of the desired SslProfiles with a revocation list
+// the router has not applied that list to, given the profiles as
+// reported by the router. Returns the names of any profiles not
+// previously known to be unenforced.
+func (r *revocations) verify(desired map[string]qdr.SslProfile, actual map[string]qdr.SslProfile) []string {
+	var unenforced []string
+	for name, profile := range desired {
+		if profile.CrlFile == "" {
+			delete(r.unenforced, name)
+			continue
+		}
+		if current, ok := actual[name]; ok && current.CrlFile == profile.CrlFile {
+			delete(r.unenforced, name)
+			continue
+		}
+		if !r.unenforced[name] {
+			unenforced = append(unenforced, name)
+		}
+		r.unenforced[name] = true
+	}
+	for name := range r.unenforced {
+		if _, ok := desired[name]; !ok {
+			delete(r.unenforced, name)
+		}
+	}
+	sort.Strings(unenforced)
+	return unenforced
+}
+
+// Returns true if the router refuses revoked certificates for every
+// SslProfile configured with a revocation list.
+func (r *revocations) enforced() bool {
+	return len(r.unenforced) == 0
+}
+
+// Returns the ids of the sites whose connections should be closed,
+// keyed by the serial number of the revoked certificate. Where the
+// router refuses revoked certificates, that is only necessary once
+// for each newly revoked certificate. Otherwise connections from any
+// site with a revoked certificate are closed, as the router would
+// accept them again.
+func (r *revocations) toClose() map[string]string {
+	if r.enforced() {
+		return r.pending()
+	}
+	revoked := map[string]string{}
+	if r.site == nil {
+		return revoked
+	}
+	for _, entry := range r.site.Status.RevokedSites {
+		if entry.SerialNumber == "" {
+			continue
+		}
+		revoked[entry.SerialNumber] = entry.Id
+	}
+	return revoked
+}
+
+func (r *revocations) issuer() string {
+	if r.site == nil {
+		return ""
+	}
+	return r.site.DefaultIssuer()
+}
+
+// Returns the revocation time of each revoked certificate, keyed by
+// its serial number.
+func revokedSerialNumbers(site *skupperv2alpha1.Site, now time.Time) map[string]time.Time {
+	revoked := map[string]time.Time{}
+	if site == nil {
+		return revoked
+	}
+	for _, entry := range site.Status.RevokedSites {
+		if entry.SerialNumber == "" {
+			continue
+		}
+		timestamp, err := time.Parse(time.RFC3339, entry.Revoked)
+		if err != nil {
+			timestamp = now
+		}
+		revoked[entry.SerialNumber] = timestamp
+	}
+	return revoked
+}
+
+// Returns the serial numbers of the certificates revoked by the site
+// that are not yet revoked by the lists in use, keyed by the id of
+// the site each was issued to.
+func (r *revocations) pending() map[string]string {
+	pending := map[string]string{}
+	if r.site == nil {
+		return pending
+	}
+	for _, entry := range r.site.Status.RevokedSites {
+		if entry.SerialNumber == "" || r.applied[entry.SerialNumber] != "" {
+			continue
+		}
+		pending[entry.SerialNumber] = entry.Id
+	}
+	return pending
+}
+
+// Writes the revocation list for the certificates revoked by the
+// site to the supplied file, signed by the supplied CA. Returns true
+// if the file was changed, i.e. if it did not already revoke exactly
+// those certificates.
+func writeRevocationList(file string, site *skupperv2alpha1.Site, ca *corev1.Secret, now time.Time) (bool, error) {
+	revoked := revokedSerialNumbers(site, now)
+	var serials []string
+	for serial := range revoked {
+		serials = append(serials, strings.ToLower(serial))
+	}
+	sort.Strings(serials)
+	if content, err := os.ReadFile(file); err == nil {
+		if current, ok := certs.RevokedSerialNumbers(content, ca); ok && reflect.DeepEqual(current, serials) {
+			return false, nil
+		}
+	}
+	data, err := certs.RevocationList(ca, revoked, now)
+	if err != nil {
+		return false, err
+	}
+	if err := mkdir(paths.Dir(file)); err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(file, data, 0777); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *ConfigSync) siteEvent(key string, site *skupperv2alpha1.Site) error {
+	if site == nil {
+		if c.revocations.site != nil && c.key(c.revocations.site.Name) == key {
+			c.revocations.site = nil
+		}
+		return nil
+	}
+	c.revocations.site = site
+	return c.updateRevocationLists()
+}
+
+// Brings the revocation lists in use by the router up to date with
+// the certificates currently revoked.
+func (c *ConfigSync) updateRevocationLists() error {
+	changed, err := c.writeRevocationLists()
+	if err != nil {
+		return err
+	}
+	if err := c.reloadRevocationLists(changed); err != nil {
+		return err
+	}
+	return c.enforceRevocations()
+}
+
+// Writes out the revocation list for each SslProfile configured with
+// one, returning the names of those whose list changed.
+func (c *ConfigSync) writeRevocationLists() ([]string, error) {
+	if len(c.revocations.profiles) == 0 {
+		return nil, nil
+	}
+	if c.revocations.site == nil {
+		return nil, fmt.Errorf("No site found to determine revoked certificates")
+	}
+	issuer := c.revocations.issuer()
+	ca, err := c.secrets.Get(c.key(issuer))
+	if err != nil {
+		return nil, err
+	}
+	if ca == nil {
+		return nil, fmt.Errorf("No secret %q cached for CA", issuer)
+	}
+	now := time.Now()
+	var names []string
+	for name := range c.revocations.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var changed []string
+	for _, name := range names {
+		tracker, _ := c.trackSslProfile(name)
+		wrote, err := writeRevocationList(paths.Join(tracker.path, qdr.RevocationListFile), c.revocations.site, ca, now)
+		if err != nil {
+			log.Printf("CONFIG_SYNC: Error writing revocation list for SslProfile %q: %s", name, err)
+			return nil, err
+		}
+		if wrote {
+			changed = append(changed, name)
+		}
+	}
+	return changed, nil
+}
+
+func (c *ConfigSync) reloadRevocationLists(profiles []string) error {
+	for _, name := range profiles {
+		if err := c.reloadSslProfileInRouter(name); err != nil {
+			log.Printf("CONFIG_SYNC: Error reloading SslProfile %q: %s", name, err)
+			return err
+		}
+		log.Printf("CONFIG_SYNC: Revocation list for SslProfile %q updated", name)
+	}
+	return nil
+}
+
+// Once the revocation lists in use cover newly revoked certificates,
+// any existing connections from the sites they were issued to are
+// closed, requiring those sites to handshake again.
+func (c *ConfigSync) enforceRevocations() error {
+	if len(c.revocations.profiles) == 0 {
+		return nil
+	}
+	if !c.revocations.enforced() {
+		c.scheduleRevocationRecheck()
+	}
+	revoked := c.revocations.toClose()
+	if len(revoked) == 0 {
+		return nil
+	}
+	if err := c.closeRevokedConnections(revoked); err != nil {
+		return err
+	}
+	if c.revocations.enforced() {
+		for serial, id := range revoked {
+			c.revocations.applied[serial] = id
+		}
+	}
+	return nil
+}
+
+func (c *ConfigSync) scheduleRevocationRecheck() {
+	if c.revocations.rechecking {
+		return
+	}
+	c.revocations.rechecking = true
+	c.controller.CallbackAfter(revocationRecheckInterval, c.recheckRevocations, "")
+}
+
+func (c *ConfigSync) recheckRevocations(context string) error {
+	c.revocations.rechecking = false
+	return c.enforceRevocations()
+}
+
+// Records whether the router applied the revocation list configured
+// for each SslProfile, reporting any that it did not.
+func (c *ConfigSync) verifyRevocationLists(desired map[string]qdr.SslProfile, actual map[string]qdr.SslProfile) {
+	for _, name := range c.revocations.verify(desired, actual) {
+		log.Printf("CONFIG_SYNC: Error: router did not apply crlFile to SslProfile %q; revoked certificates will not be refused on handshake, connections from sites with revoked certificates will be closed every %s instead", name, revocationRecheckInterval)
+	}
+}
+
+// Closes the incoming inter-router and edge connections from the
+// supplied sites. Any such site must then handshake again, at which
+// point a revoked certificate is refused, while one that is still
+// valid is accepted.
+func (c *ConfigSync) closeRevokedConnections(revoked map[string]string) error {
+	agent, err := c.agentPool.Get()
+	if err != nil {
+		return fmt.Errorf("Could not get management agent : %s", err)
+	}
+	defer c.agentPool.Put(agent)
+	connections, err := agent.GetConnections()
+	if err != nil {
+		return fmt.Errorf("Error retrieving connections: %s", err)
+	}
+	sites := map[string]bool{}
+	for _, id := range revoked {
+		sites[id] = true
+	}
+	for _, connection := range connectionsFrom(connections, sites) {
+		if err := agent.CloseConnection(connection.Identity); err != nil {
+			return fmt.Errorf("Error closing connection %s from site with revoked certificate (%s): %s", connection.Identity, connection.User, err)
+		}
+		log.Printf("CONFIG_SYNC: Closed connection %s from site with revoked certificate (%s)", connection.Identity, connection.User)
+	}
+	return nil
+}
+
+// Returns the incoming inter-router and edge connections established
+// by the supplied sites.
+func connectionsFrom(connections []qdr.Connection, sites map[string]bool) []qdr.Connection {
+	var matched []qdr.Connection
+	for _, connection := range connections {
+		if connection.Dir != "in" || (connection.Role != "inter-router" && connection.Role != "edge") {
+			continue
+		}
+		if id := subjectCommonName(connection.User); id != "" && sites[id] {
+			matched = append(matched, connection)
+		}
+	}
+	return matched
+}
+
+// The user for a connection authenticated through its client
+// certificate is the subject of that certificate, e.g. CN=xyz,O=abc.
+// Where there is no common name, the user is returned as is.
+func subjectCommonName(user string) string {
+	for _, part := range strings.Split(user, ",") {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, "CN=") {
+			return strings.TrimPrefix(part, "CN=")
+		}
+	}
+	return user
+}
@@ -0,0 +1,142 @@
+package adaptor
+
+import (
+	"os"
+	paths "path"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/skupperproject/skupper/internal/certs"
+	"github.com/skupperproject/skupper/internal/qdr"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+func TestWriteRevocationList(t *testing.T) {
+	now := time.Now()
+	ca := certs.GenerateSecret("skupper-site-ca", "skupper-site-ca", "", 0, nil)
+	issued := certs.GenerateSecret("link", "remote-site-id", "", 0, &ca)
+	cert, err := certs.DecodeCertificate(issued.Data["tls.crt"])
+	assert.NilError(t, err)
+	serial := cert.SerialNumber.Text(16)
+	site := &skupperv2alpha1.Site{}
+	file := paths.Join(t.TempDir(), "skupper-site-server", qdr.RevocationListFile)
+
+	wrote, err := writeRevocationList(file, site, &ca, now)
+	assert.NilError(t, err)
+	assert.Assert(t, wrote)
+	data, err := os.ReadFile(file)
+	assert.NilError(t, err)
+	serials, ok := certs.RevokedSerialNumbers(data, &ca)
+	assert.Assert(t, ok)
+	assert.Equal(t, len(serials), 0)
+
+	wrote, err = writeRevocationList(file, site, &ca, now)
+	assert.NilError(t, err)
+	assert.Assert(t, !wrote)
+
+	site.Status.RevokedSites = []skupperv2alpha1.RevokedSite{
+		{
+			Id:           "remote-site-id",
+			AccessGrant:  "my-grant",
+			SerialNumber: serial,
+			Revoked:      now.Format(time.RFC3339),
+		},
+	}
+	wrote, err = writeRevocationList(file, site, &ca, now)
+	assert.NilError(t, err)
+	assert.Assert(t, wrote)
+	data, err = os.ReadFile(file)
+	assert.NilError(t, err)
+	serials, ok = certs.RevokedSerialNumbers(data, &ca)
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, serials, []string{serial})
+
+	wrote, err = writeRevocationList(file, site, &ca, now)
+	assert.NilError(t, err)
+	assert.Assert(t, !wrote)
+
+	// a new CA requires a new list
+	rotated := certs.RotateCA("skupper-site-ca", "skupper-site-ca", &ca)
+	wrote, err = writeRevocationList(file, site, &rotated, now)
+	assert.NilError(t, err)
+	assert.Assert(t, wrote)
+}
+
+func TestRevocationsPending(t *testing.T) {
+	r := newRevocations()
+	assert.Equal(t, len(r.pending()), 0)
+	r.site = &skupperv2alpha1.Site{
+		Status: skupperv2alpha1.SiteStatus{
+			RevokedSites: []skupperv2alpha1.RevokedSite{
+				{Id: "site-a", SerialNumber: "1a"},
+				{Id: "site-b", SerialNumber: "2b"},
+				// revoked before serial numbers were recorded
+				{Id: "site-c"},
+			},
+		},
+	}
+	assert.DeepEqual(t, r.pending(), map[string]string{"1a": "site-a", "2b": "site-b"})
+	r.applied["1a"] = "site-a"
+	assert.DeepEqual(t, r.pending(), map[string]string{"2b": "site-b"})
+
+	r.track(map[string]qdr.SslProfile{
+		"skupper-site-server": {Name: "skupper-site-server", CrlFile: "/etc/skupper-router-certs/skupper-site-server/ca.crl"},
+		"skupper-service-ca":  {Name: "skupper-service-ca"},
+	})
+	assert.DeepEqual(t, r.profiles, map[string]bool{"skupper-site-server": true})
+}
+
+func TestConnectionsFrom(t *testing.T) {
+	connections := []qdr.Connection{
+		{Identity: "1", Dir: "in", Role: "inter-router", User: "CN=site-a"},
+		{Identity: "2", Dir: "in", Role: "edge", User: "CN=site-a,O=skupper"},
+		{Identity: "3", Dir: "out", Role: "inter-router", User: "CN=site-a"},
+		{Identity: "4", Dir: "in", Role: "normal", User: "CN=site-a"},
+		{Identity: "5", Dir: "in", Role: "inter-router", User: "CN=site-b"},
+	}
+	var closed []string
+	for _, connection := range connectionsFrom(connections, map[string]bool{"site-a": true}) {
+		closed = append(closed, connection.Identity)
+	}
+	assert.DeepEqual(t, closed, []string{"1", "2"})
+}
+
+func TestRevocationsVerify(t *testing.T) {
+	r := newRevocations()
+	r.site = &skupperv2alpha1.Site{
+		Status: skupperv2alpha1.SiteStatus{
+			RevokedSites: []skupperv2alpha1.RevokedSite{
+				{Id: "site-a", SerialNumber: "1a"},
+				{Id: "site-b", SerialNumber: "2b"},
+			},
+		},
+	}
+	r.applied["1a"] = "site-a"
+	desired := map[string]qdr.SslProfile{
+		"skupper-site-server": {Name: "skupper-site-server", CrlFile: "/etc/skupper-router-certs/skupper-site-server/ca.crl"},
+		"skupper-service-ca":  {Name: "skupper-service-ca"},
+	}
+
+	// the router applied the revocation list
+	assert.Equal(t, len(r.verify(desired, desired)), 0)
+	assert.Assert(t, r.enforced())
+	assert.DeepEqual(t, r.toClose(), map[string]string{"2b": "site-b"})
+
+	// the router ignored the revocation list
+	ignored := map[string]qdr.SslProfile{
+		"skupper-site-server": {Name: "skupper-site-server"},
+		"skupper-service-ca":  {Name: "skupper-service-ca"},
+	}
+	assert.DeepEqual(t, r.verify(desired, ignored), []string{"skupper-site-server"})
+	assert.Assert(t, !r.enforced())
+	assert.DeepEqual(t, r.toClose(), map[string]string{"1a": "site-a", "2b": "site-b"})
+	// only reported when first detected
+	assert.Equal(t, len(r.verify(desired, ignored)), 0)
+	assert.Assert(t, !r.enforced())
+
+	// the profile is no longer configured
+	assert.Equal(t, len(r.verify(map[string]qdr.SslProfile{}, ignored)), 0)
+	assert.Assert(t, r.enforced())
+}
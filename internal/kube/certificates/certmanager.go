@@ -0,0 +1,212 @@
+package certificates
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+
+	"github.com/skupperproject/skupper/internal/kube/resource"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+// Certificates whose CA refers to an external issuer are not
+// generated by skupper. Instead a cert-manager Certificate is created
+// with the same name, which cert-manager fulfils by writing the
+// Secret that skupper would otherwise have generated.
+
+var certManagerCertificateGVK = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+func certManagerCertificates() dynamicinformer.TweakListOptionsFunc {
+	return func(options *metav1.ListOptions) {
+		options.LabelSelector = "internal.skupper.io/certificate"
+	}
+}
+
+// Called by EventProcessor whenever there is a change to a
+// cert-manager Certificate created for a skupper Certificate.
+func (m *CertificateManagerImpl) checkCertManagerCertificate(key string, obj *unstructured.Unstructured) error {
+	certificate, err := m.lookup(key)
+	if err != nil || certificate == nil {
+		return err
+	}
+	if _, ok := certificate.ExternalIssuer(); !ok {
+		return nil
+	}
+	return m.checkCertificate(key, certificate)
+}
+
+// Ensures that cert-manager has been asked to issue the certificate
+// and returns a pending condition until the Secret it issues matches
+// the certificate.
+func (m *CertificateManagerImpl) requestCertificate(key string, certificate *skupperv2alpha1.Certificate, issuer skupperv2alpha1.IssuerReference, secret *corev1.Secret) (skupperv2alpha1.ConditionState, error) {
+	if m.certManagerWatcher == nil {
+		return skupperv2alpha1.ConditionState{}, fmt.Errorf("Cannot request certificate from %s as cert-manager is not installed", issuer)
+	}
+	spec := m.certManagerSpec(certificate, issuer)
+	current, err := m.certManagerWatcher.Get(key)
+	if err != nil {
+		return skupperv2alpha1.ConditionState{}, err
+	}
+	if current == nil {
+		current, err = m.createCertManagerCertificate(certificate, spec)
+		if err != nil {
+			return skupperv2alpha1.ConditionState{}, err
+		}
+	} else if !specMatches(current, spec) {
+		updated := current.DeepCopy()
+		for _, field := range optionalSpecFields {
+			unstructured.RemoveNestedField(updated.Object, "spec", field)
+		}
+		for field, value := range spec {
+			if err := unstructured.SetNestedField(updated.Object, value, "spec", field); err != nil {
+				return skupperv2alpha1.ConditionState{}, err
+			}
+		}
+		current, err = m.processor.GetDynamicClient().Resource(resource.CertManagerCertificateResource()).Namespace(certificate.Namespace).Update(context.TODO(), updated, metav1.UpdateOptions{})
+		if err != nil {
+			return skupperv2alpha1.ConditionState{}, err
+		}
+		log.Printf("Updated cert-manager Certificate for %s (issuer %s, hosts %v)", key, issuer, certificate.Spec.Hosts)
+	}
+	if secret != nil && isSecretCorrect(certificate, secret) {
+		return skupperv2alpha1.ReadyCondition(), nil
+	}
+	message := fmt.Sprintf("Waiting for %s to issue certificate", issuer)
+	if reason := certManagerNotReady(current); reason != "" {
+		message = fmt.Sprintf("%s: %s", message, reason)
+	}
+	return skupperv2alpha1.PendingCondition(message), nil
+}
+
+func (m *CertificateManagerImpl) createCertManagerCertificate(certificate *skupperv2alpha1.Certificate, spec map[string]interface{}) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(certManagerCertificateGVK)
+	obj.SetName(certificate.Name)
+	obj.SetNamespace(certificate.Namespace)
+	obj.SetOwnerReferences(ownerReferences(certificate))
+	obj.SetLabels(map[string]string{
+		"internal.skupper.io/certificate": "true",
+	})
+	if err := unstructured.SetNestedField(obj.Object, spec, "spec"); err != nil {
+		return nil, err
+	}
+	client := m.processor.GetDynamicClient().Resource(resource.CertManagerCertificateResource()).Namespace(certificate.Namespace)
+	created, err := client.Create(context.TODO(), obj, metav1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		// not yet seen by the watcher
+		return client.Get(context.TODO(), certificate.Name, metav1.GetOptions{})
+	} else if err != nil {
+		return nil, err
+	}
+	log.Printf("Created cert-manager Certificate for %s/%s", certificate.Namespace, certificate.Name)
+	return created, nil
+}
+
+func (m *CertificateManagerImpl) deleteCertManagerCertificate(key string) error {
+	if m.certManagerWatcher == nil {
+		return nil
+	}
+	current, err := m.certManagerWatcher.Get(key)
+	if err != nil || current == nil {
+		return err
+	}
+	err = m.processor.GetDynamicClient().Resource(resource.CertManagerCertificateResource()).Namespace(current.GetNamespace()).Delete(context.TODO(), current.GetName(), metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (m *CertificateManagerImpl) certManagerSpec(certificate *skupperv2alpha1.Certificate, issuer skupperv2alpha1.IssuerReference) map[string]interface{} {
+	spec := map[string]interface{}{
+		"secretName": certificate.Name,
+		"commonName": certificate.Spec.Subject,
+		"issuerRef": map[string]interface{}{
+			"name":  issuer.Name,
+			"kind":  issuer.Kind,
+			"group": issuer.Group,
+		},
+		"duration": m.config.Duration.String(),
+	}
+	if certificate.Spec.Signing {
+		spec["isCA"] = true
+		spec["usages"] = []interface{}{"digital signature", "key encipherment", "cert sign"}
+		spec["renewBefore"] = m.config.CaRenewBefore.String()
+	} else {
+		// as for generated certificates, these can be used for both
+		// server and client authentication
+		spec["usages"] = []interface{}{"digital signature", "key encipherment", "server auth", "client auth"}
+		spec["renewBefore"] = m.config.RenewBefore.String()
+	}
+	var dnsNames []interface{}
+	var ipAddresses []interface{}
+	for _, host := range certificate.Spec.Hosts {
+		if net.ParseIP(host) != nil {
+			ipAddresses = append(ipAddresses, host)
+		} else {
+			dnsNames = append(dnsNames, host)
+		}
+	}
+	if len(dnsNames) > 0 {
+		spec["dnsNames"] = dnsNames
+	}
+	if len(ipAddresses) > 0 {
+		spec["ipAddresses"] = ipAddresses
+	}
+	return spec
+}
+
+// The fields of the cert-manager Certificate spec that are removed if
+// no longer required.
+var optionalSpecFields = []string{"isCA", "dnsNames", "ipAddresses"}
+
+func specMatches(obj *unstructured.Unstructured, spec map[string]interface{}) bool {
+	for field, value := range spec {
+		actual, ok, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", field)
+		if !ok || !reflect.DeepEqual(actual, value) {
+			return false
+		}
+	}
+	for _, field := range optionalSpecFields {
+		if _, required := spec[field]; required {
+			continue
+		}
+		if _, ok, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", field); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the message from the Ready condition of the cert-manager
+// Certificate if that is false.
+func certManagerNotReady(obj *unstructured.Unstructured) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if conditionType, _, _ := unstructured.NestedString(condition, "type"); conditionType != "Ready" {
+			continue
+		}
+		if status, _, _ := unstructured.NestedString(condition, "status"); status == "False" {
+			message, _, _ := unstructured.NestedString(condition, "message")
+			return message
+		}
+	}
+	return ""
+}
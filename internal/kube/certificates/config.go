@@ -0,0 +1,31 @@
+package certificates
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/skupperproject/skupper/internal/certs"
+	iflag "github.com/skupperproject/skupper/internal/flag"
+)
+
+func BoundRenewalConfig(flags *flag.FlagSet) (*certs.RenewalConfig, error) {
+	c := certs.DefaultRenewalConfig()
+	var errors []string
+	if err := iflag.DurationVar(flags, &c.Duration, "certificate-duration", "SKUPPER_CERTIFICATE_DURATION", c.Duration, "The period for which generated certificates are valid."); err != nil {
+		errors = append(errors, err.Error())
+	}
+	if err := iflag.DurationVar(flags, &c.RenewBefore, "certificate-renew-before", "SKUPPER_CERTIFICATE_RENEW_BEFORE", c.RenewBefore, "How long before expiry generated certificates are renewed."); err != nil {
+		errors = append(errors, err.Error())
+	}
+	if err := iflag.DurationVar(flags, &c.CaRenewBefore, "ca-renew-before", "SKUPPER_CA_RENEW_BEFORE", c.CaRenewBefore, "How long before expiry generated CAs are rotated."); err != nil {
+		errors = append(errors, err.Error())
+	}
+	if err := iflag.DurationVar(flags, &c.CaRotationOverlap, "ca-rotation-overlap", "SKUPPER_CA_ROTATION_OVERLAP", c.CaRotationOverlap, "How long a rotated CA continues to be trusted alongside its replacement."); err != nil {
+		errors = append(errors, err.Error())
+	}
+	if len(errors) > 0 {
+		return &c, fmt.Errorf("Invalid environment variable(s): %s", strings.Join(errors, ", "))
+	}
+	return &c, nil
+}
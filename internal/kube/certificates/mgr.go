@@ -4,6 +4,7 @@
 package certificates
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -47,8 +49,11 @@ type CertificateManagerImpl struct {
 	secrets            map[string]*corev1.Secret
 	certificateWatcher *watchers.CertificateWatcher
 	secretWatcher      *watchers.SecretWatcher
+	certManagerWatcher *watchers.DynamicWatcher
 	processor          *watchers.EventProcessor
 	context            ControllerContext
+	config             certs.RenewalConfig
+	scheduled          map[string]time.Time
 }
 
 // Returns a correctly initialised CertificateManager.
@@ -57,6 +62,8 @@ func NewCertificateManager(processor *watchers.EventProcessor) *CertificateManag
 		definitions: map[string]*skupperv2alpha1.Certificate{},
 		secrets:     map[string]*corev1.Secret{},
 		processor:   processor,
+		config:      certs.DefaultRenewalConfig(),
+		scheduled:   map[string]time.Time{},
 	}
 }
 
@@ -65,10 +72,17 @@ func (m *CertificateManagerImpl) SetControllerContext(context ControllerContext)
 	m.context = context
 }
 
+// Allows the renewal of certificates and rotation of CAs to be
+// configured for this CertificateManager.
+func (m *CertificateManagerImpl) SetRenewalConfig(config certs.RenewalConfig) {
+	m.config = config
+}
+
 // Causes the CertificateManager to start watching relevant resources.
 func (m *CertificateManagerImpl) Watch(watchNamespace string) {
 	m.certificateWatcher = m.processor.WatchCertificates(watchNamespace, watchers.FilterByNamespace(m.isControlled, m.checkCertificate))
 	m.secretWatcher = m.processor.WatchAllSecrets(watchNamespace, watchers.FilterByNamespace(m.isControlled, m.checkSecret))
+	m.certManagerWatcher = m.processor.WatchCertManagerCertificates(certManagerCertificates(), watchNamespace, watchers.FilterByNamespace(m.isControlled, m.checkCertManagerCertificate))
 }
 
 func (m *CertificateManagerImpl) isControlled(namespace string) bool {
@@ -219,6 +233,13 @@ func (m *CertificateManagerImpl) checkCertificate(key string, certificate *skupp
 // This method does whatever is required to ensure that there is a
 // Secret resource corresponding to the supplied CertificateResource.
 func (m *CertificateManagerImpl) reconcile(key string, certificate *skupperv2alpha1.Certificate, secret *corev1.Secret) error {
+	if issuer, ok := certificate.ExternalIssuer(); ok {
+		state, err := m.requestCertificate(key, certificate, issuer, secret)
+		if err != nil {
+			return m.updateStatus(certificate, err)
+		}
+		return m.setStatus(certificate, state)
+	}
 	if secret != nil {
 		if err := m.updateSecret(key, certificate, secret); err != nil {
 			return m.updateStatus(certificate, err)
@@ -233,6 +254,9 @@ func (m *CertificateManagerImpl) reconcile(key string, certificate *skupperv2alp
 
 func (m *CertificateManagerImpl) certificateDeleted(key string) error {
 	delete(m.definitions, key)
+	if err := m.deleteCertManagerCertificate(key); err != nil {
+		return err
+	}
 	if secret, ok := m.secrets[key]; ok {
 		err := m.processor.GetKubeClient().CoreV1().Secrets(secret.Namespace).Delete(context.Background(), secret.Name, metav1.DeleteOptions{})
 		if err != nil {
@@ -250,7 +274,15 @@ func (m *CertificateManagerImpl) secretDeleted(key string) error {
 }
 
 func (m *CertificateManagerImpl) updateStatus(certificate *skupperv2alpha1.Certificate, err error) error {
-	if certificate.SetReady(err) {
+	return m.setStatus(certificate, skupperv2alpha1.ErrorOrReadyCondition(err))
+}
+
+func (m *CertificateManagerImpl) setStatus(certificate *skupperv2alpha1.Certificate, state skupperv2alpha1.ConditionState) error {
+	changed := certificate.Status.SetCondition(skupperv2alpha1.CONDITION_TYPE_READY, state, certificate.ObjectMeta.Generation)
+	if secret, ok := m.secrets[certificate.Key()]; ok && m.checkExpiration(certificate, secret) {
+		changed = true
+	}
+	if changed {
 		latest, err := m.processor.GetSkupperClient().SkupperV2alpha1().Certificates(certificate.Namespace).UpdateStatus(context.TODO(), certificate, metav1.UpdateOptions{})
 		if err != nil {
 			return err
@@ -277,6 +309,14 @@ func (m *CertificateManagerImpl) updateSecret(key string, certificate *skupperv2
 		changed = true
 		secret.Data = regenerated.Data
 		secret.Annotations["internal.skupper.io/hosts"] = strings.Join(certificate.Spec.Hosts, ",")
+	} else if controlled {
+		renewed, err := m.renew(key, certificate, secret)
+		if err != nil {
+			return err
+		}
+		if renewed {
+			changed = true
+		}
 	}
 	if m.context != nil && controlled {
 		if secret.Labels == nil {
@@ -303,6 +343,9 @@ func (m *CertificateManagerImpl) updateSecret(key string, certificate *skupperv2
 	}
 	m.secrets[key] = updated
 	log.Printf("Updated Secret %s/%s for Certificate %s (hosts %v)", secret.Namespace, secret.Name, key, certificate.Spec.Hosts)
+	if certificate.Spec.Signing {
+		return m.reconcileIssuedBy(updated)
+	}
 	return nil
 }
 
@@ -311,15 +354,16 @@ func (m *CertificateManagerImpl) generateSecret(certificate *skupperv2alpha1.Cer
 	if certificate.Spec.Signing {
 		secret = certs.GenerateSecret(certificate.Name, certificate.Spec.Subject, "", 0, nil)
 	} else {
-		expiration := time.Hour * 24 * 365 * 5 // TODO: make this configurable (through controller setting or field on certificate?)
-		caKey := fmt.Sprintf("%s/%s", certificate.Namespace, certificate.Spec.Ca)
+		caKey := issuerKey(certificate)
 		ca, ok := m.secrets[caKey]
 		if !ok {
 			// TODO: no CA exists yet, set error on certificate status
 			return nil, fmt.Errorf("CA %q not found", caKey)
 		}
 		// TODO: handle server and client roles properly
-		secret = certs.GenerateSecret(certificate.Name, certificate.Spec.Subject, strings.Join(certificate.Spec.Hosts, ","), expiration, ca)
+		signer := certs.SigningCA(ca, m.config.CaRotationOverlap, time.Now())
+		secret = certs.GenerateSecret(certificate.Name, certificate.Spec.Subject, strings.Join(certificate.Spec.Hosts, ","), m.config.Duration, signer)
+		secret.Data["ca.crt"] = certs.TrustBundle(ca)
 	}
 	secret.ObjectMeta.OwnerReferences = ownerReferences(certificate)
 	return &secret, nil
@@ -362,10 +406,184 @@ func (m *CertificateManagerImpl) checkSecret(key string, secret *corev1.Secret)
 	}
 	m.secrets[key] = secret
 	if definition, ok := m.definitions[key]; ok {
-		return m.reconcile(key, definition, secret)
+		if err := m.reconcile(key, definition, secret); err != nil {
+			return err
+		}
 	}
+	return m.reconcileIssuedBy(secret)
+}
 
-	return nil
+// When a CA is rotated, the certificates it issued need to trust the
+// new CA and, once the overlap has ended, be reissued by it.
+func (m *CertificateManagerImpl) reconcileIssuedBy(ca *corev1.Secret) error {
+	if m.certificateWatcher == nil {
+		return nil
+	}
+	var errs []error
+	for _, certificate := range m.certificateWatcher.List() {
+		if certificate.Namespace != ca.Namespace || certificate.Spec.Ca != ca.Name || certificate.Spec.Signing {
+			continue
+		}
+		key := certificate.Key()
+		if current, ok := m.definitions[key]; ok {
+			certificate = current
+		}
+		if secret, ok := m.secrets[key]; ok {
+			if err := m.reconcile(key, certificate, secret); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Renews the certificate in a controlled Secret if it is due to
+// expire, or if a CA is rotated, updates the certificates it
+// issued. Returns true if the Secret was changed.
+func (m *CertificateManagerImpl) renew(key string, certificate *skupperv2alpha1.Certificate, secret *corev1.Secret) (bool, error) {
+	cert, err := certs.DecodeCertificate(secret.Data["tls.crt"])
+	if err != nil {
+		return false, nil
+	}
+	now := time.Now()
+	if certificate.Spec.Signing {
+		if certs.RenewalDue(cert, m.config.CaRenewBefore, now) {
+			rotated := certs.RotateCA(certificate.Name, certificate.Spec.Subject, secret)
+			secret.Data = rotated.Data
+			log.Printf("Rotated CA %s, previous CA expires at %s", key, cert.NotAfter.Format(time.RFC3339))
+			m.recordEvent(certificate, corev1.EventTypeNormal, "CARotated", fmt.Sprintf("Rotated CA expiring at %s, which remains trusted until %s", cert.NotAfter.Format(time.RFC3339), now.Add(m.config.CaRotationOverlap).Format(time.RFC3339)))
+			return true, nil
+		}
+		if certs.EndCARotation(secret, m.config.CaRotationOverlap, now) {
+			log.Printf("Rotation of CA %s complete", key)
+			m.recordEvent(certificate, corev1.EventTypeNormal, "CARotationComplete", "The previous CA is no longer trusted")
+			return true, nil
+		}
+		return false, nil
+	}
+	ca, ok := m.secrets[issuerKey(certificate)]
+	if !ok {
+		return false, nil
+	}
+	bundle := certs.TrustBundle(ca)
+	reason := ""
+	if certs.RenewalDue(cert, m.config.RenewBefore, now) {
+		reason = fmt.Sprintf("Renewed certificate expiring at %s", cert.NotAfter.Format(time.RFC3339))
+	} else if len(certs.DecodeCertificates(bundle)) > 0 && !certs.IssuedBy(cert, bundle) {
+		reason = "Reissued certificate as its CA has been rotated"
+	}
+	if reason != "" {
+		regenerated, err := m.generateSecret(certificate)
+		if err != nil {
+			return false, err
+		}
+		secret.Data = regenerated.Data
+		log.Printf("%s for %s", reason, key)
+		m.recordEvent(certificate, corev1.EventTypeNormal, "CertificateRenewed", reason)
+		return true, nil
+	}
+	if len(bundle) > 0 && !bytes.Equal(secret.Data["ca.crt"], bundle) {
+		secret.Data["ca.crt"] = bundle
+		return true, nil
+	}
+	return false, nil
+}
+
+// Records the expiration of the certificate in its status, warning if
+// it is due for renewal but has not been renewed, and schedules a
+// recheck for when action is next required. Returns true if the
+// status was changed.
+func (m *CertificateManagerImpl) checkExpiration(certificate *skupperv2alpha1.Certificate, secret *corev1.Secret) bool {
+	cert, err := certs.DecodeCertificate(secret.Data["tls.crt"])
+	if err != nil {
+		return false
+	}
+	now := time.Now()
+	renewBefore := m.config.RenewBefore
+	if certificate.Spec.Signing {
+		renewBefore = m.config.CaRenewBefore
+	}
+	renewal := certs.RenewalTime(cert, renewBefore)
+	valid := certificate.IsValid()
+	changed := certificate.SetExpiration(cert.NotAfter, renewal, now)
+	if valid && !certificate.IsValid() {
+		if condition := meta.FindStatusCondition(certificate.Status.Conditions, skupperv2alpha1.CONDITION_TYPE_VALID); condition != nil {
+			log.Printf("Certificate %s: %s", certificate.Key(), condition.Message)
+			m.recordEvent(certificate, corev1.EventTypeWarning, "CertificateExpiring", condition.Message)
+		}
+	}
+	next := []time.Time{renewal, cert.NotAfter}
+	if end, ok := certs.RotationEnd(secret, m.config.CaRotationOverlap); ok && certificate.Spec.Signing {
+		next = append(next, end)
+	}
+	var recheck time.Time
+	for _, t := range next {
+		if t.After(now) && (recheck.IsZero() || t.Before(recheck)) {
+			recheck = t
+		}
+	}
+	if !recheck.IsZero() {
+		m.scheduleRecheck(certificate.Key(), recheck)
+	}
+	return changed
+}
+
+func (m *CertificateManagerImpl) scheduleRecheck(key string, at time.Time) {
+	if scheduled, ok := m.scheduled[key]; ok && !scheduled.After(at) && scheduled.After(time.Now()) {
+		return
+	}
+	m.scheduled[key] = at
+	m.processor.CallbackAfter(time.Until(at), m.recheck, key)
+}
+
+func (m *CertificateManagerImpl) recheck(key string) error {
+	if scheduled, ok := m.scheduled[key]; ok && !scheduled.After(time.Now()) {
+		delete(m.scheduled, key)
+	}
+	certificate, err := m.lookup(key)
+	if err != nil || certificate == nil {
+		return err
+	}
+	return m.checkCertificate(key, certificate)
+}
+
+// Returns the latest known definition of the Certificate with the
+// supplied key, or nil if there is none.
+func (m *CertificateManagerImpl) lookup(key string) (*skupperv2alpha1.Certificate, error) {
+	if certificate, ok := m.definitions[key]; ok {
+		return certificate, nil
+	}
+	if m.certificateWatcher == nil {
+		return nil, nil
+	}
+	return m.certificateWatcher.Get(key)
+}
+
+func (m *CertificateManagerImpl) recordEvent(certificate *skupperv2alpha1.Certificate, eventType string, reason string, message string) {
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", certificate.Name, now.UnixNano()),
+			Namespace: certificate.Namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:       "Certificate",
+			APIVersion: "skupper.io/v2alpha1",
+			Namespace:  certificate.Namespace,
+			Name:       certificate.Name,
+			UID:        certificate.ObjectMeta.UID,
+		},
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		Source:         corev1.EventSource{Component: "skupper-controller"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	if _, err := m.processor.GetKubeClient().CoreV1().Events(certificate.Namespace).Create(context.TODO(), event, metav1.CreateOptions{}); err != nil {
+		log.Printf("Error recording %s event for Certificate %s: %s", reason, certificate.Key(), err)
+	}
 }
 
 func isSecretCorrect(certificate *skupperv2alpha1.Certificate, secret *corev1.Secret) bool {
@@ -421,6 +639,10 @@ func hasCertificateOwner(secret *corev1.Secret) bool {
 	return false
 }
 
+func issuerKey(certificate *skupperv2alpha1.Certificate) string {
+	return fmt.Sprintf("%s/%s", certificate.Namespace, certificate.Spec.Ca)
+}
+
 func secretKey(secret *corev1.Secret) string {
 	return fmt.Sprintf("%s/%s", secret.Namespace, secret.Name)
 }
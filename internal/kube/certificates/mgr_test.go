@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/skupperproject/skupper/internal/certs"
 	fakeclient "github.com/skupperproject/skupper/internal/kube/client/fake"
+	"github.com/skupperproject/skupper/internal/kube/resource"
 	"github.com/skupperproject/skupper/internal/kube/watchers"
 	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
 	"gotest.tools/v3/assert"
@@ -15,6 +16,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -524,3 +526,243 @@ func fixtureCASecret(t *testing.T, name, namespace string) *corev1.Secret {
 	secret.Namespace = namespace
 	return &secret
 }
+
+func TestCertificateManagerRenewal(t *testing.T) {
+	controlled := map[string]string{"internal.skupper.io/controlled": "true"}
+	ca := controlledSecret(certs.GenerateSecret("my-ca", "my-ca-subject", "", 0, nil), "test", controlled)
+	expiringCa := controlledSecret(certs.GenerateSecret("my-ca", "my-ca-subject", "", time.Hour, nil), "test", controlled)
+	rotatedCa := controlledSecret(certs.RotateCA("my-ca", "my-ca-subject", expiringCa), "test", controlled)
+	testTable := []struct {
+		name               string
+		config             certs.RenewalConfig
+		k8sObjects         []runtime.Object
+		skupperObjects     []runtime.Object
+		expectRenewed      bool
+		expectIssuer       *corev1.Secret
+		expectCaBundleSize int
+		expectValid        metav1.ConditionStatus
+		expectEvent        string
+	}{
+		{
+			name:   "leaf certificate renewed",
+			config: certs.DefaultRenewalConfig(),
+			k8sObjects: []runtime.Object{
+				ca,
+				controlledSecret(certs.GenerateSecret("foo", "my-subject", "aaa", time.Hour, ca), "test", controlled),
+			},
+			skupperObjects: []runtime.Object{
+				caCertificate("my-ca", "test", "my-ca-subject", nil, nil),
+				certificate("foo", "test", "my-ca", "my-subject", []string{"aaa"}, false, true, nil, nil),
+			},
+			expectRenewed:      true,
+			expectIssuer:       ca,
+			expectCaBundleSize: 1,
+			expectValid:        metav1.ConditionTrue,
+			expectEvent:        "CertificateRenewed",
+		},
+		{
+			name:   "ca rotated",
+			config: certs.DefaultRenewalConfig(),
+			k8sObjects: []runtime.Object{
+				expiringCa,
+				controlledSecret(certs.GenerateSecret("foo", "my-subject", "aaa", 0, expiringCa), "test", controlled),
+			},
+			skupperObjects: []runtime.Object{
+				caCertificate("my-ca", "test", "my-ca-subject", nil, nil),
+				certificate("foo", "test", "my-ca", "my-subject", []string{"aaa"}, false, true, nil, nil),
+			},
+			expectIssuer:       expiringCa,
+			expectCaBundleSize: 2,
+			expectValid:        metav1.ConditionTrue,
+			expectEvent:        "CARotated",
+		},
+		{
+			name: "ca rotation overlap ended",
+			config: certs.RenewalConfig{
+				Duration:    time.Hour * 24,
+				RenewBefore: time.Hour,
+			},
+			k8sObjects: []runtime.Object{
+				rotatedCa,
+				controlledSecret(certs.GenerateSecret("foo", "my-subject", "aaa", 0, expiringCa), "test", controlled),
+			},
+			skupperObjects: []runtime.Object{
+				caCertificate("my-ca", "test", "my-ca-subject", nil, nil),
+				certificate("foo", "test", "my-ca", "my-subject", []string{"aaa"}, false, true, nil, nil),
+			},
+			expectRenewed:      true,
+			expectIssuer:       rotatedCa,
+			expectCaBundleSize: 1,
+			expectValid:        metav1.ConditionTrue,
+			expectEvent:        "CARotationComplete",
+		},
+		{
+			name:   "uncontrolled certificate expiring",
+			config: certs.DefaultRenewalConfig(),
+			k8sObjects: []runtime.Object{
+				ca,
+				controlledSecret(certs.GenerateSecret("foo", "my-subject", "aaa", time.Hour, ca), "test", nil),
+			},
+			skupperObjects: []runtime.Object{
+				certificate("foo", "test", "my-ca", "my-subject", []string{"aaa"}, false, true, nil, nil),
+			},
+			expectIssuer:       ca,
+			expectCaBundleSize: 1,
+			expectValid:        metav1.ConditionFalse,
+			expectEvent:        "CertificateExpiring",
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := fakeclient.NewFakeClient("test", tt.k8sObjects, tt.skupperObjects, "")
+			assert.Assert(t, err)
+			processor := watchers.NewEventProcessor("Controller", client)
+			mgr := NewCertificateManager(processor)
+			mgr.SetRenewalConfig(tt.config)
+			mgr.Watch(metav1.NamespaceAll)
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			processor.StartWatchers(stopCh)
+			processor.WaitForCacheSync(stopCh)
+			mgr.Recover()
+
+			original, err := certs.DecodeCertificate(secretData(tt.k8sObjects, "foo", "tls.crt"))
+			assert.Assert(t, err)
+			actual, err := client.GetKubeClient().CoreV1().Secrets("test").Get(context.Background(), "foo", metav1.GetOptions{})
+			assert.Assert(t, err)
+			cert, err := certs.DecodeCertificate(actual.Data["tls.crt"])
+			assert.Assert(t, err)
+			assert.Equal(t, !cert.Equal(original), tt.expectRenewed)
+			assert.Assert(t, certs.IssuedBy(cert, tt.expectIssuer.Data["tls.crt"]))
+			assert.Equal(t, len(certs.DecodeCertificates(actual.Data["ca.crt"])), tt.expectCaBundleSize)
+
+			status, err := client.GetSkupperClient().SkupperV2alpha1().Certificates("test").Get(context.Background(), "foo", metav1.GetOptions{})
+			assert.Assert(t, err)
+			assert.Equal(t, status.Status.Expiration, cert.NotAfter.UTC().Format(time.RFC3339))
+			valid := meta.FindStatusCondition(status.Status.Conditions, skupperv2alpha1.CONDITION_TYPE_VALID)
+			assert.Assert(t, valid != nil)
+			assert.Equal(t, valid.Status, tt.expectValid)
+
+			events, err := client.GetKubeClient().CoreV1().Events("test").List(context.Background(), metav1.ListOptions{})
+			assert.Assert(t, err)
+			var reasons []string
+			for _, event := range events.Items {
+				reasons = append(reasons, event.Reason)
+			}
+			assert.Assert(t, cmp.Contains(reasons, tt.expectEvent))
+		})
+	}
+}
+
+func controlledSecret(secret corev1.Secret, namespace string, annotations map[string]string) *corev1.Secret {
+	secret.Namespace = namespace
+	secret.Annotations = annotations
+	return &secret
+}
+
+func secretData(objects []runtime.Object, name string, key string) []byte {
+	for _, obj := range objects {
+		if secret, ok := obj.(*corev1.Secret); ok && secret.Name == name {
+			return secret.Data[key]
+		}
+	}
+	return nil
+}
+
+func TestCertificateManagerExternalIssuer(t *testing.T) {
+	issuer := "ClusterIssuer.cert-manager.io/corp-pki"
+	pki := certs.GenerateSecret("corp-pki", "corp-pki", "", 0, nil)
+	issued := controlledSecret(certs.GenerateSecret("foo", "my-subject", "aaa,10.0.0.1", 0, &pki), "test", nil)
+	stale := &unstructured.Unstructured{}
+	stale.SetGroupVersionKind(certManagerCertificateGVK)
+	stale.SetName("foo")
+	stale.SetNamespace("test")
+	stale.SetLabels(map[string]string{"internal.skupper.io/certificate": "true"})
+	stale.Object["spec"] = map[string]interface{}{
+		"secretName": "foo",
+		"dnsNames":   []interface{}{"zzz"},
+	}
+	stale.Object["status"] = map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{
+				"type":    "Ready",
+				"status":  "False",
+				"message": "Issuer corp-pki not found",
+			},
+		},
+	}
+	testTable := []struct {
+		name          string
+		k8sObjects    []runtime.Object
+		expectReady   metav1.ConditionStatus
+		expectMessage string
+	}{
+		{
+			name:          "certificate requested",
+			expectReady:   metav1.ConditionFalse,
+			expectMessage: "Waiting for ClusterIssuer.cert-manager.io/corp-pki to issue certificate",
+		},
+		{
+			name: "certificate issued",
+			k8sObjects: []runtime.Object{
+				issued,
+			},
+			expectReady:   metav1.ConditionTrue,
+			expectMessage: "OK",
+		},
+		{
+			name: "request updated",
+			k8sObjects: []runtime.Object{
+				stale,
+			},
+			expectReady:   metav1.ConditionFalse,
+			expectMessage: "Waiting for ClusterIssuer.cert-manager.io/corp-pki to issue certificate: Issuer corp-pki not found",
+		},
+	}
+	for _, tt := range testTable {
+		t.Run(tt.name, func(t *testing.T) {
+			skupperObjects := []runtime.Object{
+				certificate("foo", "test", issuer, "my-subject", []string{"aaa", "10.0.0.1"}, false, true, nil, nil),
+			}
+			client, err := fakeclient.NewFakeClient("test", tt.k8sObjects, skupperObjects, "")
+			assert.Assert(t, err)
+			processor := watchers.NewEventProcessor("Controller", client)
+			mgr := NewCertificateManager(processor)
+			mgr.Watch(metav1.NamespaceAll)
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			processor.StartWatchers(stopCh)
+			processor.WaitForCacheSync(stopCh)
+			mgr.Recover()
+
+			requested, err := client.GetDynamicClient().Resource(resource.CertManagerCertificateResource()).Namespace("test").Get(context.Background(), "foo", metav1.GetOptions{})
+			assert.Assert(t, err)
+			spec := requested.Object["spec"].(map[string]interface{})
+			assert.Equal(t, spec["secretName"], "foo")
+			assert.Equal(t, spec["commonName"], "my-subject")
+			assert.DeepEqual(t, spec["issuerRef"], map[string]interface{}{
+				"name":  "corp-pki",
+				"kind":  "ClusterIssuer",
+				"group": "cert-manager.io",
+			})
+			assert.DeepEqual(t, spec["dnsNames"], []interface{}{"aaa"})
+			assert.DeepEqual(t, spec["ipAddresses"], []interface{}{"10.0.0.1"})
+			assert.Equal(t, spec["renewBefore"], certs.DefaultRenewalConfig().RenewBefore.String())
+
+			secret, err := client.GetKubeClient().CoreV1().Secrets("test").Get(context.Background(), "foo", metav1.GetOptions{})
+			if tt.expectReady == metav1.ConditionTrue {
+				assert.Assert(t, err)
+				assert.DeepEqual(t, secret.Data, issued.Data)
+			} else {
+				assert.Assert(t, err != nil, "secret should not be generated for external issuer")
+			}
+
+			status, err := client.GetSkupperClient().SkupperV2alpha1().Certificates("test").Get(context.Background(), "foo", metav1.GetOptions{})
+			assert.Assert(t, err)
+			ready := meta.FindStatusCondition(status.Status.Conditions, skupperv2alpha1.CONDITION_TYPE_READY)
+			assert.Assert(t, ready != nil)
+			assert.Equal(t, ready.Status, tt.expectReady)
+			assert.Equal(t, ready.Message, tt.expectMessage)
+		})
+	}
+}
@@ -51,10 +51,11 @@ func NewFakeClient(namespace string, k8sObjects []runtime.Object, skupperObjects
 	scheme := runtime.NewScheme()
 	appsv1.AddToScheme(scheme)
 	c.Dynamic = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme, map[schema.GroupVersionResource]string{
-		resource.ContourHttpProxyResource(): "HTTPProxyList",
-		resource.GatewayResource():          "GatewayList",
-		resource.TlsRouteResource():         "TLSRouteList",
-		resource.DeploymentResource():       "DeploymentList",
+		resource.ContourHttpProxyResource():       "HTTPProxyList",
+		resource.GatewayResource():                "GatewayList",
+		resource.TlsRouteResource():               "TLSRouteList",
+		resource.DeploymentResource():             "DeploymentList",
+		resource.CertManagerCertificateResource(): "CertificateList",
 	}, dynamic...)
 	// prepopulated objects not working for some reason with dynamic client, so create them manually here for now:
 	for _, d := range dynamic {
@@ -77,6 +78,10 @@ func gvrFromGvk(gvk schema.GroupVersionKind) (schema.GroupVersionResource, bool)
 		if gvk.Kind == "HTTPProxy" {
 			return resource.ContourHttpProxyResource(), true
 		}
+	case "cert-manager.io":
+		if gvk.Kind == "Certificate" {
+			return resource.CertManagerCertificateResource(), true
+		}
 	case "gateway.networking.k8s.io":
 		if gvk.Kind == "TLSRoute" {
 			return resource.TlsRouteResource(), true
@@ -102,6 +107,19 @@ func fakedApiResources() []*metav1.APIResourceList {
 				},
 			},
 		},
+		{
+			GroupVersion: "cert-manager.io/v1",
+			APIResources: []metav1.APIResource{
+				{
+					Name:         "certificates",
+					SingularName: "certificate",
+					Namespaced:   true,
+					Group:        "cert-manager.io",
+					Version:      "v1",
+					Kind:         "Certificate",
+				},
+			},
+		},
 		{
 			GroupVersion: "gateway.networking.k8s.io/v1alpha2",
 			APIResources: []metav1.APIResource{
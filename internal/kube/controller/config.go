@@ -5,14 +5,19 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/skupperproject/skupper/internal/certs"
 	iflag "github.com/skupperproject/skupper/internal/flag"
+	"github.com/skupperproject/skupper/internal/kube/certificates"
 	"github.com/skupperproject/skupper/internal/kube/grants"
 	"github.com/skupperproject/skupper/internal/kube/securedaccess"
+	"github.com/skupperproject/skupper/internal/kube/webhook"
 )
 
 type Config struct {
 	GrantConfig            *grants.GrantConfig
 	SecuredAccessConfig    *securedaccess.Config
+	CertificateConfig      *certs.RenewalConfig
+	WebhookConfig          *webhook.WebhookConfig
 	Namespace              string
 	Kubeconfig             string
 	WatchNamespace         string
@@ -39,9 +44,19 @@ func BoundConfig(flags *flag.FlagSet) (*Config, error) {
 	} else if err := securedAccessConfig.Verify(); err != nil {
 		return nil, err
 	}
+	certificateConfig, err := certificates.BoundRenewalConfig(flags)
+	if err != nil {
+		return nil, err
+	}
+	webhookConfig, err := webhook.BoundWebhookConfig(flags)
+	if err != nil {
+		return nil, err
+	}
 	c := &Config{
 		GrantConfig:         grantConfig,
 		SecuredAccessConfig: securedAccessConfig,
+		CertificateConfig:   certificateConfig,
+		WebhookConfig:       webhookConfig,
 	}
 	iflag.StringVar(flags, &c.Namespace, "namespace", "NAMESPACE", "", "The Kubernetes namespace scope for the controller")
 	iflag.StringVar(flags, &c.Kubeconfig, "kubeconfig", "KUBECONFIG", "", "A path to the kubeconfig file to use")
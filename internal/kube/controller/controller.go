@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -24,6 +25,7 @@ import (
 	"github.com/skupperproject/skupper/internal/kube/site/labels"
 	"github.com/skupperproject/skupper/internal/kube/site/sizing"
 	"github.com/skupperproject/skupper/internal/kube/watchers"
+	"github.com/skupperproject/skupper/internal/kube/webhook"
 	"github.com/skupperproject/skupper/internal/network"
 	"github.com/skupperproject/skupper/internal/version"
 	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
@@ -39,9 +41,12 @@ type Controller struct {
 	listenerWatcher      *watchers.ListenerWatcher
 	connectorWatcher     *watchers.ConnectorWatcher
 	linkAccessWatcher    *watchers.RouterAccessWatcher
+	linkWatcher          *watchers.LinkWatcher
+	accessPolicyWatcher  *watchers.AccessPolicyWatcher
 	grantWatcher         *watchers.AccessGrantWatcher
 	sites                map[string]*site.Site
 	startGrantServer     func()
+	startWebhook         func()
 	accessMgr            *securedaccess.SecuredAccessManager
 	accessRecovery       *securedaccess.SecuredAccessResourceWatcher
 	certMgr              *certificates.CertificateManagerImpl
@@ -119,7 +124,8 @@ func NewController(cli internalclient.Clients, config *Config) (*Controller, err
 	controller.linkAccessWatcher = controller.eventProcessor.WatchRouterAccesses(config.WatchNamespace, filter(controller, controller.checkRouterAccess))
 	controller.eventProcessor.WatchAttachedConnectors(config.WatchNamespace, filter(controller, controller.checkAttachedConnector))
 	controller.eventProcessor.WatchAttachedConnectorBindings(config.WatchNamespace, filter(controller, controller.checkAttachedConnectorBinding))
-	controller.eventProcessor.WatchLinks(config.WatchNamespace, filter(controller, controller.checkLink))
+	controller.linkWatcher = controller.eventProcessor.WatchLinks(config.WatchNamespace, filter(controller, controller.checkLink))
+	controller.accessPolicyWatcher = controller.eventProcessor.WatchAccessPolicies(config.WatchNamespace, filter(controller, controller.checkAccessPolicy))
 	controller.eventProcessor.WatchConfigMaps(skupperNetworkStatus(), config.WatchNamespace, filter(controller, controller.networkStatusUpdate))
 	controller.eventProcessor.WatchAccessTokens(config.WatchNamespace, filter(controller, controller.checkAccessToken))
 	controller.eventProcessor.WatchPods("skupper.io/component=router,skupper.io/type=site", config.WatchNamespace, filter(controller, controller.routerPodEvent))
@@ -129,6 +135,12 @@ func NewController(cli internalclient.Clients, config *Config) (*Controller, err
 
 	controller.certMgr = certificates.NewCertificateManager(controller.eventProcessor)
 	controller.certMgr.SetControllerContext(controller)
+	if config.CertificateConfig != nil {
+		if err := config.CertificateConfig.Verify(); err != nil {
+			return nil, err
+		}
+		controller.certMgr.SetRenewalConfig(*config.CertificateConfig)
+	}
 	controller.certMgr.Watch(config.WatchNamespace)
 
 	controller.accessMgr = securedaccess.NewSecuredAccessManager(controller.eventProcessor, controller.certMgr, config.SecuredAccessConfig, controller)
@@ -137,7 +149,8 @@ func NewController(cli internalclient.Clients, config *Config) (*Controller, err
 	controller.accessRecovery.WatchSecuredAccesses(controller.eventProcessor, config.WatchNamespace, controller.checkSecuredAccess)
 	controller.accessRecovery.WatchGateway(controller.eventProcessor, config.Namespace)
 
-	controller.startGrantServer = grants.Initialise(controller.eventProcessor, config.Namespace, config.WatchNamespace, config.GrantConfig, controller.generateLinkConfig, controller.IsControlled)
+	controller.startGrantServer = grants.Initialise(controller.eventProcessor, config.Namespace, config.WatchNamespace, config.GrantConfig, controller.generateLinkConfig, controller.grantRevocationsChanged, controller.IsControlled)
+	controller.startWebhook = webhook.Initialise(controller.eventProcessor, config.Namespace, config.WebhookConfig, cli.GetSkupperClient(), controller.accessMgr.IsValidAccessType)
 
 	controller.eventProcessor.WatchConfigMaps(skupperLogConfig(), config.Namespace, controller.logConfigUpdate)
 
@@ -297,6 +310,9 @@ func (c *Controller) init(stopCh <-chan struct{}) error {
 	if c.startGrantServer != nil {
 		c.startGrantServer()
 	}
+	if c.startWebhook != nil {
+		c.startWebhook()
+	}
 	return nil
 }
 
@@ -314,6 +330,18 @@ func (c *Controller) getSite(namespace string) *site.Site {
 	}
 	site := site.NewSite(namespace, c.eventProcessor, c.certMgr, c.accessMgr, c.siteSizing, c)
 	c.sites[namespace] = site
+	for _, policy := range c.accessPolicyWatcher.List() {
+		if policy.Namespace != namespace {
+			continue
+		}
+		if _, err := site.CheckAccessPolicy(policy.Name, policy); err != nil {
+			c.log.Error("Error recovering access policy",
+				slog.String("name", policy.Name),
+				slog.String("namespace", policy.Namespace),
+				slog.Any("error", err),
+			)
+		}
+	}
 	return site
 }
 
@@ -380,6 +408,42 @@ func (c *Controller) checkLink(key string, linkconfig *skupperv2alpha1.Link) err
 	return c.getSite(namespace).CheckLink(name, linkconfig)
 }
 
+func (c *Controller) checkAccessPolicy(key string, policy *skupperv2alpha1.AccessPolicy) error {
+	c.log.Debug("checkAccessPolicy", slog.String("key", key))
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	site := c.getSite(namespace)
+	changed, err := site.CheckAccessPolicy(name, policy)
+	if err != nil || !changed {
+		return err
+	}
+	c.log.Info("Access policies changed, rechecking resources", slog.String("namespace", namespace))
+	var errs []error
+	for _, connector := range c.connectorWatcher.List() {
+		if connector.Namespace == namespace {
+			errs = append(errs, site.CheckConnector(connector.Name, connector))
+		}
+	}
+	for _, listener := range c.listenerWatcher.List() {
+		if listener.Namespace == namespace {
+			errs = append(errs, site.CheckListener(listener.Name, listener))
+		}
+	}
+	for _, link := range c.linkWatcher.List() {
+		if link.Namespace == namespace {
+			errs = append(errs, site.CheckLink(link.Name, link))
+		}
+	}
+	for _, la := range c.linkAccessWatcher.List() {
+		if la.Namespace == namespace {
+			errs = append(errs, site.CheckRouterAccess(la.Name, la))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (c *Controller) checkAccessToken(key string, token *skupperv2alpha1.AccessToken) error {
 	if token == nil || token.IsRedeemed() {
 		return nil
@@ -399,17 +463,38 @@ func (c *Controller) routerPodEvent(key string, pod *corev1.Pod) error {
 	return c.getSite(namespace).RouterPodEvent(key, pod)
 }
 
-func (c *Controller) generateLinkConfig(namespace string, name string, subject string, writer io.Writer) error {
+func (c *Controller) generateLinkConfig(namespace string, name string, subject string, writer io.Writer) (*skupperv2alpha1.IssuedCertificate, error) {
 	site := c.getSite(namespace).GetSite()
 	if site == nil {
-		return fmt.Errorf("Site not yet defined for %s", namespace)
+		return nil, fmt.Errorf("Site not yet defined for %s", namespace)
 	}
 	generator, err := grants.NewTokenGenerator(site, c.eventProcessor)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	token := generator.NewCertToken(name, subject)
-	return token.Write(writer)
+	if err := token.Write(writer); err != nil {
+		return nil, err
+	}
+	issued, err := token.Issued()
+	if err != nil {
+		// the link has been issued regardless, it just can't be revoked
+		c.log.Error("Could not record certificate issued for link",
+			slog.String("namespace", namespace),
+			slog.String("name", name),
+			slog.Any("error", err),
+		)
+		return nil, nil
+	}
+	return issued, nil
+}
+
+func (c *Controller) grantRevocationsChanged(namespace string, grant string, revoked []skupperv2alpha1.RevokedSite) error {
+	site, ok := c.sites[namespace]
+	if !ok {
+		return nil
+	}
+	return site.RevokedSitesUpdated(grant, revoked)
 }
 
 func (c *Controller) checkSecuredAccess(key string, se *skupperv2alpha1.SecuredAccess) error {
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"strings"
+	"time"
 
 	iflag "github.com/skupperproject/skupper/internal/flag"
 )
@@ -15,6 +16,11 @@ type GrantConfig struct {
 	Port                 int
 	TlsCredentialsSecret string
 	Hostname             string
+	// The number of failed redemption attempts allowed from a
+	// client address within the window before further attempts
+	// from it are refused; zero or less disables the limit.
+	MaxFailedRedemptions   int
+	FailedRedemptionWindow time.Duration
 }
 
 func BoundGrantConfig(flags *flag.FlagSet) (*GrantConfig, error) {
@@ -32,6 +38,12 @@ func BoundGrantConfig(flags *flag.FlagSet) (*GrantConfig, error) {
 	}
 	iflag.StringVar(flags, &c.TlsCredentialsSecret, "grant-server-tls-credentials", "SKUPPER_GRANT_SERVER_TLS_CREDENTIALS", "skupper-grant-server", "The name of a secret in which TLS credentials for the AccessGrant server are found.")
 	iflag.StringVar(flags, &c.Hostname, "grant-server-podname", "HOSTNAME", "", "The name of the pod in which the AccessGrant server is running (defaults to $HOSTNAME).")
+	if err := iflag.IntVar(flags, &c.MaxFailedRedemptions, "grant-server-max-failed-redemptions", "SKUPPER_GRANT_SERVER_MAX_FAILED_REDEMPTIONS", 10, "The number of failed redemption attempts allowed from a client within the failed redemption window (0 to disable)."); err != nil {
+		errors = append(errors, err.Error())
+	}
+	if err := iflag.DurationVar(flags, &c.FailedRedemptionWindow, "grant-server-failed-redemption-window", "SKUPPER_GRANT_SERVER_FAILED_REDEMPTION_WINDOW", time.Minute*5, "The period over which failed redemption attempts from a client are counted."); err != nil {
+		errors = append(errors, err.Error())
+	}
 	if len(errors) > 0 {
 		return c, fmt.Errorf("Invalid environment variable(s): %s", strings.Join(errors, ", "))
 	}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"os"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 )
@@ -19,9 +20,11 @@ func Test_BoundGrantConfig(t *testing.T) {
 		{
 			name: "defaults",
 			expectedValue: &GrantConfig{
-				Port:                 9090,
-				TlsCredentialsSecret: "skupper-grant-server",
-				Hostname:             os.Getenv("HOSTNAME"),
+				Port:                   9090,
+				TlsCredentialsSecret:   "skupper-grant-server",
+				Hostname:               os.Getenv("HOSTNAME"),
+				MaxFailedRedemptions:   10,
+				FailedRedemptionWindow: time.Minute * 5,
 			},
 		},
 		{
@@ -35,12 +38,14 @@ func Test_BoundGrantConfig(t *testing.T) {
 				"HOSTNAME":                             "my-host",
 			},
 			expectedValue: &GrantConfig{
-				Enabled:              true,
-				AutoConfigure:        true,
-				BaseUrl:              "https://acme.org:8888/grants",
-				Port:                 1234,
-				TlsCredentialsSecret: "my-secret",
-				Hostname:             "my-host",
+				Enabled:                true,
+				AutoConfigure:          true,
+				BaseUrl:                "https://acme.org:8888/grants",
+				Port:                   1234,
+				TlsCredentialsSecret:   "my-secret",
+				Hostname:               "my-host",
+				MaxFailedRedemptions:   10,
+				FailedRedemptionWindow: time.Minute * 5,
 			},
 		},
 		{
@@ -62,12 +67,14 @@ func Test_BoundGrantConfig(t *testing.T) {
 				"--grant-server-podname=a-different-host",
 			},
 			expectedValue: &GrantConfig{
-				Enabled:              false,
-				AutoConfigure:        false,
-				BaseUrl:              "https://anotherhost:8080/blah",
-				Port:                 9876,
-				TlsCredentialsSecret: "a-different-secret",
-				Hostname:             "a-different-host",
+				Enabled:                false,
+				AutoConfigure:          false,
+				BaseUrl:                "https://anotherhost:8080/blah",
+				Port:                   9876,
+				TlsCredentialsSecret:   "a-different-secret",
+				Hostname:               "a-different-host",
+				MaxFailedRedemptions:   10,
+				FailedRedemptionWindow: time.Minute * 5,
 			},
 		},
 		{
@@ -81,12 +88,30 @@ func Test_BoundGrantConfig(t *testing.T) {
 				"--grant-server-podname=my-host",
 			},
 			expectedValue: &GrantConfig{
-				Enabled:              true,
-				AutoConfigure:        true,
-				BaseUrl:              "https://acme.org:8888/grants",
-				Port:                 1234,
-				TlsCredentialsSecret: "my-secret",
-				Hostname:             "my-host",
+				Enabled:                true,
+				AutoConfigure:          true,
+				BaseUrl:                "https://acme.org:8888/grants",
+				Port:                   1234,
+				TlsCredentialsSecret:   "my-secret",
+				Hostname:               "my-host",
+				MaxFailedRedemptions:   10,
+				FailedRedemptionWindow: time.Minute * 5,
+			},
+		},
+		{
+			name: "rate limiting",
+			env: map[string]string{
+				"SKUPPER_GRANT_SERVER_MAX_FAILED_REDEMPTIONS": "3",
+			},
+			args: []string{
+				"--grant-server-failed-redemption-window=30s",
+			},
+			expectedValue: &GrantConfig{
+				Port:                   9090,
+				TlsCredentialsSecret:   "skupper-grant-server",
+				Hostname:               os.Getenv("HOSTNAME"),
+				MaxFailedRedemptions:   3,
+				FailedRedemptionWindow: time.Second * 30,
 			},
 		},
 		{
@@ -105,12 +130,14 @@ func Test_BoundGrantConfig(t *testing.T) {
 				"this is not a bool",
 			},
 			expectedValue: &GrantConfig{
-				Enabled:              false,
-				AutoConfigure:        true,
-				BaseUrl:              "https://acme.org:8888/grants",
-				Port:                 1234,
-				TlsCredentialsSecret: "my-secret",
-				Hostname:             "my-host",
+				Enabled:                false,
+				AutoConfigure:          true,
+				BaseUrl:                "https://acme.org:8888/grants",
+				Port:                   1234,
+				TlsCredentialsSecret:   "my-secret",
+				Hostname:               "my-host",
+				MaxFailedRedemptions:   10,
+				FailedRedemptionWindow: time.Minute * 5,
 			},
 		},
 		{
@@ -129,12 +156,14 @@ func Test_BoundGrantConfig(t *testing.T) {
 				"this is not a bool",
 			},
 			expectedValue: &GrantConfig{
-				Enabled:              true,
-				AutoConfigure:        false,
-				BaseUrl:              "https://acme.org:8888/grants",
-				Port:                 1234,
-				TlsCredentialsSecret: "my-secret",
-				Hostname:             "my-host",
+				Enabled:                true,
+				AutoConfigure:          false,
+				BaseUrl:                "https://acme.org:8888/grants",
+				Port:                   1234,
+				TlsCredentialsSecret:   "my-secret",
+				Hostname:               "my-host",
+				MaxFailedRedemptions:   10,
+				FailedRedemptionWindow: time.Minute * 5,
 			},
 		},
 		{
@@ -153,12 +182,14 @@ func Test_BoundGrantConfig(t *testing.T) {
 				"foo/bar/baz",
 			},
 			expectedValue: &GrantConfig{
-				Enabled:              true,
-				AutoConfigure:        true,
-				BaseUrl:              "https://acme.org:8888/grants",
-				Port:                 9090,
-				TlsCredentialsSecret: "my-secret",
-				Hostname:             "my-host",
+				Enabled:                true,
+				AutoConfigure:          true,
+				BaseUrl:                "https://acme.org:8888/grants",
+				Port:                   9090,
+				TlsCredentialsSecret:   "my-secret",
+				Hostname:               "my-host",
+				MaxFailedRedemptions:   10,
+				FailedRedemptionWindow: time.Minute * 5,
 			},
 		},
 	}
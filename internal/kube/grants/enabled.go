@@ -12,14 +12,17 @@ import (
 
 type NamespaceFilter func(string) bool
 
-func enabled(controller *watchers.EventProcessor, currentNamespace string, watchNamespace string, config *GrantConfig, generator GrantResponse, filter NamespaceFilter) *GrantsEnabled {
+func enabled(controller *watchers.EventProcessor, currentNamespace string, watchNamespace string, config *GrantConfig, generator GrantResponse, revoked RevocationHandler, filter NamespaceFilter) *GrantsEnabled {
 	gc := &GrantsEnabled{
 		grants: newGrants(controller, generator, config.scheme(), config.BaseUrl),
 	}
+	gc.grants.revoked = revoked
+	gc.grants.limiter = newRateLimiter(config.MaxFailedRedemptions, config.FailedRedemptionWindow)
 	gc.server = newServer(config.addr(), config.tlsEnabled(), gc.grants)
 
 	gc.grantWatcher = controller.WatchAccessGrants(watchNamespace, watchers.FilterByNamespace(filter, gc.grants.checkGrant))
 	gc.secretWatcher = controller.WatchSecrets(watchers.ByName(config.TlsCredentialsSecret), watchNamespace, watchers.FilterByNamespace(filter, gc.tlsCredentialsUpdated))
+	gc.policyWatcher = controller.WatchAccessPolicies(watchNamespace, watchers.FilterByNamespace(filter, gc.grants.policyChanged))
 
 	if config.AutoConfigure {
 		ac, err := newAutoConfigure(gc.securedAccessChanged, controller, currentNamespace, config)
@@ -36,12 +39,14 @@ type GrantsEnabled struct {
 	server        *Server
 	grantWatcher  *watchers.AccessGrantWatcher
 	secretWatcher *watchers.SecretWatcher
+	policyWatcher *watchers.AccessPolicyWatcher
 	autoConfigure *AutoConfigure
 	started       bool
 	filter        NamespaceFilter
 }
 
 func (c *GrantsEnabled) Start() {
+	c.recoverPolicies()
 	c.recoverGrants()
 	c.recoverSecrets()
 	if c.autoConfigure == nil {
@@ -58,6 +63,15 @@ func (c *GrantsEnabled) recoverGrants() {
 	}
 }
 
+func (c *GrantsEnabled) recoverPolicies() {
+	for _, policy := range c.policyWatcher.List() {
+		if c.filter != nil && !c.filter(policy.Namespace) {
+			continue
+		}
+		c.grants.updatePolicy(policy.Namespace, policy.Name, policy)
+	}
+}
+
 func (c *GrantsEnabled) recoverSecrets() {
 	for _, secret := range c.secretWatcher.List() {
 		if c.filter != nil && !c.filter(secret.Namespace) {
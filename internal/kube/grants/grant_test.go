@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -19,15 +20,18 @@ import (
 	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
 )
 
-func dummyGenerator(namespace string, name string, subject string, writer io.Writer) error {
+func dummyGenerator(namespace string, name string, subject string, writer io.Writer) (*v2alpha1.IssuedCertificate, error) {
 	io.WriteString(writer, namespace+",")
 	io.WriteString(writer, name+",")
 	io.WriteString(writer, subject)
-	return nil
+	return &v2alpha1.IssuedCertificate{
+		Name:    name,
+		Subject: subject,
+	}, nil
 }
 
-func dummyGeneratorWithError(namespace string, name string, subject string, writer io.Writer) error {
-	return errors.New("Failed")
+func dummyGeneratorWithError(namespace string, name string, subject string, writer io.Writer) (*v2alpha1.IssuedCertificate, error) {
+	return nil, errors.New("Failed")
 }
 
 func TestGrantRegistryGeneral(t *testing.T) {
@@ -517,3 +521,361 @@ func Test_checkGrant(t *testing.T) {
 		})
 	}
 }
+
+func TestGrantRevocation(t *testing.T) {
+	grant := &v2alpha1.AccessGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-grant",
+			Namespace: "test",
+			UID:       "9c0a8b6e-3a8f-4ad3-8d55-7d3c5e9ef7a1",
+		},
+		Spec: v2alpha1.AccessGrantSpec{
+			Code:               "supersecret",
+			RedemptionsAllowed: 3,
+		},
+	}
+	client, err := fake.NewFakeClient("test", nil, []runtime.Object{grant}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var revoked []v2alpha1.RevokedSite
+	registry := newGrants(client, dummyGenerator, "https", "")
+	registry.revoked = func(namespace string, name string, sites []v2alpha1.RevokedSite) error {
+		assert.Equal(t, namespace, "test")
+		assert.Equal(t, name, "my-grant")
+		revoked = sites
+		return nil
+	}
+	key := grant.Namespace + "/" + grant.Name
+	latest := func() *v2alpha1.AccessGrant {
+		current, err := client.GetSkupperClient().SkupperV2alpha1().AccessGrants(grant.Namespace).Get(context.TODO(), grant.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return current
+	}
+	redeem := func(subject string) int {
+		req := httptest.NewRequest(http.MethodPost, "/"+string(grant.ObjectMeta.UID), bytes.NewBufferString("supersecret"))
+		req.Header.Set("name", "my-link")
+		req.Header.Set("subject", subject)
+		res := httptest.NewRecorder()
+		registry.ServeHTTP(res, req)
+		return res.Code
+	}
+	assert.NilError(t, registry.checkGrant(key, grant))
+
+	assert.Equal(t, redeem("site-a"), http.StatusOK)
+	assert.Equal(t, redeem("site-b"), http.StatusOK)
+	current := latest()
+	assert.Equal(t, len(current.Status.Issued), 2)
+	assert.Equal(t, current.Status.Issued[0].Subject, "site-a")
+	assert.Equal(t, current.Status.Issued[1].Subject, "site-b")
+	assert.Assert(t, !current.Status.Issued[0].IsRevoked())
+	assert.Equal(t, len(revoked), 0)
+
+	current.Spec.Revoked = true
+	assert.NilError(t, registry.checkGrant(key, current))
+	current = latest()
+	assert.Assert(t, current.Status.Issued[0].IsRevoked())
+	assert.Assert(t, current.Status.Issued[1].IsRevoked())
+	assert.Equal(t, len(revoked), 2)
+	assert.Equal(t, revoked[0].Id, "site-a")
+	assert.Equal(t, revoked[0].AccessGrant, "my-grant")
+	assert.Equal(t, revoked[1].Id, "site-b")
+	assert.Equal(t, redeem("site-c"), http.StatusNotFound)
+
+	current.Spec.Revoked = false
+	assert.NilError(t, registry.checkGrant(key, current))
+	current = latest()
+	assert.Assert(t, !current.Status.Issued[0].IsRevoked())
+	assert.Equal(t, len(revoked), 0)
+	assert.Equal(t, redeem("site-c"), http.StatusOK)
+	assert.Equal(t, len(latest().Status.Issued), 3)
+}
+
+func TestGrantRemoteSitePolicy(t *testing.T) {
+	grant := &v2alpha1.AccessGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-grant",
+			Namespace: "test",
+			UID:       "5d7e1f2a-6b3c-4e8d-9f0a-1b2c3d4e5f60",
+		},
+		Spec: v2alpha1.AccessGrantSpec{
+			Code:               "supersecret",
+			RedemptionsAllowed: 3,
+		},
+	}
+	policy := &v2alpha1.AccessPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "trusted-sites",
+			Namespace: "test",
+		},
+		Spec: v2alpha1.AccessPolicySpec{
+			AllowedRemoteSites: []string{"trusted-*"},
+		},
+	}
+	client, err := fake.NewFakeClient("test", nil, []runtime.Object{grant}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var revoked []v2alpha1.RevokedSite
+	registry := newGrants(client, dummyGenerator, "https", "")
+	registry.revoked = func(namespace string, name string, sites []v2alpha1.RevokedSite) error {
+		revoked = sites
+		return nil
+	}
+	key := grant.Namespace + "/" + grant.Name
+	latest := func() *v2alpha1.AccessGrant {
+		current, err := client.GetSkupperClient().SkupperV2alpha1().AccessGrants(grant.Namespace).Get(context.TODO(), grant.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return current
+	}
+	redeem := func(subject string) int {
+		req := httptest.NewRequest(http.MethodPost, "/"+string(grant.ObjectMeta.UID), bytes.NewBufferString("supersecret"))
+		req.Header.Set("name", "my-link")
+		req.Header.Set("subject", subject)
+		res := httptest.NewRecorder()
+		registry.ServeHTTP(res, req)
+		return res.Code
+	}
+	assert.NilError(t, registry.policyChanged("test/trusted-sites", policy))
+	assert.NilError(t, registry.checkGrant(key, grant))
+
+	// an incoming link from a site that is not permitted is refused
+	// before any certificate is issued for it
+	assert.Equal(t, redeem("untrusted-1"), http.StatusForbidden)
+	current := latest()
+	assert.Equal(t, len(current.Status.Issued), 0)
+	assert.Equal(t, current.Status.Redemptions, 0)
+
+	// policies in other namespaces do not apply
+	assert.NilError(t, registry.policyChanged("other/deny-all", &v2alpha1.AccessPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "deny-all", Namespace: "other"},
+		Spec:       v2alpha1.AccessPolicySpec{AllowedRemoteSites: []string{"none"}},
+	}))
+	assert.Equal(t, redeem("trusted-1"), http.StatusOK)
+	current = latest()
+	assert.Equal(t, len(current.Status.Issued), 1)
+	assert.Equal(t, current.Status.Issued[0].Subject, "trusted-1")
+	assert.Assert(t, !current.Status.Issued[0].IsRevoked())
+
+	// a certificate already issued is revoked if the policy changes
+	// to no longer permit the site it was issued to
+	policy = policy.DeepCopy()
+	policy.Spec.AllowedRemoteSites = []string{"trusted-2"}
+	assert.NilError(t, registry.policyChanged("test/trusted-sites", policy))
+	current = latest()
+	assert.Assert(t, current.Status.Issued[0].IsRevoked())
+	assert.Equal(t, len(revoked), 1)
+	assert.Equal(t, revoked[0].Id, "trusted-1")
+	assert.Equal(t, revoked[0].AccessGrant, "my-grant")
+
+	// and reinstated once the policy is removed
+	assert.NilError(t, registry.policyChanged("test/trusted-sites", nil))
+	current = latest()
+	assert.Assert(t, !current.Status.Issued[0].IsRevoked())
+	assert.Equal(t, len(revoked), 0)
+	assert.Equal(t, redeem("untrusted-1"), http.StatusOK)
+}
+
+func TestGrantRedemptionConstraints(t *testing.T) {
+	expiration := time.Now().Add(time.Hour).Format(time.RFC3339)
+	tests := []struct {
+		name           string
+		spec           v2alpha1.AccessGrantSpec
+		remoteAddr     string
+		siteName       string
+		expectedCode   int
+		expectedReason string
+	}{
+		{
+			name: "allowed",
+			spec: v2alpha1.AccessGrantSpec{
+				AllowedSourceRanges: []string{"10.1.0.0/16", "192.168.0.0/24"},
+				SiteNamePattern:     "east-*",
+				NotBefore:           time.Now().Add(-time.Hour).Format(time.RFC3339),
+			},
+			remoteAddr:   "192.168.0.17:43210",
+			siteName:     "east-1",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "source not allowed",
+			spec: v2alpha1.AccessGrantSpec{
+				AllowedSourceRanges: []string{"10.1.0.0/16"},
+			},
+			remoteAddr:     "192.168.0.17:43210",
+			expectedCode:   http.StatusForbidden,
+			expectedReason: "Source address 192.168.0.17 is not allowed",
+		},
+		{
+			name: "site name does not match",
+			spec: v2alpha1.AccessGrantSpec{
+				SiteNamePattern: "east-*",
+			},
+			remoteAddr:     "192.168.0.17:43210",
+			siteName:       "west-1",
+			expectedCode:   http.StatusForbidden,
+			expectedReason: "Site name \"west-1\" does not match \"east-*\"",
+		},
+		{
+			name: "not yet valid",
+			spec: v2alpha1.AccessGrantSpec{
+				NotBefore: time.Now().Add(time.Hour).Format(time.RFC3339),
+			},
+			remoteAddr:     "192.168.0.17:43210",
+			expectedCode:   http.StatusForbidden,
+			expectedReason: "Grant is not yet valid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grant := &v2alpha1.AccessGrant{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "constrained",
+					Namespace: "test",
+					UID:       "2b5e8e9a-0c1d-4f7e-9a51-3c8f1d2e6b40",
+				},
+				Spec: tt.spec,
+				Status: v2alpha1.AccessGrantStatus{
+					Code:           "supersecret",
+					ExpirationTime: expiration,
+				},
+			}
+			grant.Spec.RedemptionsAllowed = 1
+			client, err := fake.NewFakeClient("test", nil, []runtime.Object{grant}, "")
+			assert.Assert(t, err)
+			registry := newGrants(client, dummyGenerator, "https", "")
+			assert.NilError(t, registry.checkGrant("test/constrained", grant))
+
+			req := httptest.NewRequest(http.MethodPost, "/"+string(grant.ObjectMeta.UID), bytes.NewBufferString("supersecret"))
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("name", "my-link")
+			req.Header.Set("site-name", tt.siteName)
+			res := httptest.NewRecorder()
+			registry.ServeHTTP(res, req)
+			assert.Equal(t, res.Code, tt.expectedCode)
+
+			latest, err := client.GetSkupperClient().SkupperV2alpha1().AccessGrants("test").Get(context.TODO(), "constrained", metav1.GetOptions{})
+			assert.Assert(t, err)
+			assert.Equal(t, len(latest.Status.RedemptionAttempts), 1)
+			attempt := latest.Status.RedemptionAttempts[0]
+			assert.Equal(t, attempt.Peer, "192.168.0.17")
+			assert.Equal(t, attempt.SiteName, tt.siteName)
+			assert.Equal(t, attempt.Link, "my-link")
+			assert.Equal(t, attempt.Succeeded, tt.expectedReason == "")
+			assert.Equal(t, attempt.Reason, tt.expectedReason)
+
+			events, err := client.GetKubeClient().CoreV1().Events("test").List(context.TODO(), metav1.ListOptions{})
+			assert.Assert(t, err)
+			assert.Equal(t, len(events.Items), 1)
+			if tt.expectedReason == "" {
+				assert.Equal(t, events.Items[0].Reason, "RedemptionSucceeded")
+				assert.Equal(t, latest.Status.Redemptions, 1)
+			} else {
+				assert.Equal(t, events.Items[0].Reason, "RedemptionFailed")
+				assert.Equal(t, latest.Status.Redemptions, 0)
+			}
+		})
+	}
+}
+
+func TestGrantInvalidConstraints(t *testing.T) {
+	grant := &v2alpha1.AccessGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "invalid",
+			Namespace: "test",
+			UID:       "5f3c2a1e-7d6b-4e8f-a9c0-1b2d3e4f5a60",
+		},
+		Spec: v2alpha1.AccessGrantSpec{
+			AllowedSourceRanges: []string{"not-a-cidr"},
+			SiteNamePattern:     "[",
+			NotBefore:           "tomorrow",
+		},
+	}
+	client, err := fake.NewFakeClient("test", nil, []runtime.Object{grant}, "")
+	assert.Assert(t, err)
+	registry := newGrants(client, dummyGenerator, "https", "")
+	assert.NilError(t, registry.checkGrant("test/invalid", grant))
+	latest, err := client.GetSkupperClient().SkupperV2alpha1().AccessGrants("test").Get(context.TODO(), "invalid", metav1.GetOptions{})
+	assert.Assert(t, err)
+	assert.Assert(t, !meta.IsStatusConditionTrue(latest.Status.Conditions, v2alpha1.CONDITION_TYPE_PROCESSED))
+	assert.Assert(t, strings.Contains(latest.Status.Message, "Invalid source range \"not-a-cidr\""), latest.Status.Message)
+	assert.Assert(t, strings.Contains(latest.Status.Message, "Invalid siteNamePattern \"[\""), latest.Status.Message)
+	assert.Assert(t, strings.Contains(latest.Status.Message, "Invalid notBefore \"tomorrow\""), latest.Status.Message)
+}
+
+func TestGrantRedemptionRateLimited(t *testing.T) {
+	grant := &v2alpha1.AccessGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "limited",
+			Namespace: "test",
+			UID:       "8d7c6b5a-4e3f-4a2b-9c1d-0e9f8a7b6c50",
+		},
+		Spec: v2alpha1.AccessGrantSpec{
+			RedemptionsAllowed: 1,
+		},
+		Status: v2alpha1.AccessGrantStatus{
+			Code:           "supersecret",
+			ExpirationTime: time.Now().Add(time.Hour).Format(time.RFC3339),
+		},
+	}
+	client, err := fake.NewFakeClient("test", nil, []runtime.Object{grant}, "")
+	assert.Assert(t, err)
+	registry := newGrants(client, dummyGenerator, "https", "")
+	registry.limiter = newRateLimiter(2, time.Minute)
+	assert.NilError(t, registry.checkGrant("test/limited", grant))
+	redeem := func(code string, remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodPost, "/"+string(grant.ObjectMeta.UID), bytes.NewBufferString(code))
+		req.RemoteAddr = remoteAddr
+		res := httptest.NewRecorder()
+		registry.ServeHTTP(res, req)
+		return res.Code
+	}
+	assert.Equal(t, redeem("guess1", "10.0.0.1:1000"), http.StatusForbidden)
+	assert.Equal(t, redeem("guess2", "10.0.0.1:1001"), http.StatusForbidden)
+	assert.Equal(t, redeem("supersecret", "10.0.0.1:1002"), http.StatusTooManyRequests)
+	assert.Equal(t, redeem("supersecret", "10.0.0.2:1000"), http.StatusOK)
+}
+
+func TestGrantIssuedExpiredPruned(t *testing.T) {
+	now := time.Now()
+	grant := &v2alpha1.AccessGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-grant",
+			Namespace: "test",
+			UID:       "5f4e3d2c-1b0a-4c9d-8e7f-6a5b4c3d2e10",
+		},
+		Spec: v2alpha1.AccessGrantSpec{
+			RedemptionsAllowed: 5,
+		},
+		Status: v2alpha1.AccessGrantStatus{
+			Code:           "supersecret",
+			ExpirationTime: now.Add(time.Hour).Format(time.RFC3339),
+			Issued: []v2alpha1.IssuedCertificate{
+				{Subject: "expired", Expiration: now.Add(-time.Hour).UTC().Format(time.RFC3339), Revoked: now.UTC().Format(time.RFC3339)},
+				{Subject: "unknown"},
+				{Subject: "current", Expiration: now.Add(time.Hour).UTC().Format(time.RFC3339)},
+			},
+		},
+	}
+	client, err := fake.NewFakeClient("test", nil, []runtime.Object{grant}, "")
+	assert.Assert(t, err)
+	registry := newGrants(client, dummyGenerator, "https", "")
+	assert.NilError(t, registry.checkGrant("test/my-grant", grant))
+	req := httptest.NewRequest(http.MethodPost, "/"+string(grant.ObjectMeta.UID), bytes.NewBufferString("supersecret"))
+	req.Header.Set("subject", "new")
+	res := httptest.NewRecorder()
+	registry.ServeHTTP(res, req)
+	assert.Equal(t, res.Code, http.StatusOK)
+
+	current, err := client.GetSkupperClient().SkupperV2alpha1().AccessGrants("test").Get(context.TODO(), "my-grant", metav1.GetOptions{})
+	assert.NilError(t, err)
+	var subjects []string
+	for _, issued := range current.Status.Issued {
+		subjects = append(subjects, issued.Subject)
+	}
+	assert.DeepEqual(t, subjects, []string{"unknown", "current", "new"})
+}
@@ -2,32 +2,52 @@ package grants
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
+	"path"
 	"strings"
 	"sync"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubetypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
 
 	internalclient "github.com/skupperproject/skupper/internal/kube/client"
+	"github.com/skupperproject/skupper/internal/site"
 	"github.com/skupperproject/skupper/internal/utils"
 	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
 )
 
-type GrantResponse func(namespace string, name string, subject string, writer io.Writer) error
+// The number of redemption attempts recorded in the status of an
+// AccessGrant; older attempts are still recorded as Events.
+const maxRedemptionAttempts = 10
+
+// GrantResponse writes the link configuration for a redeemed grant,
+// returning a record of the certificate that was issued.
+type GrantResponse func(namespace string, name string, subject string, writer io.Writer) (*skupperv2alpha1.IssuedCertificate, error)
+
+// RevocationHandler is invoked whenever the set of revoked sites for
+// a grant is determined, i.e. those sites whose certificates, issued
+// on redeeming that grant, have been revoked.
+type RevocationHandler func(namespace string, grant string, revoked []skupperv2alpha1.RevokedSite) error
 
 type Grants struct {
 	clients    internalclient.Clients
 	generator  GrantResponse
+	revoked    RevocationHandler
 	url        string
 	ca         string
 	scheme     string
+	limiter    *rateLimiter
 	grants     map[kubetypes.UID]*skupperv2alpha1.AccessGrant
 	grantIndex map[string]kubetypes.UID
+	policies   map[string]*site.AccessPolicies
 	lock       sync.Mutex
 }
 
@@ -39,6 +59,7 @@ func newGrants(clients internalclient.Clients, generator GrantResponse, scheme s
 		url:        url,
 		grants:     map[kubetypes.UID]*skupperv2alpha1.AccessGrant{},
 		grantIndex: map[string]kubetypes.UID{},
+		policies:   map[string]*site.AccessPolicies{},
 	}
 }
 
@@ -177,7 +198,10 @@ func (g *Grants) checkGrant(key string, grant *skupperv2alpha1.AccessGrant) erro
 	}
 	g.record(key, grant)
 	changed := false
-	var status []string
+	status := validateConstraints(grant)
+	if g.checkRevoked(key, grant) {
+		changed = true
+	}
 	if g.checkUrl(key, grant) {
 		changed = true
 	}
@@ -222,10 +246,104 @@ func (g *Grants) checkGrant(key string, grant *skupperv2alpha1.AccessGrant) erro
 		changed = true
 	}
 
-	if !changed {
+	if changed {
+		if err := g.updateGrantStatus(grant); err != nil {
+			return err
+		}
+	}
+	return g.notifyRevoked(grant)
+}
+
+// Revokes each certificate issued for the grant if the grant has
+// been revoked or if the AccessPolicies in its namespace do not allow
+// links with the site the certificate was issued to, and reinstates
+// it otherwise. Returns true if any certificate changed.
+func (g *Grants) checkRevoked(key string, grant *skupperv2alpha1.AccessGrant) bool {
+	now := time.Now()
+	changed := false
+	for i := range grant.Status.Issued {
+		issued := &grant.Status.Issued[i]
+		reason := ""
+		if grant.Spec.Revoked {
+			reason = "AccessGrant revoked"
+		} else if err := g.remoteSitePermitted(grant.Namespace, issued.Subject); err != nil {
+			reason = err.Error()
+		}
+		if reason != "" {
+			if issued.Revoke(now) {
+				log.Printf("Revoked certificate issued to %s for AccessGrant %s: %s", issued.Subject, key, reason)
+				changed = true
+			}
+		} else if issued.Reinstate() {
+			log.Printf("Reinstated certificate issued to %s for AccessGrant %s", issued.Subject, key)
+			changed = true
+		}
+	}
+	return changed
+}
+
+func (g *Grants) remoteSitePermitted(namespace string, id string) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return g.policies[namespace].RemoteSitePermitted(id)
+}
+
+func (g *Grants) updatePolicy(namespace string, name string, policy *skupperv2alpha1.AccessPolicy) bool {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	policies, ok := g.policies[namespace]
+	if !ok {
+		policies = site.NewAccessPolicies()
+		g.policies[namespace] = policies
+	}
+	return policies.Update(name, policy)
+}
+
+// Records a change to an AccessPolicy and, if that changes which
+// remote sites are permitted, rechecks the certificates issued for
+// the grants in the same namespace.
+func (g *Grants) policyChanged(key string, policy *skupperv2alpha1.AccessPolicy) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	if !g.updatePolicy(namespace, name, policy) {
 		return nil
 	}
-	return g.updateGrantStatus(grant)
+	var errs []error
+	for _, grant := range g.getAll() {
+		if grant.Namespace != namespace {
+			continue
+		}
+		if g.checkRevoked(fmt.Sprintf("%s/%s", grant.Namespace, grant.Name), grant) {
+			if err := g.updateGrantStatus(grant); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			errs = append(errs, g.notifyRevoked(grant))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (g *Grants) notifyRevoked(grant *skupperv2alpha1.AccessGrant) error {
+	if g.revoked == nil {
+		return nil
+	}
+	var revoked []skupperv2alpha1.RevokedSite
+	for _, issued := range grant.Status.Issued {
+		if !issued.IsRevoked() {
+			continue
+		}
+		revoked = append(revoked, skupperv2alpha1.RevokedSite{
+			Id:           issued.Subject,
+			AccessGrant:  grant.Name,
+			SerialNumber: issued.SerialNumber,
+			Revoked:      issued.Revoked,
+			Expiration:   issued.Expiration,
+		})
+	}
+	return g.revoked(grant.Namespace, grant.Name, revoked)
 }
 
 func (g *Grants) updateGrantStatus(grant *skupperv2alpha1.AccessGrant) error {
@@ -237,44 +355,254 @@ func (g *Grants) updateGrantStatus(grant *skupperv2alpha1.AccessGrant) error {
 	return nil
 }
 
-func (g *Grants) checkAndUpdateAccessToken(key string, data []byte) (*skupperv2alpha1.AccessGrant, *HttpError) {
+// The details of a request to redeem an access token, recorded for
+// auditing.
+type redemption struct {
+	peer     string
+	siteName string
+	link     string
+	subject  string
+}
+
+func newRedemption(r *http.Request) *redemption {
+	return &redemption{
+		peer:     peerAddress(r),
+		siteName: r.Header.Get("site-name"),
+		link:     r.Header.Get("name"),
+		subject:  r.Header.Get("subject"),
+	}
+}
+
+// Returns the subject for the certificate issued on redeeming the
+// grant, i.e. the id of the site that will link with this one. The id
+// is asserted by the client and is not verified.
+func (r *redemption) certificateSubject(grant *skupperv2alpha1.AccessGrant) string {
+	if r.subject != "" {
+		return r.subject
+	}
+	if r.link != "" {
+		return r.link
+	}
+	return grant.Name
+}
+
+func peerAddress(r *http.Request) string {
+	// Forwarding headers are not trusted, as they can be set by the
+	// client; the address is that of the immediate peer.
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// Returns the audit record for the request; an empty reason
+// indicates that it succeeded.
+func (r *redemption) attempt(now time.Time, reason string) skupperv2alpha1.RedemptionAttempt {
+	return skupperv2alpha1.RedemptionAttempt{
+		Time:      now.UTC().Format(time.RFC3339),
+		Peer:      r.peer,
+		SiteName:  r.siteName,
+		Link:      r.link,
+		Succeeded: reason == "",
+		Reason:    reason,
+	}
+}
+
+func (g *Grants) checkAndUpdateAccessToken(key string, data []byte, request *redemption) (*skupperv2alpha1.AccessGrant, *HttpError) {
 	log.Printf("Checking access token for %s", key)
 	grant := g.get(key)
 	if grant == nil {
-		return nil, httpError("No such claim", http.StatusNotFound)
+		return nil, refused("No such grant", "No such claim", http.StatusNotFound)
 	}
 
 	expiration, err := time.Parse(time.RFC3339, grant.Status.ExpirationTime)
 	if err != nil {
 		log.Printf("Cannot determine expiration for %s/%s: %s", grant.Namespace, grant.Name, err)
-		return nil, httpError("Corrupted claim", http.StatusInternalServerError)
+		return grant, refused("Cannot determine expiration", "Corrupted claim", http.StatusInternalServerError)
 	}
 	if expiration.Before(time.Now()) {
 		log.Printf("AccessGrant %s/%s expired", grant.Namespace, grant.Name)
-		return nil, httpError("No such claim", http.StatusNotFound)
+		return grant, refused("Grant has expired", "No such claim", http.StatusNotFound)
+	}
+	if grant.Spec.Revoked {
+		log.Printf("AccessGrant %s/%s has been revoked", grant.Namespace, grant.Name)
+		return grant, refused("Grant has been revoked", "No such access granted", http.StatusNotFound)
 	}
 	if grant.Spec.RedemptionsAllowed <= grant.Status.Redemptions {
 		log.Printf("AccessGrant %s/%s already redeemed", grant.Namespace, grant.Name)
-		return nil, httpError("No such access granted", http.StatusNotFound)
+		return grant, refused("Grant has already been redeemed", "No such access granted", http.StatusNotFound)
 	}
 	if grant.Status.Code != string(data) {
-		return nil, httpError("Redemption of access token refused", http.StatusForbidden)
+		return grant, refused("Incorrect code", "Redemption of access token refused", http.StatusForbidden)
+	}
+	if reason := checkConstraints(grant, request, time.Now()); reason != "" {
+		log.Printf("Redemption of AccessGrant %s/%s from %s refused: %s", grant.Namespace, grant.Name, request.peer, reason)
+		return grant, refused(reason, "Redemption of access token refused", http.StatusForbidden)
+	}
+	if err := g.remoteSitePermitted(grant.Namespace, request.certificateSubject(grant)); err != nil {
+		log.Printf("Redemption of AccessGrant %s/%s from %s refused: %s", grant.Namespace, grant.Name, request.peer, err)
+		return grant, refused(err.Error(), "Redemption of access token refused", http.StatusForbidden)
 	}
 	grant.Status.Redemptions += 1
 	err = g.updateGrantStatus(grant)
 	if err != nil {
 		log.Printf("Error updating access grant %s/%s: %s", grant.Namespace, grant.Name, err)
-		return nil, httpError("Internal error", http.StatusServiceUnavailable)
+		return grant, refused("Error updating grant", "Internal error", http.StatusServiceUnavailable)
 	}
 	return grant, nil
 }
 
+// Returns the reason the request does not satisfy the constraints
+// of the grant, or an empty string if it does. Constraints that
+// cannot be parsed are never satisfied.
+func checkConstraints(grant *skupperv2alpha1.AccessGrant, request *redemption, now time.Time) string {
+	if grant.Spec.NotBefore != "" {
+		notBefore, err := time.Parse(time.RFC3339, grant.Spec.NotBefore)
+		if err != nil {
+			return fmt.Sprintf("Invalid notBefore %q", grant.Spec.NotBefore)
+		}
+		if now.Before(notBefore) {
+			return "Grant is not yet valid"
+		}
+	}
+	if len(grant.Spec.AllowedSourceRanges) > 0 {
+		allowed, err := inSourceRanges(grant.Spec.AllowedSourceRanges, request.peer)
+		if err != nil {
+			return err.Error()
+		}
+		if !allowed {
+			return fmt.Sprintf("Source address %s is not allowed", request.peer)
+		}
+	}
+	if grant.Spec.SiteNamePattern != "" {
+		matched, err := path.Match(grant.Spec.SiteNamePattern, request.siteName)
+		if err != nil {
+			return fmt.Sprintf("Invalid siteNamePattern %q", grant.Spec.SiteNamePattern)
+		}
+		if !matched {
+			return fmt.Sprintf("Site name %q does not match %q", request.siteName, grant.Spec.SiteNamePattern)
+		}
+	}
+	return ""
+}
+
+func inSourceRanges(ranges []string, address string) (bool, error) {
+	ip := net.ParseIP(address)
+	for _, cidr := range ranges {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return false, fmt.Errorf("Invalid source range %q", cidr)
+		}
+		if ip != nil && network.Contains(ip) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ValidateAccessGrant checks that the spec of an AccessGrant is well
+// formed.
+func ValidateAccessGrant(grant *skupperv2alpha1.AccessGrant) error {
+	var invalid []string
+	if grant.Spec.ExpirationWindow != "" {
+		if _, err := time.ParseDuration(grant.Spec.ExpirationWindow); err != nil {
+			invalid = append(invalid, fmt.Sprintf("Invalid duration %q: %s", grant.Spec.ExpirationWindow, err))
+		}
+	}
+	if grant.Spec.RedemptionsAllowed < 0 {
+		invalid = append(invalid, fmt.Sprintf("Invalid redemptionsAllowed %d: must not be negative", grant.Spec.RedemptionsAllowed))
+	}
+	invalid = append(invalid, validateConstraints(grant)...)
+	if len(invalid) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(invalid, ", "))
+}
+
+// Checks that the constraints on redemption are well formed.
+func validateConstraints(grant *skupperv2alpha1.AccessGrant) []string {
+	var invalid []string
+	if grant.Spec.NotBefore != "" {
+		if _, err := time.Parse(time.RFC3339, grant.Spec.NotBefore); err != nil {
+			invalid = append(invalid, fmt.Sprintf("Invalid notBefore %q: %s", grant.Spec.NotBefore, err))
+		}
+	}
+	for _, cidr := range grant.Spec.AllowedSourceRanges {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			invalid = append(invalid, fmt.Sprintf("Invalid source range %q: %s", cidr, err))
+		}
+	}
+	if grant.Spec.SiteNamePattern != "" {
+		if _, err := path.Match(grant.Spec.SiteNamePattern, ""); err != nil {
+			invalid = append(invalid, fmt.Sprintf("Invalid siteNamePattern %q: %s", grant.Spec.SiteNamePattern, err))
+		}
+	}
+	return invalid
+}
+
+// Records the outcome of an attempt to redeem the grant, along with
+// any certificate issued as a result, in the status of the grant and
+// as an Event.
+func (g *Grants) recordRedemption(grant *skupperv2alpha1.AccessGrant, attempt skupperv2alpha1.RedemptionAttempt, issued *skupperv2alpha1.IssuedCertificate) {
+	g.recordEvent(grant, attempt)
+	// the grant may have been updated since redemption was attempted
+	if latest := g.get(string(grant.ObjectMeta.UID)); latest != nil {
+		grant = latest
+	}
+	grant.AddRedemptionAttempt(attempt, maxRedemptionAttempts)
+	if issued != nil {
+		grant.AddIssuedCertificate(*issued, time.Now())
+	}
+	if err := g.updateGrantStatus(grant); err != nil {
+		log.Printf("Error recording redemption attempt for %s/%s from %s: %s", grant.Namespace, grant.Name, attempt.Peer, err)
+	}
+}
+
+func (g *Grants) recordEvent(grant *skupperv2alpha1.AccessGrant, attempt skupperv2alpha1.RedemptionAttempt) {
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", grant.Name, now.UnixNano()),
+			Namespace: grant.Namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:       "AccessGrant",
+			APIVersion: "skupper.io/v2alpha1",
+			Namespace:  grant.Namespace,
+			Name:       grant.Name,
+			UID:        grant.ObjectMeta.UID,
+		},
+		Source:         corev1.EventSource{Component: "skupper-grant-server"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	if attempt.Succeeded {
+		event.Type = corev1.EventTypeNormal
+		event.Reason = "RedemptionSucceeded"
+		event.Message = fmt.Sprintf("Link %q issued to site %q from %s", attempt.Link, attempt.SiteName, attempt.Peer)
+	} else {
+		event.Type = corev1.EventTypeWarning
+		event.Reason = "RedemptionFailed"
+		event.Message = fmt.Sprintf("Redemption by site %q from %s refused: %s", attempt.SiteName, attempt.Peer, attempt.Reason)
+	}
+	if _, err := g.clients.GetKubeClient().CoreV1().Events(grant.Namespace).Create(context.TODO(), event, metav1.CreateOptions{}); err != nil {
+		log.Printf("Error recording %s event for AccessGrant %s/%s: %s", event.Reason, grant.Namespace, grant.Name, err)
+	}
+}
+
 func (g *Grants) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		log.Printf("Bad method %s for path %s", r.Method, r.URL.Path)
 		http.Error(w, "Only POST is supported", http.StatusMethodNotAllowed)
 		return
 	}
+	request := newRedemption(r)
+	if !g.limiter.allow(request.peer, time.Now()) {
+		log.Printf("Too many failed redemption attempts from %s, refusing request for path %s", request.peer, r.URL.Path)
+		http.Error(w, "Too many failed attempts", http.StatusTooManyRequests)
+		return
+	}
 	key := strings.Join(strings.Split(r.URL.Path, "/"), "")
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -283,41 +611,51 @@ func (g *Grants) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	grant, e := g.checkAndUpdateAccessToken(key, body)
+	grant, e := g.checkAndUpdateAccessToken(key, body, request)
 	if e != nil {
+		g.limiter.failed(request.peer, time.Now())
+		if grant != nil {
+			g.recordRedemption(grant, request.attempt(time.Now(), e.reason), nil)
+		}
 		e.write(w)
 		return
 	}
 
-	name := r.Header.Get("name")
+	name := request.link
 	if name == "" {
 		log.Printf("No name specified when redeeming access token for %s/%s, using access grant name", grant.Namespace, grant.Name)
 		name = grant.Name
+		request.link = name
 	}
-	subject := r.Header.Get("subject")
-	if subject == "" {
-		subject = name
-	}
-	if err := g.generator(grant.Namespace, name, subject, w); err != nil {
+	subject := request.certificateSubject(grant)
+	issued, err := g.generator(grant.Namespace, name, subject, w)
+	if err != nil {
 		log.Printf("Failed to create token for %s/%s: %s", grant.Namespace, grant.Name, err.Error())
+		g.recordRedemption(grant, request.attempt(time.Now(), fmt.Sprintf("Failed to create token: %s", err)), nil)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	g.recordRedemption(grant, request.attempt(time.Now(), ""), issued)
 	log.Printf("Redemption of access token %s/%s succeeded", grant.Namespace, grant.Name)
 }
 
 type HttpError struct {
-	text string
-	code int
+	text   string
+	code   int
+	reason string
 }
 
 func (e *HttpError) write(w http.ResponseWriter) {
 	http.Error(w, e.text, e.code)
 }
 
-func httpError(text string, code int) *HttpError {
+// Returns an error for a refused redemption, where the reason
+// recorded for auditing is more specific than the text returned to
+// the client.
+func refused(reason string, text string, code int) *HttpError {
 	return &HttpError{
-		text: text,
-		code: code,
+		text:   text,
+		code:   code,
+		reason: reason,
 	}
 }
@@ -0,0 +1,70 @@
+package grants
+
+import (
+	"sync"
+	"time"
+)
+
+// Limits the rate at which a client can make failed attempts to
+// redeem access tokens, to prevent codes being guessed by brute
+// force. Once a client has made the maximum number of failed attempts
+// within the window, further attempts are refused until the earliest
+// of those failures falls outside the window.
+type rateLimiter struct {
+	max      int
+	window   time.Duration
+	failures map[string][]time.Time
+	lock     sync.Mutex
+}
+
+func newRateLimiter(max int, window time.Duration) *rateLimiter {
+	if max <= 0 || window <= 0 {
+		return nil
+	}
+	return &rateLimiter{
+		max:      max,
+		window:   window,
+		failures: map[string][]time.Time{},
+	}
+}
+
+func (l *rateLimiter) allow(client string, now time.Time) bool {
+	if l == nil {
+		return true
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	return len(l.recent(client, now)) < l.max
+}
+
+func (l *rateLimiter) failed(client string, now time.Time) {
+	if l == nil {
+		return
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for other := range l.failures {
+		if other != client {
+			l.recent(other, now)
+		}
+	}
+	l.failures[client] = append(l.recent(client, now), now)
+}
+
+// Returns the failures for the client within the window, discarding
+// any older ones. Must be called with the lock held.
+func (l *rateLimiter) recent(client string, now time.Time) []time.Time {
+	failures := l.failures[client]
+	cutoff := now.Add(-l.window)
+	i := 0
+	for i < len(failures) && !failures[i].After(cutoff) {
+		i++
+	}
+	failures = failures[i:]
+	if len(failures) == 0 {
+		delete(l.failures, client)
+		return nil
+	}
+	l.failures[client] = failures
+	return failures
+}
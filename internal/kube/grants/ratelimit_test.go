@@ -0,0 +1,31 @@
+package grants
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	start := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(3, time.Minute)
+	for i := 0; i < 3; i++ {
+		assert.Assert(t, limiter.allow("10.0.0.1", start))
+		limiter.failed("10.0.0.1", start.Add(time.Duration(i)*time.Second))
+	}
+	assert.Assert(t, !limiter.allow("10.0.0.1", start.Add(time.Second*10)))
+	assert.Assert(t, limiter.allow("10.0.0.2", start.Add(time.Second*10)), "other clients should not be limited")
+	// the first failure falls outside the window
+	assert.Assert(t, limiter.allow("10.0.0.1", start.Add(time.Minute+time.Millisecond)))
+	// failures outside the window are discarded
+	limiter.failed("10.0.0.2", start.Add(time.Hour))
+	assert.Equal(t, len(limiter.failures), 1)
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	limiter := newRateLimiter(0, time.Minute)
+	assert.Assert(t, limiter == nil)
+	limiter.failed("10.0.0.1", time.Now())
+	assert.Assert(t, limiter.allow("10.0.0.1", time.Now()))
+}
@@ -53,6 +53,7 @@ func postTokenRequest(token *skupperv2alpha1.AccessToken, site *skupperv2alpha1.
 	}
 	request.Header.Add("name", token.Name)
 	request.Header.Add("subject", string(site.ObjectMeta.UID))
+	request.Header.Add("site-name", site.Name)
 	response, err := client.Do(request)
 	if err != nil {
 		return nil, fmt.Errorf("Controller got error: %s", err)
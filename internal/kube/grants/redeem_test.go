@@ -473,13 +473,16 @@ type TestTokenGenerator struct {
 	clients internalclient.Clients
 }
 
-func (g *TestTokenGenerator) generate(namespace string, name string, subject string, writer io.Writer) error {
+func (g *TestTokenGenerator) generate(namespace string, name string, subject string, writer io.Writer) (*v2alpha1.IssuedCertificate, error) {
 	generator, err := NewTokenGenerator(g.site, g.clients)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	token := generator.NewCertToken(name, subject)
-	return token.Write(writer)
+	if err := token.Write(writer); err != nil {
+		return nil, err
+	}
+	return token.Issued()
 }
 
 func newTestTokenGenerator(site *v2alpha1.Site, clients internalclient.Clients) *TestTokenGenerator {
@@ -2,11 +2,14 @@ package grants
 
 import (
 	"context"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -43,6 +46,11 @@ func NewTokenGenerator(site *skupperv2alpha1.Site, clients internalclient.Client
 		namespace: site.Namespace,
 		clients:   clients,
 	}
+	if issuer, ok := skupperv2alpha1.ParseIssuerReference(site.DefaultIssuer()); ok {
+		// the key for an external issuer is not available to sign link certificates
+		log.Printf("Cannot generate link for site %s in %s as its default issuer %s is external", site.Name, site.Namespace, issuer)
+		return nil, errors.New("Cannot issue certificate for link as the site's default issuer is external")
+	}
 	if err := generator.loadCA(site.DefaultIssuer()); err != nil {
 		log.Printf("Error retrieving default issuer %s for site %s in %s: %s", site.DefaultIssuer(), site.Name, site.Namespace, err)
 		return nil, errors.New("Could not get issuer for requested certficate")
@@ -88,7 +96,7 @@ func (g *TokenGenerator) setValidHostsFromSite(site *skupperv2alpha1.Site) bool
 	return true
 }
 
-func (g *TokenGenerator) NewCertToken(name string, subject string) Token {
+func (g *TokenGenerator) NewCertToken(name string, subject string) *CertToken {
 	cert := certs.GenerateSecret(name, subject, strings.Join(g.hosts, ","), 0, g.ca)
 	token := &CertToken{
 		tlsCredentials: &cert,
@@ -116,6 +124,25 @@ func (g *TokenGenerator) NewCertToken(name string, subject string) Token {
 	return token
 }
 
+// Issued returns a record of the certificate included in the token,
+// by which it can later be revoked.
+func (t *CertToken) Issued() (*skupperv2alpha1.IssuedCertificate, error) {
+	block, _ := pem.Decode(t.tlsCredentials.Data["tls.crt"])
+	if block == nil {
+		return nil, errors.New("No certificate found in token")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	return &skupperv2alpha1.IssuedCertificate{
+		Name:         t.tlsCredentials.Name,
+		Subject:      cert.Subject.CommonName,
+		SerialNumber: cert.SerialNumber.Text(16),
+		Expiration:   cert.NotAfter.UTC().Format(time.RFC3339),
+	}, nil
+}
+
 func (t *CertToken) Write(writer io.Writer) error {
 	s := json.NewYAMLSerializer(json.DefaultMetaFactory, scheme.Scheme, scheme.Scheme)
 	writer.Write([]byte("---\n"))
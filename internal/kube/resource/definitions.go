@@ -29,6 +29,14 @@ func TlsRouteResource() schema.GroupVersionResource {
 	}
 }
 
+func CertManagerCertificateResource() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    "cert-manager.io",
+		Version:  "v1",
+		Resource: "certificates",
+	}
+}
+
 func DeploymentResource() schema.GroupVersionResource {
 	return schema.GroupVersionResource{
 		Group:    "apps",
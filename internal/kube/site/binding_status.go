@@ -6,10 +6,13 @@ import (
 	"strings"
 
 	internalclient "github.com/skupperproject/skupper/internal/kube/client"
+	"github.com/skupperproject/skupper/internal/site"
 	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
 )
 
 type BindingStatus struct {
+	siteId     string
+	network    []skupperv2alpha1.SiteRecord
 	connectors map[string][]string
 	listeners  map[string][]string
 	client     internalclient.Clients
@@ -17,8 +20,10 @@ type BindingStatus struct {
 	logger     *slog.Logger
 }
 
-func newBindingStatus(client internalclient.Clients, network []skupperv2alpha1.SiteRecord) *BindingStatus {
+func newBindingStatus(client internalclient.Clients, siteId string, network []skupperv2alpha1.SiteRecord) *BindingStatus {
 	s := &BindingStatus{
+		siteId:     siteId,
+		network:    network,
 		client:     client,
 		connectors: map[string][]string{},
 		listeners:  map[string][]string{},
@@ -49,7 +54,13 @@ func (s *BindingStatus) populate(network []skupperv2alpha1.SiteRecord) {
 }
 
 func (s *BindingStatus) updateMatchingListenerCount(connector *skupperv2alpha1.Connector) *skupperv2alpha1.Connector {
-	if connector.SetHasMatchingListener(len(s.listeners[connector.Spec.RoutingKey]) > 0) {
+	matched := true
+	for _, c := range connector.PortConnectors() {
+		if len(s.listeners[c.Spec.RoutingKey]) == 0 {
+			matched = false
+		}
+	}
+	if connector.SetHasMatchingListener(matched) {
 		updated, err := updateConnectorStatus(s.client, connector)
 		if err != nil {
 			s.logger.Error("Failed to update status for connector",
@@ -64,7 +75,17 @@ func (s *BindingStatus) updateMatchingListenerCount(connector *skupperv2alpha1.C
 }
 
 func (s *BindingStatus) updateMatchingConnectorCount(listener *skupperv2alpha1.Listener) *skupperv2alpha1.Listener {
-	if listener.SetHasMatchingConnector(len(s.connectors[listener.Spec.RoutingKey]) > 0) {
+	matched := true
+	for _, l := range listener.PortListeners() {
+		if len(s.connectors[l.Spec.RoutingKey]) == 0 {
+			matched = false
+		}
+	}
+	changed := listener.SetHasMatchingConnector(matched)
+	if listener.SetActiveConnectors(site.ActiveConnectors(s.siteId, listener, s.network)) {
+		changed = true
+	}
+	if changed {
 		updated, err := updateListenerStatus(s.client, listener)
 		if err != nil {
 			s.logger.Error("Failed to update status for listener",
@@ -73,9 +73,12 @@ func (w *TargetSelectionImpl) Updated(pods []skupperv2alpha1.PodDetails) error {
 	}
 	if len(pods) == 0 {
 		bindings_logger.Debug("No pods available for target selection", w.Attr())
-		return w.site.updateConnectorConfiguredStatus(connector, fmt.Errorf("No matches for selector"))
+		err = fmt.Errorf("No matches for selector")
 	}
-	return w.site.updateConnectorConfiguredStatus(connector, nil)
+	if err := w.site.updateConnectorConfiguredStatus(connector, err); err != nil {
+		return err
+	}
+	return w.site.updateConnectorHealthStatus(w.name)
 }
 
 type PodWatchingContext interface {
@@ -618,3 +618,67 @@ func TestBindingAdaptor_updateBridgeConfigForListener(t *testing.T) {
 		})
 	}
 }
+
+func TestBindingAdaptor_multiPortListener(t *testing.T) {
+	context := NewMockBindingContext(nil)
+	a := NewExtendedBindings(nil, "")
+	a.init(context, &qdr.RouterConfig{})
+	listener := &skupperv2alpha1.Listener{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "broker",
+			Namespace: "test",
+		},
+		Spec: skupperv2alpha1.ListenerSpec{
+			Host:       "broker",
+			RoutingKey: "broker",
+			Ports: []skupperv2alpha1.BindingPort{
+				{Name: "amqp", Port: 5672},
+				{Name: "console", Port: 15672},
+			},
+		},
+	}
+	_, err := a.UpdateListener(listener.Name, listener)
+	assert.Assert(t, err)
+	assert.DeepEqual(t, context.exposed["broker"], &ExposedPortSet{
+		Host: "broker",
+		Ports: map[string]Port{
+			"broker-amqp": {
+				Name:       "broker-amqp",
+				Port:       5672,
+				TargetPort: 1024,
+				Protocol:   "TCP",
+			},
+			"broker-console": {
+				Name:       "broker-console",
+				Port:       15672,
+				TargetPort: 1025,
+				Protocol:   "TCP",
+			},
+		},
+	})
+	config := a.bindings.ToBridgeConfig()
+	assert.Equal(t, config.TcpListeners["broker-amqp"].Address, "broker.amqp")
+	assert.Equal(t, config.TcpListeners["broker-amqp"].Port, "1024")
+	assert.Equal(t, config.TcpListeners["broker-console"].Address, "broker.console")
+
+	updated := listener.DeepCopy()
+	updated.Spec.Ports = updated.Spec.Ports[:1]
+	_, err = a.UpdateListener(updated.Name, updated)
+	assert.Assert(t, err)
+	assert.DeepEqual(t, context.exposed["broker"], &ExposedPortSet{
+		Host: "broker",
+		Ports: map[string]Port{
+			"broker-amqp": {
+				Name:       "broker-amqp",
+				Port:       5672,
+				TargetPort: 1024,
+				Protocol:   "TCP",
+			},
+		},
+	})
+
+	_, err = a.UpdateListener(updated.Name, nil)
+	assert.Assert(t, err)
+	assert.Equal(t, context.unexposedHost, "broker")
+	assert.Assert(t, !a.isHostExposed("broker"))
+}
@@ -3,6 +3,7 @@ package site
 import (
 	"errors"
 	"log/slog"
+	"slices"
 
 	"github.com/skupperproject/skupper/internal/kube/watchers"
 	"github.com/skupperproject/skupper/internal/qdr"
@@ -18,7 +19,10 @@ type ExtendedBindings struct {
 	bindings           *site.Bindings
 	connectors         map[string]*AttachedConnector
 	perTargetListeners map[string]*PerTargetListener
-	listenerHosts      map[string]string // listener name -> host
+	listenerHosts      map[string]string   // listener name -> host
+	listenerPorts      map[string][]string // listener name -> names of exposed ports
+	healthCheckers     map[string]*HealthChecker
+	healthProbe        HealthProbe
 	controller         *watchers.EventProcessor
 	site               *Site
 	logger             *slog.Logger
@@ -30,6 +34,9 @@ func NewExtendedBindings(controller *watchers.EventProcessor, profilePath string
 		connectors:         map[string]*AttachedConnector{},
 		perTargetListeners: map[string]*PerTargetListener{},
 		listenerHosts:      map[string]string{},
+		listenerPorts:      map[string][]string{},
+		healthCheckers:     map[string]*HealthChecker{},
+		healthProbe:        probeTarget,
 		controller:         controller,
 		logger: slog.New(slog.Default().Handler()).With(
 			slog.String("component", "kube.site.attached_connector"),
@@ -55,9 +62,14 @@ func (a *ExtendedBindings) cleanup() {
 	for _, s := range a.selectors {
 		s.Close()
 	}
+	for name, checker := range a.healthCheckers {
+		checker.Close()
+		delete(a.healthCheckers, name)
+	}
 }
 
 func (a *ExtendedBindings) ConnectorUpdated(connector *skupperv2alpha1.Connector) bool {
+	a.updateHealthChecker(connector)
 	if selector, ok := a.selectors[connector.Name]; ok {
 		if selector.Selector() == connector.Spec.Selector {
 			// don't need to change the pod watcher, but may need to reconfigure for other change to spec
@@ -87,43 +99,118 @@ func (a *ExtendedBindings) ConnectorDeleted(connector *skupperv2alpha1.Connector
 		current.Close()
 		delete(a.selectors, connector.Name)
 	}
+	if current, ok := a.healthCheckers[connector.Name]; ok {
+		current.Close()
+		delete(a.healthCheckers, connector.Name)
+	}
+}
+
+// updateHealthChecker starts, restarts or stops health checking for a
+// connector as required by its spec. Changes in the health of targets
+// are handled on the event processing goroutine.
+func (a *ExtendedBindings) updateHealthChecker(connector *skupperv2alpha1.Connector) {
+	current, ok := a.healthCheckers[connector.Name]
+	port := connector.PortConnectors()[0].Spec.Port
+	if ok && current.matches(connector.Spec.HealthCheck, port) {
+		return
+	}
+	if ok {
+		current.Close()
+		delete(a.healthCheckers, connector.Name)
+	}
+	if connector.Spec.HealthCheck == nil || a.controller == nil {
+		return
+	}
+	name := connector.Name
+	checker := newHealthChecker(*connector.Spec.HealthCheck, port, a.healthProbe, func() {
+		a.controller.CallbackAfter(0, a.healthChanged, name)
+	})
+	a.healthCheckers[name] = checker
+	checker.start()
+}
+
+func (a *ExtendedBindings) healthChanged(name string) error {
+	if a.site == nil {
+		return nil
+	}
+	return a.site.connectorHealthChanged(name)
+}
+
+// updateHealthStatus sets the Healthy condition of a connector and the
+// health of each of its selected pods, returning true if the status
+// changed
+func (a *ExtendedBindings) updateHealthStatus(connector *skupperv2alpha1.Connector) bool {
+	checker, ok := a.healthCheckers[connector.Name]
+	if !ok {
+		changed := connector.ClearHealthy()
+		if connector.SetSelectedPods(nil) {
+			changed = true
+		}
+		return changed
+	}
+	changed := connector.SetHealthy(checker.err())
+	var pods []skupperv2alpha1.PodDetails
+	if selector, ok := a.selectors[connector.Name]; ok && connector.Spec.Selector != "" {
+		for _, pod := range selector.List() {
+			pod.Health, pod.HealthMessage = checker.health(pod.IP)
+			pods = append(pods, pod)
+		}
+	}
+	if connector.SetSelectedPods(pods) {
+		changed = true
+	}
+	return changed
 }
 
 func (a *ExtendedBindings) ListenerUpdated(listener *skupperv2alpha1.Listener) {
-	allocatedRouterPort, err := a.mapping.GetPortForKey(listener.Name)
-	if err != nil {
-		bindings_logger.Error("Unable to get port for listener",
-			slog.String("namespace", listener.Namespace),
-			slog.String("name", listener.Name),
-			slog.Any("error", err),
-		)
-	} else {
+	var exposed *ExposedPortSet
+	for _, l := range listener.PortListeners() {
+		allocatedRouterPort, err := a.mapping.GetPortForKey(l.Name)
+		if err != nil {
+			bindings_logger.Error("Unable to get port for listener",
+				slog.String("namespace", listener.Namespace),
+				slog.String("name", l.Name),
+				slog.Any("error", err),
+			)
+			continue
+		}
 		port := Port{
-			Name:       listener.Name,
-			Port:       listener.Spec.Port,
+			Name:       l.Name,
+			Port:       l.Spec.Port,
 			TargetPort: allocatedRouterPort,
-			Protocol:   listener.Protocol(),
+			Protocol:   l.Protocol(),
 		}
-		if exposed := a.exposed.Expose(listener.Spec.Host, port); exposed != nil {
-			if err := a.context.Expose(exposed); err != nil {
-				//TODO: write error to listener status
-				bindings_logger.Error("Error exposing listener",
-					slog.String("namespace", listener.Namespace),
-					slog.String("name", listener.Name),
-					slog.Any("error", err))
-			} else {
-				bindings_logger.Info("Exposed listener",
-					slog.String("namespace", listener.Namespace),
-					slog.String("name", listener.Name))
+		if changed := a.exposed.Expose(listener.Spec.Host, port); changed != nil {
+			exposed = changed
+		}
+	}
+	if exposed != nil {
+		if err := a.context.Expose(exposed); err != nil {
+			//TODO: write error to listener status
+			bindings_logger.Error("Error exposing listener",
+				slog.String("namespace", listener.Namespace),
+				slog.String("name", listener.Name),
+				slog.Any("error", err))
+		} else {
+			bindings_logger.Info("Exposed listener",
+				slog.String("namespace", listener.Namespace),
+				slog.String("name", listener.Name))
 
-			}
 		}
 	}
 }
 
 func (a *ExtendedBindings) ListenerDeleted(listener *skupperv2alpha1.Listener) {
-	if exposed := a.exposed.Unexpose(listener.Spec.Host, listener.Name); exposed != nil {
-		a.mapping.ReleasePortForKey(listener.Name)
+	delete(a.listenerHosts, listener.Name)
+	delete(a.listenerPorts, listener.Name)
+	var exposed *ExposedPortSet
+	for _, l := range listener.PortListeners() {
+		if changed := a.exposed.Unexpose(listener.Spec.Host, l.Name); changed != nil {
+			a.mapping.ReleasePortForKey(l.Name)
+			exposed = changed
+		}
+	}
+	if exposed != nil {
 		if exposed.empty() {
 			if err := a.context.Unexpose(listener.Spec.Host); err != nil {
 				//TODO: write error to listener status
@@ -145,11 +232,33 @@ func (a *ExtendedBindings) ListenerDeleted(listener *skupperv2alpha1.Listener) {
 }
 
 func (a *ExtendedBindings) updateBridgeConfigForConnector(siteId string, connector *skupperv2alpha1.Connector, config *qdr.BridgeConfig) {
+	checker := a.healthCheckers[connector.Name]
 	if connector.Spec.Host != "" {
+		checker.setTargets([]string{connector.Spec.Host})
+		if checker.isUnhealthy(connector.Spec.Host) {
+			bindings_logger.Debug("Omitting unhealthy target for connector",
+				slog.String("namespace", connector.Namespace),
+				slog.String("name", connector.Name),
+				slog.String("host", connector.Spec.Host))
+			return
+		}
 		site.UpdateBridgeConfigForConnector(siteId, connector, config)
 	} else if connector.Spec.Selector != "" {
 		if selector, ok := a.selectors[connector.Name]; ok {
-			for _, pod := range selector.List() {
+			pods := selector.List()
+			var hosts []string
+			for _, pod := range pods {
+				hosts = append(hosts, pod.IP)
+			}
+			checker.setTargets(hosts)
+			for _, pod := range pods {
+				if checker.isUnhealthy(pod.IP) {
+					bindings_logger.Debug("Omitting unhealthy pod for connector",
+						slog.String("namespace", connector.Namespace),
+						slog.String("name", connector.Name),
+						slog.String("pod", pod.Name))
+					continue
+				}
 				site.UpdateBridgeConfigForConnectorToPod(siteId, connector, pod, connector.Spec.ExposePodsByName, config)
 			}
 		} else {
@@ -215,14 +324,11 @@ func (b *ExtendedBindings) UpdateListener(name string, listener *skupperv2alpha1
 		}
 	}
 	if listener != nil {
-		if previousHost, ok := b.listenerHosts[name]; !ok || previousHost != listener.Spec.Host {
-			if exposed := b.exposed.Unexpose(previousHost, name); exposed != nil && exposed.empty() {
-				if err := b.context.Unexpose(previousHost); err != nil {
-					errs = append(errs, err)
-				}
-			}
+		if err := b.unexposeStalePorts(name, listener); err != nil {
+			errs = append(errs, err)
 		}
 		b.listenerHosts[name] = listener.Spec.Host
+		b.listenerPorts[name] = listenerPortNames(listener)
 	}
 	if b.bindings.UpdateListener(name, listener) != nil {
 		updateConfig = true
@@ -233,6 +339,45 @@ func (b *ExtendedBindings) UpdateListener(name string, listener *skupperv2alpha1
 	return b, errors.Join(errs...)
 }
 
+// unexposeStalePorts removes the ports previously exposed for a listener
+// that are no longer exposed on the same host, either because the host
+// has changed or the port has been removed from the listener
+func (b *ExtendedBindings) unexposeStalePorts(name string, listener *skupperv2alpha1.Listener) error {
+	previousHost, ok := b.listenerHosts[name]
+	if !ok {
+		return nil
+	}
+	current := listenerPortNames(listener)
+	var exposed *ExposedPortSet
+	for _, port := range b.listenerPorts[name] {
+		retained := slices.Contains(current, port)
+		if retained && previousHost == listener.Spec.Host {
+			continue
+		}
+		if changed := b.exposed.Unexpose(previousHost, port); changed != nil {
+			exposed = changed
+		}
+		if !retained {
+			b.mapping.ReleasePortForKey(port)
+		}
+	}
+	if exposed == nil {
+		return nil
+	}
+	if exposed.empty() {
+		return b.context.Unexpose(previousHost)
+	}
+	return b.context.Expose(exposed)
+}
+
+func listenerPortNames(listener *skupperv2alpha1.Listener) []string {
+	var names []string
+	for _, l := range listener.PortListeners() {
+		names = append(names, l.Name)
+	}
+	return names
+}
+
 func (b *ExtendedBindings) GetConnector(name string) *skupperv2alpha1.Connector {
 	return b.bindings.GetConnector(name)
 }
@@ -258,6 +403,7 @@ func (b *ExtendedBindings) Apply(config *qdr.RouterConfig) bool {
 		ptl.updateBridgeConfig(b.bindings.SiteId, &desired)
 	}
 	b.bindings.AddSslProfiles(config)
+	b.bindings.AddLoadBalancingAddresses(config)
 	config.UpdateBridgeConfig(desired)
 	config.RemoveUnreferencedSslProfiles()
 	return true //TODO: can optimise by indicating if no change was required
@@ -333,6 +479,9 @@ func (b *ExtendedBindings) networkUpdated(network []skupperv2alpha1.SiteRecord)
 			changed = true
 		}
 	}
+	if b.bindings.NetworkUpdated(network) {
+		changed = true
+	}
 	if !changed {
 		return nil
 	}
@@ -0,0 +1,264 @@
+package site
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+const (
+	defaultHealthCheckPeriod           = 10 * time.Second
+	defaultHealthCheckTimeout          = time.Second
+	defaultHealthCheckFailureThreshold = 3
+	defaultHealthCheckSuccessThreshold = 1
+)
+
+// HealthProbe checks a single target address, returning an error if
+// it is not healthy
+type HealthProbe func(ctx context.Context, check skupperv2alpha1.HealthCheck, address string) error
+
+// probeTarget opens a TCP connection to the address and, for tls
+// checks, completes a TLS handshake over it. The certificate presented
+// is not verified, as the check is only concerned with whether the
+// target is responsive; verification is left to the router.
+func probeTarget(ctx context.Context, check skupperv2alpha1.HealthCheck, address string) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if check.Type != skupperv2alpha1.HEALTH_CHECK_TLS {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	client := tls.Client(conn, &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: true,
+	})
+	return client.HandshakeContext(ctx)
+}
+
+type targetHealth struct {
+	state     string
+	message   string
+	successes int
+	failures  int
+}
+
+// HealthChecker periodically probes the targets of a connector. The
+// health of each target starts out unknown and changes to unhealthy
+// after FailureThreshold consecutive failures, or to healthy after
+// SuccessThreshold consecutive successes. Whenever the health of any
+// target changes, notify is called (from the checker's goroutine).
+type HealthChecker struct {
+	check            skupperv2alpha1.HealthCheck
+	port             int
+	period           time.Duration
+	timeout          time.Duration
+	failureThreshold int
+	successThreshold int
+	probe            HealthProbe
+	notify           func()
+	ctx              context.Context
+	cancel           context.CancelFunc
+
+	mu      sync.Mutex
+	targets map[string]*targetHealth
+}
+
+func newHealthChecker(check skupperv2alpha1.HealthCheck, port int, probe HealthProbe, notify func()) *HealthChecker {
+	h := &HealthChecker{
+		check:            check,
+		port:             port,
+		period:           defaultHealthCheckPeriod,
+		timeout:          defaultHealthCheckTimeout,
+		failureThreshold: defaultHealthCheckFailureThreshold,
+		successThreshold: defaultHealthCheckSuccessThreshold,
+		probe:            probe,
+		notify:           notify,
+		targets:          map[string]*targetHealth{},
+	}
+	if check.PeriodSeconds > 0 {
+		h.period = time.Duration(check.PeriodSeconds) * time.Second
+	}
+	if check.TimeoutSeconds > 0 {
+		h.timeout = time.Duration(check.TimeoutSeconds) * time.Second
+	}
+	if check.FailureThreshold > 0 {
+		h.failureThreshold = check.FailureThreshold
+	}
+	if check.SuccessThreshold > 0 {
+		h.successThreshold = check.SuccessThreshold
+	}
+	h.ctx, h.cancel = context.WithCancel(context.Background())
+	return h
+}
+
+func (h *HealthChecker) matches(check *skupperv2alpha1.HealthCheck, port int) bool {
+	return check != nil && reflect.DeepEqual(h.check, *check) && h.port == port
+}
+
+func (h *HealthChecker) start() {
+	go h.run()
+}
+
+func (h *HealthChecker) Close() {
+	h.cancel()
+}
+
+func (h *HealthChecker) run() {
+	ticker := time.NewTicker(h.period)
+	defer ticker.Stop()
+	for {
+		h.checkAll()
+		select {
+		case <-h.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkAll probes all targets concurrently and notifies if the health
+// of any of them changed
+func (h *HealthChecker) checkAll() {
+	hosts := h.hosts()
+	results := make([]error, len(hosts))
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(h.ctx, h.timeout)
+			defer cancel()
+			results[i] = h.probe(ctx, h.check, net.JoinHostPort(host, strconv.Itoa(h.port)))
+		}(i, host)
+	}
+	wg.Wait()
+	if h.ctx.Err() != nil {
+		return
+	}
+	changed := false
+	for i, host := range hosts {
+		if h.record(host, results[i]) {
+			changed = true
+		}
+	}
+	if changed && h.notify != nil {
+		h.notify()
+	}
+}
+
+func (h *HealthChecker) hosts() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var hosts []string
+	for host := range h.targets {
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+// record updates the health of a target with the result of a probe,
+// returning true if its state changed
+func (h *HealthChecker) record(host string, err error) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	target, ok := h.targets[host]
+	if !ok {
+		// target was removed while being probed
+		return false
+	}
+	previous := target.state
+	if err != nil {
+		target.successes = 0
+		target.failures++
+		target.message = err.Error()
+		if target.failures >= h.failureThreshold {
+			target.state = skupperv2alpha1.HEALTH_UNHEALTHY
+		}
+	} else {
+		target.failures = 0
+		target.successes++
+		target.message = ""
+		if previous == "" || target.successes >= h.successThreshold {
+			target.state = skupperv2alpha1.HEALTH_HEALTHY
+		}
+	}
+	return target.state != previous
+}
+
+// setTargets replaces the set of hosts probed. Hosts already being
+// probed retain their health.
+func (h *HealthChecker) setTargets(hosts []string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	current := map[string]bool{}
+	for _, host := range hosts {
+		current[host] = true
+		if _, ok := h.targets[host]; !ok {
+			h.targets[host] = &targetHealth{}
+		}
+	}
+	for host := range h.targets {
+		if !current[host] {
+			delete(h.targets, host)
+		}
+	}
+}
+
+// health returns the state of a target and the error from its last
+// failed probe, if any. The state is empty until the target has been
+// probed.
+func (h *HealthChecker) health(host string) (string, string) {
+	if h == nil {
+		return "", ""
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if target, ok := h.targets[host]; ok {
+		return target.state, target.message
+	}
+	return "", ""
+}
+
+func (h *HealthChecker) isUnhealthy(host string) bool {
+	state, _ := h.health(host)
+	return state == skupperv2alpha1.HEALTH_UNHEALTHY
+}
+
+// err summarises the health of all targets
+func (h *HealthChecker) err() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	unhealthy := 0
+	var message string
+	for host, target := range h.targets {
+		if target.state == skupperv2alpha1.HEALTH_UNHEALTHY {
+			unhealthy++
+			message = host + ": " + target.message
+		}
+	}
+	switch {
+	case unhealthy == 0:
+		return nil
+	case unhealthy == 1:
+		return fmt.Errorf("1 of %d targets unhealthy (%s)", len(h.targets), message)
+	default:
+		return fmt.Errorf("%d of %d targets unhealthy", unhealthy, len(h.targets))
+	}
+}
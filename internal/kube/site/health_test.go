@@ -0,0 +1,247 @@
+package site
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/internal/qdr"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeProbe struct {
+	mu     sync.Mutex
+	failed map[string]bool
+}
+
+func (p *fakeProbe) setFailed(address string, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failed[address] = failed
+}
+
+func (p *fakeProbe) probe(ctx context.Context, check skupperv2alpha1.HealthCheck, address string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failed[address] {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestHealthChecker_thresholds(t *testing.T) {
+	probe := &fakeProbe{failed: map[string]bool{}}
+	notifications := 0
+	checker := newHealthChecker(skupperv2alpha1.HealthCheck{
+		FailureThreshold: 2,
+		SuccessThreshold: 2,
+	}, 8080, probe.probe, func() { notifications++ })
+	checker.setTargets([]string{"10.0.0.1", "10.0.0.2"})
+
+	state, _ := checker.health("10.0.0.1")
+	assert.Equal(t, state, "")
+	assert.Assert(t, !checker.isUnhealthy("10.0.0.1"))
+
+	checker.checkAll()
+	assert.Equal(t, notifications, 1)
+	state, _ = checker.health("10.0.0.1")
+	assert.Equal(t, state, skupperv2alpha1.HEALTH_HEALTHY)
+	assert.NilError(t, checker.err())
+
+	probe.setFailed("10.0.0.1:8080", true)
+	checker.checkAll()
+	assert.Equal(t, notifications, 1, "should not be unhealthy before failure threshold")
+	assert.Assert(t, !checker.isUnhealthy("10.0.0.1"))
+
+	checker.checkAll()
+	assert.Equal(t, notifications, 2)
+	assert.Assert(t, checker.isUnhealthy("10.0.0.1"))
+	state, message := checker.health("10.0.0.1")
+	assert.Equal(t, state, skupperv2alpha1.HEALTH_UNHEALTHY)
+	assert.Equal(t, message, "connection refused")
+	assert.Error(t, checker.err(), "1 of 2 targets unhealthy (10.0.0.1: connection refused)")
+
+	probe.setFailed("10.0.0.1:8080", false)
+	checker.checkAll()
+	assert.Assert(t, checker.isUnhealthy("10.0.0.1"), "should not recover before success threshold")
+	checker.checkAll()
+	assert.Equal(t, notifications, 3)
+	assert.Assert(t, !checker.isUnhealthy("10.0.0.1"))
+	assert.NilError(t, checker.err())
+}
+
+func TestHealthChecker_setTargets(t *testing.T) {
+	probe := &fakeProbe{failed: map[string]bool{"10.0.0.1:8080": true, "10.0.0.2:8080": true}}
+	checker := newHealthChecker(skupperv2alpha1.HealthCheck{FailureThreshold: 1}, 8080, probe.probe, nil)
+	checker.setTargets([]string{"10.0.0.1", "10.0.0.2"})
+	checker.checkAll()
+	assert.Assert(t, checker.isUnhealthy("10.0.0.1"))
+	assert.Assert(t, checker.isUnhealthy("10.0.0.2"))
+	assert.Error(t, checker.err(), "2 of 2 targets unhealthy")
+
+	checker.setTargets([]string{"10.0.0.2", "10.0.0.3"})
+	assert.Assert(t, !checker.isUnhealthy("10.0.0.1"), "removed target should be forgotten")
+	assert.Assert(t, checker.isUnhealthy("10.0.0.2"), "retained target should keep its health")
+	state, _ := checker.health("10.0.0.3")
+	assert.Equal(t, state, "")
+	assert.Error(t, checker.err(), "1 of 2 targets unhealthy (10.0.0.2: connection refused)")
+
+	var nilChecker *HealthChecker
+	nilChecker.setTargets([]string{"10.0.0.1"})
+	assert.Assert(t, !nilChecker.isUnhealthy("10.0.0.1"))
+}
+
+func TestHealthChecker_run(t *testing.T) {
+	probe := &fakeProbe{failed: map[string]bool{"10.0.0.1:8080": true}}
+	notified := make(chan struct{}, 1)
+	checker := newHealthChecker(skupperv2alpha1.HealthCheck{PeriodSeconds: 1, FailureThreshold: 1}, 8080, probe.probe, func() {
+		select {
+		case notified <- struct{}{}:
+		default:
+		}
+	})
+	checker.setTargets([]string{"10.0.0.1"})
+	checker.start()
+	defer checker.Close()
+	select {
+	case <-notified:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for health change")
+	}
+	assert.Assert(t, checker.isUnhealthy("10.0.0.1"))
+}
+
+func TestProbeTarget(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsServer.Close()
+	tlsAddress := strings.TrimPrefix(tlsServer.URL, "https://")
+
+	plain, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer plain.Close()
+	go func() {
+		for {
+			conn, err := plain.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	closedAddress := closed.Addr().String()
+	closed.Close()
+
+	tests := []struct {
+		name    string
+		check   skupperv2alpha1.HealthCheck
+		address string
+		fails   bool
+	}{
+		{
+			name:    "tcp success",
+			address: plain.Addr().String(),
+		},
+		{
+			name:    "tcp refused",
+			address: closedAddress,
+			fails:   true,
+		},
+		{
+			name:    "tls success",
+			check:   skupperv2alpha1.HealthCheck{Type: skupperv2alpha1.HEALTH_CHECK_TLS},
+			address: tlsAddress,
+		},
+		{
+			name:    "tls handshake fails",
+			check:   skupperv2alpha1.HealthCheck{Type: skupperv2alpha1.HEALTH_CHECK_TLS},
+			address: plain.Addr().String(),
+			fails:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			err := probeTarget(ctx, tt.check, tt.address)
+			if tt.fails {
+				assert.Assert(t, err != nil)
+			} else {
+				assert.NilError(t, err)
+			}
+		})
+	}
+}
+
+func TestExtendedBindings_unhealthyTargets(t *testing.T) {
+	probe := &fakeProbe{failed: map[string]bool{"10.244.0.9:8080": true}}
+	pods := []skupperv2alpha1.PodDetails{
+		{UID: "30af5279-be83-41e4-86fe-cc45396786f4", Name: "backend-1", IP: "10.244.0.9"},
+		{UID: "8a96ffdf-403b-4e4a-83a8-97d3d459adb6", Name: "backend-2", IP: "10.244.0.10"},
+	}
+	connector := &skupperv2alpha1.Connector{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "backend",
+			Namespace: "test",
+		},
+		Spec: skupperv2alpha1.ConnectorSpec{
+			Selector:    "app=backend",
+			Port:        8080,
+			HealthCheck: &skupperv2alpha1.HealthCheck{FailureThreshold: 1},
+		},
+	}
+	checker := newHealthChecker(*connector.Spec.HealthCheck, 8080, probe.probe, nil)
+	a := &ExtendedBindings{
+		selectors: map[string]TargetSelection{
+			"backend": NewMockTargetSelection("app=backend", pods),
+		},
+		healthCheckers: map[string]*HealthChecker{
+			"backend": checker,
+		},
+	}
+
+	config := qdr.NewBridgeConfig()
+	a.updateBridgeConfigForConnector("site-1", connector, &config)
+	assert.Equal(t, len(config.TcpConnectors), 2, "targets are used until known to be unhealthy")
+
+	checker.checkAll()
+	config = qdr.NewBridgeConfig()
+	a.updateBridgeConfigForConnector("site-1", connector, &config)
+	assert.Equal(t, len(config.TcpConnectors), 1)
+	_, ok := config.TcpConnectors["backend@10.244.0.10"]
+	assert.Assert(t, ok)
+
+	assert.Assert(t, a.updateHealthStatus(connector))
+	healthy := meta.FindStatusCondition(connector.Status.Conditions, skupperv2alpha1.CONDITION_TYPE_HEALTHY)
+	assert.Assert(t, healthy != nil)
+	assert.Equal(t, healthy.Status, v1.ConditionFalse)
+	assert.DeepEqual(t, connector.Status.SelectedPods, []skupperv2alpha1.PodDetails{
+		{UID: "30af5279-be83-41e4-86fe-cc45396786f4", Name: "backend-1", IP: "10.244.0.9", Health: skupperv2alpha1.HEALTH_UNHEALTHY, HealthMessage: "connection refused"},
+		{UID: "8a96ffdf-403b-4e4a-83a8-97d3d459adb6", Name: "backend-2", IP: "10.244.0.10", Health: skupperv2alpha1.HEALTH_HEALTHY},
+	})
+	assert.Assert(t, !a.updateHealthStatus(connector))
+
+	probe.setFailed("10.244.0.9:8080", false)
+	checker.checkAll()
+	config = qdr.NewBridgeConfig()
+	a.updateBridgeConfigForConnector("site-1", connector, &config)
+	assert.Equal(t, len(config.TcpConnectors), 2, "recovered target should be restored")
+	assert.Assert(t, a.updateHealthStatus(connector))
+	assert.Assert(t, meta.IsStatusConditionTrue(connector.Status.Conditions, skupperv2alpha1.CONDITION_TYPE_HEALTHY))
+
+	delete(a.healthCheckers, "backend")
+	assert.Assert(t, a.updateHealthStatus(connector))
+	assert.Assert(t, meta.FindStatusCondition(connector.Status.Conditions, skupperv2alpha1.CONDITION_TYPE_HEALTHY) == nil)
+	assert.Assert(t, connector.Status.SelectedPods == nil)
+}
@@ -0,0 +1,72 @@
+package site
+
+import (
+	"log/slog"
+	"reflect"
+	"time"
+
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+// RevokedSitesUpdated replaces the revoked sites recorded in the site
+// status for the named AccessGrant. Entries are retained after the
+// grant is deleted, as the certificates it issued remain valid until
+// they expire; expired entries are removed whenever the list changes.
+func (s *Site) RevokedSitesUpdated(grant string, revoked []skupperv2alpha1.RevokedSite) error {
+	if s.site == nil {
+		return nil
+	}
+	sites := mergeRevokedSites(s.site.Status.RevokedSites, grant, revoked, s.site.Status.Network, time.Now())
+	if reflect.DeepEqual(sites, s.site.Status.RevokedSites) {
+		return nil
+	}
+	for _, site := range sites {
+		if !s.site.Status.IsRevoked(site.Id) {
+			s.logger.Info("Revoked access for remote site",
+				slog.String("namespace", s.namespace),
+				slog.String("id", site.Id),
+				slog.String("name", site.Name),
+				slog.String("grant", site.AccessGrant))
+		}
+	}
+	s.site.Status.RevokedSites = sites
+	return s.updateSiteStatus()
+}
+
+func mergeRevokedSites(current []skupperv2alpha1.RevokedSite, grant string, revoked []skupperv2alpha1.RevokedSite, network []skupperv2alpha1.SiteRecord, now time.Time) []skupperv2alpha1.RevokedSite {
+	names := map[string]string{}
+	for _, site := range current {
+		if site.Name != "" {
+			names[site.Id] = site.Name
+		}
+	}
+	for _, record := range network {
+		names[record.Id] = record.Name
+	}
+	var merged []skupperv2alpha1.RevokedSite
+	for _, site := range current {
+		if site.AccessGrant == grant || isExpired(site, now) {
+			continue
+		}
+		merged = append(merged, site)
+	}
+	for _, site := range revoked {
+		if isExpired(site, now) {
+			continue
+		}
+		site.Name = names[site.Id]
+		merged = append(merged, site)
+	}
+	return merged
+}
+
+func isExpired(site skupperv2alpha1.RevokedSite, now time.Time) bool {
+	if site.Expiration == "" {
+		return false
+	}
+	expiration, err := time.Parse(time.RFC3339, site.Expiration)
+	if err != nil {
+		return false
+	}
+	return expiration.Before(now)
+}
@@ -55,6 +55,7 @@ type Site struct {
 	logger        *slog.Logger
 	currentGroups []string
 	labelling     Labelling
+	policies      *site.AccessPolicies
 }
 
 func NewSite(namespace string, eventProcessor *watchers.EventProcessor, certs certificates.CertificateManager, access SecuredAccessFactory, sizes *sizing.Registry, labelling Labelling) *Site {
@@ -72,6 +73,7 @@ func NewSite(namespace string, eventProcessor *watchers.EventProcessor, certs ce
 			slog.String("component", "kube.site.site"),
 		),
 		labelling: labelling,
+		policies:  site.NewAccessPolicies(),
 	}
 }
 
@@ -176,9 +178,12 @@ func (s *Site) reconcile(siteDef *skupperv2alpha1.Site, inRecovery bool) error {
 			return err
 		}
 	}
-	// CAs for local and site access
-	if err := s.certs.EnsureCA(s.namespace, "skupper-site-ca", fmt.Sprintf("%s site CA", s.name), s.ownerReferences()); err != nil {
-		return err
+	// CAs for local and site access (the latter is not needed if
+	// site access certificates are from an external issuer)
+	if _, external := skupperv2alpha1.ParseIssuerReference(s.site.DefaultIssuer()); !external {
+		if err := s.certs.EnsureCA(s.namespace, "skupper-site-ca", fmt.Sprintf("%s site CA", s.name), s.ownerReferences()); err != nil {
+			return err
+		}
 	}
 	if err := s.certs.EnsureCA(s.namespace, "skupper-local-ca", fmt.Sprintf("%s local CA", s.name), s.ownerReferences()); err != nil {
 		return err
@@ -269,7 +274,7 @@ func (s *Site) checkDefaultRouterAccess(ctxt context.Context, site *skupperv2alp
 		Spec: skupperv2alpha1.RouterAccessSpec{
 			AccessType:             accessType,
 			TlsCredentials:         "skupper-site-server",
-			Issuer:                 "skupper-site-ca", //TODO: can rely ondefault here
+			Issuer:                 site.DefaultIssuer(),
 			GenerateTlsCredentials: true,
 			Roles: []skupperv2alpha1.RouterAccessRole{
 				{
@@ -297,7 +302,10 @@ func (s *Site) checkDefaultRouterAccess(ctxt context.Context, site *skupperv2alp
 		return nil
 	} else {
 		created, err := s.clients.GetSkupperClient().SkupperV2alpha1().RouterAccesses(s.namespace).Create(context.Background(), desired, metav1.CreateOptions{})
-		if err != nil {
+		if errors.IsAlreadyExists(err) && s.policies.RouterAccessPermitted(desired) != nil {
+			// the existing RouterAccess is not tracked while policy does not permit link access
+			return nil
+		} else if err != nil {
 			return err
 		}
 		s.linkAccess[name] = created
@@ -393,6 +401,12 @@ func (s *Site) checkRole(ctxt context.Context) error {
 			APIGroups: []string{"coordination.k8s.io"},
 			Resources: []string{"leases"},
 		},
+		//needed for closing links from revoked sites
+		{
+			Verbs:     []string{"get", "list", "watch"},
+			APIGroups: []string{"skupper.io"},
+			Resources: []string{"sites"},
+		},
 	}
 	desired := &rbacv1.Role{
 		TypeMeta: metav1.TypeMeta{
@@ -661,7 +675,7 @@ func (s *Site) recoverRouterConfig(update bool) ([]*qdr.RouterConfig, error) {
 	for i, group := range groups {
 		if config, ok := byName[group]; ok {
 			if update {
-				op := ConfigUpdateList{s.bindings, s, s.linkAccess.DesiredConfig(groups[:i], SSL_PROFILE_PATH)}
+				op := ConfigUpdateList{s.bindings, s, s.linkAccessConfig(groups[:i])}
 				if err := kubeqdr.UpdateRouterConfig(s.clients.GetKubeClient(), group, s.namespace, context.TODO(), op, s.labelling); err != nil {
 					s.logger.Error("Failed to update router config map",
 						slog.String("namespace", s.namespace),
@@ -674,7 +688,7 @@ func (s *Site) recoverRouterConfig(update bool) ([]*qdr.RouterConfig, error) {
 		} else {
 			routerConfig := s.initialRouterConfig()
 			s.bindings.Apply(routerConfig)
-			s.linkAccess.DesiredConfig(groups[:i], SSL_PROFILE_PATH).Apply(routerConfig)
+			s.linkAccessConfig(groups[:i]).Apply(routerConfig)
 			if err := s.createRouterConfigForGroup(group, routerConfig); err != nil {
 				s.logger.Error("Failed to create router config map",
 					slog.String("namespace", s.namespace),
@@ -797,12 +811,50 @@ func (s *Site) updateConnectorStatus(connector *skupperv2alpha1.Connector) error
 }
 
 func (s *Site) updateConnectorConfiguredStatus(connector *skupperv2alpha1.Connector, err error) error {
-	if connector.SetConfigured(err) {
+	permitted := s.policies.SetPermitted(connector, nil)
+	if connector.SetConfigured(err) || permitted {
+		return s.updateConnectorStatus(connector)
+	}
+	return nil
+}
+
+// denyConnector removes a connector the access policies do not permit
+// from the router config, recording the reason in its status
+func (s *Site) denyConnector(name string, connector *skupperv2alpha1.Connector, denied error) error {
+	var err error
+	if update := s.bindings.UpdateConnector(name, nil); update != nil && s.site != nil {
+		err = s.updateRouterConfig(update)
+	}
+	if connector.SetPermitted(denied) {
+		if _, err := updateConnectorStatus(s.clients, connector); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (s *Site) updateConnectorHealthStatus(name string) error {
+	connector := s.bindings.GetConnector(name)
+	if connector == nil {
+		return nil
+	}
+	if s.bindings.updateHealthStatus(connector) {
 		return s.updateConnectorStatus(connector)
 	}
 	return nil
 }
 
+func (s *Site) connectorHealthChanged(name string) error {
+	connector := s.bindings.GetConnector(name)
+	if connector == nil {
+		return nil
+	}
+	if err := s.updateRouterConfig(s.bindings); err != nil {
+		return s.updateConnectorConfiguredStatus(connector, err)
+	}
+	return s.updateConnectorHealthStatus(name)
+}
+
 func (s *Site) updateConnectorConfiguredStatusWithSelectedPods(connector *skupperv2alpha1.Connector, selected []skupperv2alpha1.PodDetails) error {
 	var err error
 	if len(selected) == 0 {
@@ -820,6 +872,11 @@ func (s *Site) updateConnectorConfiguredStatusWithSelectedPods(connector *skuppe
 }
 
 func (s *Site) CheckConnector(name string, connector *skupperv2alpha1.Connector) error {
+	if connector != nil {
+		if denied := s.policies.ConnectorPermitted(connector); denied != nil {
+			return s.denyConnector(name, connector, denied)
+		}
+	}
 	update := s.bindings.UpdateConnector(name, connector)
 	if s.site == nil {
 		if connector == nil {
@@ -828,17 +885,24 @@ func (s *Site) CheckConnector(name string, connector *skupperv2alpha1.Connector)
 		return s.updateConnectorConfiguredStatus(connector, stderrors.New("No active site in namespace"))
 	}
 	if update == nil {
+		if connector != nil && s.policies.SetPermitted(connector, nil) {
+			return s.updateConnectorStatus(connector)
+		}
 		return nil
 	}
 	err := s.updateRouterConfig(update)
 	if connector == nil {
 		return err
 	}
-	return s.updateConnectorConfiguredStatus(connector, err)
+	if err := s.updateConnectorConfiguredStatus(connector, stderrors.Join(site.ValidateConnectorPorts(connector), err)); err != nil {
+		return err
+	}
+	return s.updateConnectorHealthStatus(name)
 }
 
 func (s *Site) updateListenerStatus(listener *skupperv2alpha1.Listener, err error) error {
-	if listener.SetConfigured(err) {
+	permitted := s.policies.SetPermitted(listener, nil)
+	if listener.SetConfigured(err) || permitted {
 		updated, err := s.clients.GetSkupperClient().SkupperV2alpha1().Listeners(listener.ObjectMeta.Namespace).UpdateStatus(context.TODO(), listener, metav1.UpdateOptions{})
 		if err == nil {
 			return err
@@ -878,7 +942,27 @@ func (s *Site) CheckListenerService(svc *corev1.Service) error {
 	return nil
 }
 
+// denyListener removes a listener the access policies do not permit
+// from the router config, recording the reason in its status
+func (s *Site) denyListener(name string, listener *skupperv2alpha1.Listener, denied error) error {
+	update, err := s.bindings.UpdateListener(name, nil)
+	if update != nil && s.site != nil {
+		err = stderrors.Join(err, s.updateRouterConfig(update))
+	}
+	if listener.SetPermitted(denied) {
+		if _, err := updateListenerStatus(s.clients, listener); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
 func (s *Site) CheckListener(name string, listener *skupperv2alpha1.Listener) error {
+	if listener != nil {
+		if denied := s.policies.ListenerPermitted(listener); denied != nil {
+			return s.denyListener(name, listener, denied)
+		}
+	}
 	update, err1 := s.bindings.UpdateListener(name, listener)
 	if s.site == nil {
 		if listener == nil {
@@ -887,13 +971,17 @@ func (s *Site) CheckListener(name string, listener *skupperv2alpha1.Listener) er
 		return s.updateListenerStatus(listener, stderrors.New("No active site in namespace"))
 	}
 	if update == nil {
+		if listener != nil && s.policies.SetPermitted(listener, nil) {
+			_, err := updateListenerStatus(s.clients, listener)
+			return err
+		}
 		return nil
 	}
 	err2 := s.updateRouterConfig(update)
 	if listener == nil {
 		return stderrors.Join(err1, err2)
 	}
-	return s.updateListenerStatus(listener, stderrors.Join(err1, err2))
+	return s.updateListenerStatus(listener, stderrors.Join(site.ValidateLoadBalancing(listener), site.ValidateListenerPorts(listener), err1, err2))
 }
 
 func (s *Site) setBindingsConfiguredStatus(err error) {
@@ -934,15 +1022,46 @@ func (s *Site) newLink(linkconfig *skupperv2alpha1.Link) *site.Link {
 	return config
 }
 
+// CheckAccessPolicy records a change to an AccessPolicy in the
+// namespace, returning true if listeners, connectors, links and router
+// accesses need to be checked again
+func (s *Site) CheckAccessPolicy(name string, policy *skupperv2alpha1.AccessPolicy) (bool, error) {
+	changed := s.policies.Update(name, policy)
+	if policy == nil {
+		return changed, nil
+	}
+	if policy.SetConfigured(site.ValidateAccessPolicy(policy)) {
+		if _, err := s.clients.GetSkupperClient().SkupperV2alpha1().AccessPolicies(policy.Namespace).UpdateStatus(context.TODO(), policy, metav1.UpdateOptions{}); err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
 func (s *Site) CheckLink(name string, linkconfig *skupperv2alpha1.Link) error {
 	s.logger.Debug("checkLink",
 		slog.String("name", name))
 	if linkconfig == nil {
 		return s.unlink(name)
 	}
+	if denied := s.policies.LinkPermitted(linkconfig); denied != nil {
+		return s.denyLink(name, linkconfig, denied)
+	}
 	return s.link(linkconfig)
 }
 
+// denyLink disconnects a link to a remote site the access policies do
+// not permit, recording the reason in its status
+func (s *Site) denyLink(name string, linkconfig *skupperv2alpha1.Link, denied error) error {
+	err := s.unlink(name)
+	if linkconfig.SetPermitted(denied) {
+		if _, err := s.clients.GetSkupperClient().SkupperV2alpha1().Links(linkconfig.ObjectMeta.Namespace).UpdateStatus(context.TODO(), linkconfig, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
 func (s *Site) link(linkconfig *skupperv2alpha1.Link) error {
 	var config *site.Link
 	if existing, ok := s.links[linkconfig.ObjectMeta.Name]; ok {
@@ -964,6 +1083,9 @@ func (s *Site) link(linkconfig *skupperv2alpha1.Link) error {
 			s.logger.Debug("No update to router config required for link",
 				slog.String("namespace", linkconfig.ObjectMeta.Namespace),
 				slog.String("token", linkconfig.ObjectMeta.Name))
+			if s.policies.SetPermitted(linkconfig, nil) {
+				return s.updateLinkStatus(linkconfig)
+			}
 		}
 	} else {
 		s.logger.Info("Site is not yet initialised, cannot configure router for link",
@@ -991,7 +1113,8 @@ func (s *Site) updateLinkConfiguredCondition(link *skupperv2alpha1.Link, err err
 	if link == nil {
 		return nil
 	}
-	if link.SetConfigured(err) {
+	permitted := s.policies.SetPermitted(link, nil)
+	if link.SetConfigured(err) || permitted {
 		return s.updateLinkStatus(link)
 	}
 	return nil
@@ -1105,7 +1228,7 @@ func (s *Site) NetworkStatusUpdated(network []skupperv2alpha1.SiteRecord) error
 		}
 	}
 
-	bindingStatus := newBindingStatus(s.clients, network)
+	bindingStatus := newBindingStatus(s.clients, s.site.GetSiteId(), network)
 	s.bindings.Map(bindingStatus.updateMatchingListenerCount, bindingStatus.updateMatchingConnectorCount)
 	s.logger.Debug("Updating matching listeners for attached connectors")
 	s.bindings.MapOverAttachedConnectors(bindingStatus.updateMatchingListenerCountForAttachedConnector)
@@ -1162,6 +1285,29 @@ func (s *Site) updateRouterAccessStatus(la *skupperv2alpha1.RouterAccess) {
 	}
 }
 
+// linkAccessConfig returns the router config for link access. Links
+// into the site authenticate with certificates issued by its default
+// issuer, so router accesses whose credentials are generated from that
+// issuer refuse the certificates it has revoked (unless the issuer is
+// external, in which case it does not issue them).
+func (s *Site) linkAccessConfig(targetGroups []string) *site.RouterAccessConfig {
+	config := s.linkAccess.DesiredConfig(targetGroups, SSL_PROFILE_PATH)
+	if s.site == nil {
+		return config
+	}
+	defaultIssuer := s.site.DefaultIssuer()
+	if _, external := skupperv2alpha1.ParseIssuerReference(defaultIssuer); external {
+		return config
+	}
+	var profiles []string
+	for _, la := range s.linkAccess {
+		if la.Spec.GenerateTlsCredentials && (la.Spec.Issuer == "" || la.Spec.Issuer == defaultIssuer) {
+			profiles = append(profiles, la.Spec.TlsCredentials)
+		}
+	}
+	return config.WithRevocationList(profiles...)
+}
+
 func asSecuredAccessSpec(la *skupperv2alpha1.RouterAccess, group string, defaultIssuer string) skupperv2alpha1.SecuredAccessSpec {
 	issuer := la.Spec.Issuer
 	if issuer == "" {
@@ -1214,7 +1360,28 @@ func (s *Site) checkSecuredAccess() error {
 	return nil
 }
 
+// denyRouterAccess removes link access the access policies do not
+// permit from the router config, recording the reason in its status
+func (s *Site) denyRouterAccess(name string, la *skupperv2alpha1.RouterAccess, denied error) error {
+	var err error
+	if _, ok := s.linkAccess[name]; ok {
+		err = s.CheckRouterAccess(name, nil)
+	}
+	permitted := la.SetPermitted(denied)
+	if la.SetConfigured(denied) || permitted {
+		if _, err := s.clients.GetSkupperClient().SkupperV2alpha1().RouterAccesses(la.Namespace).UpdateStatus(context.TODO(), la, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
 func (s *Site) CheckRouterAccess(name string, la *skupperv2alpha1.RouterAccess) error {
+	if la != nil {
+		if denied := s.policies.RouterAccessPermitted(la); denied != nil {
+			return s.denyRouterAccess(name, la, denied)
+		}
+	}
 	specChanged := false
 	if la == nil {
 		delete(s.linkAccess, name)
@@ -1233,7 +1400,7 @@ func (s *Site) CheckRouterAccess(name string, la *skupperv2alpha1.RouterAccess)
 		groups := s.groups()
 		var errors []string
 		for i, group := range groups {
-			if err := s.updateRouterConfigForGroup(s.linkAccess.DesiredConfig(previousGroups, SSL_PROFILE_PATH), group); err != nil {
+			if err := s.updateRouterConfigForGroup(s.linkAccessConfig(previousGroups), group); err != nil {
 				s.logger.Error("Error updating router config",
 					slog.String("namespace", s.namespace),
 					slog.Any("error", err))
@@ -1261,9 +1428,14 @@ func (s *Site) CheckRouterAccess(name string, la *skupperv2alpha1.RouterAccess)
 		if len(errors) > 0 {
 			err = fmt.Errorf("%s", strings.Join(errors, ", "))
 		}
-		if la != nil && la.SetConfigured(err) {
-			s.updateRouterAccessStatus(la)
+		if la != nil {
+			permitted := s.policies.SetPermitted(la, nil)
+			if la.SetConfigured(err) || permitted {
+				s.updateRouterAccessStatus(la)
+			}
 		}
+	} else if la != nil && s.policies.SetPermitted(la, nil) {
+		s.updateRouterAccessStatus(la)
 	}
 	return s.updateResolved()
 }
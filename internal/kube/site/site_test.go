@@ -3,6 +3,7 @@ package site
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/skupperproject/skupper/internal/kube/certificates"
 	fakeclient "github.com/skupperproject/skupper/internal/kube/client/fake"
@@ -15,6 +16,7 @@ import (
 	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
 	"gotest.tools/v3/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -492,6 +494,72 @@ func TestSite_CheckConnector(t *testing.T) {
 	}
 }
 
+func TestSite_CheckAccessPolicy(t *testing.T) {
+	policy := &skupperv2alpha1.AccessPolicy{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "restricted",
+			Namespace: "test",
+		},
+		Spec: skupperv2alpha1.AccessPolicySpec{
+			AllowedExposedRoutingKeys:  []string{"backend"},
+			AllowedConsumedRoutingKeys: []string{"frontend-*"},
+		},
+	}
+	listener := &skupperv2alpha1.Listener{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "backend",
+			Namespace: "test",
+		},
+		Spec: skupperv2alpha1.ListenerSpec{
+			RoutingKey: "backend",
+			Port:       8080,
+			Host:       "backend",
+		},
+	}
+	connector := &skupperv2alpha1.Connector{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "backend",
+			Namespace: "test",
+		},
+		Spec: skupperv2alpha1.ConnectorSpec{
+			RoutingKey: "backend",
+			Port:       8080,
+			Host:       "10.0.0.1",
+		},
+	}
+	s, err := newSiteMocks("test", nil, []runtime.Object{policy, listener, connector}, "", false)
+	assert.Assert(t, err)
+	s.initialised = true
+	assert.Assert(t, createRouterConfigMock(s))
+
+	changed, err := s.CheckAccessPolicy(policy.Name, policy)
+	assert.Assert(t, err)
+	assert.Assert(t, changed)
+	assert.Assert(t, meta.IsStatusConditionTrue(policy.Status.Conditions, skupperv2alpha1.CONDITION_TYPE_CONFIGURED))
+	changed, err = s.CheckAccessPolicy(policy.Name, policy)
+	assert.Assert(t, err)
+	assert.Assert(t, !changed, "unchanged policy should not require resources to be checked")
+
+	assert.Assert(t, s.CheckListener(listener.Name, listener))
+	assert.Assert(t, s.bindings.bindings.GetListener(listener.Name) == nil, "listener should not be configured")
+	permitted := meta.FindStatusCondition(listener.Status.Conditions, skupperv2alpha1.CONDITION_TYPE_PERMITTED)
+	assert.Assert(t, permitted != nil)
+	assert.Equal(t, permitted.Status, v1.ConditionFalse)
+	assert.Equal(t, permitted.Message, "consuming routing key \"backend\" is not permitted by AccessPolicy \"restricted\"")
+	assert.Assert(t, !meta.IsStatusConditionTrue(listener.Status.Conditions, skupperv2alpha1.CONDITION_TYPE_READY))
+
+	assert.Assert(t, s.CheckConnector(connector.Name, connector))
+	assert.Assert(t, s.bindings.GetConnector(connector.Name) != nil)
+	assert.Assert(t, meta.IsStatusConditionTrue(connector.Status.Conditions, skupperv2alpha1.CONDITION_TYPE_PERMITTED))
+
+	changed, err = s.CheckAccessPolicy(policy.Name, nil)
+	assert.Assert(t, err)
+	assert.Assert(t, changed)
+	assert.Assert(t, s.CheckListener(listener.Name, listener))
+	assert.Assert(t, s.bindings.bindings.GetListener(listener.Name) != nil, "listener should be configured once policy is removed")
+	assert.Assert(t, meta.FindStatusCondition(listener.Status.Conditions, skupperv2alpha1.CONDITION_TYPE_PERMITTED) == nil)
+}
+
 func TestSite_CheckLink(t *testing.T) {
 	type args struct {
 		name       string
@@ -881,6 +949,54 @@ func Test_NetworkStatusUpdate(t *testing.T) {
 	}
 }
 
+func TestSite_RevokedSitesUpdated(t *testing.T) {
+	s, err := newSiteMocks("test", nil, nil, "", false)
+	assert.Assert(t, err)
+	s.site.Status.Network = []skupperv2alpha1.SiteRecord{
+		{
+			Id:   "d3a1b7a4-1d4c-4c1f-9d6b-6f1e0c2a9f10",
+			Name: "east",
+		},
+	}
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	assert.Assert(t, s.RevokedSitesUpdated("grant-a", []skupperv2alpha1.RevokedSite{
+		{
+			Id:          "d3a1b7a4-1d4c-4c1f-9d6b-6f1e0c2a9f10",
+			AccessGrant: "grant-a",
+			Revoked:     past,
+			Expiration:  future,
+		},
+		{
+			Id:          "expired-site",
+			AccessGrant: "grant-a",
+			Revoked:     past,
+			Expiration:  past,
+		},
+	}))
+	assert.Assert(t, s.RevokedSitesUpdated("grant-b", []skupperv2alpha1.RevokedSite{
+		{
+			Id:          "west-id",
+			AccessGrant: "grant-b",
+			Revoked:     past,
+		},
+	}))
+	latest, err := s.clients.GetSkupperClient().SkupperV2alpha1().Sites("test").Get(context.TODO(), "site1", v1.GetOptions{})
+	assert.Assert(t, err)
+	assert.Equal(t, len(latest.Status.RevokedSites), 2)
+	assert.Equal(t, latest.Status.RevokedSites[0].Name, "east")
+	assert.Equal(t, latest.Status.RevokedSites[0].AccessGrant, "grant-a")
+	assert.Equal(t, latest.Status.RevokedSites[1].Id, "west-id")
+	assert.Assert(t, latest.Status.IsRevoked("d3a1b7a4-1d4c-4c1f-9d6b-6f1e0c2a9f10"))
+	assert.Assert(t, !latest.Status.IsRevoked("expired-site"))
+
+	// reinstating the grant removes only the sites it revoked
+	assert.Assert(t, s.RevokedSitesUpdated("grant-a", nil))
+	assert.Equal(t, len(s.site.Status.RevokedSites), 1)
+	assert.Equal(t, s.site.Status.RevokedSites[0].Id, "west-id")
+}
+
 func Test_CheckSecuredAccess(t *testing.T) {
 	type args struct {
 		sa *skupperv2alpha1.SecuredAccess
@@ -1050,6 +1166,7 @@ func newSiteMocks(namespace string, k8sObjects []runtime.Object, skupperObjects
 		logger: slog.New(slog.Default().Handler()).With(
 			slog.String("component", "kube.site.site"),
 		),
+		policies: site1.NewAccessPolicies(),
 	}
 	newSite.bindings.init(NewMockBindingContext(map[string]TargetSelection{}), &qdr.RouterConfig{})
 
@@ -120,6 +120,10 @@ func (c *EventProcessor) HasTlsRoute() bool {
 	return resource.IsResourceAvailable(c.discoveryClient, resource.TlsRouteResource())
 }
 
+func (c *EventProcessor) HasCertManagerCertificate() bool {
+	return resource.IsResourceAvailable(c.discoveryClient, resource.CertManagerCertificateResource())
+}
+
 func (c *EventProcessor) GetRouteInterface() openshiftroute.Interface {
 	return c.routeClient
 }
@@ -582,6 +586,14 @@ func (c *EventProcessor) WatchTlsRoutes(options dynamicinformer.TweakListOptions
 	return c.WatchDynamic(resource.TlsRouteResource(), options, namespace, handler)
 }
 
+func (c *EventProcessor) WatchCertManagerCertificates(options dynamicinformer.TweakListOptionsFunc, namespace string, handler DynamicHandler) *DynamicWatcher {
+	if !c.HasCertManagerCertificate() {
+		log.Println("Cannot watch cert-manager Certificates; resource not installed")
+		return nil
+	}
+	return c.WatchDynamic(resource.CertManagerCertificateResource(), options, namespace, handler)
+}
+
 func (c *EventProcessor) WatchDynamic(resource schema.GroupVersionResource, options dynamicinformer.TweakListOptionsFunc, namespace string, handler DynamicHandler) *DynamicWatcher {
 	watcher := &DynamicWatcher{
 		handler: handler,
@@ -1576,6 +1588,73 @@ func (w *RouterAccessWatcher) List() []*skupperv2alpha1.RouterAccess {
 	return results
 }
 
+func (c *EventProcessor) WatchAccessPolicies(namespace string, handler AccessPolicyHandler) *AccessPolicyWatcher {
+	watcher := &AccessPolicyWatcher{
+		handler: handler,
+		informer: skupperv2alpha1informer.NewAccessPolicyInformer(
+			c.skupperClient,
+			namespace,
+			time.Second*30,
+			cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}),
+		namespace: namespace,
+	}
+	watcher.informer.AddEventHandler(c.newEventHandler(watcher))
+	c.addWatcher(watcher)
+	return watcher
+}
+
+type AccessPolicyHandler func(string, *skupperv2alpha1.AccessPolicy) error
+
+type AccessPolicyWatcher struct {
+	handler   AccessPolicyHandler
+	informer  cache.SharedIndexInformer
+	namespace string
+}
+
+func (w *AccessPolicyWatcher) Handle(event ResourceChange) error {
+	obj, err := w.Get(event.Key)
+	if err != nil {
+		return err
+	}
+	return w.handler(event.Key, obj)
+}
+
+func (w *AccessPolicyWatcher) HasSynced() func() bool {
+	return w.informer.HasSynced
+}
+
+func (w *AccessPolicyWatcher) Describe(event ResourceChange) string {
+	return fmt.Sprintf("AccessPolicy %s", event.Key)
+}
+
+func (w *AccessPolicyWatcher) Start(stopCh <-chan struct{}) {
+	go w.informer.Run(stopCh)
+}
+
+func (w *AccessPolicyWatcher) Sync(stopCh <-chan struct{}) bool {
+	return cache.WaitForCacheSync(stopCh, w.informer.HasSynced)
+}
+
+func (w *AccessPolicyWatcher) Get(key string) (*skupperv2alpha1.AccessPolicy, error) {
+	entity, exists, err := w.informer.GetStore().GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, nil
+	}
+	return entity.(*skupperv2alpha1.AccessPolicy), nil
+}
+
+func (w *AccessPolicyWatcher) List() []*skupperv2alpha1.AccessPolicy {
+	list := w.informer.GetStore().List()
+	results := []*skupperv2alpha1.AccessPolicy{}
+	for _, o := range list {
+		results = append(results, o.(*skupperv2alpha1.AccessPolicy))
+	}
+	return results
+}
+
 func ByName(name string) internalinterfaces.TweakListOptionsFunc {
 	return func(options *metav1.ListOptions) {
 		options.FieldSelector = "metadata.name=" + name
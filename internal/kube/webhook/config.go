@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	iflag "github.com/skupperproject/skupper/internal/flag"
+)
+
+type WebhookConfig struct {
+	Enabled              bool
+	Port                 int
+	TlsCredentialsSecret string
+}
+
+func BoundWebhookConfig(flags *flag.FlagSet) (*WebhookConfig, error) {
+	c := &WebhookConfig{}
+	var errors []string
+	if err := iflag.BoolVar(flags, &c.Enabled, "enable-validating-webhook", "SKUPPER_ENABLE_VALIDATING_WEBHOOK", false, "Serve a validating admission webhook for Skupper resources."); err != nil {
+		errors = append(errors, err.Error())
+	}
+	if err := iflag.IntVar(flags, &c.Port, "validating-webhook-port", "SKUPPER_VALIDATING_WEBHOOK_PORT", 9443, "The port on which the validating admission webhook should listen."); err != nil {
+		errors = append(errors, err.Error())
+	}
+	iflag.StringVar(flags, &c.TlsCredentialsSecret, "validating-webhook-tls-credentials", "SKUPPER_VALIDATING_WEBHOOK_TLS_CREDENTIALS", "skupper-webhook-server", "The name of a secret in the controller's namespace in which TLS credentials for the validating admission webhook are found.")
+	if len(errors) > 0 {
+		return c, fmt.Errorf("Invalid environment variable(s): %s", strings.Join(errors, ", "))
+	}
+	return c, nil
+}
+
+func (c *WebhookConfig) addr() string {
+	return fmt.Sprintf(":%d", c.Port)
+}
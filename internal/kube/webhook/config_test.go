@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"flag"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func Test_BoundWebhookConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		env            map[string]string
+		expectedValue  *WebhookConfig
+		expectedErrors []string
+	}{
+		{
+			name: "defaults",
+			expectedValue: &WebhookConfig{
+				Port:                 9443,
+				TlsCredentialsSecret: "skupper-webhook-server",
+			},
+		},
+		{
+			name: "env vars",
+			env: map[string]string{
+				"SKUPPER_ENABLE_VALIDATING_WEBHOOK":          "true",
+				"SKUPPER_VALIDATING_WEBHOOK_PORT":            "8443",
+				"SKUPPER_VALIDATING_WEBHOOK_TLS_CREDENTIALS": "my-secret",
+			},
+			expectedValue: &WebhookConfig{
+				Enabled:              true,
+				Port:                 8443,
+				TlsCredentialsSecret: "my-secret",
+			},
+		},
+		{
+			name: "args",
+			env: map[string]string{
+				"SKUPPER_VALIDATING_WEBHOOK_PORT": "8443",
+			},
+			args: []string{
+				"-enable-validating-webhook",
+				"-validating-webhook-port=7443",
+				"-validating-webhook-tls-credentials=other-secret",
+			},
+			expectedValue: &WebhookConfig{
+				Enabled:              true,
+				Port:                 7443,
+				TlsCredentialsSecret: "other-secret",
+			},
+		},
+		{
+			name: "bad env vars",
+			env: map[string]string{
+				"SKUPPER_ENABLE_VALIDATING_WEBHOOK": "maybe",
+				"SKUPPER_VALIDATING_WEBHOOK_PORT":   "a",
+			},
+			expectedErrors: []string{
+				"SKUPPER_ENABLE_VALIDATING_WEBHOOK",
+				"SKUPPER_VALIDATING_WEBHOOK_PORT",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := &flag.FlagSet{}
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			config, err := BoundWebhookConfig(flags)
+			if len(tt.expectedErrors) > 0 {
+				for _, expected := range tt.expectedErrors {
+					assert.ErrorContains(t, err, expected)
+				}
+				return
+			}
+			assert.Assert(t, err)
+			flags.Parse(tt.args)
+			assert.DeepEqual(t, config, tt.expectedValue)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"fmt"
+	"log"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/skupperproject/skupper/internal/kube/watchers"
+	skupperclient "github.com/skupperproject/skupper/pkg/generated/client/clientset/versioned"
+)
+
+type webhookEnabled struct {
+	server        *Server
+	secretWatcher *watchers.SecretWatcher
+}
+
+// Initialise sets up the validating webhook if enabled in the
+// config, returning a function that starts it, or nil if it is not
+// enabled.
+func Initialise(events *watchers.EventProcessor, namespace string, config *WebhookConfig, client skupperclient.Interface, isValidAccessType AccessTypeCheck) func() {
+	if !config.Enabled {
+		return nil
+	}
+	w := &webhookEnabled{
+		server: newServer(config.addr(), &Handler{validator: NewValidator(client, isValidAccessType)}),
+	}
+	w.secretWatcher = events.WatchSecrets(watchers.ByName(config.TlsCredentialsSecret), namespace, w.tlsCredentialsUpdated)
+	return w.start
+}
+
+func (w *webhookEnabled) start() {
+	for _, secret := range w.secretWatcher.List() {
+		w.tlsCredentialsUpdated(fmt.Sprintf("%s/%s", secret.Namespace, secret.Name), secret)
+	}
+	w.server.start()
+}
+
+func (w *webhookEnabled) tlsCredentialsUpdated(key string, secret *corev1.Secret) error {
+	if secret == nil {
+		return nil
+	}
+	if err := w.server.setCertificateFromSecret(secret); err != nil {
+		log.Printf("Could not set certificate for validating webhook from %s: %s", key, err)
+		return nil
+	}
+	log.Print("Validating webhook tls credentials updated")
+	return nil
+}
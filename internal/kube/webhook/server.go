@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/skupperproject/skupper/internal/utils/tlscfg"
+)
+
+// Handler serves AdmissionReview requests, responding with the
+// outcome of validating the object under review.
+type Handler struct {
+	validator *Validator
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	review := &admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(body, review); err != nil || review.Request == nil {
+		http.Error(w, "Invalid AdmissionReview", http.StatusBadRequest)
+		return
+	}
+	review.Response = h.review(review.Request)
+	review.Request = nil
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		log.Printf("Failed to write admission response: %s", err)
+	}
+}
+
+func (h *Handler) review(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	response := &admissionv1.AdmissionResponse{
+		UID:     request.UID,
+		Allowed: true,
+	}
+	if err := h.validator.Validate(request); err != nil {
+		response.Allowed = false
+		response.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Reason:  metav1.StatusReasonInvalid,
+			Code:    http.StatusUnprocessableEntity,
+			Message: fmt.Sprintf("Invalid %s %s: %s", request.Kind.Kind, request.Name, err),
+		}
+	}
+	return response
+}
+
+type Server struct {
+	lock   sync.RWMutex
+	cert   *tls.Certificate
+	server *http.Server
+}
+
+func newServer(addr string, handler http.Handler) *Server {
+	s := &Server{
+		server: &http.Server{
+			Addr:         addr,
+			Handler:      handler,
+			ReadTimeout:  30 * time.Second,
+			WriteTimeout: 30 * time.Second,
+			TLSConfig:    tlscfg.Modern(),
+		},
+	}
+	s.server.TLSConfig.GetCertificate = s.getCertificate
+	return s
+}
+
+func (s *Server) start() {
+	go func() {
+		log.Printf("Validating webhook listening on %s", s.server.Addr)
+		if err := s.server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			log.Printf("Validating webhook failed: %s", err)
+		}
+	}()
+}
+
+func (s *Server) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.cert == nil {
+		return nil, fmt.Errorf("No TLS credentials available")
+	}
+	return s.cert, nil
+}
+
+func (s *Server) setCertificateFromSecret(secret *corev1.Secret) error {
+	cert, err := tls.X509KeyPair(secret.Data["tls.crt"], secret.Data["tls.key"])
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.cert = &cert
+	return nil
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name            string
+		method          string
+		review          *admissionv1.AdmissionReview
+		expectedCode    int
+		expectedAllowed bool
+		expectedMessage string
+	}{
+		{
+			name:   "allowed",
+			method: http.MethodPost,
+			review: &admissionv1.AdmissionReview{
+				Request: admissionRequest(t, admissionv1.Create, listener("db", "database", "", "database", 5432)),
+			},
+			expectedCode:    http.StatusOK,
+			expectedAllowed: true,
+		},
+		{
+			name:   "denied",
+			method: http.MethodPost,
+			review: &admissionv1.AdmissionReview{
+				Request: admissionRequest(t, admissionv1.Update, listener("db", "database", "", "database", 0)),
+			},
+			expectedCode:    http.StatusOK,
+			expectedMessage: "Invalid Listener db: invalid port 0: must be between 1 and 65535",
+		},
+		{
+			name:         "no request",
+			method:       http.MethodPost,
+			review:       &admissionv1.AdmissionReview{},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "wrong method",
+			method:       http.MethodGet,
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &Handler{validator: NewValidator(nil, nil)}
+			body, err := json.Marshal(tt.review)
+			assert.Assert(t, err)
+			req := httptest.NewRequest(tt.method, "/validate", bytes.NewReader(body))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, rec.Code, tt.expectedCode)
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+			response := &admissionv1.AdmissionReview{}
+			assert.Assert(t, json.Unmarshal(rec.Body.Bytes(), response))
+			assert.Assert(t, response.Response != nil)
+			assert.Equal(t, response.Response.UID, tt.review.Request.UID)
+			assert.Equal(t, response.Response.Allowed, tt.expectedAllowed)
+			if tt.expectedMessage != "" {
+				assert.Equal(t, response.Response.Result.Message, tt.expectedMessage)
+			}
+		})
+	}
+}
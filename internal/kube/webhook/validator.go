@@ -0,0 +1,281 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/skupperproject/skupper/internal/kube/grants"
+	"github.com/skupperproject/skupper/internal/site"
+	"github.com/skupperproject/skupper/internal/utils/validator"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	skupperclient "github.com/skupperproject/skupper/pkg/generated/client/clientset/versioned"
+)
+
+type AccessTypeCheck func(string) bool
+
+// Validator applies the checks that would otherwise only be made
+// when a resource is reconciled, so that invalid resources can be
+// rejected when they are created or updated.
+type Validator struct {
+	client            skupperclient.Interface
+	isValidAccessType AccessTypeCheck
+}
+
+func NewValidator(client skupperclient.Interface, isValidAccessType AccessTypeCheck) *Validator {
+	return &Validator{
+		client:            client,
+		isValidAccessType: isValidAccessType,
+	}
+}
+
+// Validate returns an error describing why the object in the request
+// is not acceptable, or nil if it may be admitted.
+func (v *Validator) Validate(request *admissionv1.AdmissionRequest) error {
+	if request.Operation == admissionv1.Delete {
+		return nil
+	}
+	switch request.Kind.Kind {
+	case "Site":
+		obj := &skupperv2alpha1.Site{}
+		if err := json.Unmarshal(request.Object.Raw, obj); err != nil {
+			return err
+		}
+		return v.validateSite(obj)
+	case "Listener":
+		obj := &skupperv2alpha1.Listener{}
+		if err := json.Unmarshal(request.Object.Raw, obj); err != nil {
+			return err
+		}
+		return v.validateListener(request.Namespace, obj)
+	case "Connector":
+		obj := &skupperv2alpha1.Connector{}
+		if err := json.Unmarshal(request.Object.Raw, obj); err != nil {
+			return err
+		}
+		return v.validateConnector(request.Namespace, obj)
+	case "Link":
+		obj := &skupperv2alpha1.Link{}
+		if err := json.Unmarshal(request.Object.Raw, obj); err != nil {
+			return err
+		}
+		return validateLink(obj)
+	case "AccessGrant":
+		obj := &skupperv2alpha1.AccessGrant{}
+		if err := json.Unmarshal(request.Object.Raw, obj); err != nil {
+			return err
+		}
+		return grants.ValidateAccessGrant(obj)
+	case "RouterAccess":
+		obj := &skupperv2alpha1.RouterAccess{}
+		if err := json.Unmarshal(request.Object.Raw, obj); err != nil {
+			return err
+		}
+		return v.validateRouterAccess(obj)
+	case "SecuredAccess":
+		obj := &skupperv2alpha1.SecuredAccess{}
+		if err := json.Unmarshal(request.Object.Raw, obj); err != nil {
+			return err
+		}
+		return v.validateSecuredAccess(obj)
+	}
+	return nil
+}
+
+func (v *Validator) validateSite(obj *skupperv2alpha1.Site) error {
+	var errs []error
+	if err := site.ValidateSiteSettings(obj.Spec.Settings); err != nil {
+		errs = append(errs, err)
+	}
+	switch obj.Spec.LinkAccess {
+	case "", "none", "default":
+	default:
+		if !v.validAccessType(obj.Spec.LinkAccess) {
+			errs = append(errs, fmt.Errorf("Unsupported value for LinkAccess: %s", obj.Spec.LinkAccess))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (v *Validator) validateListener(namespace string, obj *skupperv2alpha1.Listener) error {
+	var errs []error
+	if obj.Spec.RoutingKey == "" {
+		errs = append(errs, fmt.Errorf("routingKey is required"))
+	}
+	if obj.Spec.Host == "" {
+		errs = append(errs, fmt.Errorf("host is required"))
+	} else if err := site.ValidateHost(obj.Spec.Host); err != nil {
+		errs = append(errs, fmt.Errorf("invalid host %q: %w", obj.Spec.Host, err))
+	}
+	if len(obj.Spec.Ports) == 0 {
+		if err := validatePort(obj.Spec.Port); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := site.ValidateListenerPorts(obj); err != nil {
+		errs = append(errs, err)
+	}
+	if err := site.ValidateLoadBalancing(obj); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		keys := map[string]string{}
+		for _, l := range obj.PortListeners() {
+			keys[l.Spec.RoutingKey] = bindingType(obj.Spec.Type)
+		}
+		if err := v.checkRoutingKeyTypes(namespace, "Listener", obj.Name, keys); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (v *Validator) validateConnector(namespace string, obj *skupperv2alpha1.Connector) error {
+	var errs []error
+	if obj.Spec.RoutingKey == "" {
+		errs = append(errs, fmt.Errorf("routingKey is required"))
+	}
+	if obj.Spec.Host == "" && obj.Spec.Selector == "" {
+		errs = append(errs, fmt.Errorf("host or selector is required"))
+	} else if obj.Spec.Host != "" {
+		if err := site.ValidateHost(obj.Spec.Host); err != nil {
+			errs = append(errs, fmt.Errorf("invalid host %q: %w", obj.Spec.Host, err))
+		}
+	}
+	if len(obj.Spec.Ports) == 0 {
+		if err := validatePort(obj.Spec.Port); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := site.ValidateConnectorPorts(obj); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		keys := map[string]string{}
+		for _, c := range obj.PortConnectors() {
+			keys[c.Spec.RoutingKey] = bindingType(obj.Spec.Type)
+		}
+		if err := v.checkRoutingKeyTypes(namespace, "Connector", obj.Name, keys); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Checks that none of the routing keys of a listener or connector are
+// already in use in the namespace by a listener or connector of a
+// different type.
+func (v *Validator) checkRoutingKeyTypes(namespace string, kind string, name string, keys map[string]string) error {
+	if v.client == nil {
+		return nil
+	}
+	var errs []error
+	check := func(otherKind string, otherName string, routingKey string, otherType string) {
+		if otherKind == kind && otherName == name {
+			return
+		}
+		if t, ok := keys[routingKey]; ok && t != otherType {
+			errs = append(errs, fmt.Errorf("routingKey %q is already used with type %s by %s %s", routingKey, otherType, otherKind, otherName))
+		}
+	}
+	listeners, err := v.client.SkupperV2alpha1().Listeners(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range listeners.Items {
+		for _, l := range listeners.Items[i].PortListeners() {
+			check("Listener", listeners.Items[i].Name, l.Spec.RoutingKey, bindingType(l.Spec.Type))
+		}
+	}
+	connectors, err := v.client.SkupperV2alpha1().Connectors(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range connectors.Items {
+		for _, c := range connectors.Items[i].PortConnectors() {
+			check("Connector", connectors.Items[i].Name, c.Spec.RoutingKey, bindingType(c.Spec.Type))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateLink(obj *skupperv2alpha1.Link) error {
+	var errs []error
+	if len(obj.Spec.Endpoints) == 0 {
+		errs = append(errs, fmt.Errorf("at least one endpoint is required"))
+	}
+	for i, endpoint := range obj.Spec.Endpoints {
+		if endpoint.Host == "" {
+			errs = append(errs, fmt.Errorf("endpoints[%d]: host is required", i))
+		}
+		if port, err := strconv.Atoi(endpoint.Port); err != nil {
+			errs = append(errs, fmt.Errorf("endpoints[%d]: invalid port %q", i, endpoint.Port))
+		} else if err := validatePort(port); err != nil {
+			errs = append(errs, fmt.Errorf("endpoints[%d]: %w", i, err))
+		}
+	}
+	if ok, _ := validator.NewNumberValidator().Evaluate(obj.Spec.Cost); !ok {
+		errs = append(errs, fmt.Errorf("invalid cost %d: must not be negative", obj.Spec.Cost))
+	}
+	return errors.Join(errs...)
+}
+
+func (v *Validator) validateRouterAccess(obj *skupperv2alpha1.RouterAccess) error {
+	var errs []error
+	if err := site.ValidateRouterAccessRoles(obj); err != nil {
+		errs = append(errs, err)
+	}
+	if obj.Spec.AccessType != "" && !v.validAccessType(obj.Spec.AccessType) {
+		errs = append(errs, fmt.Errorf("Unsupported access type: %s", obj.Spec.AccessType))
+	}
+	return errors.Join(errs...)
+}
+
+func (v *Validator) validateSecuredAccess(obj *skupperv2alpha1.SecuredAccess) error {
+	var errs []error
+	if len(obj.Spec.Ports) == 0 {
+		errs = append(errs, fmt.Errorf("at least one port is required"))
+	}
+	names := map[string]bool{}
+	for i, port := range obj.Spec.Ports {
+		if ok, err := validator.NewResourceStringValidator().Evaluate(port.Name); !ok {
+			errs = append(errs, fmt.Errorf("ports[%d]: invalid name %q: %s", i, port.Name, err))
+		} else if names[port.Name] {
+			errs = append(errs, fmt.Errorf("ports[%d]: duplicate name %q", i, port.Name))
+		}
+		names[port.Name] = true
+		if err := validatePort(port.Port); err != nil {
+			errs = append(errs, fmt.Errorf("ports[%d]: %w", i, err))
+		}
+	}
+	if obj.Spec.AccessType != "" && !v.validAccessType(obj.Spec.AccessType) {
+		errs = append(errs, fmt.Errorf("Unsupported access type: %s", obj.Spec.AccessType))
+	}
+	return errors.Join(errs...)
+}
+
+func (v *Validator) validAccessType(accessType string) bool {
+	if v.isValidAccessType == nil {
+		return true
+	}
+	return v.isValidAccessType(accessType)
+}
+
+func validatePort(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port %d: must be between 1 and 65535", port)
+	}
+	return nil
+}
+
+func bindingType(t string) string {
+	if t == "" {
+		return "tcp"
+	}
+	return t
+}
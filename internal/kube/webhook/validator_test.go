@@ -0,0 +1,267 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	fakeclient "github.com/skupperproject/skupper/internal/kube/client/fake"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+func TestValidator(t *testing.T) {
+	tests := []struct {
+		name           string
+		skupperObjects []runtime.Object
+		operation      admissionv1.Operation
+		obj            interface{}
+		expectedErrors []string
+	}{
+		{
+			name: "valid site",
+			obj: &skupperv2alpha1.Site{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-site", Namespace: "test"},
+				Spec: skupperv2alpha1.SiteSpec{
+					LinkAccess: "default",
+					Settings: map[string]string{
+						"size":           "large",
+						"router-logging": "debug",
+					},
+				},
+			},
+		},
+		{
+			name: "site with unknown setting",
+			obj: &skupperv2alpha1.Site{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-site", Namespace: "test"},
+				Spec: skupperv2alpha1.SiteSpec{
+					Settings: map[string]string{
+						"sise":                         "large",
+						"router-data-connection-count": "0",
+					},
+				},
+			},
+			expectedErrors: []string{`unknown setting "sise"`, "invalid value for router-data-connection-count"},
+		},
+		{
+			name: "site with unsupported link access",
+			obj: &skupperv2alpha1.Site{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-site", Namespace: "test"},
+				Spec: skupperv2alpha1.SiteSpec{
+					LinkAccess: "teleport",
+				},
+			},
+			expectedErrors: []string{"Unsupported value for LinkAccess: teleport"},
+		},
+		{
+			name: "valid listener",
+			obj:  listener("db", "database", "tcp", "database", 5432),
+		},
+		{
+			name:           "listener with port zero",
+			obj:            listener("db", "database", "tcp", "database", 0),
+			expectedErrors: []string{"invalid port 0"},
+		},
+		{
+			name:           "listener without routing key or host",
+			obj:            listener("db", "", "tcp", "", 5432),
+			expectedErrors: []string{"routingKey is required", "host is required"},
+		},
+		{
+			name:           "listener with invalid host",
+			obj:            listener("db", "database", "tcp", "Not_A_Host", 5432),
+			expectedErrors: []string{`invalid host "Not_A_Host"`},
+		},
+		{
+			name:           "listener with conflicting type",
+			skupperObjects: []runtime.Object{connector("db", "database", "udp", "10.0.0.1", 5432)},
+			obj:            listener("db", "database", "", "database", 5432),
+			expectedErrors: []string{`routingKey "database" is already used with type udp by Connector db`},
+		},
+		{
+			name:           "listener with matching type",
+			skupperObjects: []runtime.Object{connector("db", "database", "tcp", "10.0.0.1", 5432)},
+			obj:            listener("db", "database", "", "database", 5432),
+		},
+		{
+			name:           "update of listener changing type",
+			skupperObjects: []runtime.Object{listener("db", "database", "tcp", "database", 5432)},
+			obj:            listener("db", "database", "udp", "database", 5432),
+		},
+		{
+			name: "connector with selector",
+			obj: &skupperv2alpha1.Connector{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "test"},
+				Spec: skupperv2alpha1.ConnectorSpec{
+					RoutingKey: "database",
+					Selector:   "app=db",
+					Port:       5432,
+				},
+			},
+		},
+		{
+			name:           "connector without host or selector",
+			obj:            connector("db", "database", "", "", 5432),
+			expectedErrors: []string{"host or selector is required"},
+		},
+		{
+			name:           "connector with invalid port",
+			obj:            connector("db", "database", "", "10.0.0.1", 70000),
+			expectedErrors: []string{"invalid port 70000"},
+		},
+		{
+			name:           "connector with conflicting type",
+			skupperObjects: []runtime.Object{listener("db", "database", "udp", "database", 5432)},
+			obj:            connector("db", "database", "tcp", "10.0.0.1", 5432),
+			expectedErrors: []string{`routingKey "database" is already used with type udp by Listener db`},
+		},
+		{
+			name: "valid link",
+			obj: &skupperv2alpha1.Link{
+				ObjectMeta: metav1.ObjectMeta{Name: "link", Namespace: "test"},
+				Spec: skupperv2alpha1.LinkSpec{
+					Endpoints: []skupperv2alpha1.Endpoint{
+						{Name: "inter-router", Host: "a.example.com", Port: "55671"},
+					},
+					Cost: 1,
+				},
+			},
+		},
+		{
+			name: "invalid link",
+			obj: &skupperv2alpha1.Link{
+				ObjectMeta: metav1.ObjectMeta{Name: "link", Namespace: "test"},
+				Spec: skupperv2alpha1.LinkSpec{
+					Endpoints: []skupperv2alpha1.Endpoint{
+						{Name: "inter-router", Port: "abc"},
+					},
+					Cost: -1,
+				},
+			},
+			expectedErrors: []string{"endpoints[0]: host is required", `endpoints[0]: invalid port "abc"`, "invalid cost -1"},
+		},
+		{
+			name: "access grant with invalid expiration window",
+			obj: &skupperv2alpha1.AccessGrant{
+				ObjectMeta: metav1.ObjectMeta{Name: "grant", Namespace: "test"},
+				Spec: skupperv2alpha1.AccessGrantSpec{
+					ExpirationWindow: "ten minutes",
+				},
+			},
+			expectedErrors: []string{`Invalid duration "ten minutes"`},
+		},
+		{
+			name: "router access with invalid role",
+			obj: &skupperv2alpha1.RouterAccess{
+				ObjectMeta: metav1.ObjectMeta{Name: "ra", Namespace: "test"},
+				Spec: skupperv2alpha1.RouterAccessSpec{
+					AccessType: "teleport",
+					Roles: []skupperv2alpha1.RouterAccessRole{
+						{Name: "interior"},
+					},
+				},
+			},
+			expectedErrors: []string{"invalid role: interior", "Unsupported access type: teleport"},
+		},
+		{
+			name: "secured access with duplicate ports",
+			obj: &skupperv2alpha1.SecuredAccess{
+				ObjectMeta: metav1.ObjectMeta{Name: "sa", Namespace: "test"},
+				Spec: skupperv2alpha1.SecuredAccessSpec{
+					Ports: []skupperv2alpha1.SecuredAccessPort{
+						{Name: "api", Port: 8080},
+						{Name: "api", Port: 0},
+					},
+				},
+			},
+			expectedErrors: []string{`ports[1]: duplicate name "api"`, "ports[1]: invalid port 0"},
+		},
+		{
+			name:      "deletion is always allowed",
+			operation: admissionv1.Delete,
+			obj:       listener("db", "", "", "", 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := fakeclient.NewFakeClient("test", nil, tt.skupperObjects, "")
+			assert.Assert(t, err)
+			v := NewValidator(client.GetSkupperClient(), func(accessType string) bool {
+				return accessType == "loadbalancer" || accessType == "route"
+			})
+			err = v.Validate(admissionRequest(t, tt.operation, tt.obj))
+			if len(tt.expectedErrors) == 0 {
+				assert.NilError(t, err)
+			} else {
+				for _, expected := range tt.expectedErrors {
+					assert.ErrorContains(t, err, expected)
+				}
+			}
+		})
+	}
+}
+
+func admissionRequest(t *testing.T, operation admissionv1.Operation, obj interface{}) *admissionv1.AdmissionRequest {
+	raw, err := json.Marshal(obj)
+	assert.Assert(t, err)
+	if operation == "" {
+		operation = admissionv1.Create
+	}
+	meta := obj.(metav1.Object)
+	return &admissionv1.AdmissionRequest{
+		UID:       "12345",
+		Kind:      metav1.GroupVersionKind{Group: "skupper.io", Version: "v2alpha1", Kind: kindOf(obj)},
+		Name:      meta.GetName(),
+		Namespace: meta.GetNamespace(),
+		Operation: operation,
+		Object:    runtime.RawExtension{Raw: raw},
+	}
+}
+
+func kindOf(obj interface{}) string {
+	switch obj.(type) {
+	case *skupperv2alpha1.Site:
+		return "Site"
+	case *skupperv2alpha1.Listener:
+		return "Listener"
+	case *skupperv2alpha1.Connector:
+		return "Connector"
+	case *skupperv2alpha1.Link:
+		return "Link"
+	case *skupperv2alpha1.AccessGrant:
+		return "AccessGrant"
+	case *skupperv2alpha1.RouterAccess:
+		return "RouterAccess"
+	case *skupperv2alpha1.SecuredAccess:
+		return "SecuredAccess"
+	}
+	return ""
+}
+
+func listener(name string, routingKey string, protocol string, host string, port int) *skupperv2alpha1.Listener {
+	return &skupperv2alpha1.Listener{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec: skupperv2alpha1.ListenerSpec{
+			RoutingKey: routingKey,
+			Host:       host,
+			Port:       port,
+			Type:       protocol,
+		},
+	}
+}
+
+func connector(name string, routingKey string, protocol string, host string, port int) *skupperv2alpha1.Connector {
+	return &skupperv2alpha1.Connector{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec: skupperv2alpha1.ConnectorSpec{
+			RoutingKey: routingKey,
+			Host:       host,
+			Port:       port,
+			Type:       protocol,
+		},
+	}
+}
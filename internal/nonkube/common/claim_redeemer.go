@@ -60,7 +60,9 @@ func redeemAccessToken(claim *skupperv2alpha1.AccessToken, siteState *api.SiteSt
 		return err
 	}
 	request.Header.Add("name", claim.Name)
-	request.Header.Add("subject", string(siteState.Site.Name))
+	// identifies the site by its id, as sites on kubernetes do
+	request.Header.Add("subject", siteState.GetSiteId())
+	request.Header.Add("site-name", siteState.Site.Name)
 	response, err := client.Do(request)
 	if err != nil {
 		return err
@@ -0,0 +1,100 @@
+package common
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+const redeemedLinkConfig = `---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: my-token
+data:
+  tls.crt: ""
+---
+apiVersion: skupper.io/v2alpha1
+kind: Link
+metadata:
+  name: my-token
+spec:
+  tlsCredentials: my-token
+`
+
+func TestRedeemClaims(t *testing.T) {
+	var headers http.Header
+	var code string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		body, _ := io.ReadAll(r.Body)
+		code = string(body)
+		io.WriteString(w, redeemedLinkConfig)
+	}))
+	defer srv.Close()
+
+	ss := fakeSiteState()
+	ss.Claims = map[string]*v2alpha1.AccessToken{
+		"my-token": {
+			ObjectMeta: metav1.ObjectMeta{Name: "my-token"},
+			Spec: v2alpha1.AccessTokenSpec{
+				Url:  srv.URL,
+				Code: "supersecret",
+			},
+		},
+	}
+	assert.NilError(t, RedeemClaims(ss))
+	assert.Equal(t, code, "supersecret")
+	assert.Equal(t, headers.Get("name"), "my-token")
+	assert.Equal(t, headers.Get("subject"), "site-id")
+	assert.Equal(t, headers.Get("site-name"), "site-name")
+	assert.Assert(t, ss.Secrets["my-token"] != nil)
+	assert.Assert(t, ss.Links["my-token"] != nil)
+}
+
+func TestRedeemClaimsAssignsSiteId(t *testing.T) {
+	var subject string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject = r.Header.Get("subject")
+		io.WriteString(w, redeemedLinkConfig)
+	}))
+	defer srv.Close()
+
+	ss := fakeSiteState()
+	ss.SiteId = ""
+	ss.Claims = map[string]*v2alpha1.AccessToken{
+		"my-token": {
+			ObjectMeta: metav1.ObjectMeta{Name: "my-token"},
+			Spec:       v2alpha1.AccessTokenSpec{Url: srv.URL, Code: "supersecret"},
+		},
+	}
+	assert.NilError(t, RedeemClaims(ss))
+	assert.Assert(t, subject != "")
+	// the router is configured with the id the claim was redeemed with
+	assert.Equal(t, ss.SiteId, subject)
+	assert.Equal(t, ss.GetSiteId(), subject)
+}
+
+func TestRedeemClaimsRefused(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "site name \"site-name\" does not match pattern \"west-*\"", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	ss := fakeSiteState()
+	ss.Claims = map[string]*v2alpha1.AccessToken{
+		"my-token": {
+			ObjectMeta: metav1.ObjectMeta{Name: "my-token"},
+			Spec:       v2alpha1.AccessTokenSpec{Url: srv.URL, Code: "supersecret"},
+		},
+	}
+	assert.ErrorContains(t, RedeemClaims(ss), "failed to redeem 1 claims")
+	_, ok := ss.Links["my-token"]
+	assert.Assert(t, !ok)
+}
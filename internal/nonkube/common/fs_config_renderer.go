@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/skupperproject/skupper/internal/certs"
 	"github.com/skupperproject/skupper/internal/qdr"
@@ -132,6 +133,9 @@ func (c *FileSystemConfigurationRenderer) Render(siteState *api.SiteState) error
 	siteState.Site.SetRunning(v2alpha1.ReadyCondition())
 	endpoints := make([]v2alpha1.Endpoint, 0)
 	for raName, ra := range siteState.RouterAccesses {
+		if siteState.RouterAccessPermitted(ra) != nil {
+			continue
+		}
 		for _, role := range ra.Spec.Roles {
 			if role.Name != "normal" {
 				logger.Debug("site endpoint configured:",
@@ -269,6 +273,12 @@ func (c *FileSystemConfigurationRenderer) createTokens(siteState *api.SiteState)
 		if noInterRouterRole && noEdgeRole {
 			continue
 		}
+		if err := siteState.RouterAccessPermitted(linkAccess); err != nil {
+			logger.Warn("Not creating tokens for router access",
+				slog.String("name", name),
+				slog.Any("reason", err))
+			continue
+		}
 		certName := name
 		if linkAccess.Spec.TlsCredentials != "" {
 			certName = linkAccess.Spec.TlsCredentials
@@ -365,6 +375,11 @@ func (c *FileSystemConfigurationRenderer) createTlsCertificates(siteState *api.S
 	writeSecretFiles := func(basePath string, secret *corev1.Secret) error {
 		return writeSecretFilesIgnore(basePath, secret, false)
 	}
+	renewal, err := certs.RenewalConfigFromSettings(siteState.Site.Spec.Settings)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
 	// create certificate authorities first
 	outputPath := c.GetOutputPath(siteState)
 	for name, certificate := range siteState.Certificates {
@@ -380,43 +395,64 @@ func (c *FileSystemConfigurationRenderer) createTlsCertificates(siteState *api.S
 			ignoreExisting = false
 			secret = *userCaSecret
 			fmt.Printf("-> User provided CA found: %s\n", name)
+		} else if existing, err := c.loadCASecret(siteState, name); err == nil {
+			if rotated := rotateCA(certificate, existing, renewal, now); rotated != nil {
+				ignoreExisting = false
+				secret = *rotated
+			}
 		}
 		caPath := path.Join(outputPath, string(api.IssuersPath), name)
 		err = writeSecretFilesIgnore(caPath, &secret, ignoreExisting)
 		if err != nil {
 			return err
 		}
+		for _, previous := range []string{certs.PreviousCaCertificate, certs.PreviousCaKey} {
+			if _, ok := secret.Data[previous]; !ok && !ignoreExisting {
+				if err := os.Remove(path.Join(caPath, previous)); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("unable to remove %s: %v", previous, err)
+				}
+			}
+		}
+		if caSecret, err := c.loadCASecret(siteState, name); err == nil {
+			setExpiration(certificate, caSecret, renewal.CaRenewBefore, now)
+		}
 	}
 	// generate all other certificates now
 	for name, certificate := range siteState.Certificates {
 		var purpose string
 		var secret corev1.Secret
 		var caSecret *corev1.Secret
+		var signer *corev1.Secret
 		if certificate.Spec.Ca != "" {
 			caSecret, err = c.loadCASecret(siteState, certificate.Spec.Ca)
 			if err != nil {
 				return fmt.Errorf("unable to load CA secret %s: %v", certificate.Spec.Ca, err)
 			}
+			signer = certs.SigningCA(caSecret, renewal.CaRotationOverlap, now)
 		}
 		if certificate.Spec.Client {
 			purpose = "client"
-			secret = certs.GenerateSecret(name, certificate.Spec.Subject, strings.Join(certificate.Spec.Hosts, ","), 0, caSecret)
+			secret = certs.GenerateSecret(name, certificate.Spec.Subject, strings.Join(certificate.Spec.Hosts, ","), renewal.Duration, signer)
 			// TODO Not sure if connect.json is needed (probably need to get rid of it)
 			if connectJson := c.connectJson(siteState); connectJson != nil {
 				secret.Data["connect.json"] = []byte(*connectJson)
 			}
 		} else if certificate.Spec.Server {
 			purpose = "server"
-			secret = certs.GenerateSecret(name, certificate.Spec.Subject, strings.Join(certificate.Spec.Hosts, ","), 0, caSecret)
+			secret = certs.GenerateSecret(name, certificate.Spec.Subject, strings.Join(certificate.Spec.Hosts, ","), renewal.Duration, signer)
 		} else {
 			continue
 		}
+		if caSecret != nil {
+			secret.Data["ca.crt"] = certs.TrustBundle(caSecret)
+		}
 		userSecret, err := c.loadUserCertAsSecret(siteState, purpose, name)
 		if userSecret != nil && err == nil {
 			// override with user provided secret
 			secret = *userSecret
 			fmt.Printf("-> User provided %s certificate found: %s\n", purpose, name)
 		}
+		setExpiration(certificate, &secret, renewal.RenewBefore, now)
 		certPath := path.Join(outputPath, string(api.CertificatesPath), name)
 		err = writeSecretFiles(certPath, &secret)
 		if err != nil {
@@ -439,6 +475,46 @@ func (c *FileSystemConfigurationRenderer) createTlsCertificates(siteState *api.S
 	return nil
 }
 
+// rotateCA returns a replacement for the existing CA if it is due to
+// expire, or the existing CA without the one it replaced once the
+// rotation overlap has ended. If neither applies it returns nil.
+func rotateCA(certificate *v2alpha1.Certificate, existing *corev1.Secret, renewal certs.RenewalConfig, now time.Time) *corev1.Secret {
+	logger := NewLogger()
+	cert, err := certs.DecodeCertificate(existing.Data["tls.crt"])
+	if err != nil {
+		return nil
+	}
+	if certs.RenewalDue(cert, renewal.CaRenewBefore, now) {
+		logger.Info("rotating CA",
+			slog.String("name", certificate.Name),
+			slog.String("expiration", cert.NotAfter.Format(time.RFC3339)),
+			slog.String("trustedUntil", now.Add(renewal.CaRotationOverlap).Format(time.RFC3339)))
+		rotated := certs.RotateCA(certificate.Name, certificate.Spec.Subject, existing)
+		return &rotated
+	}
+	if certs.EndCARotation(existing, renewal.CaRotationOverlap, now) {
+		logger.Info("CA rotation complete", slog.String("name", certificate.Name))
+		return existing
+	}
+	return nil
+}
+
+// setExpiration records the expiration of the certificate in the
+// supplied secret on the Certificate's status, warning if it is due
+// for renewal.
+func setExpiration(certificate *v2alpha1.Certificate, secret *corev1.Secret, renewBefore time.Duration, now time.Time) {
+	cert, err := certs.DecodeCertificate(secret.Data["tls.crt"])
+	if err != nil {
+		return
+	}
+	certificate.SetExpiration(cert.NotAfter, certs.RenewalTime(cert, renewBefore), now)
+	if !certificate.IsValid() {
+		NewLogger().Warn("certificate requires renewal",
+			slog.String("name", certificate.Name),
+			slog.String("expiration", certificate.Status.Expiration))
+	}
+}
+
 func (c *FileSystemConfigurationRenderer) connectJson(siteState *api.SiteState) *string {
 	var host string
 	port := 0
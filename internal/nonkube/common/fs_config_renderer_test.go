@@ -5,6 +5,7 @@ import (
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/skupperproject/skupper/api/types"
 	"github.com/skupperproject/skupper/internal/certs"
@@ -94,6 +95,55 @@ func testFileSystemConfigurationRendererRender(t *testing.T, addInputCertificate
 	}
 }
 
+func TestFileSystemConfigurationRenderer_RotateCA(t *testing.T) {
+	ss := fakeSiteState()
+	ss.CreateLinkAccessesCertificates()
+	customOutputPath, err := os.MkdirTemp("", "fs-config-renderer-*")
+	assert.Assert(t, err)
+	defer os.RemoveAll(customOutputPath)
+	fsConfigRenderer := new(FileSystemConfigurationRenderer)
+	fsConfigRenderer.customOutputPath = customOutputPath
+	outputPath := fsConfigRenderer.GetOutputPath(ss)
+	caPath := path.Join(outputPath, string(api.IssuersPath), "skupper-site-ca")
+	serverPath := path.Join(outputPath, string(api.CertificatesPath), "link-access-one")
+	readCert := func(dir string, name string) []byte {
+		data, err := os.ReadFile(path.Join(dir, name))
+		assert.Assert(t, err)
+		return data
+	}
+
+	// replace the generated CA with one that is about to expire
+	assert.Assert(t, fsConfigRenderer.Render(ss))
+	expiring := certs.GenerateSecret("skupper-site-ca", "skupper-site-ca", "", time.Hour, nil)
+	for name, data := range expiring.Data {
+		assert.Assert(t, os.WriteFile(path.Join(caPath, name), data, 0640))
+	}
+
+	assert.Assert(t, fsConfigRenderer.Render(ss))
+	assert.Equal(t, len(certs.DecodeCertificates(readCert(caPath, "ca.crt"))), 2)
+	assert.DeepEqual(t, readCert(caPath, certs.PreviousCaCertificate), expiring.Data["tls.crt"])
+	assert.DeepEqual(t, readCert(serverPath, "ca.crt"), readCert(caPath, "ca.crt"))
+	server, err := certs.DecodeCertificate(readCert(serverPath, "tls.crt"))
+	assert.Assert(t, err)
+	assert.Assert(t, certs.IssuedBy(server, expiring.Data["tls.crt"]), "certificates are issued by the previous CA during the overlap")
+	ca := ss.Certificates["skupper-site-ca"]
+	assert.Assert(t, ca.IsValid())
+	caCert, err := certs.DecodeCertificate(readCert(caPath, "tls.crt"))
+	assert.Assert(t, err)
+	assert.Equal(t, ca.Status.Expiration, caCert.NotAfter.UTC().Format(time.RFC3339))
+
+	// end the overlap
+	ss.Site.Spec.Settings = map[string]string{"ca-rotation-overlap": "0s"}
+	assert.Assert(t, fsConfigRenderer.Render(ss))
+	assert.DeepEqual(t, readCert(caPath, "ca.crt"), readCert(caPath, "tls.crt"))
+	_, err = os.Stat(path.Join(caPath, certs.PreviousCaKey))
+	assert.Assert(t, os.IsNotExist(err))
+	server, err = certs.DecodeCertificate(readCert(serverPath, "tls.crt"))
+	assert.Assert(t, err)
+	assert.Assert(t, certs.IssuedBy(server, readCert(caPath, "tls.crt")))
+	assert.DeepEqual(t, readCert(serverPath, "ca.crt"), readCert(caPath, "tls.crt"))
+}
+
 func compareCertificates(t *testing.T, customOutputPath string) {
 	caPath := path.Join(customOutputPath, string(api.IssuersPath), "skupper-site-ca")
 	serverPath := path.Join(customOutputPath, string(api.CertificatesPath), "link-access-one")
@@ -84,6 +84,7 @@ func GetNamespacesFound(s *api.SiteState) []string {
 	addNamespacesFromMap(s.Claims, nsMap)
 	addNamespacesFromMap(s.Certificates, nsMap)
 	addNamespacesFromMap(s.SecuredAccesses, nsMap)
+	addNamespacesFromMap(s.AccessPolicies, nsMap)
 	for ns := range nsMap {
 		namespaces = append(namespaces, ns)
 	}
@@ -152,6 +153,10 @@ func LoadIntoSiteState(reader *bufio.Reader, siteState *api.SiteState) error {
 				var securedAccess v2alpha1.SecuredAccess
 				runtime.DefaultUnstructuredConverter.FromUnstructured(obj.(runtime.Unstructured).UnstructuredContent(), &securedAccess)
 				siteState.SecuredAccesses[securedAccess.Name] = &securedAccess
+			case "AccessPolicy":
+				var policy v2alpha1.AccessPolicy
+				runtime.DefaultUnstructuredConverter.FromUnstructured(obj.(runtime.Unstructured).UnstructuredContent(), &policy)
+				siteState.AccessPolicies[policy.Name] = &policy
 			default:
 				logInvalidResource(gvk)
 			}
@@ -23,6 +23,7 @@ func CopySiteState(siteState *api.SiteState) *api.SiteState {
 	activeSiteState.SecuredAccesses = copySiteStateMap(siteState.SecuredAccesses)
 	activeSiteState.Certificates = copySiteStateMap(siteState.Certificates)
 	activeSiteState.Secrets = copySiteStateMap(siteState.Secrets)
+	activeSiteState.AccessPolicies = copySiteStateMap(siteState.AccessPolicies)
 	return activeSiteState
 }
 
@@ -50,6 +51,8 @@ func copySiteStateMap[T any](m map[string]T) map[string]T {
 			c = vv.DeepCopy()
 		case *v2alpha1.SecuredAccess:
 			c = vv.DeepCopy()
+		case *v2alpha1.AccessPolicy:
+			c = vv.DeepCopy()
 		case *corev1.Secret:
 			c = vv.DeepCopy()
 		}
@@ -2,9 +2,10 @@ package common
 
 import (
 	"fmt"
-	"net"
 	"regexp"
 
+	"github.com/skupperproject/skupper/internal/certs"
+	"github.com/skupperproject/skupper/internal/site"
 	"github.com/skupperproject/skupper/internal/utils"
 	"github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
 	"github.com/skupperproject/skupper/pkg/nonkube/api"
@@ -12,9 +13,7 @@ import (
 )
 
 var (
-	validLinkAccessRoles = []string{"edge", "inter-router"}
-	rfc1123Regex         = regexp.MustCompile("^[a-z0-9]([-a-z0-9]*[a-z0-9])?$")
-	hostnameRfc1123Regex = regexp.MustCompile(`^[a-z0-9]+([-.]{1}[a-z0-9]+)*$`)
+	rfc1123Regex = regexp.MustCompile("^[a-z0-9]([-a-z0-9]*[a-z0-9])?$")
 )
 
 const (
@@ -51,6 +50,9 @@ func (s *SiteStateValidator) Validate(siteState *api.SiteState) error {
 	if err = s.validateConnectors(siteState.Connectors); err != nil {
 		return err
 	}
+	if err = s.validateAccessPolicies(siteState.AccessPolicies); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -65,6 +67,12 @@ func (s *SiteStateValidator) validateSite(site *v2alpha1.Site) error {
 	if err := ValidateName(site.Name); err != nil {
 		return fmt.Errorf("invalid site name: %w", err)
 	}
+	if issuer, ok := v2alpha1.ParseIssuerReference(site.Spec.DefaultIssuer); ok {
+		return fmt.Errorf("invalid site default issuer: external issuer %s is only supported on Kubernetes", issuer)
+	}
+	if _, err := certs.RenewalConfigFromSettings(site.Spec.Settings); err != nil {
+		return fmt.Errorf("invalid site settings: %w", err)
+	}
 	return nil
 }
 
@@ -78,14 +86,11 @@ func (s *SiteStateValidator) validateRouterAccesses(routerAccesses map[string]*v
 				return fmt.Errorf("invalid router access tls credentials: %w", err)
 			}
 		}
-		if len(routerAccess.Spec.Roles) == 0 {
-			return fmt.Errorf("invalid router access: %s - roles are required", routerAccess.Name)
+		if issuer, ok := v2alpha1.ParseIssuerReference(routerAccess.Spec.Issuer); ok {
+			return fmt.Errorf("invalid router access: %s - external issuer %s is only supported on Kubernetes", routerAccess.Name, issuer)
 		}
-		for _, role := range routerAccess.Spec.Roles {
-			if !utils.StringSliceContains(validLinkAccessRoles, role.Name) {
-				return fmt.Errorf("invalid router access: %s - invalid role: %s (valid roles: %s)",
-					routerAccess.Name, role.Name, validLinkAccessRoles)
-			}
+		if err := site.ValidateRouterAccessRoles(routerAccess); err != nil {
+			return fmt.Errorf("invalid router access: %s - %w", routerAccess.Name, err)
 		}
 	}
 	return nil
@@ -134,21 +139,24 @@ func (s *SiteStateValidator) validateListeners(listeners map[string]*v2alpha1.Li
 		if err := ValidateName(listener.Name); err != nil {
 			return fmt.Errorf("invalid listener name: %w", err)
 		}
-		if listener.Spec.Host == "" || listener.Spec.Port == 0 {
+		if listener.Spec.Host == "" || (listener.Spec.Port == 0 && len(listener.Spec.Ports) == 0) {
 			return fmt.Errorf("invalid listener: %s - host and port are required", listener.Name)
 		}
-		ip := net.ParseIP(listener.Spec.Host)
-		validHostname := hostnameRfc1123Regex.MatchString(listener.Spec.Host)
-		if ip == nil && !validHostname {
-			return fmt.Errorf("invalid listener host: %s - a valid IP address or hostname is expected (listener: %q)", listener.Spec.Host, name)
+		if err := site.ValidateHost(listener.Spec.Host); err != nil {
+			return fmt.Errorf("invalid listener host: %s - %w (listener: %q)", listener.Spec.Host, err, name)
+		}
+		if err := site.ValidateListenerPorts(listener); err != nil {
+			return fmt.Errorf("invalid listener ports: %w (listener: %q)", err, name)
 		}
-		if utils.IntSliceContains(hostPorts[listener.Spec.Host], listener.Spec.Port) {
-			return fmt.Errorf("port %d is already mapped for host %q (listener: %q)", listener.Spec.Port, listener.Spec.Host, name)
+		for _, l := range listener.PortListeners() {
+			if utils.IntSliceContains(hostPorts[listener.Spec.Host], l.Spec.Port) {
+				return fmt.Errorf("port %d is already mapped for host %q (listener: %q)", l.Spec.Port, listener.Spec.Host, name)
+			}
+			hostPorts[listener.Spec.Host] = append(hostPorts[listener.Spec.Host], l.Spec.Port)
 		}
 		if listener.Spec.RoutingKey == "" {
 			return fmt.Errorf("routingKey is missing for listener: %s", listener.Name)
 		}
-		hostPorts[listener.Spec.Host] = append(hostPorts[listener.Spec.Host], listener.Spec.Port)
 	}
 	return nil
 }
@@ -158,13 +166,14 @@ func (s *SiteStateValidator) validateConnectors(connectors map[string]*v2alpha1.
 		if err := ValidateName(connector.Name); err != nil {
 			return fmt.Errorf("invalid connector name: %w", err)
 		}
-		if connector.Spec.Host == "" || connector.Spec.Port == 0 {
+		if connector.Spec.Host == "" || (connector.Spec.Port == 0 && len(connector.Spec.Ports) == 0) {
 			return fmt.Errorf("connector host and port are required (connector: %q)", connector.Name)
 		}
-		ip := net.ParseIP(connector.Spec.Host)
-		validHostname := hostnameRfc1123Regex.MatchString(connector.Spec.Host)
-		if ip == nil && !validHostname {
-			return fmt.Errorf("invalid connector host: %s - a valid IP address or hostname is expected (connector: %q)", connector.Spec.Host, connector.Name)
+		if err := site.ValidateConnectorPorts(connector); err != nil {
+			return fmt.Errorf("invalid connector ports: %w (connector: %q)", err, connector.Name)
+		}
+		if err := site.ValidateHost(connector.Spec.Host); err != nil {
+			return fmt.Errorf("invalid connector host: %s - %w (connector: %q)", connector.Spec.Host, err, connector.Name)
 		}
 		if connector.Spec.RoutingKey == "" {
 			return fmt.Errorf("routingKey is missing for connector: %s", connector.Name)
@@ -173,6 +182,18 @@ func (s *SiteStateValidator) validateConnectors(connectors map[string]*v2alpha1.
 	return nil
 }
 
+func (s *SiteStateValidator) validateAccessPolicies(policies map[string]*v2alpha1.AccessPolicy) error {
+	for _, policy := range policies {
+		if err := ValidateName(policy.Name); err != nil {
+			return fmt.Errorf("invalid access policy name: %w", err)
+		}
+		if err := site.ValidateAccessPolicy(policy); err != nil {
+			return fmt.Errorf("invalid access policy: %w (access policy: %q)", err, policy.Name)
+		}
+	}
+	return nil
+}
+
 func ValidateName(name string) error {
 	if !rfc1123Regex.MatchString(name) {
 		return fmt.Errorf("invalid name %q: %s", name, rfc1123Error)
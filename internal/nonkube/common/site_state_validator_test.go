@@ -25,6 +25,14 @@ func TestSiteStateValidator_Validate(t *testing.T) {
 			valid:         false,
 			errorContains: "invalid site name:",
 		},
+		{
+			info: "invalid-site-certificate-settings",
+			siteState: customize(func(siteState *api.SiteState) {
+				siteState.Site.Spec.Settings = map[string]string{"ca-renew-before": "90 days"}
+			}),
+			valid:         false,
+			errorContains: "invalid site settings: invalid value for ca-renew-before",
+		},
 		{
 			info: "invalid-link-access-name",
 			siteState: customize(func(siteState *api.SiteState) {
@@ -79,6 +87,17 @@ func TestSiteStateValidator_Validate(t *testing.T) {
 			valid:         false,
 			errorContains: "invalid role: ",
 		},
+		{
+			info: "invalid-link-access-external-issuer",
+			siteState: customize(func(siteState *api.SiteState) {
+				for _, la := range siteState.RouterAccesses {
+					la.Spec.Issuer = "ClusterIssuer.cert-manager.io/corp-pki"
+					break
+				}
+			}),
+			valid:         false,
+			errorContains: "external issuer ClusterIssuer.cert-manager.io/corp-pki is only supported on Kubernetes",
+		},
 		{
 			info: "invalid-links-no-secrets",
 			siteState: customize(func(siteState *api.SiteState) {
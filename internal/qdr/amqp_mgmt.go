@@ -28,12 +28,14 @@ func (r *RouterNode) IsSelf() bool {
 }
 
 type Connection struct {
+	Identity   string `json:"identity"`
 	Container  string `json:"container"`
 	OperStatus string `json:"operStatus"`
 	Host       string `json:"host"`
 	Role       string `json:"role"`
 	Active     bool   `json:"active"`
 	Dir        string `json:"dir"`
+	User       string `json:"user"`
 }
 
 type Agent struct {
@@ -142,12 +144,14 @@ func asTcpEndpoint(record Record) TcpEndpoint {
 
 func asConnection(record Record) Connection {
 	return Connection{
+		Identity:   record.AsString("identity"),
 		Role:       record.AsString("role"),
 		Container:  record.AsString("container"),
 		Host:       record.AsString("host"),
 		OperStatus: record.AsString("operStatus"),
 		Dir:        record.AsString("dir"),
 		Active:     record.AsBool("active"),
+		User:       record.AsString("user"),
 	}
 }
 
@@ -333,6 +337,10 @@ func GetRouterAddress(id string, edge bool) string {
 }
 
 func (a *Agent) request(operation string, typename string, name string, attributes map[string]interface{}) error {
+	return a.requestWithProperties(operation, typename, map[string]interface{}{"name": name}, attributes)
+}
+
+func (a *Agent) requestWithProperties(operation string, typename string, identifiers map[string]interface{}, attributes map[string]interface{}) error {
 	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
 	defer cancel()
 
@@ -344,7 +352,9 @@ func (a *Agent) request(operation string, typename string, name string, attribut
 	request.ApplicationProperties = make(map[string]interface{})
 	request.ApplicationProperties["operation"] = operation
 	request.ApplicationProperties["type"] = typename
-	request.ApplicationProperties["name"] = name
+	for key, value := range identifiers {
+		request.ApplicationProperties[key] = value
+	}
 	if attributes != nil {
 		request.Value = attributes
 	}
@@ -386,6 +396,18 @@ func (a *Agent) Delete(typename string, name string) error {
 	return a.request("DELETE", typename, name, nil)
 }
 
+// CloseConnection asks the router to close the connection with the
+// supplied identity.
+func (a *Agent) CloseConnection(identity string) error {
+	if identity == "" {
+		return fmt.Errorf("Cannot close connection with no identity")
+	}
+	log.Println("CLOSE CONNECTION", identity)
+	return a.requestWithProperties("UPDATE", "io.skupper.router.connection", map[string]interface{}{"identity": identity}, map[string]interface{}{
+		"adminStatus": "deleted",
+	})
+}
+
 func (a *Agent) Query(typename string, attributes []string) ([]Record, error) {
 	return a.QueryRouterNode(typename, attributes, nil)
 }
@@ -1138,6 +1160,7 @@ func asSslProfile(record Record) SslProfile {
 		CertFile:       record.AsString("certFile"),
 		PrivateKeyFile: record.AsString("privateKeyFile"),
 		CaCertFile:     record.AsString("caCertFile"),
+		CrlFile:        record.AsString("crlFile"),
 	}
 }
 
@@ -1238,6 +1261,41 @@ func (a *Agent) UpdateListenerConfig(changes *ListenerDifference) error {
 	return nil
 }
 
+func (a *Agent) UpdateAddressConfig(changes *AddressDifference) error {
+	for _, deleted := range changes.Deleted {
+		if err := a.Delete("io.skupper.router.router.config.address", deleted.Name); err != nil {
+			return fmt.Errorf("Error deleting addresses: %s", err)
+		}
+	}
+	for _, added := range changes.Added {
+		if err := a.Create("io.skupper.router.router.config.address", added.Name, added); err != nil {
+			return fmt.Errorf("Error adding addresses: %s", err)
+		}
+	}
+	return nil
+}
+
+// GetLocalAddresses returns the address configuration of the router keyed
+// by prefix
+func (a *Agent) GetLocalAddresses() (map[string]Address, error) {
+	results, err := a.Query("io.skupper.router.router.config.address", []string{})
+	if err != nil {
+		return nil, err
+	}
+	addresses := map[string]Address{}
+	for _, record := range results {
+		address := Address{
+			Name:         record.AsString("name"),
+			Prefix:       record.AsString("prefix"),
+			Distribution: record.AsString("distribution"),
+		}
+		if address.Prefix != "" {
+			addresses[address.Prefix] = address
+		}
+	}
+	return addresses, nil
+}
+
 func (a *Agent) GetLocalListeners() (map[string]Listener, error) {
 	results, err := a.Query("io.skupper.router.listener", []string{})
 	if err != nil {
@@ -139,6 +139,10 @@ func (r *RouterConfig) IsEdge() bool {
 
 const SSL_PROFILE_PATH = "/etc/skupper-router-certs"
 
+// The name of the file within an SslProfile's directory holding the
+// revocation lists for its CA, if it has them
+const RevocationListFile = "ca.crl"
+
 func ConfigureSslProfile(name string, path string, clientAuth bool) SslProfile {
 	profile := SslProfile{
 		Name:       name,
@@ -151,6 +155,14 @@ func ConfigureSslProfile(name string, path string, clientAuth bool) SslProfile {
 	return profile
 }
 
+// ConfigureRevocationList sets the file from which the router reads
+// the revocation lists for the profile's CA. Peers presenting a
+// certificate listed there are refused during the TLS handshake, by
+// routers that support the crlFile attribute of sslProfile.
+func (p *SslProfile) ConfigureRevocationList(path string) {
+	p.CrlFile = path_.Join(path, p.Name, RevocationListFile)
+}
+
 func (r *RouterConfig) AddSslProfile(s SslProfile) bool {
 	if original, ok := r.SslProfiles[s.Name]; ok && original == s {
 		return false
@@ -206,6 +218,28 @@ func (r *RouterConfig) AddAddress(a Address) {
 	r.Addresses[a.Prefix] = a
 }
 
+// UpdateAddresses replaces the addresses whose name starts with namePrefix
+// with those in desired, keyed by prefix
+func (r *RouterConfig) UpdateAddresses(namePrefix string, desired map[string]Address) bool {
+	changed := false
+	if r.Addresses == nil {
+		r.Addresses = map[string]Address{}
+	}
+	for prefix, address := range r.Addresses {
+		if _, ok := desired[prefix]; !ok && strings.HasPrefix(address.Name, namePrefix) {
+			delete(r.Addresses, prefix)
+			changed = true
+		}
+	}
+	for prefix, address := range desired {
+		if existing, ok := r.Addresses[prefix]; !ok || existing != address {
+			r.Addresses[prefix] = address
+			changed = true
+		}
+	}
+	return changed
+}
+
 func (r *RouterConfig) AddTcpConnector(e TcpEndpoint) {
 	r.Bridges.AddTcpConnector(e)
 }
@@ -375,6 +409,7 @@ type SslProfile struct {
 	CertFile       string `json:"certFile,omitempty"`
 	PrivateKeyFile string `json:"privateKeyFile,omitempty"`
 	CaCertFile     string `json:"caCertFile,omitempty"`
+	CrlFile        string `json:"crlFile,omitempty"`
 }
 
 func (p SslProfile) toRecord() Record {
@@ -391,6 +426,9 @@ func (p SslProfile) toRecord() Record {
 	if p.CaCertFile != "" {
 		result["caCertFile"] = p.CaCertFile
 	}
+	if p.CrlFile != "" {
+		result["crlFile"] = p.CrlFile
+	}
 	return result
 }
 
@@ -527,10 +565,25 @@ const (
 )
 
 type Address struct {
+	Name         string `json:"name,omitempty"`
 	Prefix       string `json:"prefix,omitempty"`
 	Distribution string `json:"distribution,omitempty"`
 }
 
+func (a Address) toRecord() Record {
+	result := make(map[string]any)
+	if a.Name != "" {
+		result["name"] = a.Name
+	}
+	if a.Prefix != "" {
+		result["prefix"] = a.Prefix
+	}
+	if a.Distribution != "" {
+		result["distribution"] = a.Distribution
+	}
+	return result
+}
+
 type TcpEndpoint struct {
 	Name           string `json:"name,omitempty"`
 	Host           string `json:"host,omitempty"`
@@ -1051,6 +1104,39 @@ func (a *ConnectorDifference) Empty() bool {
 	return len(a.Deleted) == 0 && len(a.Added) == 0
 }
 
+type AddressDifference struct {
+	Deleted []Address
+	Added   []Address
+}
+
+// AddressesDifference compares the addresses configured in a router, keyed
+// by prefix, with those desired
+func AddressesDifference(actual map[string]Address, desired map[string]Address) *AddressDifference {
+	result := AddressDifference{}
+	for prefix, desiredValue := range desired {
+		if actualValue, ok := actual[prefix]; ok {
+			if actualValue.Distribution != desiredValue.Distribution {
+				// addresses cannot be updated over the management
+				// protocol, so handle change as delete then add
+				result.Deleted = append(result.Deleted, actualValue)
+				result.Added = append(result.Added, desiredValue)
+			}
+		} else {
+			result.Added = append(result.Added, desiredValue)
+		}
+	}
+	for prefix, value := range actual {
+		if _, ok := desired[prefix]; !ok {
+			result.Deleted = append(result.Deleted, value)
+		}
+	}
+	return &result
+}
+
+func (a *AddressDifference) Empty() bool {
+	return len(a.Deleted) == 0 && len(a.Added) == 0
+}
+
 type ListenerDifference struct {
 	Deleted []Listener
 	Added   []Listener
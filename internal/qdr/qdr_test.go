@@ -164,6 +164,56 @@ func TestAddAddress(t *testing.T) {
 	}
 }
 
+func TestUpdateAddresses(t *testing.T) {
+	config := InitialConfig("foo", "bar", "undefined", true, 3)
+	config.AddAddress(Address{Prefix: "mc", Distribution: DistributionMulticast})
+	config.AddAddress(Address{Name: "managed/old", Prefix: "old", Distribution: DistributionClosest})
+
+	assert.Assert(t, config.UpdateAddresses("managed/", map[string]Address{
+		"new": {Name: "managed/new", Prefix: "new", Distribution: DistributionClosest},
+	}))
+	assert.DeepEqual(t, config.Addresses, map[string]Address{
+		"mc":  {Prefix: "mc", Distribution: DistributionMulticast},
+		"new": {Name: "managed/new", Prefix: "new", Distribution: DistributionClosest},
+	})
+	assert.Assert(t, !config.UpdateAddresses("managed/", map[string]Address{
+		"new": {Name: "managed/new", Prefix: "new", Distribution: DistributionClosest},
+	}))
+}
+
+func TestAddressesDifference(t *testing.T) {
+	actual := map[string]Address{
+		"mc":      {Name: "address/0", Prefix: "mc", Distribution: DistributionMulticast},
+		"changed": {Name: "managed/changed", Prefix: "changed", Distribution: string(DistributionBalanced)},
+		"stale":   {Name: "managed/stale", Prefix: "stale", Distribution: DistributionClosest},
+	}
+	desired := map[string]Address{
+		"mc":      {Prefix: "mc", Distribution: DistributionMulticast},
+		"changed": {Name: "managed/changed", Prefix: "changed", Distribution: DistributionClosest},
+		"added":   {Name: "managed/added", Prefix: "added", Distribution: DistributionClosest},
+	}
+	diff := AddressesDifference(actual, desired)
+	var deleted, added []string
+	for _, a := range diff.Deleted {
+		deleted = append(deleted, a.Name)
+	}
+	for _, a := range diff.Added {
+		added = append(added, a.Name)
+	}
+	assert.Assert(t, !diff.Empty())
+	assert.DeepEqual(t, toSet(deleted), toSet([]string{"managed/changed", "managed/stale"}))
+	assert.DeepEqual(t, toSet(added), toSet([]string{"managed/changed", "managed/added"}))
+	assert.Assert(t, AddressesDifference(desired, desired).Empty())
+}
+
+func toSet(values []string) map[string]bool {
+	result := map[string]bool{}
+	for _, value := range values {
+		result[value] = true
+	}
+	return result
+}
+
 func TestMarshalUnmarshalRouterConfig(t *testing.T) {
 	verifyHostName := new(bool)
 	*verifyHostName = false
@@ -648,6 +698,10 @@ func TestRecordTypes_GH2081(t *testing.T) {
 		SslProfile{
 			Name: "myprofile",
 		},
+		Address{
+			Name:   "myaddress",
+			Prefix: "backend",
+		},
 	}
 	for _, rt := range testCases {
 		t.Run("", func(t *testing.T) {
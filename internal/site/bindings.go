@@ -25,6 +25,7 @@ type Bindings struct {
 	profilePath string
 	connectors  map[string]*skupperv2alpha1.Connector
 	listeners   map[string]*skupperv2alpha1.Listener
+	network     []skupperv2alpha1.SiteRecord
 	handler     BindingEventHandler
 	configure   struct {
 		listener  ListenerConfiguration
@@ -159,6 +160,38 @@ func (b *Bindings) deleteListener(name string) qdr.ConfigUpdate {
 	return nil
 }
 
+// NetworkUpdated records the latest network status, which determines the
+// address listeners with the failover policy route to. It returns true if
+// that address has changed for any listener.
+func (b *Bindings) NetworkUpdated(network []skupperv2alpha1.SiteRecord) bool {
+	changed := false
+	for _, l := range b.listeners {
+		if l.Spec.LoadBalancing != skupperv2alpha1.LOAD_BALANCING_FAILOVER {
+			continue
+		}
+		for _, pl := range l.PortListeners() {
+			if ListenerAddress(pl, b.network) != ListenerAddress(pl, network) {
+				changed = true
+			}
+		}
+	}
+	b.network = network
+	return changed
+}
+
+// routedListener returns the listener to configure the router with: a copy
+// with the routing key replaced by the failover address when the listener
+// routes to one
+func (b *Bindings) routedListener(l *skupperv2alpha1.Listener) *skupperv2alpha1.Listener {
+	address := ListenerAddress(l, b.network)
+	if address == l.Spec.RoutingKey {
+		return l
+	}
+	routed := l.DeepCopy()
+	routed.Spec.RoutingKey = address
+	return routed
+}
+
 func (b *Bindings) ToBridgeConfig() qdr.BridgeConfig {
 	config := qdr.BridgeConfig{
 		TcpListeners:  qdr.TcpEndpointMap{},
@@ -168,12 +201,20 @@ func (b *Bindings) ToBridgeConfig() qdr.BridgeConfig {
 		b.configure.connector(b.SiteId, c, &config)
 	}
 	for _, l := range b.listeners {
-		b.configure.listener(b.SiteId, l, &config)
+		for _, pl := range l.PortListeners() {
+			b.configure.listener(b.SiteId, b.routedListener(pl), &config)
+		}
 	}
 
 	return config
 }
 
+// AddLoadBalancingAddresses configures the router addresses required by the
+// load balancing policies of listeners, removing any no longer required
+func (b *Bindings) AddLoadBalancingAddresses(config *qdr.RouterConfig) bool {
+	return config.UpdateAddresses(LoadBalancingAddressName, loadBalancingAddresses(b.listeners))
+}
+
 func (b *Bindings) AddSslProfiles(config *qdr.RouterConfig) bool {
 	profiles := map[string]qdr.SslProfile{}
 	for _, c := range b.connectors {
@@ -208,6 +249,7 @@ func (b *Bindings) AddSslProfiles(config *qdr.RouterConfig) bool {
 
 func (b *Bindings) Apply(config *qdr.RouterConfig) bool {
 	b.AddSslProfiles(config)
+	b.AddLoadBalancingAddresses(config)
 	config.UpdateBridgeConfig(b.ToBridgeConfig())
 	config.RemoveUnreferencedSslProfiles()
 	return true //TODO: can optimise by indicating if no change was required
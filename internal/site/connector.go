@@ -9,14 +9,35 @@ import (
 
 func UpdateBridgeConfigForConnector(siteId string, connector *skupperv2alpha1.Connector, config *qdr.BridgeConfig) {
 	if connector.Spec.Host != "" {
-		updateBridgeConfigForConnector(connector.Name+"@"+connector.Spec.Host, siteId, connector, connector.Spec.Host, "", connector.Spec.RoutingKey, config)
+		for _, c := range connector.PortConnectors() {
+			updateBridgeConfigForConnectorTarget(c.Name+"@"+c.Spec.Host, siteId, c, c.Spec.Host, "", config)
+		}
 	}
 }
 
 func UpdateBridgeConfigForConnectorToPod(siteId string, connector *skupperv2alpha1.Connector, pod skupperv2alpha1.PodDetails, addQualifiedAddress bool, config *qdr.BridgeConfig) {
-	updateBridgeConfigForConnector(connector.Name+"@"+pod.IP, siteId, connector, pod.IP, pod.UID, connector.Spec.RoutingKey, config)
-	if addQualifiedAddress {
-		updateBridgeConfigForConnector(connector.Name+"@"+pod.Name, siteId, connector, pod.IP, pod.UID, connector.Spec.RoutingKey+"."+pod.Name, config)
+	for _, c := range connector.PortConnectors() {
+		updateBridgeConfigForConnectorTarget(c.Name+"@"+pod.IP, siteId, c, pod.IP, pod.UID, config)
+		if addQualifiedAddress {
+			updateBridgeConfigForConnector(c.Name+"@"+pod.Name, siteId, c, pod.IP, pod.UID, c.Spec.RoutingKey+"."+pod.Name, config)
+		}
+	}
+}
+
+// updateBridgeConfigForConnectorTarget binds a target of a connector to its
+// routing key, and to its failover address when it has a priority. A
+// connector with a weight gets that many router connectors per address so
+// that a proportionate share of connections is balanced to it.
+func updateBridgeConfigForConnectorTarget(name string, siteId string, connector *skupperv2alpha1.Connector, host string, processID string, config *qdr.BridgeConfig) {
+	for i := 1; i <= connectorWeight(connector); i++ {
+		qualifiedName := name
+		if i > 1 {
+			qualifiedName = name + "#" + strconv.Itoa(i)
+		}
+		updateBridgeConfigForConnector(qualifiedName, siteId, connector, host, processID, connector.Spec.RoutingKey, config)
+		if connector.Spec.Priority > 0 {
+			updateBridgeConfigForConnector(qualifiedName+"@priority", siteId, connector, host, processID, FailoverAddress(connector.Spec.RoutingKey, connector.Spec.Priority), config)
+		}
 	}
 }
 
@@ -0,0 +1,164 @@
+package site
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/skupperproject/skupper/internal/qdr"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+// LoadBalancingAddressName prefixes the names of the router addresses
+// configured for the load balancing policies of listeners
+const LoadBalancingAddressName = "loadbalancing/"
+
+const failoverSeparator = "@priority-"
+
+// FailoverAddress returns the address a connector with a priority binds to
+// in addition to its routing key. Listeners with the failover policy route
+// to the address of the lowest priority present in the network.
+func FailoverAddress(routingKey string, priority int) string {
+	return routingKey + failoverSeparator + strconv.Itoa(priority)
+}
+
+// failoverPriority returns the lowest priority of the connectors with a
+// routing key in the network, or 0 if none of them have a priority
+func failoverPriority(routingKey string, network []skupperv2alpha1.SiteRecord) int {
+	lowest := 0
+	prefix := routingKey + failoverSeparator
+	for _, site := range network {
+		for _, service := range site.Services {
+			if !strings.HasPrefix(service.RoutingKey, prefix) || len(service.Connectors) == 0 {
+				continue
+			}
+			priority, err := strconv.Atoi(strings.TrimPrefix(service.RoutingKey, prefix))
+			if err != nil || priority <= 0 {
+				continue
+			}
+			if lowest == 0 || priority < lowest {
+				lowest = priority
+			}
+		}
+	}
+	return lowest
+}
+
+// ListenerAddress returns the router address a listener routes connections
+// to. That is its routing key unless it has the failover policy and there
+// are connectors with a priority in the network.
+func ListenerAddress(listener *skupperv2alpha1.Listener, network []skupperv2alpha1.SiteRecord) string {
+	if listener.Spec.LoadBalancing == skupperv2alpha1.LOAD_BALANCING_FAILOVER {
+		if priority := failoverPriority(listener.Spec.RoutingKey, network); priority > 0 {
+			return FailoverAddress(listener.Spec.RoutingKey, priority)
+		}
+	}
+	return listener.Spec.RoutingKey
+}
+
+// ActiveConnectors returns the connectors in the network, as site/host, that
+// a listener in the site with the given id currently routes connections to.
+// For a multi-port listener these are the connectors for any of its ports.
+func ActiveConnectors(siteId string, listener *skupperv2alpha1.Listener, network []skupperv2alpha1.SiteRecord) []string {
+	if len(listener.Spec.Ports) == 0 {
+		return activeConnectors(siteId, listener, network)
+	}
+	var connectors []string
+	for _, pl := range listener.PortListeners() {
+		connectors = append(connectors, activeConnectors(siteId, pl, network)...)
+	}
+	return uniqueSorted(connectors)
+}
+
+func activeConnectors(siteId string, listener *skupperv2alpha1.Listener, network []skupperv2alpha1.SiteRecord) []string {
+	address := ListenerAddress(listener, network)
+	var local, all []string
+	for _, site := range network {
+		name := site.Name
+		if name == "" {
+			name = site.Id
+		}
+		for _, service := range site.Services {
+			if service.RoutingKey != address {
+				continue
+			}
+			for _, host := range service.Connectors {
+				connector := name + "/" + host
+				all = append(all, connector)
+				if site.Id == siteId {
+					local = append(local, connector)
+				}
+			}
+		}
+	}
+	if listener.Spec.LoadBalancing == skupperv2alpha1.LOAD_BALANCING_PREFER_LOCAL && len(local) > 0 {
+		return uniqueSorted(local)
+	}
+	return uniqueSorted(all)
+}
+
+// ValidateLoadBalancing checks the load balancing policy of a listener
+func ValidateLoadBalancing(listener *skupperv2alpha1.Listener) error {
+	switch listener.Spec.LoadBalancing {
+	case "", skupperv2alpha1.LOAD_BALANCING_BALANCED, skupperv2alpha1.LOAD_BALANCING_PREFER_LOCAL:
+	case skupperv2alpha1.LOAD_BALANCING_FAILOVER:
+		if listener.Spec.ExposePodsByName {
+			return fmt.Errorf("loadBalancing %q cannot be used with exposePodsByName", listener.Spec.LoadBalancing)
+		}
+	default:
+		return fmt.Errorf("invalid loadBalancing %q: must be one of %s, %s or %s", listener.Spec.LoadBalancing,
+			skupperv2alpha1.LOAD_BALANCING_BALANCED, skupperv2alpha1.LOAD_BALANCING_PREFER_LOCAL, skupperv2alpha1.LOAD_BALANCING_FAILOVER)
+	}
+	return nil
+}
+
+// loadBalancingAddresses returns the router address configuration for the
+// load balancing policies of listeners, keyed by prefix. Listeners that
+// prefer local connectors have their routing key distributed to the closest
+// connectors.
+func loadBalancingAddresses(listeners map[string]*skupperv2alpha1.Listener) map[string]qdr.Address {
+	addresses := map[string]qdr.Address{}
+	for _, l := range listeners {
+		if l.Spec.LoadBalancing != skupperv2alpha1.LOAD_BALANCING_PREFER_LOCAL {
+			continue
+		}
+		for _, pl := range l.PortListeners() {
+			addresses[pl.Spec.RoutingKey] = qdr.Address{
+				Name:         LoadBalancingAddressName + pl.Spec.RoutingKey,
+				Prefix:       pl.Spec.RoutingKey,
+				Distribution: qdr.DistributionClosest,
+			}
+		}
+	}
+	return addresses
+}
+
+// MaxConnectorWeight is the largest weight honoured for a connector
+const MaxConnectorWeight = 10
+
+// connectorWeight returns the number of router connectors configured for
+// each target of a connector
+func connectorWeight(connector *skupperv2alpha1.Connector) int {
+	if connector.Spec.Weight < 1 {
+		return 1
+	}
+	if connector.Spec.Weight > MaxConnectorWeight {
+		return MaxConnectorWeight
+	}
+	return connector.Spec.Weight
+}
+
+func uniqueSorted(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	sort.Strings(values)
+	result := values[:1]
+	for _, value := range values[1:] {
+		if value != result[len(result)-1] {
+			result = append(result, value)
+		}
+	}
+	return result
+}
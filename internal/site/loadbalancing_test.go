@@ -0,0 +1,124 @@
+package site
+
+import (
+	"testing"
+
+	"github.com/skupperproject/skupper/internal/qdr"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"gotest.tools/v3/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func loadBalancedListener(policy string) *skupperv2alpha1.Listener {
+	return &skupperv2alpha1.Listener{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "db",
+			Namespace: "test",
+		},
+		Spec: skupperv2alpha1.ListenerSpec{
+			RoutingKey:    "db",
+			Host:          "db",
+			Port:          5432,
+			LoadBalancing: policy,
+		},
+	}
+}
+
+func failoverNetwork() []skupperv2alpha1.SiteRecord {
+	return []skupperv2alpha1.SiteRecord{
+		{
+			Id:   "site-1",
+			Name: "west",
+			Services: []skupperv2alpha1.ServiceRecord{
+				{RoutingKey: "db", Connectors: []string{"10.0.0.1", "10.0.0.1"}, Listeners: []string{"db"}},
+				{RoutingKey: FailoverAddress("db", 1), Connectors: []string{"10.0.0.1"}},
+			},
+		},
+		{
+			Id:   "site-2",
+			Name: "east",
+			Services: []skupperv2alpha1.ServiceRecord{
+				{RoutingKey: "db", Connectors: []string{"10.1.0.1"}},
+				{RoutingKey: FailoverAddress("db", 2), Connectors: []string{"10.1.0.1"}},
+			},
+		},
+	}
+}
+
+func TestListenerAddress(t *testing.T) {
+	network := failoverNetwork()
+	assert.Equal(t, ListenerAddress(loadBalancedListener(""), network), "db")
+	assert.Equal(t, ListenerAddress(loadBalancedListener(skupperv2alpha1.LOAD_BALANCING_PREFER_LOCAL), network), "db")
+	assert.Equal(t, ListenerAddress(loadBalancedListener(skupperv2alpha1.LOAD_BALANCING_FAILOVER), network), "db@priority-1")
+
+	// primary gone, fail over to the next priority
+	network[0].Services = network[0].Services[:1]
+	assert.Equal(t, ListenerAddress(loadBalancedListener(skupperv2alpha1.LOAD_BALANCING_FAILOVER), network), "db@priority-2")
+
+	// no prioritised connectors left, route to any
+	network[1].Services = network[1].Services[:1]
+	assert.Equal(t, ListenerAddress(loadBalancedListener(skupperv2alpha1.LOAD_BALANCING_FAILOVER), network), "db")
+}
+
+func TestActiveConnectors(t *testing.T) {
+	network := failoverNetwork()
+	assert.DeepEqual(t, ActiveConnectors("site-1", loadBalancedListener(""), network), []string{"east/10.1.0.1", "west/10.0.0.1"})
+	assert.DeepEqual(t, ActiveConnectors("site-1", loadBalancedListener(skupperv2alpha1.LOAD_BALANCING_PREFER_LOCAL), network), []string{"west/10.0.0.1"})
+	assert.DeepEqual(t, ActiveConnectors("site-3", loadBalancedListener(skupperv2alpha1.LOAD_BALANCING_PREFER_LOCAL), network), []string{"east/10.1.0.1", "west/10.0.0.1"})
+	assert.DeepEqual(t, ActiveConnectors("site-2", loadBalancedListener(skupperv2alpha1.LOAD_BALANCING_FAILOVER), network), []string{"west/10.0.0.1"})
+	assert.Assert(t, ActiveConnectors("site-1", loadBalancedListener(""), nil) == nil)
+}
+
+func TestValidateLoadBalancing(t *testing.T) {
+	assert.NilError(t, ValidateLoadBalancing(loadBalancedListener("")))
+	assert.NilError(t, ValidateLoadBalancing(loadBalancedListener(skupperv2alpha1.LOAD_BALANCING_FAILOVER)))
+	assert.ErrorContains(t, ValidateLoadBalancing(loadBalancedListener("random")), `invalid loadBalancing "random"`)
+	listener := loadBalancedListener(skupperv2alpha1.LOAD_BALANCING_FAILOVER)
+	listener.Spec.ExposePodsByName = true
+	assert.ErrorContains(t, ValidateLoadBalancing(listener), "cannot be used with exposePodsByName")
+}
+
+func TestBindingsLoadBalancing(t *testing.T) {
+	b := NewBindings("/etc/skupper-router-certs")
+	b.SetSiteId("site-2")
+	b.UpdateListener("db", loadBalancedListener(skupperv2alpha1.LOAD_BALANCING_FAILOVER))
+	local := loadBalancedListener(skupperv2alpha1.LOAD_BALANCING_PREFER_LOCAL)
+	local.Name = "db-local"
+	local.Spec.RoutingKey = "db-local"
+	b.UpdateListener("db-local", local)
+	b.UpdateConnector("db", &skupperv2alpha1.Connector{
+		ObjectMeta: v1.ObjectMeta{Name: "db", Namespace: "test"},
+		Spec: skupperv2alpha1.ConnectorSpec{
+			RoutingKey: "db",
+			Host:       "10.1.0.1",
+			Port:       5432,
+			Priority:   2,
+			Weight:     2,
+		},
+	})
+
+	config := qdr.InitialConfig("router", "site-2", "undefined", false, 3)
+	b.Apply(&config)
+	assert.Equal(t, config.Bridges.TcpListeners["db"].Address, "db")
+	assert.Equal(t, config.Bridges.TcpConnectors["db@10.1.0.1"].Address, "db")
+	assert.Equal(t, config.Bridges.TcpConnectors["db@10.1.0.1#2"].Address, "db")
+	assert.Equal(t, config.Bridges.TcpConnectors["db@10.1.0.1@priority"].Address, "db@priority-2")
+	assert.Equal(t, config.Bridges.TcpConnectors["db@10.1.0.1#2@priority"].Address, "db@priority-2")
+	assert.Equal(t, len(config.Bridges.TcpConnectors), 4)
+	assert.DeepEqual(t, config.Addresses["db-local"], qdr.Address{
+		Name:         "loadbalancing/db-local",
+		Prefix:       "db-local",
+		Distribution: qdr.DistributionClosest,
+	})
+
+	assert.Assert(t, b.NetworkUpdated(failoverNetwork()))
+	assert.Assert(t, !b.NetworkUpdated(failoverNetwork()))
+	b.Apply(&config)
+	assert.Equal(t, config.Bridges.TcpListeners["db"].Address, "db@priority-1")
+	assert.Equal(t, b.GetListener("db").Spec.RoutingKey, "db")
+
+	b.UpdateListener("db-local", nil)
+	b.Apply(&config)
+	_, ok := config.Addresses["db-local"]
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,170 @@
+package site
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// AccessPolicies holds the AccessPolicy resources in effect for a
+// site. If there are none, nothing is restricted; otherwise a resource
+// must be permitted by every policy.
+type AccessPolicies struct {
+	policies map[string]*skupperv2alpha1.AccessPolicy
+}
+
+func NewAccessPolicies() *AccessPolicies {
+	return &AccessPolicies{
+		policies: map[string]*skupperv2alpha1.AccessPolicy{},
+	}
+}
+
+// Update records the named policy, or removes it if nil, returning
+// true if that changes what is permitted
+func (p *AccessPolicies) Update(name string, policy *skupperv2alpha1.AccessPolicy) bool {
+	existing, ok := p.policies[name]
+	if policy == nil {
+		delete(p.policies, name)
+		return ok
+	}
+	p.policies[name] = policy
+	return !ok || !reflect.DeepEqual(existing.Spec, policy.Spec)
+}
+
+// Restricted returns true if any policy is in effect
+func (p *AccessPolicies) Restricted() bool {
+	return p != nil && len(p.policies) > 0
+}
+
+func (p *AccessPolicies) sorted() []*skupperv2alpha1.AccessPolicy {
+	var policies []*skupperv2alpha1.AccessPolicy
+	if p == nil {
+		return policies
+	}
+	for _, policy := range p.policies {
+		policies = append(policies, policy)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Name < policies[j].Name
+	})
+	return policies
+}
+
+// ListenerPermitted returns an error if the policies do not allow the
+// listener to consume its routing key(s)
+func (p *AccessPolicies) ListenerPermitted(listener *skupperv2alpha1.Listener) error {
+	var errs []error
+	for _, l := range listener.PortListeners() {
+		for _, policy := range p.sorted() {
+			if !matchesAny(policy.Spec.AllowedConsumedRoutingKeys, l.Spec.RoutingKey) {
+				errs = append(errs, fmt.Errorf("consuming routing key %q is not permitted by AccessPolicy %q", l.Spec.RoutingKey, policy.Name))
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ConnectorPermitted returns an error if the policies do not allow
+// the connector to expose its routing key(s)
+func (p *AccessPolicies) ConnectorPermitted(connector *skupperv2alpha1.Connector) error {
+	var errs []error
+	for _, c := range connector.PortConnectors() {
+		for _, policy := range p.sorted() {
+			if !matchesAny(policy.Spec.AllowedExposedRoutingKeys, c.Spec.RoutingKey) {
+				errs = append(errs, fmt.Errorf("exposing routing key %q is not permitted by AccessPolicy %q", c.Spec.RoutingKey, policy.Name))
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LinkPermitted returns an error if the policies do not allow links
+// to the remote site the link connects to. The remote site is only
+// known once the link has been established, and is disregarded if the
+// link has been changed since.
+func (p *AccessPolicies) LinkPermitted(link *skupperv2alpha1.Link) error {
+	operational := meta.FindStatusCondition(link.Status.Conditions, skupperv2alpha1.CONDITION_TYPE_OPERATIONAL)
+	if link.Status.RemoteSiteId == "" || operational == nil || operational.ObservedGeneration != link.ObjectMeta.Generation {
+		return nil
+	}
+	return p.RemoteSitePermitted(link.Status.RemoteSiteId)
+}
+
+// RemoteSitePermitted returns an error if the policies do not allow
+// links between this site and the remote site with the supplied id,
+// in either direction
+func (p *AccessPolicies) RemoteSitePermitted(id string) error {
+	for _, policy := range p.sorted() {
+		if !matchesAny(policy.Spec.AllowedRemoteSites, id) {
+			return fmt.Errorf("linking with site %q is not permitted by AccessPolicy %q", id, policy.Name)
+		}
+	}
+	return nil
+}
+
+// RouterAccessPermitted returns an error if the router access accepts
+// links from other sites and the policies do not allow link access
+func (p *AccessPolicies) RouterAccessPermitted(ra *skupperv2alpha1.RouterAccess) error {
+	if !ra.IsLinkAccess() {
+		return nil
+	}
+	for _, policy := range p.sorted() {
+		if policy.Spec.AllowLinkAccess != nil && !*policy.Spec.AllowLinkAccess {
+			return fmt.Errorf("link access is not permitted by AccessPolicy %q", policy.Name)
+		}
+	}
+	return nil
+}
+
+type permittable interface {
+	SetPermitted(err error) bool
+	ClearPermitted() bool
+}
+
+// SetPermitted records the outcome of a policy check in the Permitted
+// condition of a resource's status, removing the condition when no
+// policy is in effect. It returns true if the status changed.
+func (p *AccessPolicies) SetPermitted(resource permittable, err error) bool {
+	if !p.Restricted() {
+		return resource.ClearPermitted()
+	}
+	return resource.SetPermitted(err)
+}
+
+// ValidateAccessPolicy checks that the patterns in a policy are well
+// formed
+func ValidateAccessPolicy(policy *skupperv2alpha1.AccessPolicy) error {
+	var errs []error
+	check := func(field string, patterns []string) {
+		for i, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				errs = append(errs, fmt.Errorf("%s[%d]: invalid pattern %q", field, i, pattern))
+			}
+		}
+	}
+	check("allowedExposedRoutingKeys", policy.Spec.AllowedExposedRoutingKeys)
+	check("allowedConsumedRoutingKeys", policy.Spec.AllowedConsumedRoutingKeys)
+	check("allowedRemoteSites", policy.Spec.AllowedRemoteSites)
+	return errors.Join(errs...)
+}
+
+// matchesAny returns true if there are no patterns or if the value
+// matches one of them; malformed patterns never match
+func matchesAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, value); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
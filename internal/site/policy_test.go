@@ -0,0 +1,209 @@
+package site
+
+import (
+	"testing"
+
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func accessPolicy(name string, spec skupperv2alpha1.AccessPolicySpec) *skupperv2alpha1.AccessPolicy {
+	return &skupperv2alpha1.AccessPolicy{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+		},
+		Spec: spec,
+	}
+}
+
+func TestAccessPolicies_routingKeys(t *testing.T) {
+	tests := []struct {
+		name         string
+		policies     []*skupperv2alpha1.AccessPolicy
+		routingKey   string
+		ports        []skupperv2alpha1.BindingPort
+		listenerErr  string
+		connectorErr string
+	}{
+		{
+			name:       "no policies",
+			routingKey: "backend",
+		},
+		{
+			name: "unrestricted policy",
+			policies: []*skupperv2alpha1.AccessPolicy{
+				accessPolicy("links-only", skupperv2alpha1.AccessPolicySpec{AllowedRemoteSites: []string{"*"}}),
+			},
+			routingKey: "backend",
+		},
+		{
+			name: "patterns",
+			policies: []*skupperv2alpha1.AccessPolicy{
+				accessPolicy("keys", skupperv2alpha1.AccessPolicySpec{
+					AllowedExposedRoutingKeys:  []string{"backend-*"},
+					AllowedConsumedRoutingKeys: []string{"frontend", "backend"},
+				}),
+			},
+			routingKey:   "backend",
+			connectorErr: "exposing routing key \"backend\" is not permitted by AccessPolicy \"keys\"",
+		},
+		{
+			name: "all policies must permit",
+			policies: []*skupperv2alpha1.AccessPolicy{
+				accessPolicy("b", skupperv2alpha1.AccessPolicySpec{AllowedConsumedRoutingKeys: []string{"back*"}}),
+				accessPolicy("a", skupperv2alpha1.AccessPolicySpec{AllowedConsumedRoutingKeys: []string{"frontend"}}),
+			},
+			routingKey:  "backend",
+			listenerErr: "consuming routing key \"backend\" is not permitted by AccessPolicy \"a\"",
+		},
+		{
+			name: "multiple ports",
+			policies: []*skupperv2alpha1.AccessPolicy{
+				accessPolicy("broker", skupperv2alpha1.AccessPolicySpec{
+					AllowedExposedRoutingKeys:  []string{"broker.amqp"},
+					AllowedConsumedRoutingKeys: []string{"broker.*"},
+				}),
+			},
+			routingKey: "broker",
+			ports: []skupperv2alpha1.BindingPort{
+				{Name: "amqp", Port: 5672},
+				{Name: "console", Port: 15672},
+			},
+			connectorErr: "exposing routing key \"broker.console\" is not permitted by AccessPolicy \"broker\"",
+		},
+		{
+			name: "malformed pattern",
+			policies: []*skupperv2alpha1.AccessPolicy{
+				accessPolicy("bad", skupperv2alpha1.AccessPolicySpec{AllowedExposedRoutingKeys: []string{"[backend"}}),
+			},
+			routingKey:   "backend",
+			connectorErr: "exposing routing key \"backend\" is not permitted by AccessPolicy \"bad\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies := NewAccessPolicies()
+			for _, policy := range tt.policies {
+				policies.Update(policy.Name, policy)
+			}
+			listener := &skupperv2alpha1.Listener{
+				ObjectMeta: v1.ObjectMeta{Name: "test"},
+				Spec: skupperv2alpha1.ListenerSpec{
+					RoutingKey: tt.routingKey,
+					Host:       "test",
+					Port:       8080,
+					Ports:      tt.ports,
+				},
+			}
+			connector := &skupperv2alpha1.Connector{
+				ObjectMeta: v1.ObjectMeta{Name: "test"},
+				Spec: skupperv2alpha1.ConnectorSpec{
+					RoutingKey: tt.routingKey,
+					Host:       "test",
+					Port:       8080,
+					Ports:      tt.ports,
+				},
+			}
+			if tt.listenerErr == "" {
+				assert.NilError(t, policies.ListenerPermitted(listener))
+			} else {
+				assert.Error(t, policies.ListenerPermitted(listener), tt.listenerErr)
+			}
+			if tt.connectorErr == "" {
+				assert.NilError(t, policies.ConnectorPermitted(connector))
+			} else {
+				assert.Error(t, policies.ConnectorPermitted(connector), tt.connectorErr)
+			}
+		})
+	}
+}
+
+func TestAccessPolicies_LinkPermitted(t *testing.T) {
+	policies := NewAccessPolicies()
+	policies.Update("sites", accessPolicy("sites", skupperv2alpha1.AccessPolicySpec{AllowedRemoteSites: []string{"trusted-*"}}))
+	link := &skupperv2alpha1.Link{
+		ObjectMeta: v1.ObjectMeta{Name: "west", Generation: 1},
+	}
+	assert.NilError(t, policies.LinkPermitted(link), "remote site is not yet known")
+
+	link.SetOperational(true, "untrusted-1", "west")
+	assert.Error(t, policies.LinkPermitted(link), "linking with site \"untrusted-1\" is not permitted by AccessPolicy \"sites\"")
+
+	link.ObjectMeta.Generation = 2
+	assert.NilError(t, policies.LinkPermitted(link), "remote site is not known for the latest spec")
+
+	link.SetOperational(true, "trusted-1", "west")
+	assert.NilError(t, policies.LinkPermitted(link))
+
+	assert.NilError(t, policies.RemoteSitePermitted("trusted-2"))
+	assert.Error(t, policies.RemoteSitePermitted("untrusted-2"), "linking with site \"untrusted-2\" is not permitted by AccessPolicy \"sites\"")
+	assert.NilError(t, NewAccessPolicies().RemoteSitePermitted("untrusted-2"))
+}
+
+func TestAccessPolicies_RouterAccessPermitted(t *testing.T) {
+	deny := false
+	allow := true
+	linkAccess := &skupperv2alpha1.RouterAccess{
+		Spec: skupperv2alpha1.RouterAccessSpec{
+			Roles: []skupperv2alpha1.RouterAccessRole{{Name: "inter-router", Port: 55671}, {Name: "edge", Port: 45671}},
+		},
+	}
+	localAccess := &skupperv2alpha1.RouterAccess{
+		Spec: skupperv2alpha1.RouterAccessSpec{
+			Roles: []skupperv2alpha1.RouterAccessRole{{Name: "normal", Port: 5671}},
+		},
+	}
+	policies := NewAccessPolicies()
+	policies.Update("allow", accessPolicy("allow", skupperv2alpha1.AccessPolicySpec{AllowLinkAccess: &allow}))
+	policies.Update("unset", accessPolicy("unset", skupperv2alpha1.AccessPolicySpec{}))
+	assert.NilError(t, policies.RouterAccessPermitted(linkAccess))
+
+	assert.Assert(t, policies.Update("allow", accessPolicy("allow", skupperv2alpha1.AccessPolicySpec{AllowLinkAccess: &deny})))
+	assert.Error(t, policies.RouterAccessPermitted(linkAccess), "link access is not permitted by AccessPolicy \"allow\"")
+	assert.NilError(t, policies.RouterAccessPermitted(localAccess))
+
+	assert.Assert(t, policies.Update("allow", nil))
+	assert.NilError(t, policies.RouterAccessPermitted(linkAccess))
+}
+
+func TestAccessPolicies_SetPermitted(t *testing.T) {
+	listener := &skupperv2alpha1.Listener{
+		ObjectMeta: v1.ObjectMeta{Name: "backend"},
+		Spec: skupperv2alpha1.ListenerSpec{
+			RoutingKey: "backend",
+			Host:       "backend",
+			Port:       8080,
+		},
+	}
+	listener.SetConfigured(nil)
+	listener.SetHasMatchingConnector(true)
+	assert.Assert(t, meta.IsStatusConditionTrue(listener.Status.Conditions, skupperv2alpha1.CONDITION_TYPE_READY))
+
+	policies := NewAccessPolicies()
+	assert.Assert(t, !policies.SetPermitted(listener, nil), "no condition without policies")
+
+	policies.Update("keys", accessPolicy("keys", skupperv2alpha1.AccessPolicySpec{AllowedConsumedRoutingKeys: []string{"frontend"}}))
+	assert.Assert(t, policies.SetPermitted(listener, policies.ListenerPermitted(listener)))
+	assert.Assert(t, !meta.IsStatusConditionTrue(listener.Status.Conditions, skupperv2alpha1.CONDITION_TYPE_READY))
+	assert.Equal(t, listener.Status.Message, "consuming routing key \"backend\" is not permitted by AccessPolicy \"keys\"")
+
+	policies.Update("keys", nil)
+	assert.Assert(t, policies.SetPermitted(listener, policies.ListenerPermitted(listener)))
+	assert.Assert(t, meta.FindStatusCondition(listener.Status.Conditions, skupperv2alpha1.CONDITION_TYPE_PERMITTED) == nil)
+	assert.Assert(t, meta.IsStatusConditionTrue(listener.Status.Conditions, skupperv2alpha1.CONDITION_TYPE_READY))
+}
+
+func TestValidateAccessPolicy(t *testing.T) {
+	assert.NilError(t, ValidateAccessPolicy(accessPolicy("valid", skupperv2alpha1.AccessPolicySpec{
+		AllowedExposedRoutingKeys:  []string{"backend", "db-*"},
+		AllowedConsumedRoutingKeys: []string{"*"},
+		AllowedRemoteSites:         []string{"0f0a0b6e-*"},
+	})))
+	assert.Error(t, ValidateAccessPolicy(accessPolicy("invalid", skupperv2alpha1.AccessPolicySpec{
+		AllowedExposedRoutingKeys: []string{"backend", "[db"},
+		AllowedRemoteSites:        []string{"site\\"},
+	})), "allowedExposedRoutingKeys[1]: invalid pattern \"[db\"\nallowedRemoteSites[0]: invalid pattern \"site\\\\\"")
+}
@@ -0,0 +1,46 @@
+package site
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ValidateListenerPorts checks the ports of a multi-port listener
+func ValidateListenerPorts(listener *skupperv2alpha1.Listener) error {
+	if len(listener.Spec.Ports) > 0 && listener.Spec.ExposePodsByName {
+		return fmt.Errorf("ports cannot be used with exposePodsByName")
+	}
+	return validatePorts(listener.Spec.Ports)
+}
+
+// ValidateConnectorPorts checks the ports of a multi-port connector
+func ValidateConnectorPorts(connector *skupperv2alpha1.Connector) error {
+	return validatePorts(connector.Spec.Ports)
+}
+
+func validatePorts(ports []skupperv2alpha1.BindingPort) error {
+	var errs []error
+	names := map[string]bool{}
+	routingKeys := map[string]bool{}
+	for i, port := range ports {
+		if msgs := validation.IsDNS1123Label(port.Name); len(msgs) > 0 {
+			errs = append(errs, fmt.Errorf("ports[%d]: invalid name %q: %s", i, port.Name, strings.Join(msgs, ", ")))
+		} else if names[port.Name] {
+			errs = append(errs, fmt.Errorf("ports[%d]: duplicate name %q", i, port.Name))
+		}
+		names[port.Name] = true
+		if port.Port < 1 || port.Port > 65535 {
+			errs = append(errs, fmt.Errorf("ports[%d]: invalid port %d", i, port.Port))
+		}
+		suffix := port.RoutingKey("")
+		if routingKeys[suffix] {
+			errs = append(errs, fmt.Errorf("ports[%d]: duplicate routing key suffix %q", i, strings.TrimPrefix(suffix, ".")))
+		}
+		routingKeys[suffix] = true
+	}
+	return errors.Join(errs...)
+}
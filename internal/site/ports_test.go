@@ -0,0 +1,168 @@
+package site
+
+import (
+	"testing"
+
+	"github.com/skupperproject/skupper/internal/qdr"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+	"gotest.tools/v3/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func brokerPorts() []skupperv2alpha1.BindingPort {
+	return []skupperv2alpha1.BindingPort{
+		{Name: "amqp", Port: 5672},
+		{Name: "console", Port: 15672, RoutingKeySuffix: "mgmt"},
+	}
+}
+
+func TestValidatePorts(t *testing.T) {
+	tests := []struct {
+		name             string
+		ports            []skupperv2alpha1.BindingPort
+		exposePodsByName bool
+		listenerErr      string
+		connectorErr     string
+	}{
+		{
+			name: "no ports",
+		},
+		{
+			name:  "valid ports",
+			ports: brokerPorts(),
+		},
+		{
+			name: "invalid name and port",
+			ports: []skupperv2alpha1.BindingPort{
+				{Name: "AMQP", Port: 5672},
+				{Name: "console", Port: 0},
+			},
+			listenerErr:  "ports[0]: invalid name \"AMQP\": a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')\nports[1]: invalid port 0",
+			connectorErr: "ports[0]: invalid name \"AMQP\": a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')\nports[1]: invalid port 0",
+		},
+		{
+			name: "duplicates",
+			ports: []skupperv2alpha1.BindingPort{
+				{Name: "amqp", Port: 5672},
+				{Name: "amqp", Port: 5671},
+				{Name: "amqps", Port: 5671, RoutingKeySuffix: "amqp"},
+			},
+			listenerErr:  "ports[1]: duplicate name \"amqp\"\nports[1]: duplicate routing key suffix \"amqp\"\nports[2]: duplicate routing key suffix \"amqp\"",
+			connectorErr: "ports[1]: duplicate name \"amqp\"\nports[1]: duplicate routing key suffix \"amqp\"\nports[2]: duplicate routing key suffix \"amqp\"",
+		},
+		{
+			name:             "exposePodsByName",
+			ports:            brokerPorts(),
+			exposePodsByName: true,
+			listenerErr:      "ports cannot be used with exposePodsByName",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			listener := &skupperv2alpha1.Listener{
+				Spec: skupperv2alpha1.ListenerSpec{
+					RoutingKey:       "broker",
+					Host:             "broker",
+					Ports:            tt.ports,
+					ExposePodsByName: tt.exposePodsByName,
+				},
+			}
+			connector := &skupperv2alpha1.Connector{
+				Spec: skupperv2alpha1.ConnectorSpec{
+					RoutingKey:       "broker",
+					Selector:         "app=broker",
+					Ports:            tt.ports,
+					ExposePodsByName: tt.exposePodsByName,
+				},
+			}
+			if tt.listenerErr == "" {
+				assert.NilError(t, ValidateListenerPorts(listener))
+			} else {
+				assert.Error(t, ValidateListenerPorts(listener), tt.listenerErr)
+			}
+			if tt.connectorErr == "" {
+				assert.NilError(t, ValidateConnectorPorts(connector))
+			} else {
+				assert.Error(t, ValidateConnectorPorts(connector), tt.connectorErr)
+			}
+		})
+	}
+}
+
+func TestMultiPortBridgeConfig(t *testing.T) {
+	b := NewBindings("")
+	b.SetSiteId("site-1")
+	b.UpdateListener("broker", &skupperv2alpha1.Listener{
+		ObjectMeta: v1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: skupperv2alpha1.ListenerSpec{
+			RoutingKey: "broker",
+			Host:       "0.0.0.0",
+			Ports:      brokerPorts(),
+		},
+	})
+	b.UpdateConnector("broker", &skupperv2alpha1.Connector{
+		ObjectMeta: v1.ObjectMeta{Name: "broker", Namespace: "test"},
+		Spec: skupperv2alpha1.ConnectorSpec{
+			RoutingKey: "broker",
+			Host:       "10.0.0.1",
+			Ports:      brokerPorts(),
+		},
+	})
+	assert.DeepEqual(t, b.ToBridgeConfig(), qdr.BridgeConfig{
+		TcpListeners: qdr.TcpEndpointMap{
+			"broker-amqp": {
+				Name:    "broker-amqp",
+				SiteId:  "site-1",
+				Host:    "0.0.0.0",
+				Port:    "5672",
+				Address: "broker.amqp",
+			},
+			"broker-console": {
+				Name:    "broker-console",
+				SiteId:  "site-1",
+				Host:    "0.0.0.0",
+				Port:    "15672",
+				Address: "broker.mgmt",
+			},
+		},
+		TcpConnectors: qdr.TcpEndpointMap{
+			"broker-amqp@10.0.0.1": {
+				Name:    "broker-amqp@10.0.0.1",
+				SiteId:  "site-1",
+				Host:    "10.0.0.1",
+				Port:    "5672",
+				Address: "broker.amqp",
+			},
+			"broker-console@10.0.0.1": {
+				Name:    "broker-console@10.0.0.1",
+				SiteId:  "site-1",
+				Host:    "10.0.0.1",
+				Port:    "15672",
+				Address: "broker.mgmt",
+			},
+		},
+	})
+}
+
+func TestMultiPortActiveConnectors(t *testing.T) {
+	listener := &skupperv2alpha1.Listener{
+		ObjectMeta: v1.ObjectMeta{Name: "broker"},
+		Spec: skupperv2alpha1.ListenerSpec{
+			RoutingKey: "broker",
+			Host:       "broker",
+			Ports:      brokerPorts(),
+		},
+	}
+	network := []skupperv2alpha1.SiteRecord{
+		{
+			Id:   "site-1",
+			Name: "west",
+			Services: []skupperv2alpha1.ServiceRecord{
+				{RoutingKey: "broker.amqp", Connectors: []string{"10.0.0.1", "10.0.0.2"}},
+				{RoutingKey: "broker.mgmt", Connectors: []string{"10.0.0.1"}},
+				{RoutingKey: "broker", Connectors: []string{"10.0.0.3"}},
+			},
+		},
+	}
+	assert.DeepEqual(t, ActiveConnectors("site-1", listener, network), []string{"west/10.0.0.1", "west/10.0.0.2"})
+}
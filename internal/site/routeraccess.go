@@ -67,9 +67,28 @@ func (m RouterAccessMap) DesiredConfig(targetGroups []string, profilePath string
 }
 
 type RouterAccessConfig struct {
-	listeners   map[string]qdr.Listener
-	connectors  []qdr.Connector
-	profilePath string
+	listeners      map[string]qdr.Listener
+	connectors     []qdr.Connector
+	profilePath    string
+	revocationList map[string]bool
+}
+
+// WithRevocationList configures the named SslProfiles to refuse
+// certificates in the revocation lists for their CA
+func (g *RouterAccessConfig) WithRevocationList(profiles ...string) *RouterAccessConfig {
+	g.revocationList = map[string]bool{}
+	for _, profile := range profiles {
+		g.revocationList[profile] = true
+	}
+	return g
+}
+
+func (g *RouterAccessConfig) sslProfile(name string) qdr.SslProfile {
+	profile := qdr.ConfigureSslProfile(name, g.profilePath, true)
+	if g.revocationList[name] {
+		profile.ConfigureRevocationList(g.profilePath)
+	}
+	return profile
 }
 
 func (g *RouterAccessConfig) Apply(config *qdr.RouterConfig) bool {
@@ -83,10 +102,20 @@ func (g *RouterAccessConfig) Apply(config *qdr.RouterConfig) bool {
 		}
 	}
 	for _, value := range lc.Added {
-		if config.AddListener(value) && config.AddSslProfile(qdr.ConfigureSslProfile(value.SslProfile, g.profilePath, true)) {
+		if config.AddListener(value) && config.AddSslProfile(g.sslProfile(value.SslProfile)) {
 			changed = true
 		}
 	}
+	// profiles for existing listeners may need updated if use of
+	// revocation lists has changed
+	for _, value := range g.listeners {
+		if profile, ok := config.SslProfiles[value.SslProfile]; ok {
+			profile.CrlFile = g.sslProfile(value.SslProfile).CrlFile
+			if config.AddSslProfile(profile) {
+				changed = true
+			}
+		}
+	}
 	for _, connector := range g.connectors {
 		if config.AddConnector(connector) {
 			changed = true
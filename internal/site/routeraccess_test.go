@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"testing"
 
+	"gotest.tools/v3/assert"
+
 	"github.com/skupperproject/skupper/internal/qdr"
 	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -452,3 +454,38 @@ func TestRouterAccessMap_DesiredConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestRouterAccessConfig_RevocationList(t *testing.T) {
+	ras := RouterAccessMap{
+		"skupper": &skupperv2alpha1.RouterAccess{
+			ObjectMeta: v1.ObjectMeta{Name: "skupper"},
+			Spec: skupperv2alpha1.RouterAccessSpec{
+				TlsCredentials: "skupper-site-server",
+				Roles: []skupperv2alpha1.RouterAccessRole{
+					{Name: "inter-router"},
+				},
+			},
+		},
+	}
+	config := qdr.InitialConfig("router-1", "site-1", "v2.0", false, 10)
+	assert.Assert(t, ras.DesiredConfig(nil, "/etc/certs").Apply(&config))
+	assert.Equal(t, config.SslProfiles["skupper-site-server"].CrlFile, "")
+
+	// enabling revocation lists updates the existing profile
+	assert.Assert(t, ras.DesiredConfig(nil, "/etc/certs").WithRevocationList("skupper-site-server").Apply(&config))
+	assert.DeepEqual(t, config.SslProfiles["skupper-site-server"], qdr.SslProfile{
+		Name:           "skupper-site-server",
+		CertFile:       "/etc/certs/skupper-site-server/tls.crt",
+		PrivateKeyFile: "/etc/certs/skupper-site-server/tls.key",
+		CaCertFile:     "/etc/certs/skupper-site-server/ca.crt",
+		CrlFile:        "/etc/certs/skupper-site-server/ca.crl",
+	})
+	assert.Assert(t, !ras.DesiredConfig(nil, "/etc/certs").WithRevocationList("skupper-site-server").Apply(&config))
+
+	// other profiles are unaffected
+	assert.Assert(t, !ras.DesiredConfig(nil, "/etc/certs").WithRevocationList("skupper-site-server", "other").Apply(&config))
+
+	// and disabling them does likewise
+	assert.Assert(t, ras.DesiredConfig(nil, "/etc/certs").Apply(&config))
+	assert.Equal(t, config.SslProfiles["skupper-site-server"].CrlFile, "")
+}
@@ -0,0 +1,108 @@
+package site
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/skupperproject/skupper/internal/certs"
+	"github.com/skupperproject/skupper/internal/qdr"
+	skupperv2alpha1 "github.com/skupperproject/skupper/pkg/apis/skupper/v2alpha1"
+)
+
+var (
+	hostnameRegex    = regexp.MustCompile(`^[a-z0-9]+([-.]{1}[a-z0-9]+)*$`)
+	validAccessRoles = []string{"edge", "inter-router"}
+)
+
+// The settings recognised for a Site. The certificate settings are
+// validated by certs.RenewalConfigFromSettings.
+var siteSettings = map[string]func(string) error{
+	"size":                         nil,
+	"disable-anti-affinity":        validateBool,
+	"router-logging":               validateRouterLogging,
+	"router-data-connection-count": validatePositiveInt,
+	"certificate-duration":         nil,
+	"certificate-renew-before":     nil,
+	"ca-renew-before":              nil,
+	"ca-rotation-overlap":          nil,
+}
+
+// ValidateHost checks that a host is either an IP address or a
+// hostname
+func ValidateHost(host string) error {
+	if net.ParseIP(host) == nil && !hostnameRegex.MatchString(host) {
+		return fmt.Errorf("a valid IP address or hostname is expected")
+	}
+	return nil
+}
+
+// ValidateRouterAccessRoles checks that a router access has at least
+// one role and that all its roles are recognised
+func ValidateRouterAccessRoles(ra *skupperv2alpha1.RouterAccess) error {
+	if len(ra.Spec.Roles) == 0 {
+		return fmt.Errorf("roles are required")
+	}
+	for _, role := range ra.Spec.Roles {
+		if !isValidAccessRole(role.Name) {
+			return fmt.Errorf("invalid role: %s (valid roles: %s)", role.Name, validAccessRoles)
+		}
+	}
+	return nil
+}
+
+func isValidAccessRole(name string) bool {
+	for _, role := range validAccessRoles {
+		if role == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateSiteSettings checks that all the settings of a site are
+// recognised and have valid values
+func ValidateSiteSettings(settings map[string]string) error {
+	var errs []error
+	var keys []string
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		check, known := siteSettings[key]
+		if !known {
+			errs = append(errs, fmt.Errorf("unknown setting %q", key))
+		} else if check != nil {
+			if err := check(settings[key]); err != nil {
+				errs = append(errs, fmt.Errorf("invalid value for %s: %s", key, err))
+			}
+		}
+	}
+	if _, err := certs.RenewalConfigFromSettings(settings); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func validateBool(value string) error {
+	_, err := strconv.ParseBool(value)
+	return err
+}
+
+func validatePositiveInt(value string) error {
+	if i, err := strconv.Atoi(value); err != nil {
+		return err
+	} else if i < 1 {
+		return fmt.Errorf("must be greater than zero")
+	}
+	return nil
+}
+
+func validateRouterLogging(value string) error {
+	_, err := qdr.ParseRouterLogConfig(value)
+	return err
+}
@@ -3,6 +3,7 @@ package v2alpha1
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -84,6 +85,31 @@ func (s *Status) readyState(requiredConditions []string) ConditionState {
 	return ReadyCondition()
 }
 
+// requiredConditions adds the Permitted condition to those required
+// for readiness whenever an access policy has been applied
+func (s *Status) requiredConditions(conditions ...string) []string {
+	if meta.FindStatusCondition(s.Conditions, CONDITION_TYPE_PERMITTED) != nil {
+		return append(conditions, CONDITION_TYPE_PERMITTED)
+	}
+	return conditions
+}
+
+func (s *Status) setPermitted(err error, generation int64, requiredConditions ...string) bool {
+	if s.SetCondition(CONDITION_TYPE_PERMITTED, ErrorOrReadyCondition(err), generation) {
+		s.setReady(s.requiredConditions(requiredConditions...), generation)
+		return true
+	}
+	return false
+}
+
+func (s *Status) clearPermitted(generation int64, requiredConditions ...string) bool {
+	if meta.RemoveStatusCondition(&s.Conditions, CONDITION_TYPE_PERMITTED) {
+		s.setReady(requiredConditions, generation)
+		return true
+	}
+	return false
+}
+
 func (s *Status) setReady(requiredConditions []string, generation int64) bool {
 	state := s.readyState(requiredConditions)
 	changed := false
@@ -281,14 +307,41 @@ const CONDITION_TYPE_PROCESSED = "Processed"
 const CONDITION_TYPE_REDEEMED = "Redeemed"
 const CONDITION_TYPE_OPERATIONAL = "Operational"
 const CONDITION_TYPE_READY = "Ready"
+const CONDITION_TYPE_HEALTHY = "Healthy"
+const CONDITION_TYPE_PERMITTED = "Permitted"
+const CONDITION_TYPE_VALID = "Valid"
 
 type SiteStatus struct {
 	Status         `json:",inline"`
-	Endpoints      []Endpoint   `json:"endpoints,omitempty"`
-	SitesInNetwork int          `json:"sitesInNetwork,omitempty"`
-	Network        []SiteRecord `json:"network,omitempty"`
-	DefaultIssuer  string       `json:"defaultIssuer,omitempty"`
-	Controller     *Controller  `json:"controller,omitempty"`
+	Endpoints      []Endpoint    `json:"endpoints,omitempty"`
+	SitesInNetwork int           `json:"sitesInNetwork,omitempty"`
+	Network        []SiteRecord  `json:"network,omitempty"`
+	DefaultIssuer  string        `json:"defaultIssuer,omitempty"`
+	Controller     *Controller   `json:"controller,omitempty"`
+	RevokedSites   []RevokedSite `json:"revokedSites,omitempty"`
+}
+
+// RevokedSite identifies a remote site whose links to this site are
+// no longer accepted, as the certificate it was issued on redeeming
+// an AccessGrant has been revoked.
+type RevokedSite struct {
+	Id           string `json:"id"`
+	Name         string `json:"name,omitempty"`
+	AccessGrant  string `json:"accessGrant"`
+	SerialNumber string `json:"serialNumber,omitempty"`
+	Revoked      string `json:"revoked"`
+	Expiration   string `json:"expiration,omitempty"`
+}
+
+// IsRevoked returns true if the site with the supplied id has had
+// its access revoked.
+func (s *SiteStatus) IsRevoked(id string) bool {
+	for _, revoked := range s.RevokedSites {
+		if revoked.Id == id {
+			return true
+		}
+	}
+	return false
 }
 
 type Controller struct {
@@ -347,7 +400,7 @@ type Listener struct {
 
 func (l *Listener) SetConfigured(err error) bool {
 	if l.Status.SetCondition(CONDITION_TYPE_CONFIGURED, ErrorOrReadyCondition(err), l.ObjectMeta.Generation) {
-		l.Status.setReady([]string{CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_MATCHED}, l.ObjectMeta.Generation)
+		l.Status.setReady(l.Status.requiredConditions(CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_MATCHED), l.ObjectMeta.Generation)
 		return true
 	}
 	return false
@@ -363,7 +416,7 @@ func (l *Listener) matched() ConditionState {
 
 func (l *Listener) setMatched() bool {
 	if l.Status.SetCondition(CONDITION_TYPE_MATCHED, l.matched(), l.ObjectMeta.Generation) {
-		l.Status.setReady([]string{CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_MATCHED}, l.ObjectMeta.Generation)
+		l.Status.setReady(l.Status.requiredConditions(CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_MATCHED), l.ObjectMeta.Generation)
 		return true
 	}
 	return false
@@ -381,6 +434,16 @@ func (l *Listener) SetHasMatchingConnector(value bool) bool {
 	return changed
 }
 
+// SetActiveConnectors records the connectors the listener currently
+// routes connections to
+func (l *Listener) SetActiveConnectors(connectors []string) bool {
+	if reflect.DeepEqual(l.Status.ActiveConnectors, connectors) {
+		return false
+	}
+	l.Status.ActiveConnectors = connectors
+	return true
+}
+
 func (l *Listener) Protocol() corev1.Protocol {
 	if l.Spec.Type == "udp" {
 		return corev1.ProtocolUDP
@@ -388,6 +451,35 @@ func (l *Listener) Protocol() corev1.Protocol {
 	return corev1.ProtocolTCP
 }
 
+// SetPermitted records whether the listener is permitted by the access
+// policies in effect for its namespace
+func (l *Listener) SetPermitted(err error) bool {
+	return l.Status.setPermitted(err, l.ObjectMeta.Generation, CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_MATCHED)
+}
+
+func (l *Listener) ClearPermitted() bool {
+	return l.Status.clearPermitted(l.ObjectMeta.Generation, CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_MATCHED)
+}
+
+// PortListeners returns a listener for each port of a multi-port
+// listener, named and routed for that port, or the listener itself if
+// it has a single port
+func (l *Listener) PortListeners() []*Listener {
+	if len(l.Spec.Ports) == 0 {
+		return []*Listener{l}
+	}
+	var listeners []*Listener
+	for _, port := range l.Spec.Ports {
+		listener := l.DeepCopy()
+		listener.Name = PortName(l.Name, port)
+		listener.Spec.Port = port.Port
+		listener.Spec.RoutingKey = port.RoutingKey(l.Spec.RoutingKey)
+		listener.Spec.Ports = nil
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
+
 func (s *Listener) IsConfigured() bool {
 	return meta.IsStatusConditionTrue(s.Status.Conditions, CONDITION_TYPE_CONFIGURED)
 }
@@ -401,6 +493,20 @@ type ListenerList struct {
 	Items       []Listener `json:"items"`
 }
 
+// Load balancing policies for the connections accepted by a listener
+const (
+	// LOAD_BALANCING_BALANCED spreads connections over all connectors with
+	// the listener's routing key. This is the default.
+	LOAD_BALANCING_BALANCED = "balanced"
+	// LOAD_BALANCING_PREFER_LOCAL sends connections to the connectors
+	// closest to the listener, i.e. those in the listener's own site while
+	// there are any.
+	LOAD_BALANCING_PREFER_LOCAL = "preferLocal"
+	// LOAD_BALANCING_FAILOVER sends connections only to the connectors with
+	// the lowest priority value currently present in the network.
+	LOAD_BALANCING_FAILOVER = "failover"
+)
+
 type ListenerSpec struct {
 	RoutingKey       string            `json:"routingKey"`
 	Host             string            `json:"host"`
@@ -408,12 +514,39 @@ type ListenerSpec struct {
 	TlsCredentials   string            `json:"tlsCredentials,omitempty"`
 	Type             string            `json:"type,omitempty"`
 	ExposePodsByName bool              `json:"exposePodsByName,omitempty"`
+	LoadBalancing    string            `json:"loadBalancing,omitempty"`
+	Ports            []BindingPort     `json:"ports,omitempty"`
 	Settings         map[string]string `json:"settings,omitempty"`
 }
 
+// BindingPort is one of the ports of a multi-port listener or
+// connector. Each port is routed on its own routing key: the routing
+// key of the listener or connector followed by '.' and the suffix,
+// which defaults to the name of the port.
+type BindingPort struct {
+	Name             string `json:"name"`
+	Port             int    `json:"port"`
+	RoutingKeySuffix string `json:"routingKeySuffix,omitempty"`
+}
+
+func (p BindingPort) RoutingKey(routingKey string) string {
+	suffix := p.RoutingKeySuffix
+	if suffix == "" {
+		suffix = p.Name
+	}
+	return routingKey + "." + suffix
+}
+
+// PortName returns the name of the listener or connector that a port
+// of a multi-port listener or connector is configured as
+func PortName(name string, port BindingPort) string {
+	return name + "-" + port.Name
+}
+
 type ListenerStatus struct {
 	Status               `json:",inline"`
-	HasMatchingConnector bool `json:"hasMatchingConnector,omitempty"`
+	HasMatchingConnector bool     `json:"hasMatchingConnector,omitempty"`
+	ActiveConnectors     []string `json:"activeConnectors,omitempty"`
 }
 
 type ServicePort struct {
@@ -433,7 +566,7 @@ type Connector struct {
 
 func (c *Connector) SetConfigured(err error) bool {
 	if c.Status.SetCondition(CONDITION_TYPE_CONFIGURED, ErrorOrReadyCondition(err), c.ObjectMeta.Generation) {
-		c.Status.setReady([]string{CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_MATCHED}, c.ObjectMeta.Generation)
+		c.Status.setReady(c.Status.requiredConditions(CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_MATCHED), c.ObjectMeta.Generation)
 		return true
 	}
 	return false
@@ -449,7 +582,7 @@ func (c *Connector) matched() ConditionState {
 
 func (c *Connector) setMatched() bool {
 	if c.Status.SetCondition(CONDITION_TYPE_MATCHED, c.matched(), c.ObjectMeta.Generation) {
-		c.Status.setReady([]string{CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_MATCHED}, c.ObjectMeta.Generation)
+		c.Status.setReady(c.Status.requiredConditions(CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_MATCHED), c.ObjectMeta.Generation)
 		return true
 	}
 	return false
@@ -475,6 +608,43 @@ func (c *Connector) SetSelectedPods(pods []PodDetails) bool {
 	return false
 }
 
+// PortConnectors returns a connector for each port of a multi-port
+// connector, named and routed for that port, or the connector itself
+// if it has a single port
+func (c *Connector) PortConnectors() []*Connector {
+	if len(c.Spec.Ports) == 0 {
+		return []*Connector{c}
+	}
+	var connectors []*Connector
+	for _, port := range c.Spec.Ports {
+		connector := c.DeepCopy()
+		connector.Name = PortName(c.Name, port)
+		connector.Spec.Port = port.Port
+		connector.Spec.RoutingKey = port.RoutingKey(c.Spec.RoutingKey)
+		connector.Spec.Ports = nil
+		connectors = append(connectors, connector)
+	}
+	return connectors
+}
+
+func (c *Connector) SetHealthy(err error) bool {
+	return c.Status.SetCondition(CONDITION_TYPE_HEALTHY, ErrorOrReadyCondition(err), c.ObjectMeta.Generation)
+}
+
+func (c *Connector) ClearHealthy() bool {
+	return meta.RemoveStatusCondition(&c.Status.Conditions, CONDITION_TYPE_HEALTHY)
+}
+
+// SetPermitted records whether the connector is permitted by the access
+// policies in effect for its namespace
+func (c *Connector) SetPermitted(err error) bool {
+	return c.Status.setPermitted(err, c.ObjectMeta.Generation, CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_MATCHED)
+}
+
+func (c *Connector) ClearPermitted() bool {
+	return c.Status.clearPermitted(c.ObjectMeta.Generation, CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_MATCHED)
+}
+
 func (s *Connector) IsConfigured() bool {
 	return meta.IsStatusConditionTrue(s.Status.Conditions, CONDITION_TYPE_CONFIGURED)
 }
@@ -499,13 +669,39 @@ type ConnectorSpec struct {
 	Type                string            `json:"type,omitempty"`
 	ExposePodsByName    bool              `json:"exposePodsByName,omitempty"`
 	IncludeNotReadyPods bool              `json:"includeNotReadyPods,omitempty"`
+	Priority            int               `json:"priority,omitempty"`
+	Weight              int               `json:"weight,omitempty"`
+	HealthCheck         *HealthCheck      `json:"healthCheck,omitempty"`
+	Ports               []BindingPort     `json:"ports,omitempty"`
 	Settings            map[string]string `json:"settings,omitempty"`
 }
 
+const HEALTH_CHECK_TCP = "tcp"
+const HEALTH_CHECK_TLS = "tls"
+
+const HEALTH_HEALTHY = "Healthy"
+const HEALTH_UNHEALTHY = "Unhealthy"
+
+// HealthCheck configures the probing of the targets of a connector,
+// on its port or the first of its ports. Targets that fail are removed
+// from the router configuration until they recover.
+type HealthCheck struct {
+	// Type is either tcp (the default), which checks that a
+	// connection can be established, or tls, which also completes a
+	// TLS handshake.
+	Type             string `json:"type,omitempty"`
+	PeriodSeconds    int    `json:"periodSeconds,omitempty"`
+	TimeoutSeconds   int    `json:"timeoutSeconds,omitempty"`
+	FailureThreshold int    `json:"failureThreshold,omitempty"`
+	SuccessThreshold int    `json:"successThreshold,omitempty"`
+}
+
 type PodDetails struct {
-	UID  string `json:"-"`
-	Name string `json:"name,omitempty"`
-	IP   string `json:"ip,omitempty"`
+	UID           string `json:"-"`
+	Name          string `json:"name,omitempty"`
+	IP            string `json:"ip,omitempty"`
+	Health        string `json:"health,omitempty"`
+	HealthMessage string `json:"healthMessage,omitempty"`
 }
 
 type ConnectorStatus struct {
@@ -526,7 +722,7 @@ type Link struct {
 
 func (l *Link) SetConfigured(err error) bool {
 	if l.Status.SetCondition(CONDITION_TYPE_CONFIGURED, ErrorOrReadyCondition(err), l.ObjectMeta.Generation) {
-		l.Status.setReady([]string{CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_OPERATIONAL}, l.ObjectMeta.Generation)
+		l.Status.setReady(l.Status.requiredConditions(CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_OPERATIONAL), l.ObjectMeta.Generation)
 		return true
 	}
 	return false
@@ -551,7 +747,7 @@ func (l *Link) SetOperational(operational bool, remoteSiteId string, remoteSiteN
 		changed = true
 	}
 	if l.Status.SetCondition(CONDITION_TYPE_OPERATIONAL, operationalState(operational), l.ObjectMeta.Generation) {
-		l.Status.setReady([]string{CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_OPERATIONAL}, l.ObjectMeta.Generation)
+		l.Status.setReady(l.Status.requiredConditions(CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_OPERATIONAL), l.ObjectMeta.Generation)
 		return true
 	}
 	return changed
@@ -566,6 +762,16 @@ func (l *Link) IsReady() bool {
 		meta.IsStatusConditionTrue(l.Status.Conditions, CONDITION_TYPE_OPERATIONAL)
 }
 
+// SetPermitted records whether the link is permitted by the access
+// policies in effect for its namespace
+func (l *Link) SetPermitted(err error) bool {
+	return l.Status.setPermitted(err, l.ObjectMeta.Generation, CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_OPERATIONAL)
+}
+
+func (l *Link) ClearPermitted() bool {
+	return l.Status.clearPermitted(l.ObjectMeta.Generation, CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_OPERATIONAL)
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // LinkList contains a List of Link instances
@@ -692,20 +898,107 @@ type AccessGrantList struct {
 }
 
 type AccessGrantSpec struct {
-	RedemptionsAllowed int               `json:"redemptionsAllowed,omitempty"`
-	ExpirationWindow   string            `json:"expirationWindow,omitempty"`
-	Code               string            `json:"code,omitempty"`
-	Issuer             string            `json:"issuer,omitempty"`
-	Settings           map[string]string `json:"settings,omitempty"`
+	RedemptionsAllowed int    `json:"redemptionsAllowed,omitempty"`
+	ExpirationWindow   string `json:"expirationWindow,omitempty"`
+	Code               string `json:"code,omitempty"`
+	Issuer             string `json:"issuer,omitempty"`
+	Revoked            bool   `json:"revoked,omitempty"`
+	// AllowedSourceRanges, if specified, restricts redemption to
+	// clients whose address is within one of the listed CIDRs.
+	AllowedSourceRanges []string `json:"allowedSourceRanges,omitempty"`
+	// SiteNamePattern, if specified, is a glob pattern which the
+	// name of the redeeming site must match.
+	SiteNamePattern string `json:"siteNamePattern,omitempty"`
+	// NotBefore, if specified, is the RFC3339 time before which
+	// the grant cannot be redeemed.
+	NotBefore string            `json:"notBefore,omitempty"`
+	Settings  map[string]string `json:"settings,omitempty"`
 }
 
 type AccessGrantStatus struct {
 	Status         `json:",inline"`
-	Url            string `json:"url,omitempty"`
-	Code           string `json:"code,omitempty"`
-	Ca             string `json:"ca,omitempty"`
-	Redemptions    int    `json:"redemptions,omitempty"`
-	ExpirationTime string `json:"expirationTime,omitempty"`
+	Url            string              `json:"url,omitempty"`
+	Code           string              `json:"code,omitempty"`
+	Ca             string              `json:"ca,omitempty"`
+	Redemptions    int                 `json:"redemptions,omitempty"`
+	ExpirationTime string              `json:"expirationTime,omitempty"`
+	Issued         []IssuedCertificate `json:"issued,omitempty"`
+	// The most recent attempts to redeem the grant, oldest first.
+	RedemptionAttempts []RedemptionAttempt `json:"redemptionAttempts,omitempty"`
+}
+
+// RedemptionAttempt is an audit record of an attempt to redeem an
+// AccessGrant.
+type RedemptionAttempt struct {
+	Time      string `json:"time"`
+	Peer      string `json:"peer,omitempty"`
+	SiteName  string `json:"siteName,omitempty"`
+	Link      string `json:"link,omitempty"`
+	Succeeded bool   `json:"succeeded"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// AddRedemptionAttempt records an attempt to redeem the grant,
+// retaining at most limit records.
+func (g *AccessGrant) AddRedemptionAttempt(attempt RedemptionAttempt, limit int) {
+	g.Status.RedemptionAttempts = append(g.Status.RedemptionAttempts, attempt)
+	if excess := len(g.Status.RedemptionAttempts) - limit; excess > 0 {
+		g.Status.RedemptionAttempts = g.Status.RedemptionAttempts[excess:]
+	}
+}
+
+// AddIssuedCertificate records a certificate issued on redemption of
+// the grant, discarding the records of any certificates that have
+// expired, as those no longer need to be revoked.
+func (g *AccessGrant) AddIssuedCertificate(issued IssuedCertificate, now time.Time) {
+	var current []IssuedCertificate
+	for _, existing := range g.Status.Issued {
+		if !existing.IsExpired(now) {
+			current = append(current, existing)
+		}
+	}
+	g.Status.Issued = append(current, issued)
+}
+
+// IssuedCertificate records a certificate issued on redemption of an
+// AccessGrant. The subject identifies the site that redeemed it.
+type IssuedCertificate struct {
+	Name         string `json:"name"`
+	Subject      string `json:"subject"`
+	SerialNumber string `json:"serialNumber,omitempty"`
+	Expiration   string `json:"expiration,omitempty"`
+	Revoked      string `json:"revoked,omitempty"`
+}
+
+func (c *IssuedCertificate) IsRevoked() bool {
+	return c.Revoked != ""
+}
+
+// IsExpired returns true if the certificate is known to have expired
+// by the supplied time.
+func (c *IssuedCertificate) IsExpired(now time.Time) bool {
+	expiration, err := time.Parse(time.RFC3339, c.Expiration)
+	return err == nil && !now.Before(expiration)
+}
+
+// Revoke marks the certificate as revoked, returning true if it was
+// not already.
+func (c *IssuedCertificate) Revoke(now time.Time) bool {
+	if c.IsRevoked() {
+		return false
+	}
+	c.Revoked = now.UTC().Format(time.RFC3339)
+	return true
+}
+
+// Reinstate clears any revocation of the certificate, returning true
+// if it had been revoked.
+func (c *IssuedCertificate) Reinstate() bool {
+	if !c.IsRevoked() {
+		return false
+	}
+	c.Revoked = ""
+	return true
 }
 
 // +genclient
@@ -845,6 +1138,75 @@ func (c *Certificate) SetReady(err error) bool {
 	return c.Status.SetCondition(CONDITION_TYPE_READY, ErrorOrReadyCondition(err), c.ObjectMeta.Generation)
 }
 
+func validity(expiration time.Time, renewal time.Time, now time.Time) ConditionState {
+	if !now.Before(expiration) {
+		return ErrorCondition(fmt.Errorf("Expired at %s", expiration.Format(time.RFC3339)))
+	}
+	if !now.Before(renewal) {
+		return PendingCondition(fmt.Sprintf("Expires at %s", expiration.Format(time.RFC3339)))
+	}
+	return ReadyCondition()
+}
+
+// SetExpiration records when the certificate expires. The Valid
+// condition is false once the renewal time has passed without the
+// certificate having been renewed.
+func (c *Certificate) SetExpiration(expiration time.Time, renewal time.Time, now time.Time) bool {
+	changed := false
+	value := expiration.UTC().Format(time.RFC3339)
+	if c.Status.Expiration != value {
+		c.Status.Expiration = value
+		changed = true
+	}
+	if c.Status.SetCondition(CONDITION_TYPE_VALID, validity(expiration, renewal, now), c.ObjectMeta.Generation) {
+		changed = true
+	}
+	return changed
+}
+
+func (c *Certificate) IsValid() bool {
+	return !meta.IsStatusConditionFalse(c.Status.Conditions, CONDITION_TYPE_VALID)
+}
+
+// IssuerReference identifies an issuer external to skupper, such as a
+// cert-manager Issuer or ClusterIssuer.
+type IssuerReference struct {
+	Name  string
+	Kind  string
+	Group string
+}
+
+func (r IssuerReference) String() string {
+	return fmt.Sprintf("%s.%s/%s", r.Kind, r.Group, r.Name)
+}
+
+// ParseIssuerReference determines whether the CA of a Certificate
+// refers to an external issuer. These are specified as
+// <kind>.<group>/<name>, e.g. ClusterIssuer.cert-manager.io/my-pki,
+// which cannot be confused with the name of a CA managed by skupper
+// as names of Secrets cannot contain a '/'.
+func ParseIssuerReference(ca string) (IssuerReference, bool) {
+	kindAndGroup, name, ok := strings.Cut(ca, "/")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return IssuerReference{}, false
+	}
+	kind, group, ok := strings.Cut(kindAndGroup, ".")
+	if !ok || kind == "" || group == "" {
+		return IssuerReference{}, false
+	}
+	return IssuerReference{
+		Name:  name,
+		Kind:  kind,
+		Group: group,
+	}, true
+}
+
+// ExternalIssuer returns the reference to the issuer of the
+// certificate if that issuer is external to skupper.
+func (c *Certificate) ExternalIssuer() (IssuerReference, bool) {
+	return ParseIssuerReference(c.Spec.Ca)
+}
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -870,7 +1232,7 @@ func (r *RouterAccess) FindRole(name string) *RouterAccessRole {
 
 func (r *RouterAccess) SetConfigured(err error) bool {
 	if r.Status.SetCondition(CONDITION_TYPE_CONFIGURED, ErrorOrReadyCondition(err), r.ObjectMeta.Generation) {
-		r.Status.setReady([]string{CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_RESOLVED}, r.ObjectMeta.Generation)
+		r.Status.setReady(r.Status.requiredConditions(CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_RESOLVED), r.ObjectMeta.Generation)
 		return true
 	}
 	return false
@@ -882,7 +1244,7 @@ func (r *RouterAccess) Resolve(endpoints []Endpoint, group string) bool {
 		changed = true
 	}
 	if r.Status.SetCondition(CONDITION_TYPE_RESOLVED, ReadyOrPendingCondition(len(r.Status.Endpoints) > 0), r.ObjectMeta.Generation) {
-		r.Status.setReady([]string{CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_RESOLVED}, r.ObjectMeta.Generation)
+		r.Status.setReady(r.Status.requiredConditions(CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_RESOLVED), r.ObjectMeta.Generation)
 		changed = true
 	}
 	return changed
@@ -892,6 +1254,22 @@ func (r *RouterAccess) IsConfigured() bool {
 	return meta.IsStatusConditionTrue(r.Status.Conditions, CONDITION_TYPE_CONFIGURED)
 }
 
+// SetPermitted records whether the router access is permitted by the
+// access policies in effect for its namespace
+func (r *RouterAccess) SetPermitted(err error) bool {
+	return r.Status.setPermitted(err, r.ObjectMeta.Generation, CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_RESOLVED)
+}
+
+func (r *RouterAccess) ClearPermitted() bool {
+	return r.Status.clearPermitted(r.ObjectMeta.Generation, CONDITION_TYPE_CONFIGURED, CONDITION_TYPE_RESOLVED)
+}
+
+// IsLinkAccess returns true if the router access accepts links from
+// other sites
+func (r *RouterAccess) IsLinkAccess() bool {
+	return r.FindRole("inter-router") != nil || r.FindRole("edge") != nil
+}
+
 // endpoints are assumed all to be from one group
 func (s *RouterAccessStatus) UpdateEndpointsForGroup(endpoints []Endpoint, group string) bool {
 	all := []Endpoint{}
@@ -1074,3 +1452,51 @@ type AttachedConnectorBindingSpec struct {
 	ExposePodsByName   bool              `json:"exposePodsByName,omitempty"`
 	Settings           map[string]string `json:"settings,omitempty"`
 }
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AccessPolicy restricts what the sites in a namespace may expose,
+// consume and link to. A field that is not set places no restriction;
+// where several policies apply, all of them must permit a resource.
+type AccessPolicy struct {
+	v1.TypeMeta   `json:",inline"`
+	v1.ObjectMeta `json:"metadata,omitempty"`
+	Spec          AccessPolicySpec   `json:"spec,omitempty"`
+	Status        AccessPolicyStatus `json:"status,omitempty"`
+}
+
+func (p *AccessPolicy) SetConfigured(err error) bool {
+	if p.Status.SetCondition(CONDITION_TYPE_CONFIGURED, ErrorOrReadyCondition(err), p.ObjectMeta.Generation) {
+		p.Status.setReady([]string{CONDITION_TYPE_CONFIGURED}, p.ObjectMeta.Generation)
+		return true
+	}
+	return false
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AccessPolicyList contains a List of AccessPolicy instances
+type AccessPolicyList struct {
+	v1.TypeMeta `json:",inline"`
+	v1.ListMeta `json:"metadata,omitempty"`
+	Items       []AccessPolicy `json:"items"`
+}
+
+type AccessPolicySpec struct {
+	// routing keys (or glob patterns) that connectors may expose
+	AllowedExposedRoutingKeys []string `json:"allowedExposedRoutingKeys,omitempty"`
+	// routing keys (or glob patterns) that listeners may consume
+	AllowedConsumedRoutingKeys []string `json:"allowedConsumedRoutingKeys,omitempty"`
+	// ids (or glob patterns) of the remote sites that links may connect
+	// to, or that may redeem AccessGrants to link to this site. The id
+	// presented when redeeming an AccessGrant is asserted by the
+	// redeeming site and is not verified.
+	AllowedRemoteSites []string `json:"allowedRemoteSites,omitempty"`
+	// whether router access accepting links from other sites may be enabled
+	AllowLinkAccess *bool `json:"allowLinkAccess,omitempty"`
+}
+
+type AccessPolicyStatus struct {
+	Status `json:",inline"`
+}
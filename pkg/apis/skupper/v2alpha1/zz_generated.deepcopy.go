@@ -90,6 +90,11 @@ func (in *AccessGrantList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccessGrantSpec) DeepCopyInto(out *AccessGrantSpec) {
 	*out = *in
+	if in.AllowedSourceRanges != nil {
+		in, out := &in.AllowedSourceRanges, &out.AllowedSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Settings != nil {
 		in, out := &in.Settings, &out.Settings
 		*out = make(map[string]string, len(*in))
@@ -114,6 +119,16 @@ func (in *AccessGrantSpec) DeepCopy() *AccessGrantSpec {
 func (in *AccessGrantStatus) DeepCopyInto(out *AccessGrantStatus) {
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
+	if in.Issued != nil {
+		in, out := &in.Issued, &out.Issued
+		*out = make([]IssuedCertificate, len(*in))
+		copy(*out, *in)
+	}
+	if in.RedemptionAttempts != nil {
+		in, out := &in.RedemptionAttempts, &out.RedemptionAttempts
+		*out = make([]RedemptionAttempt, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -127,6 +142,120 @@ func (in *AccessGrantStatus) DeepCopy() *AccessGrantStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessPolicy) DeepCopyInto(out *AccessPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessPolicy.
+func (in *AccessPolicy) DeepCopy() *AccessPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccessPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessPolicyList) DeepCopyInto(out *AccessPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AccessPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessPolicyList.
+func (in *AccessPolicyList) DeepCopy() *AccessPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AccessPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessPolicySpec) DeepCopyInto(out *AccessPolicySpec) {
+	*out = *in
+	if in.AllowedExposedRoutingKeys != nil {
+		in, out := &in.AllowedExposedRoutingKeys, &out.AllowedExposedRoutingKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedConsumedRoutingKeys != nil {
+		in, out := &in.AllowedConsumedRoutingKeys, &out.AllowedConsumedRoutingKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedRemoteSites != nil {
+		in, out := &in.AllowedRemoteSites, &out.AllowedRemoteSites
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowLinkAccess != nil {
+		in, out := &in.AllowLinkAccess, &out.AllowLinkAccess
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessPolicySpec.
+func (in *AccessPolicySpec) DeepCopy() *AccessPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessPolicyStatus) DeepCopyInto(out *AccessPolicyStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessPolicyStatus.
+func (in *AccessPolicyStatus) DeepCopy() *AccessPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccessToken) DeepCopyInto(out *AccessToken) {
 	*out = *in
@@ -435,6 +564,22 @@ func (in *AttachedConnectorStatus) DeepCopy() *AttachedConnectorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindingPort) DeepCopyInto(out *BindingPort) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingPort.
+func (in *BindingPort) DeepCopy() *BindingPort {
+	if in == nil {
+		return nil
+	}
+	out := new(BindingPort)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Certificate) DeepCopyInto(out *Certificate) {
 	*out = *in
@@ -621,6 +766,16 @@ func (in *ConnectorList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConnectorSpec) DeepCopyInto(out *ConnectorSpec) {
 	*out = *in
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HealthCheck)
+		**out = **in
+	}
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]BindingPort, len(*in))
+		copy(*out, *in)
+	}
 	if in.Settings != nil {
 		in, out := &in.Settings, &out.Settings
 		*out = make(map[string]string, len(*in))
@@ -695,6 +850,38 @@ func (in *Endpoint) DeepCopy() *Endpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthCheck) DeepCopyInto(out *HealthCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthCheck.
+func (in *HealthCheck) DeepCopy() *HealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IssuedCertificate) DeepCopyInto(out *IssuedCertificate) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IssuedCertificate.
+func (in *IssuedCertificate) DeepCopy() *IssuedCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(IssuedCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Link) DeepCopyInto(out *Link) {
 	*out = *in
@@ -881,6 +1068,11 @@ func (in *ListenerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ListenerSpec) DeepCopyInto(out *ListenerSpec) {
 	*out = *in
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]BindingPort, len(*in))
+		copy(*out, *in)
+	}
 	if in.Settings != nil {
 		in, out := &in.Settings, &out.Settings
 		*out = make(map[string]string, len(*in))
@@ -905,6 +1097,11 @@ func (in *ListenerSpec) DeepCopy() *ListenerSpec {
 func (in *ListenerStatus) DeepCopyInto(out *ListenerStatus) {
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
+	if in.ActiveConnectors != nil {
+		in, out := &in.ActiveConnectors, &out.ActiveConnectors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -934,6 +1131,38 @@ func (in *PodDetails) DeepCopy() *PodDetails {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedemptionAttempt) DeepCopyInto(out *RedemptionAttempt) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedemptionAttempt.
+func (in *RedemptionAttempt) DeepCopy() *RedemptionAttempt {
+	if in == nil {
+		return nil
+	}
+	out := new(RedemptionAttempt)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RevokedSite) DeepCopyInto(out *RevokedSite) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RevokedSite.
+func (in *RevokedSite) DeepCopy() *RevokedSite {
+	if in == nil {
+		return nil
+	}
+	out := new(RevokedSite)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouterAccess) DeepCopyInto(out *RouterAccess) {
 	*out = *in
@@ -1375,6 +1604,11 @@ func (in *SiteStatus) DeepCopyInto(out *SiteStatus) {
 		*out = new(Controller)
 		**out = **in
 	}
+	if in.RevokedSites != nil {
+		in, out := &in.RevokedSites, &out.RevokedSites
+		*out = make([]RevokedSite, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -32,6 +32,10 @@ func (c *FakeSkupperV2alpha1) AccessGrants(namespace string) v2alpha1.AccessGran
 	return &FakeAccessGrants{c, namespace}
 }
 
+func (c *FakeSkupperV2alpha1) AccessPolicies(namespace string) v2alpha1.AccessPolicyInterface {
+	return &FakeAccessPolicies{c, namespace}
+}
+
 func (c *FakeSkupperV2alpha1) AccessTokens(namespace string) v2alpha1.AccessTokenInterface {
 	return &FakeAccessTokens{c, namespace}
 }
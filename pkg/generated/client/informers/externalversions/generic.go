@@ -55,6 +55,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=skupper.io, Version=v2alpha1
 	case v2alpha1.SchemeGroupVersion.WithResource("accessgrants"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Skupper().V2alpha1().AccessGrants().Informer()}, nil
+	case v2alpha1.SchemeGroupVersion.WithResource("accesspolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Skupper().V2alpha1().AccessPolicies().Informer()}, nil
 	case v2alpha1.SchemeGroupVersion.WithResource("accesstokens"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Skupper().V2alpha1().AccessTokens().Informer()}, nil
 	case v2alpha1.SchemeGroupVersion.WithResource("attachedconnectors"):
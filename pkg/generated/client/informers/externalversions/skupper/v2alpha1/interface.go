@@ -26,6 +26,8 @@ import (
 type Interface interface {
 	// AccessGrants returns a AccessGrantInformer.
 	AccessGrants() AccessGrantInformer
+	// AccessPolicies returns a AccessPolicyInformer.
+	AccessPolicies() AccessPolicyInformer
 	// AccessTokens returns a AccessTokenInformer.
 	AccessTokens() AccessTokenInformer
 	// AttachedConnectors returns a AttachedConnectorInformer.
@@ -64,6 +66,11 @@ func (v *version) AccessGrants() AccessGrantInformer {
 	return &accessGrantInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// AccessPolicies returns a AccessPolicyInformer.
+func (v *version) AccessPolicies() AccessPolicyInformer {
+	return &accessPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // AccessTokens returns a AccessTokenInformer.
 func (v *version) AccessTokens() AccessTokenInformer {
 	return &accessTokenInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
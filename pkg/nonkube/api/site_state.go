@@ -36,6 +36,7 @@ type SiteState struct {
 	Claims          map[string]*v2alpha1.AccessToken
 	Certificates    map[string]*v2alpha1.Certificate
 	SecuredAccesses map[string]*v2alpha1.SecuredAccess
+	AccessPolicies  map[string]*v2alpha1.AccessPolicy
 	bundle          bool
 }
 
@@ -51,6 +52,7 @@ func NewSiteState(bundle bool) *SiteState {
 		Claims:          make(map[string]*v2alpha1.AccessToken),
 		Certificates:    map[string]*v2alpha1.Certificate{},
 		SecuredAccesses: map[string]*v2alpha1.SecuredAccess{},
+		AccessPolicies:  map[string]*v2alpha1.AccessPolicy{},
 		bundle:          bundle,
 	}
 }
@@ -93,6 +95,20 @@ func (s *SiteState) HasLinkAccess() bool {
 	return false
 }
 
+func (s *SiteState) accessPolicies() *site.AccessPolicies {
+	policies := site.NewAccessPolicies()
+	for name, policy := range s.AccessPolicies {
+		policies.Update(name, policy)
+	}
+	return policies
+}
+
+// RouterAccessPermitted returns an error if the access policies of the
+// site do not permit the given router access
+func (s *SiteState) RouterAccessPermitted(la *v2alpha1.RouterAccess) error {
+	return s.accessPolicies().RouterAccessPermitted(la)
+}
+
 func (s *SiteState) CreateRouterAccess(name string, port int) {
 	tlsCaName := fmt.Sprintf("%s-ca", name)
 	tlsServerName := fmt.Sprintf("%s-server", name)
@@ -241,15 +257,27 @@ func (s *SiteState) newCertificate(name string, spec *v2alpha1.CertificateSpec)
 }
 
 func (s *SiteState) linkAccessMap() site.RouterAccessMap {
+	policies := s.accessPolicies()
 	linkAccessMap := site.RouterAccessMap{}
 	for name, linkAccess := range s.RouterAccesses {
+		err := policies.RouterAccessPermitted(linkAccess)
+		policies.SetPermitted(linkAccess, err)
+		if err != nil {
+			continue
+		}
 		linkAccessMap[name] = linkAccess
 	}
 	return linkAccessMap
 }
 func (s *SiteState) linkMap(sslProfileBasePath string) site.LinkMap {
+	policies := s.accessPolicies()
 	linkMap := site.LinkMap{}
 	for name, link := range s.Links {
+		err := policies.LinkPermitted(link)
+		policies.SetPermitted(link, err)
+		if err != nil {
+			continue
+		}
 		siteLink := site.NewLink(name, path.Join(sslProfileBasePath, string(CertificatesPath)))
 		link.SetConfigured(nil)
 		siteLink.Update(link)
@@ -259,22 +287,40 @@ func (s *SiteState) linkMap(sslProfileBasePath string) site.LinkMap {
 }
 
 func (s *SiteState) bindings(sslProfileBasePath string) *site.Bindings {
+	policies := s.accessPolicies()
 	b := site.NewBindings(path.Join(sslProfileBasePath, string(CertificatesPath)))
 	for name, connector := range s.Connectors {
+		err := policies.ConnectorPermitted(connector)
+		policies.SetPermitted(connector, err)
+		if err != nil {
+			continue
+		}
 		connector.SetConfigured(nil)
 		_ = b.UpdateConnector(name, connector)
 	}
 	for name, listener := range s.Listeners {
+		err := policies.ListenerPermitted(listener)
+		policies.SetPermitted(listener, err)
+		if err != nil {
+			continue
+		}
 		listener.SetConfigured(nil)
 		_ = b.UpdateListener(name, listener)
 	}
 	return b
 }
 
-func (s *SiteState) ToRouterConfig(sslProfileBasePath string, platform string) qdr.RouterConfig {
+// GetSiteId returns the id of the site, assigning one if the site does
+// not have one yet.
+func (s *SiteState) GetSiteId() string {
 	if s.SiteId == "" {
 		s.SiteId = uuid.New().String()
 	}
+	return s.SiteId
+}
+
+func (s *SiteState) ToRouterConfig(sslProfileBasePath string, platform string) qdr.RouterConfig {
+	s.GetSiteId()
 	var routerName = s.Site.Name
 	if !s.bundle {
 		routerName = fmt.Sprintf("%s-%d", s.Site.Name, time.Now().Unix())
@@ -336,6 +382,7 @@ func (s *SiteState) SetNamespace(namespace string) {
 	setNamespaceOnMap(s.Claims, namespace)
 	setNamespaceOnMap(s.Certificates, namespace)
 	setNamespaceOnMap(s.SecuredAccesses, namespace)
+	setNamespaceOnMap(s.AccessPolicies, namespace)
 }
 
 func marshal(outputDirectory, resourceType, resourceName string, resource interface{}) error {
@@ -397,6 +444,9 @@ func MarshalSiteState(siteState SiteState, outputDirectory string) error {
 	if err = marshalMap(outputDirectory, "SecuredAccess", siteState.SecuredAccesses); err != nil {
 		return err
 	}
+	if err = marshalMap(outputDirectory, "AccessPolicy", siteState.AccessPolicies); err != nil {
+		return err
+	}
 	if err = marshalMap(outputDirectory, "Secret", siteState.Secrets); err != nil {
 		return err
 	}
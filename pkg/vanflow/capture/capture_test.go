@@ -0,0 +1,133 @@
+package capture
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/session"
+	"gotest.tools/v3/assert"
+)
+
+func TestCaptureReplay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	beacon := vanflow.BeaconMessage{
+		Version:    1,
+		SourceType: "ROUTER",
+		Address:    "mc/sfe.router-1",
+		Direct:     "sfe.router-1",
+		Identity:   "router-1",
+	}
+	heartbeat := vanflow.HeartbeatMessage{Identity: "router-1", Version: 1, Now: 1700000000}
+	record := vanflow.RecordMessage{
+		MessageProps: vanflow.MessageProps{To: "mc/sfe.router-1"},
+		Records: []vanflow.Record{
+			vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-1"), Name: ptrTo("west")},
+		},
+	}
+	recordMsg, err := record.Encode()
+	assert.NilError(t, err)
+
+	// capture messages sent through a mock router
+	var buf bytes.Buffer
+	writer := NewWriter(&buf)
+	factory := NewCaptureContainerFactory(session.NewMockContainerFactory(), writer, nil)
+	ctr := factory.Create()
+	ctr.Start(ctx)
+	beacons := ctr.NewReceiver("mc/sfe.all", session.ReceiverOptions{})
+	source := ctr.NewReceiver("mc/sfe.router-1", session.ReceiverOptions{})
+	assert.NilError(t, ctr.NewSender("mc/sfe.all", session.SenderOptions{}).Send(ctx, beacon.Encode()))
+	assert.NilError(t, ctr.NewSender("mc/sfe.router-1", session.SenderOptions{}).Send(ctx, heartbeat.Encode()))
+	assert.NilError(t, ctr.NewSender("mc/sfe.router-1", session.SenderOptions{}).Send(ctx, recordMsg))
+	for _, expected := range []struct {
+		Receiver session.Receiver
+		Subject  string
+	}{
+		{beacons, "BEACON"},
+		{source, "HEARTBEAT"},
+		{source, "RECORD"},
+	} {
+		msg, err := expected.Receiver.Next(ctx)
+		assert.NilError(t, err)
+		assert.Equal(t, *msg.Properties.Subject, expected.Subject)
+	}
+	assert.Equal(t, writer.Count(), 3)
+	assert.NilError(t, writer.Close())
+
+	// read the capture back
+	reader, err := NewReader(bytes.NewReader(buf.Bytes()))
+	assert.NilError(t, err)
+	var entries []Entry
+	for {
+		entry, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NilError(t, err)
+		entries = append(entries, entry)
+	}
+	assert.Equal(t, len(entries), 3)
+	assert.Equal(t, entries[0].Address, "mc/sfe.all")
+	capturedBeacon, err := entries[0].Decode()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, capturedBeacon, vanflow.BeaconMessage{
+		MessageProps: vanflow.MessageProps{To: "mc/sfe.all", Subject: "BEACON"},
+		Version:      1,
+		SourceType:   "ROUTER",
+		Address:      "mc/sfe.router-1",
+		Direct:       "sfe.router-1",
+		Identity:     "router-1",
+	})
+	decoded, err := entries[2].Decode()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, decoded.(vanflow.RecordMessage).Records, record.Records)
+
+	// replay the capture
+	reader, err = NewReader(bytes.NewReader(buf.Bytes()))
+	assert.NilError(t, err)
+	completed := make(chan error, 1)
+	replay := NewReplayContainerFactory(reader, ReplayOptions{
+		OnComplete: func(err error) { completed <- err },
+	}).Create()
+	replayBeacons := replay.NewReceiver("mc/sfe.all", session.ReceiverOptions{})
+	replay.Start(ctx)
+	msg, err := replayBeacons.Next(ctx)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, vanflow.DecodeBeacon(msg), capturedBeacon)
+
+	// messages wait for a receiver to listen on their address
+	replaySource := replay.NewReceiver("mc/sfe.router-1", session.ReceiverOptions{})
+	msg, err = replaySource.Next(ctx)
+	assert.NilError(t, err)
+	assert.Equal(t, *msg.Properties.Subject, "HEARTBEAT")
+	msg, err = replaySource.Next(ctx)
+	assert.NilError(t, err)
+	replayed, err := vanflow.DecodeRecord(msg)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, replayed.Records, record.Records)
+	assert.NilError(t, <-completed)
+	assert.NilError(t, replay.NewSender("sfe.router-1", session.SenderOptions{}).Send(ctx, recordMsg))
+}
+
+func TestReaderInvalid(t *testing.T) {
+	_, err := NewReader(bytes.NewReader([]byte("not a capture")))
+	assert.ErrorContains(t, err, "not a vanflow capture")
+
+	var buf bytes.Buffer
+	writer := NewWriter(&buf)
+	assert.NilError(t, writer.Close())
+	reader, err := NewReader(&buf)
+	assert.NilError(t, err)
+	_, err = reader.Next()
+	assert.Assert(t, errors.Is(err, io.EOF))
+}
+
+func ptrTo[T any](c T) *T {
+	return &c
+}
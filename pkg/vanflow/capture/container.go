@@ -0,0 +1,376 @@
+package capture
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	amqp "github.com/Azure/go-amqp"
+	"github.com/skupperproject/skupper/pkg/vanflow/session"
+)
+
+// NewCaptureContainerFactory wraps a ContainerFactory so that every beacon,
+// heartbeat and record message received by its containers is written to w.
+func NewCaptureContainerFactory(factory session.ContainerFactory, w *Writer, logger *slog.Logger) session.ContainerFactory {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return captureFactory{
+		factory: factory,
+		writer:  w,
+		logger:  logger,
+	}
+}
+
+type captureFactory struct {
+	factory session.ContainerFactory
+	writer  *Writer
+	logger  *slog.Logger
+}
+
+func (f captureFactory) Create() session.Container {
+	return &captureContainer{
+		Container: f.factory.Create(),
+		writer:    f.writer,
+		logger:    f.logger,
+	}
+}
+
+type captureContainer struct {
+	session.Container
+	writer *Writer
+	logger *slog.Logger
+}
+
+func (c *captureContainer) NewReceiver(address string, opts session.ReceiverOptions) session.Receiver {
+	return &captureReceiver{
+		Receiver: c.Container.NewReceiver(address, opts),
+		address:  address,
+		writer:   c.writer,
+		logger:   c.logger,
+	}
+}
+
+type captureReceiver struct {
+	session.Receiver
+	address string
+	writer  *Writer
+	logger  *slog.Logger
+}
+
+func (r *captureReceiver) Next(ctx context.Context) (*amqp.Message, error) {
+	msg, err := r.Receiver.Next(ctx)
+	if err != nil || !isCapturedSubject(msg) {
+		return msg, err
+	}
+	entry := Entry{Time: time.Now(), Address: r.address, Message: msg}
+	if err := r.writer.Write(entry); err != nil {
+		r.logger.Error("failed to capture vanflow message",
+			slog.String("address", r.address),
+			slog.Any("error", err))
+	}
+	return msg, nil
+}
+
+func isCapturedSubject(msg *amqp.Message) bool {
+	if msg == nil || msg.Properties == nil || msg.Properties.Subject == nil {
+		return false
+	}
+	switch *msg.Properties.Subject {
+	case "BEACON", "HEARTBEAT", "RECORD":
+		return true
+	}
+	return false
+}
+
+const (
+	// replaySubscribeTimeout is how long a replayed message waits for a
+	// receiver to listen on its address before being dropped.
+	replaySubscribeTimeout = 5 * time.Second
+	// replayKeepaliveInterval is how often the last heartbeat for each
+	// address is repeated once the capture has been fully replayed.
+	replayKeepaliveInterval = 10 * time.Second
+)
+
+// ReplayOptions configure how a capture is replayed
+type ReplayOptions struct {
+	// Speed is the factor by which the time between messages is reduced.
+	// A speed of 1 replays the capture in real time, 10 replays it ten
+	// times faster. When zero messages are replayed as quickly as they are
+	// consumed.
+	Speed float64
+	// Logger for replay progress and errors. Defaults to slog.Default().
+	Logger *slog.Logger
+	// OnComplete is called once the capture has been replayed with the
+	// error that stopped the replay, or nil when the end of the capture
+	// was reached.
+	OnComplete func(error)
+}
+
+// NewReplayContainerFactory returns a ContainerFactory whose containers
+// deliver the messages in a capture to receivers listening on the addresses
+// they were captured from. Messages are delivered with the same relative
+// timing as they were captured, scaled by the replay speed. Senders discard
+// their messages. Once the end of the capture is reached the most recent
+// heartbeat captured on each address is repeated periodically so that event
+// sources remain active.
+func NewReplayContainerFactory(r *Reader, opts ReplayOptions) session.ContainerFactory {
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	return &replayFactory{
+		router: &replayRouter{
+			reader:     r,
+			opts:       opts,
+			receivers:  make(map[string][]*replayReceiver),
+			subscribed: make(chan struct{}),
+			heartbeats: make(map[string]*amqp.Message),
+		},
+	}
+}
+
+type replayFactory struct {
+	router *replayRouter
+}
+
+// Create returns a container for the replay. All containers created by the
+// factory share the same replay, which starts when the first container is
+// started.
+func (f *replayFactory) Create() session.Container {
+	return replayContainer{router: f.router}
+}
+
+type replayContainer struct {
+	router *replayRouter
+}
+
+func (c replayContainer) Start(ctx context.Context) {
+	c.router.start(ctx)
+}
+
+func (c replayContainer) OnSessionError(func(error)) {}
+
+func (c replayContainer) NewReceiver(address string, opts session.ReceiverOptions) session.Receiver {
+	credit := opts.Credit
+	if credit <= 0 {
+		credit = 256
+	}
+	r := &replayReceiver{
+		router:  c.router,
+		address: address,
+		channel: make(chan *amqp.Message, credit),
+		done:    make(chan struct{}),
+	}
+	c.router.subscribe(r)
+	return r
+}
+
+func (c replayContainer) NewSender(address string, opts session.SenderOptions) session.Sender {
+	return discardSender{}
+}
+
+type replayRouter struct {
+	reader    *Reader
+	opts      ReplayOptions
+	startOnce sync.Once
+
+	mu         sync.Mutex
+	receivers  map[string][]*replayReceiver
+	subscribed chan struct{}
+	heartbeats map[string]*amqp.Message
+}
+
+func (r *replayRouter) start(ctx context.Context) {
+	r.startOnce.Do(func() {
+		go r.run(ctx)
+	})
+}
+
+func (r *replayRouter) run(ctx context.Context) {
+	err := r.replay(ctx)
+	if r.opts.OnComplete != nil {
+		r.opts.OnComplete(err)
+	}
+	if err != nil {
+		if !errors.Is(err, ctx.Err()) {
+			r.opts.Logger.Error("capture replay failed", slog.Any("error", err))
+		}
+		return
+	}
+	r.keepalive(ctx)
+}
+
+func (r *replayRouter) replay(ctx context.Context) error {
+	var (
+		count      int
+		firstEntry time.Time
+		startedAt  time.Time
+	)
+	for {
+		entry, err := r.reader.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				r.opts.Logger.Info("capture replay complete", slog.Int("messages", count))
+				return nil
+			}
+			return err
+		}
+		if count == 0 {
+			firstEntry, startedAt = entry.Time, time.Now()
+			r.opts.Logger.Info("capture replay started", slog.Time("captured", firstEntry))
+		}
+		if r.opts.Speed > 0 {
+			offset := time.Duration(float64(entry.Time.Sub(firstEntry)) / r.opts.Speed)
+			if wait := time.Until(startedAt.Add(offset)); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				case <-timer.C:
+				}
+			}
+		}
+		if subject := entry.Message.Properties.Subject; subject != nil && *subject == "HEARTBEAT" {
+			r.mu.Lock()
+			r.heartbeats[entry.Address] = entry.Message
+			r.mu.Unlock()
+		}
+		if err := r.deliver(ctx, entry.Address, entry.Message, replaySubscribeTimeout); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			r.opts.Logger.Debug("dropped replayed message",
+				slog.String("address", entry.Address),
+				slog.Any("error", err))
+		}
+		count++
+	}
+}
+
+func (r *replayRouter) keepalive(ctx context.Context) {
+	ticker := time.NewTicker(replayKeepaliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.mu.Lock()
+			heartbeats := make(map[string]*amqp.Message, len(r.heartbeats))
+			for address, msg := range r.heartbeats {
+				heartbeats[address] = msg
+			}
+			r.mu.Unlock()
+			for address, msg := range heartbeats {
+				r.deliver(ctx, address, msg, 0)
+			}
+		}
+	}
+}
+
+// deliver a message to each receiver listening on the address, waiting up
+// to timeout for a receiver to listen.
+func (r *replayRouter) deliver(ctx context.Context, address string, msg *amqp.Message, timeout time.Duration) error {
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	for {
+		r.mu.Lock()
+		receivers := r.receivers[address]
+		subscribed := r.subscribed
+		r.mu.Unlock()
+		if len(receivers) > 0 {
+			for _, receiver := range receivers {
+				receiver.send(ctx, msg)
+			}
+			return nil
+		}
+		if deadline == nil {
+			return fmt.Errorf("no receivers")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline:
+			return fmt.Errorf("no receivers after %s", timeout)
+		case <-subscribed:
+		}
+	}
+}
+
+func (r *replayRouter) subscribe(receiver *replayReceiver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.receivers[receiver.address] = append(r.receivers[receiver.address], receiver)
+	close(r.subscribed)
+	r.subscribed = make(chan struct{})
+}
+
+func (r *replayRouter) unsubscribe(receiver *replayReceiver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	receivers := r.receivers[receiver.address]
+	for i, candidate := range receivers {
+		if candidate == receiver {
+			receivers = append(receivers[:i:i], receivers[i+1:]...)
+			break
+		}
+	}
+	if len(receivers) == 0 {
+		delete(r.receivers, receiver.address)
+		return
+	}
+	r.receivers[receiver.address] = receivers
+}
+
+type replayReceiver struct {
+	router    *replayRouter
+	address   string
+	channel   chan *amqp.Message
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func (r *replayReceiver) send(ctx context.Context, msg *amqp.Message) {
+	select {
+	case <-ctx.Done():
+	case <-r.done:
+	case r.channel <- msg:
+	}
+}
+
+func (r *replayReceiver) Next(ctx context.Context) (*amqp.Message, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-r.done:
+		return nil, fmt.Errorf("closed")
+	case msg := <-r.channel:
+		return msg, nil
+	}
+}
+
+func (r *replayReceiver) Accept(context.Context, *amqp.Message) error {
+	return nil
+}
+
+func (r *replayReceiver) Close(context.Context) error {
+	r.closeOnce.Do(func() {
+		close(r.done)
+		r.router.unsubscribe(r)
+	})
+	return nil
+}
+
+type discardSender struct{}
+
+func (discardSender) Send(context.Context, *amqp.Message) error { return nil }
+func (discardSender) Close(context.Context) error               { return nil }
@@ -0,0 +1,6 @@
+/*
+Package capture records the vanflow messages received through a session
+container to a compressed file, and replays such a file through a container
+that stands in for a router session.
+*/
+package capture
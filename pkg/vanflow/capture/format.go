@@ -0,0 +1,186 @@
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	amqp "github.com/Azure/go-amqp"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+)
+
+// magic identifies a capture file and its format version
+var magic = []byte("vanflow-capture\x00\x01")
+
+var errWriterClosed = errors.New("capture writer closed")
+
+// maxFieldSize bounds the size of addresses and messages read from a
+// capture so that corrupt files do not cause huge allocations.
+const maxFieldSize = 64 << 20
+
+// Entry is a vanflow message captured from an address
+type Entry struct {
+	// Time the message was received
+	Time time.Time
+	// Address the message was received from
+	Address string
+	// Message as received
+	Message *amqp.Message
+}
+
+// Decode the entry's message into one of vanflow.BeaconMessage,
+// vanflow.HeartbeatMessage or vanflow.RecordMessage.
+func (e Entry) Decode() (interface{}, error) {
+	return vanflow.Decode(e.Message)
+}
+
+// Writer writes entries to a gzip compressed capture. Writer is safe for
+// concurrent use.
+type Writer struct {
+	mu    sync.Mutex
+	gz    *gzip.Writer
+	buf   []byte
+	count int
+	err   error
+}
+
+// NewWriter creates a Writer that writes a capture to w
+func NewWriter(w io.Writer) *Writer {
+	gz := gzip.NewWriter(w)
+	_, err := gz.Write(magic)
+	return &Writer{gz: gz, err: err}
+}
+
+// Write an entry to the capture
+func (w *Writer) Write(entry Entry) error {
+	data, err := entry.Message.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("error encoding message: %w", err)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	buf := w.buf[:0]
+	buf = binary.AppendVarint(buf, entry.Time.UnixNano())
+	buf = binary.AppendUvarint(buf, uint64(len(entry.Address)))
+	buf = append(buf, entry.Address...)
+	buf = binary.AppendUvarint(buf, uint64(len(data)))
+	buf = append(buf, data...)
+	w.buf = buf
+	if _, err := w.gz.Write(buf); err != nil {
+		w.err = err
+		return err
+	}
+	w.count++
+	return nil
+}
+
+// Count returns the number of entries written
+func (w *Writer) Count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.count
+}
+
+// Flush buffered entries to the underlying writer
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	return w.gz.Flush()
+}
+
+// Close flushes remaining entries and writes the gzip footer. It does not
+// close the underlying writer.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if errors.Is(w.err, errWriterClosed) {
+		return nil
+	}
+	err := w.gz.Close()
+	if w.err != nil {
+		err = w.err
+	}
+	w.err = errWriterClosed
+	return err
+}
+
+// Reader reads entries from a capture
+type Reader struct {
+	r *bufio.Reader
+}
+
+// NewReader creates a Reader for a capture written by Writer
+func NewReader(r io.Reader) (*Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a vanflow capture: %w", err)
+	}
+	br := bufio.NewReader(gz)
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(br, header); err != nil || !bytes.Equal(header, magic) {
+		return nil, errors.New("not a vanflow capture: invalid header")
+	}
+	return &Reader{r: br}, nil
+}
+
+// Next returns the next entry in the capture. Returns io.EOF when there are
+// no more entries.
+func (r *Reader) Next() (Entry, error) {
+	var entry Entry
+	ts, err := binary.ReadVarint(r.r)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return entry, io.EOF
+		}
+		return entry, fmt.Errorf("error reading capture entry: %w", err)
+	}
+	entry.Time = time.Unix(0, ts)
+	address, err := r.readField()
+	if err != nil {
+		return entry, fmt.Errorf("error reading capture entry address: %w", err)
+	}
+	entry.Address = string(address)
+	data, err := r.readField()
+	if err != nil {
+		return entry, fmt.Errorf("error reading capture entry message: %w", err)
+	}
+	entry.Message = new(amqp.Message)
+	if err := entry.Message.UnmarshalBinary(data); err != nil {
+		return entry, fmt.Errorf("error decoding capture entry message: %w", err)
+	}
+	return entry, nil
+}
+
+func (r *Reader) readField() ([]byte, error) {
+	size, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	if size > maxFieldSize {
+		return nil, fmt.Errorf("field size %d exceeds maximum", size)
+	}
+	out := make([]byte, size)
+	if _, err := io.ReadFull(r.r, out); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return out, nil
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,368 @@
+// codegen generates RecordEncoder and RecordDecoder implementations for the
+// record types a package registers with encoding.MustRegisterRecord so that
+// they can be encoded and decoded without reflection.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const tmpl = `// Code generated by vanflow encoding codegen. DO NOT EDIT.
+
+package {{ .PackageName }}
+
+import (
+{{- if .UsesErrors }}
+	"errors"
+{{- end }}
+	"fmt"
+
+	"github.com/skupperproject/skupper/pkg/vanflow/encoding"
+)
+{{range .Records}}
+// EncodeRecord implements encoding.RecordEncoder
+func (r {{ .TypeName }}) EncodeRecord() (encoding.RecordAttributeSet, error) {
+	attrs := make(encoding.RecordAttributeSet, {{ len .Fields | inc }})
+{{- range .Fields}}
+{{- if eq .Kind "raw"}}
+	if r.{{ .Path }} != {{ .Zero }} {
+		attrs[uint32({{ .Codepoint }})] = r.{{ .Path }}
+	}{{ if .Required }} else {
+		return attrs, fmt.Errorf("missing or empty required field %s: %w", {{ printf "%q" .Name }}, encoding.ErrAttributeNotSet)
+	}{{ end }}
+{{- else if eq .Kind "rawPointer"}}
+	if r.{{ .Path }} != nil && *r.{{ .Path }} != {{ .Zero }} {
+		attrs[uint32({{ .Codepoint }})] = *r.{{ .Path }}
+	}{{ if .Required }} else {
+		return attrs, fmt.Errorf("missing or empty required field %s: %w", {{ printf "%q" .Name }}, encoding.ErrAttributeNotSet)
+	}{{ end }}
+{{- else}}
+	{{ if eq .Kind "attributePointer" }}if r.{{ .Path }} != nil {{ end }}{
+		attr, err := r.{{ .Path }}.EncodeRecordAttribute()
+		switch {
+		case errors.Is(err, encoding.ErrAttributeNotSet):
+			{{- if .Required }}
+			return attrs, fmt.Errorf("missing or empty required field %s: %w", {{ printf "%q" .Name }}, err)
+			{{- end }}
+		case err != nil:
+			return attrs, fmt.Errorf("error encoding field %q: %w", {{ printf "%q" .Name }}, err)
+		case attr != nil:
+			attrs[uint32({{ .Codepoint }})] = attr
+		}
+	}{{ if and .Required (eq .Kind "attributePointer") }} else {
+		return attrs, fmt.Errorf("missing or empty required field %s: %w", {{ printf "%q" .Name }}, encoding.ErrAttributeNotSet)
+	}{{ end }}
+{{- end}}
+{{- end}}
+	return attrs, nil
+}
+
+// DecodeRecord implements encoding.RecordDecoder
+func (r *{{ .TypeName }}) DecodeRecord(attrs encoding.RecordAttributeSet) error {
+{{- range .Fields}}
+	if attr, ok := attrs[uint32({{ .Codepoint }})]; ok {
+{{- if eq .Kind "raw" "rawPointer"}}
+		v, ok := attr.({{ .Type }})
+		if !ok {
+			return fmt.Errorf("error decoding field %q: cannot assign value to type", {{ printf "%q" .Name }})
+		}
+		r.{{ .Path }} = {{ if eq .Kind "rawPointer" }}&{{ end }}v
+{{- else if eq .Kind "attributePointer"}}
+		r.{{ .Path }} = new({{ .Type }})
+		if err := r.{{ .Path }}.DecodeRecordAttribute(attr); err != nil {
+			return fmt.Errorf("error decoding field %q: %w", {{ printf "%q" .Name }}, err)
+		}
+{{- else}}
+		if err := r.{{ .Path }}.DecodeRecordAttribute(attr); err != nil {
+			return fmt.Errorf("error decoding field %q: %w", {{ printf "%q" .Name }}, err)
+		}
+{{- end}}
+	}{{ if .Required }} else {
+		return fmt.Errorf("record attribute set missing required field %q", {{ printf "%q" .Name }})
+	}{{ end }}
+{{- end}}
+	return nil
+}
+{{end}}`
+
+const (
+	kindRaw              = "raw"
+	kindRawPointer       = "rawPointer"
+	kindAttribute        = "attribute"
+	kindAttributePointer = "attributePointer"
+)
+
+// zeroValues of the attribute types encoded as is. Other attribute types must
+// implement encoding.RecordAttributeEncoder and encoding.RecordAttributeDecoder
+var zeroValues = map[string]string{
+	"string": `""`,
+	"uint64": "0",
+	"int64":  "0",
+	"uint32": "0",
+	"int32":  "0",
+}
+
+type Codegen struct {
+	PackageName string
+	Records     []Record
+	// UsesErrors is set when a record has attributes implementing
+	// encoding.RecordAttributeEncoder
+	UsesErrors bool
+}
+
+type Record struct {
+	TypeName string
+	Fields   []Field
+}
+
+type Field struct {
+	Name      string
+	Path      string
+	Type      string
+	Kind      string
+	Zero      string
+	Codepoint uint32
+	Required  bool
+}
+
+func main() {
+	var output string
+	flags := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	flags.StringVar(&output, "o", "", "file to write output to. Defaults to stdout")
+	flags.Usage = func() {
+		fmt.Println("codegen [options...] <package directory>")
+		fmt.Println(`generates vanflow record encoders and decoders for the record types registered with encoding.MustRegisterRecord.`)
+		flags.PrintDefaults()
+	}
+	flags.Parse(os.Args[1:])
+
+	input := flags.Args()
+	if len(input) != 1 {
+		flags.Usage()
+		os.Exit(1)
+	}
+	gen, err := parsePackage(input[0], output)
+	if err != nil {
+		log.Fatalf("error parsing package: %s", err)
+	}
+	out, err := gen.generate()
+	if err != nil {
+		log.Fatalf("error generating code: %s", err)
+	}
+	if output == "" {
+		os.Stdout.Write(out)
+		return
+	}
+	if err := os.WriteFile(output, out, 0644); err != nil {
+		log.Fatalf("error writing output: %s", err)
+	}
+}
+
+func (gen Codegen) generate() ([]byte, error) {
+	t, err := template.New("codegen").Funcs(template.FuncMap{
+		"inc": func(i int) int { return i + 1 },
+	}).Parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, gen); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// parsePackage finds the record types registered with MustRegisterRecord in
+// the non-test go files in dir, excluding the output file.
+func parsePackage(dir string, output string) (Codegen, error) {
+	var gen Codegen
+	fset := token.NewFileSet()
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return gen, err
+	}
+	sort.Strings(paths)
+	structs := make(map[string]*ast.StructType)
+	var registered []string
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") || (output != "" && sameFile(path, output)) {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return gen, err
+		}
+		if gen.PackageName == "" {
+			gen.PackageName = file.Name.Name
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.TypeSpec:
+				if st, ok := node.Type.(*ast.StructType); ok {
+					structs[node.Name.Name] = st
+				}
+			case *ast.CallExpr:
+				if name, ok := registeredRecordType(node); ok {
+					registered = append(registered, name)
+				}
+			}
+			return true
+		})
+	}
+	if len(registered) == 0 {
+		return gen, fmt.Errorf("no record types registered with MustRegisterRecord in %s", dir)
+	}
+	for _, name := range registered {
+		st, ok := structs[name]
+		if !ok {
+			return gen, fmt.Errorf("registered record type %s is not a struct declared in the package", name)
+		}
+		fields, err := structFields(structs, st, "")
+		if err != nil {
+			return gen, fmt.Errorf("%s: %w", name, err)
+		}
+		codepoints := make(map[uint32]string, len(fields))
+		for _, field := range fields {
+			if existing, ok := codepoints[field.Codepoint]; ok {
+				return gen, fmt.Errorf("%s: struct field %s repeats vflow tag \"%d\" also used by %s", name, field.Name, field.Codepoint, existing)
+			}
+			codepoints[field.Codepoint] = field.Name
+			if field.Kind == kindAttribute || field.Kind == kindAttributePointer {
+				gen.UsesErrors = true
+			}
+		}
+		gen.Records = append(gen.Records, Record{TypeName: name, Fields: fields})
+	}
+	return gen, nil
+}
+
+// registeredRecordType returns the name of the type registered by a call like
+// encoding.MustRegisterRecord(1, RouterRecord{})
+func registeredRecordType(call *ast.CallExpr) (string, bool) {
+	var fn string
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		fn = fun.Sel.Name
+	case *ast.Ident:
+		fn = fun.Name
+	}
+	if fn != "MustRegisterRecord" || len(call.Args) != 2 {
+		return "", false
+	}
+	lit, ok := call.Args[1].(*ast.CompositeLit)
+	if !ok {
+		return "", false
+	}
+	ident, ok := lit.Type.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	return ident.Name, true
+}
+
+// structFields returns the vflow tagged fields of a struct including those
+// of embedded structs in the order the encoding package visits them.
+func structFields(structs map[string]*ast.StructType, st *ast.StructType, prefix string) ([]Field, error) {
+	var fields []Field
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			ident, ok := f.Type.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			embedded, ok := structs[ident.Name]
+			if !ok {
+				continue
+			}
+			nested, err := structFields(structs, embedded, prefix+ident.Name+".")
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, nested...)
+			continue
+		}
+		if f.Tag == nil {
+			continue
+		}
+		tagValue, err := strconv.Unquote(f.Tag.Value)
+		if err != nil {
+			return nil, err
+		}
+		tag, ok := reflect.StructTag(tagValue).Lookup("vflow")
+		if !ok || tag == "" {
+			continue
+		}
+		sCode, sOpt, _ := strings.Cut(tag, ",")
+		code, err := strconv.ParseUint(sCode, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("vflow struct tag parse error for field %s: %s", f.Names[0].Name, err)
+		}
+		var required bool
+		switch sOpt {
+		case "required":
+			required = true
+		case "":
+		default:
+			return nil, fmt.Errorf("vflow struct tag parse error: unexpected option %s", sOpt)
+		}
+		typ, pointer := f.Type, false
+		if star, ok := typ.(*ast.StarExpr); ok {
+			typ, pointer = star.X, true
+		}
+		ident, ok := typ.(*ast.Ident)
+		if !ok {
+			return nil, fmt.Errorf("unsupported type for field %s: expected a type declared in the package or a pointer to one", f.Names[0].Name)
+		}
+		typeName := ident.Name
+		zero, raw := zeroValues[typeName]
+		var kind string
+		switch {
+		case raw && pointer:
+			kind = kindRawPointer
+		case raw:
+			kind = kindRaw
+		case pointer:
+			kind = kindAttributePointer
+		default:
+			kind = kindAttribute
+		}
+		for _, name := range f.Names {
+			if !name.IsExported() {
+				continue
+			}
+			fields = append(fields, Field{
+				Name:      name.Name,
+				Path:      prefix + name.Name,
+				Type:      typeName,
+				Kind:      kind,
+				Zero:      zero,
+				Codepoint: uint32(code),
+				Required:  required,
+			})
+		}
+	}
+	return fields, nil
+}
+
+func sameFile(a, b string) bool {
+	aInfo, aErr := os.Stat(a)
+	bInfo, bErr := os.Stat(b)
+	if aErr != nil || bErr != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return os.SameFile(aInfo, bInfo)
+}
@@ -16,10 +16,22 @@ type RecordAttributeDecoder interface {
 	DecodeRecordAttribute(attr interface{}) error
 }
 
+// RecordDecoder is implemented by pointers to record types that can decode
+// themselves without reflection. Implementations are usually generated by the
+// codegen tool in this package.
+type RecordDecoder interface {
+	DecodeRecord(attrs RecordAttributeSet) error
+}
+
 // Decode a record from its record attribute set. Decode is only aware of
 // record types registered with MustRegisterRecord. Input must be a map type
-// containing uint32 keys (map[uint32]X or map[any]X)
+// containing uint32 keys (map[uint32]X or map[any]X). Records implementing
+// RecordDecoder are decoded using it instead of reflection.
 func Decode(recordset RecordAttributeSet) (interface{}, error) {
+	return decode(recordset, true)
+}
+
+func decode(recordset RecordAttributeSet, useRecordDecoder bool) (interface{}, error) {
 	if recordset == nil {
 		return nil, errors.New("decode error: cannot decode nil record attribute set")
 	}
@@ -40,7 +52,13 @@ func Decode(recordset RecordAttributeSet) (interface{}, error) {
 		return nil, fmt.Errorf("decode error: unknown record type for %d", recordTypeCode)
 	}
 	recordV := reflect.New(encoding.t)
-	if err := encoding.decode(recordset, recordV); err != nil {
+	var err error
+	if decoder, ok := recordV.Interface().(RecordDecoder); ok && useRecordDecoder {
+		err = decoder.DecodeRecord(recordset)
+	} else {
+		err = encoding.decode(recordset, recordV)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("decode error: %w", err)
 	}
 	return recordV.Elem().Interface(), nil
@@ -30,10 +30,22 @@ type RecordAttributeEncoder interface {
 	EncodeRecordAttribute() (interface{}, error)
 }
 
+// RecordEncoder is implemented by record types that can encode themselves
+// without reflection. Implementations are usually generated by the codegen
+// tool in this package.
+type RecordEncoder interface {
+	EncodeRecord() (RecordAttributeSet, error)
+}
+
 // Encode a record into a record attribute set so that it can be sent over the
 // vanflow protocol. Only records types that have been registered with
-// MustRegisterRecord can be encoded.
+// MustRegisterRecord can be encoded. Records implementing RecordEncoder are
+// encoded using it instead of reflection.
 func Encode(record any) (RecordAttributeSet, error) {
+	return encode(record, true)
+}
+
+func encode(record any, useRecordEncoder bool) (RecordAttributeSet, error) {
 	mu.RLock()
 	defer mu.RUnlock()
 	if record == nil {
@@ -52,7 +64,15 @@ func Encode(record any) (RecordAttributeSet, error) {
 			return nil, fmt.Errorf("encode error: unregistered record type %T", record)
 		}
 	}
-	result, err := encoding.encode(recordV)
+	var (
+		result RecordAttributeSet
+		err    error
+	)
+	if encoder, ok := record.(RecordEncoder); ok && useRecordEncoder {
+		result, err = encoder.EncodeRecord()
+	} else {
+		result, err = encoding.encode(recordV)
+	}
 	if result != nil {
 		result[typeOfRecord] = encoding.codepoint
 	}
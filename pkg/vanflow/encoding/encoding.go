@@ -29,6 +29,13 @@
 //	  uint32(99): int64(1),
 //	  uint32(100): "test",
 //	}
+//
+// Walking struct fields with reflection is relatively expensive. Record types
+// can skip it by implementing RecordEncoder and RecordDecoder. The codegen
+// tool in this package generates implementations for the record types a
+// package registers with MustRegisterRecord:
+//
+//	//go:generate go run github.com/skupperproject/skupper/pkg/vanflow/encoding/codegen -o records_gen.go .
 package encoding
 
 import (
@@ -0,0 +1,13 @@
+package encoding
+
+// EncodeReflect encodes a record using reflection even when it implements
+// RecordEncoder
+func EncodeReflect(record any) (RecordAttributeSet, error) {
+	return encode(record, false)
+}
+
+// DecodeReflect decodes a record using reflection even when it implements
+// RecordDecoder
+func DecodeReflect(recordset RecordAttributeSet) (interface{}, error) {
+	return decode(recordset, false)
+}
@@ -0,0 +1,168 @@
+package encoding_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/encoding"
+)
+
+var generatedRecords = []any{
+	vanflow.SiteRecord{},
+	vanflow.RouterRecord{},
+	vanflow.LinkRecord{},
+	vanflow.ControllerRecord{},
+	vanflow.ListenerRecord{},
+	vanflow.ConnectorRecord{},
+	vanflow.FlowRecord{},
+	vanflow.ProcessRecord{},
+	vanflow.ImageRecord{},
+	vanflow.IngressRecord{},
+	vanflow.EgressRecord{},
+	vanflow.CollectorRecord{},
+	vanflow.ProcessGroupRecord{},
+	vanflow.HostRecord{},
+	vanflow.LogRecord{},
+	vanflow.RouterAccessRecord{},
+	vanflow.TransportBiflowRecord{},
+	vanflow.AppBiflowRecord{},
+}
+
+// TestGeneratedCodecs checks that the generated record encoders and decoders
+// behave the same as the reflective ones
+func TestGeneratedCodecs(t *testing.T) {
+	for _, exemplar := range generatedRecords {
+		typ := reflect.TypeOf(exemplar)
+		t.Run(typ.Name(), func(t *testing.T) {
+			if _, ok := exemplar.(encoding.RecordEncoder); !ok {
+				t.Fatalf("%s does not implement RecordEncoder: regenerate encoders with go generate", typ)
+			}
+			if _, ok := reflect.New(typ).Interface().(encoding.RecordDecoder); !ok {
+				t.Fatalf("*%s does not implement RecordDecoder: regenerate encoders with go generate", typ)
+			}
+			full := reflect.New(typ)
+			fill(full.Elem(), 1)
+			for _, record := range []any{exemplar, full.Elem().Interface(), full.Interface()} {
+				expected, expectedErr := encoding.EncodeReflect(record)
+				actual, err := encoding.Encode(record)
+				if !equalErrors(expectedErr, err) {
+					t.Fatalf("unexpected encode error: %v expected %v", err, expectedErr)
+				}
+				if !cmp.Equal(expected, actual) {
+					t.Fatalf("generated encoding differs from reflective encoding: %s", cmp.Diff(expected, actual))
+				}
+				if err != nil {
+					continue
+				}
+				expectedRecord, expectedErr := encoding.DecodeReflect(expected)
+				actualRecord, err := encoding.Decode(actual)
+				if !equalErrors(expectedErr, err) {
+					t.Fatalf("unexpected decode error: %v expected %v", err, expectedErr)
+				}
+				if !cmp.Equal(expectedRecord, actualRecord) {
+					t.Fatalf("generated decoding differs from reflective decoding: %s", cmp.Diff(expectedRecord, actualRecord))
+				}
+			}
+
+			attrs, err := encoding.Encode(full.Interface())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for codepoint := range attrs {
+				if codepoint == uint32(0) {
+					continue
+				}
+				invalid := make(encoding.RecordAttributeSet, len(attrs))
+				for k, v := range attrs {
+					invalid[k] = v
+				}
+				invalid[codepoint] = struct{}{}
+				_, expectedErr := encoding.DecodeReflect(invalid)
+				_, err := encoding.Decode(invalid)
+				if !equalErrors(expectedErr, err) {
+					t.Fatalf("unexpected decode error for invalid attribute %v: %v expected %v", codepoint, err, expectedErr)
+				}
+			}
+		})
+	}
+}
+
+func equalErrors(a, b error) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Error() == b.Error()
+}
+
+// fill sets every field of a record to a distinct non-zero value
+func fill(v reflect.Value, seed int) int {
+	switch v.Kind() {
+	case reflect.Struct:
+		if t, ok := v.Addr().Interface().(*vanflow.Time); ok {
+			t.Time = time.UnixMicro(int64(seed) * 1000)
+			return seed + 1
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				seed = fill(v.Field(i), seed)
+			}
+		}
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		return fill(v.Elem(), seed)
+	case reflect.String:
+		v.SetString(fmt.Sprintf("value-%d", seed))
+	case reflect.Uint64, reflect.Uint32:
+		v.SetUint(uint64(seed))
+	case reflect.Int64, reflect.Int32:
+		v.SetInt(int64(seed))
+	}
+	return seed + 1
+}
+
+func benchmarkRecord() vanflow.TransportBiflowRecord {
+	var record vanflow.TransportBiflowRecord
+	fill(reflect.ValueOf(&record).Elem(), 1)
+	return record
+}
+
+func BenchmarkEncode(b *testing.B) {
+	record := benchmarkRecord()
+	for name, encode := range map[string]func(any) (encoding.RecordAttributeSet, error){
+		"generated": encoding.Encode,
+		"reflect":   encoding.EncodeReflect,
+	} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := encode(record); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	attrs, err := encoding.Encode(benchmarkRecord())
+	if err != nil {
+		b.Fatal(err)
+	}
+	for name, decode := range map[string]func(encoding.RecordAttributeSet) (any, error){
+		"generated": encoding.Decode,
+		"reflect":   encoding.DecodeReflect,
+	} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := decode(attrs); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+package vanflow
+
+//go:generate go run ./encoding/codegen -o records_gen.go .
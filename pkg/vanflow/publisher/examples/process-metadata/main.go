@@ -0,0 +1,131 @@
+// process-metadata is an example vanflow event source that attaches
+// additional metadata to a process running in an existing application network
+// by publishing a ProcessRecord on behalf of a site.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/publisher"
+	"github.com/skupperproject/skupper/pkg/vanflow/session"
+)
+
+type config struct {
+	RouterURL  string
+	TLSCert    string
+	TLSKey     string
+	TLSCA      string
+	SkipVerify bool
+
+	SourceID  string
+	SiteID    string
+	ProcessID string
+	Name      string
+	Group     string
+	Host      string
+	Image     string
+}
+
+func main() {
+	var cfg config
+	flags := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	flags.StringVar(&cfg.RouterURL, "router-endpoint", "amqps://skupper-router-local", "URL to the skupper router amqp(s) endpoint")
+	flags.StringVar(&cfg.TLSCert, "router-tls-cert", "", "Path to the client certificate for the router endpoint")
+	flags.StringVar(&cfg.TLSKey, "router-tls-key", "", "Path to the client key for the router endpoint")
+	flags.StringVar(&cfg.TLSCA, "router-tls-ca", "", "Path to the CA certificate file for the router endpoint")
+	flags.BoolVar(&cfg.SkipVerify, "router-tls-insecure", false, "Set to skip verification of the router certificate and host name")
+	flags.StringVar(&cfg.SourceID, "source-id", "process-metadata", "ID of the event source. Must be unique within the application network")
+	flags.StringVar(&cfg.SiteID, "site-id", "", "ID of the site the process belongs to")
+	flags.StringVar(&cfg.ProcessID, "process-id", "", "ID of the process record to publish")
+	flags.StringVar(&cfg.Name, "name", "", "Name of the process")
+	flags.StringVar(&cfg.Group, "group", "", "Process group the process belongs to")
+	flags.StringVar(&cfg.Host, "host", "", "Host name or address of the process")
+	flags.StringVar(&cfg.Image, "image", "", "Image the process is running")
+	flags.Parse(os.Args[1:])
+
+	if err := run(cfg); err != nil {
+		slog.Error("process-metadata run error", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+func run(cfg config) error {
+	if cfg.SiteID == "" || cfg.ProcessID == "" {
+		return fmt.Errorf("site-id and process-id are required")
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	ctrCfg, err := sessionConfig(cfg)
+	if err != nil {
+		return err
+	}
+	pub, err := publisher.New(session.NewContainerFactory(cfg.RouterURL, ctrCfg), publisher.Config{
+		ID: cfg.SourceID,
+	})
+	if err != nil {
+		return err
+	}
+
+	process := vanflow.ProcessRecord{
+		BaseRecord: vanflow.NewBase(cfg.ProcessID, time.Now()),
+		Parent:     &cfg.SiteID,
+		Name:       optional(cfg.Name),
+		Group:      optional(cfg.Group),
+		SourceHost: optional(cfg.Host),
+		ImageName:  optional(cfg.Image),
+	}
+	if err := pub.Publish(process); err != nil {
+		return err
+	}
+	slog.Info("publishing process record", slog.String("id", cfg.ProcessID), slog.String("site", cfg.SiteID))
+	return pub.Run(ctx)
+}
+
+func sessionConfig(cfg config) (session.ContainerConfig, error) {
+	var ctrCfg session.ContainerConfig
+	if cfg.TLSCert == "" && cfg.TLSCA == "" && !cfg.SkipVerify {
+		return ctrCfg, nil
+	}
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.SkipVerify,
+	}
+	if cfg.TLSCA != "" {
+		ca, err := os.ReadFile(cfg.TLSCA)
+		if err != nil {
+			return ctrCfg, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return ctrCfg, fmt.Errorf("failed to add CA to certificate pool")
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if cfg.TLSCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCert, cfg.TLSKey)
+		if err != nil {
+			return ctrCfg, err
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+		ctrCfg.SASLType = session.SASLTypeExternal
+	}
+	ctrCfg.TLSConfig = tlsCfg
+	return ctrCfg, nil
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
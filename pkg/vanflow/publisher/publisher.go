@@ -0,0 +1,256 @@
+/*
+Package publisher provides a high level API for programs that publish vanflow
+records to an application network as an event source.
+
+A Publisher advertises itself to vanflow collectors (i.e. the network
+observer) with beacon and heartbeat messages, answers flush requests with the
+full set of records it has published, and sends record updates as they are
+published:
+
+	pub, err := publisher.New(session.NewContainerFactory(routerURL, cfg), publisher.Config{
+		ID: "my-agent",
+	})
+	if err != nil {
+		return err
+	}
+	go pub.Run(ctx)
+	err = pub.Publish(vanflow.ProcessRecord{
+		BaseRecord: vanflow.NewBase("my-process", time.Now()),
+		Parent:     &siteID,
+		Name:       &name,
+	})
+*/
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/encoding"
+	"github.com/skupperproject/skupper/pkg/vanflow/eventsource"
+	"github.com/skupperproject/skupper/pkg/vanflow/session"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+)
+
+// ErrInvalidRecord is returned when publishing a record that cannot be sent
+// to the application network
+var ErrInvalidRecord = errors.New("invalid record")
+
+// ErrUnknownRecord is returned when terminating a record that has not been
+// published
+var ErrUnknownRecord = errors.New("unknown record")
+
+// Config for a Publisher
+type Config struct {
+	// ID uniquely identifies the event source within the application
+	// network. Required.
+	ID string
+	// Type of event source advertised to collectors. Defaults to
+	// "CONTROLLER", the type used by skupper controllers publishing process
+	// records.
+	Type string
+	// Version of the event source. Defaults to 1.
+	Version int
+
+	// HeartbeatInterval defaults to 2 seconds
+	HeartbeatInterval time.Duration
+	// BeaconInterval defaults to 10 seconds
+	BeaconInterval time.Duration
+	// FlushBatchSize is the maximum number of records sent in a single
+	// message in response to a flush request. Defaults to 20.
+	FlushBatchSize int
+	// UpdateBatchSize is the maximum number of record updates sent in a
+	// single message. Defaults to 10.
+	UpdateBatchSize int
+	// UpdateBufferTime is how long to wait for a full batch of updates
+	// before sending a partial batch. Defaults to 1 second.
+	UpdateBufferTime time.Duration
+
+	// Logger defaults to slog.Default()
+	Logger *slog.Logger
+}
+
+func (c Config) withDefaults() Config {
+	if c.Type == "" {
+		c.Type = "CONTROLLER"
+	}
+	if c.Version == 0 {
+		c.Version = 1
+	}
+	if c.FlushBatchSize <= 0 {
+		c.FlushBatchSize = 20
+	}
+	if c.UpdateBatchSize <= 0 {
+		c.UpdateBatchSize = 10
+	}
+	if c.UpdateBufferTime <= 0 {
+		c.UpdateBufferTime = time.Second
+	}
+	if c.Logger == nil {
+		c.Logger = slog.Default()
+	}
+	return c
+}
+
+// Publisher publishes vanflow records as an event source
+type Publisher struct {
+	logger    *slog.Logger
+	source    store.SourceRef
+	container session.Container
+	manager   *eventsource.Manager
+
+	mu      sync.Mutex
+	records store.Interface
+}
+
+// New creates a Publisher using a container created by factory
+func New(factory session.ContainerFactory, cfg Config) (*Publisher, error) {
+	if cfg.ID == "" {
+		return nil, fmt.Errorf("publisher ID is required")
+	}
+	cfg = cfg.withDefaults()
+	records := store.NewSyncMapStore(store.SyncMapStoreConfig{})
+	container := factory.Create()
+	manager := eventsource.NewManager(container, eventsource.ManagerConfig{
+		Source: eventsource.Info{
+			ID:      cfg.ID,
+			Version: cfg.Version,
+			Type:    cfg.Type,
+			Address: fmt.Sprintf("mc/sfe.%s", cfg.ID),
+			Direct:  fmt.Sprintf("sfe.%s", cfg.ID),
+		},
+		Stores:            []store.Interface{records},
+		HeartbeatInterval: cfg.HeartbeatInterval,
+		BeaconInterval:    cfg.BeaconInterval,
+		// collectors listen for heartbeats from controllers on the
+		// alternate address
+		UseAlternateHeartbeatAddress: cfg.Type == "CONTROLLER",
+		FlushDelay:                   time.Millisecond * 100,
+		FlushBatchSize:               cfg.FlushBatchSize,
+		UpdateBufferTime:             cfg.UpdateBufferTime,
+		UpdateBatchSize:              cfg.UpdateBatchSize,
+	})
+	return &Publisher{
+		logger: cfg.Logger.With(
+			slog.String("component", "vanflow.publisher"),
+			slog.String("source", cfg.ID),
+		),
+		source:    store.SourceRef{ID: cfg.ID, Version: fmt.Sprint(cfg.Version)},
+		container: container,
+		manager:   manager,
+		records:   records,
+	}, nil
+}
+
+// Run connects to the router and serves the event source until ctx is
+// cancelled or an unrecoverable session error occurs. Retryable session errors
+// are logged and the connection reestablished.
+func (p *Publisher) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	p.container.OnSessionError(func(err error) {
+		if _, retryable := err.(session.RetryableError); retryable {
+			p.logger.Error("amqp session error", slog.Any("error", err))
+			return
+		}
+		cancel(fmt.Errorf("unrecoverable session error: %w", err))
+	})
+	p.container.Start(ctx)
+	p.manager.Run(ctx)
+	if err := context.Cause(ctx); !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+// Publish adds or updates a record. Only the attributes that changed since the
+// record was last published are sent. Publish may block while the publisher
+// is not running and a large number of updates are pending.
+func (p *Publisher) Publish(record vanflow.Record) error {
+	record, err := validate(record)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var prev vanflow.Record
+	if entry, ok := p.records.Get(record.Identity()); ok {
+		if entry.Record.GetTypeMeta() != record.GetTypeMeta() {
+			return fmt.Errorf("%w: %s %q already published as a %s",
+				ErrInvalidRecord, record.GetTypeMeta().Type, record.Identity(), entry.Record.GetTypeMeta().Type)
+		}
+		prev = entry.Record
+		p.records.Update(record)
+	} else {
+		p.records.Add(record, p.source)
+	}
+	p.manager.PublishUpdate(eventsource.RecordUpdate{Prev: prev, Curr: record})
+	return nil
+}
+
+// Terminate marks a published record as ended at the current time and stops
+// including it in responses to flush requests
+func (p *Publisher) Terminate(id string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, ok := p.records.Get(id)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownRecord, id)
+	}
+	attrs, err := encoding.Encode(entry.Record)
+	if err != nil {
+		return err
+	}
+	// record attribute 4 is the end time common to all record types
+	attrs[uint32(4)] = uint64(time.Now().UnixMicro())
+	terminated, err := encoding.Decode(attrs)
+	if err != nil {
+		return err
+	}
+	p.records.Delete(id)
+	p.manager.PublishUpdate(eventsource.RecordUpdate{Prev: entry.Record, Curr: terminated.(vanflow.Record)})
+	return nil
+}
+
+// Records returns the records that have been published and not terminated
+func (p *Publisher) Records() []vanflow.Record {
+	entries := p.records.List()
+	records := make([]vanflow.Record, len(entries))
+	for i, entry := range entries {
+		records[i] = entry.Record
+	}
+	return records
+}
+
+// validate checks that a record can be sent to collectors and returns it as
+// the record type collectors decode it to
+func validate(record vanflow.Record) (vanflow.Record, error) {
+	if record == nil {
+		return nil, fmt.Errorf("%w: record is nil", ErrInvalidRecord)
+	}
+	if record.Identity() == "" {
+		return nil, fmt.Errorf("%w: %s has no ID", ErrInvalidRecord, record.GetTypeMeta().Type)
+	}
+	attrs, err := encoding.Encode(record)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRecord, err)
+	}
+	decoded, err := encoding.Decode(attrs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRecord, err)
+	}
+	switch decoded := decoded.(type) {
+	case vanflow.TransportBiflowRecord, vanflow.AppBiflowRecord, vanflow.FlowRecord:
+		return nil, fmt.Errorf("%w: flow records are published by routers", ErrInvalidRecord)
+	case vanflow.ProcessRecord:
+		if decoded.Parent == nil {
+			return nil, fmt.Errorf("%w: ProcessRecord %q has no Parent site", ErrInvalidRecord, decoded.ID)
+		}
+	}
+	return decoded.(vanflow.Record), nil
+}
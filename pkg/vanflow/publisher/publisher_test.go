@@ -0,0 +1,130 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+	"github.com/skupperproject/skupper/pkg/vanflow/eventsource"
+	"github.com/skupperproject/skupper/pkg/vanflow/session"
+	"github.com/skupperproject/skupper/pkg/vanflow/store"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/poll"
+)
+
+type unregisteredRecord struct {
+	vanflow.BaseRecord
+}
+
+func (r unregisteredRecord) GetTypeMeta() vanflow.TypeMeta {
+	return vanflow.TypeMeta{APIVersion: "test", Type: "UnregisteredRecord"}
+}
+
+func TestPublisher(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	factory := session.NewMockContainerFactory()
+
+	_, err := New(factory, Config{})
+	assert.ErrorContains(t, err, "publisher ID is required")
+
+	pub, err := New(factory, Config{
+		ID:                "test-agent",
+		HeartbeatInterval: 10 * time.Millisecond,
+		BeaconInterval:    50 * time.Millisecond,
+		UpdateBufferTime:  10 * time.Millisecond,
+	})
+	assert.NilError(t, err)
+	runErr := make(chan error, 1)
+	go func() { runErr <- pub.Run(ctx) }()
+
+	source := eventsource.Info{ID: "test-agent", Address: "mc/sfe.test-agent", Direct: "sfe.test-agent"}
+	clientCtr := factory.Create()
+	clientCtr.Start(ctx)
+	received := store.NewSyncMapStore(store.SyncMapStoreConfig{})
+	client := eventsource.NewClient(clientCtr, eventsource.ClientOptions{Source: source})
+	defer client.Close()
+	client.OnRecord(eventsource.RecordStoreRouter{
+		Source: store.SourceRef{ID: source.ID},
+		Stores: eventsource.RecordStoreMap{
+			vanflow.ProcessRecord{}.GetTypeMeta().String(): received,
+			vanflow.LogRecord{}.GetTypeMeta().String():     received,
+		},
+	}.Route)
+	assert.NilError(t, client.Listen(ctx, eventsource.FromSourceAddress()))
+	assert.NilError(t, client.Listen(ctx, eventsource.FromSourceAddressHeartbeats()))
+
+	process := vanflow.ProcessRecord{
+		BaseRecord: vanflow.NewBase("process-1", time.UnixMicro(1000)),
+		Parent:     ptrTo("site-1"),
+		Name:       ptrTo("backend"),
+	}
+	assert.NilError(t, pub.Publish(process))
+	// records published before the client flushes are received on flush
+	flushCtx, cancelFlush := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelFlush()
+	assert.NilError(t, eventsource.FlushOnFirstMessage(flushCtx, client))
+
+	waitFor := func(t *testing.T, id string, check func(vanflow.Record) bool) {
+		t.Helper()
+		poll.WaitOn(t, func(poll.LogT) poll.Result {
+			entry, ok := received.Get(id)
+			if !ok || !check(entry.Record) {
+				return poll.Continue("waiting for %s", id)
+			}
+			return poll.Success()
+		}, poll.WithDelay(5*time.Millisecond), poll.WithTimeout(5*time.Second))
+	}
+	waitFor(t, "process-1", func(r vanflow.Record) bool {
+		return *r.(vanflow.ProcessRecord).Name == "backend"
+	})
+
+	process.Group = ptrTo("payments")
+	assert.NilError(t, pub.Publish(process))
+	assert.NilError(t, pub.Publish(vanflow.LogRecord{
+		BaseRecord: vanflow.NewBase("log-1", time.Now()),
+		LogText:    ptrTo("deployment rolled out"),
+	}))
+	waitFor(t, "process-1", func(r vanflow.Record) bool {
+		group := r.(vanflow.ProcessRecord).Group
+		return group != nil && *group == "payments"
+	})
+	waitFor(t, "log-1", func(r vanflow.Record) bool { return true })
+	assert.Equal(t, len(pub.Records()), 2)
+
+	assert.NilError(t, pub.Terminate("process-1"))
+	waitFor(t, "process-1", func(r vanflow.Record) bool {
+		return r.(vanflow.ProcessRecord).EndTime != nil
+	})
+	assert.Equal(t, len(pub.Records()), 1)
+	assert.Assert(t, errors.Is(pub.Terminate("process-1"), ErrUnknownRecord))
+
+	cancel()
+	assert.NilError(t, <-runErr)
+}
+
+func TestPublishInvalid(t *testing.T) {
+	pub, err := New(session.NewMockContainerFactory(), Config{ID: "test-agent"})
+	assert.NilError(t, err)
+	assert.NilError(t, pub.Publish(vanflow.SiteRecord{BaseRecord: vanflow.NewBase("shared-id")}))
+	testcases := []struct {
+		Record   vanflow.Record
+		ErrorMsg string
+	}{
+		{Record: nil, ErrorMsg: "invalid record: record is nil"},
+		{Record: vanflow.SiteRecord{}, ErrorMsg: "invalid record: SiteRecord has no ID"},
+		{Record: unregisteredRecord{BaseRecord: vanflow.NewBase("x")}, ErrorMsg: "invalid record: encode error: unregistered record type"},
+		{Record: vanflow.TransportBiflowRecord{BaseRecord: vanflow.NewBase("flow-1")}, ErrorMsg: "invalid record: flow records are published by routers"},
+		{Record: vanflow.ProcessRecord{BaseRecord: vanflow.NewBase("process-1")}, ErrorMsg: `invalid record: ProcessRecord "process-1" has no Parent site`},
+		{Record: vanflow.RouterRecord{BaseRecord: vanflow.NewBase("shared-id")}, ErrorMsg: `invalid record: RouterRecord "shared-id" already published as a SiteRecord`},
+	}
+	for _, tc := range testcases {
+		err := pub.Publish(tc.Record)
+		assert.Assert(t, errors.Is(err, ErrInvalidRecord))
+		assert.ErrorContains(t, err, tc.ErrorMsg)
+	}
+}
+
+func ptrTo[T any](obj T) *T { return &obj }
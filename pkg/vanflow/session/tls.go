@@ -0,0 +1,68 @@
+package session
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/skupperproject/skupper/internal/utils/tlscfg"
+)
+
+// TLSSpec locates the files holding the TLS credentials for a connection
+type TLSSpec struct {
+	CA         string `json:"ca,omitempty"`
+	Cert       string `json:"cert,omitempty"`
+	Key        string `json:"key,omitempty"`
+	SkipVerify bool   `json:"insecure,omitempty"`
+}
+
+// HasCert returns true when a certificate is configured
+func (t TLSSpec) HasCert() bool {
+	return len(t.Cert) > 0
+}
+
+// TLSConfig loads the configured credentials into a tls.Config
+func (t TLSSpec) TLSConfig() (*tls.Config, error) {
+	config := tlscfg.Modern()
+
+	config.InsecureSkipVerify = t.SkipVerify
+
+	if len(t.CA) > 0 && !t.SkipVerify {
+		certPool := x509.NewCertPool()
+		file, err := os.ReadFile(t.CA)
+		if err != nil {
+			return nil, err
+		}
+		if ok := certPool.AppendCertsFromPEM(file); !ok {
+			return nil, fmt.Errorf("failed to add CA to certificate pool")
+		}
+		config.RootCAs = certPool
+	}
+
+	if t.HasCert() {
+		tlsCert, err := tls.LoadX509KeyPair(t.Cert, t.Key)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{tlsCert}
+	}
+
+	return config, nil
+}
+
+// ContainerConfig returns the configuration for a Container connecting to
+// a router with these credentials, authenticating with the client
+// certificate when one is configured.
+func (t TLSSpec) ContainerConfig() (ContainerConfig, error) {
+	var cfg ContainerConfig
+	tlsConfig, err := t.TLSConfig()
+	if err != nil {
+		return cfg, err
+	}
+	cfg.TLSConfig = tlsConfig
+	if t.HasCert() {
+		cfg.SASLType = SASLTypeExternal
+	}
+	return cfg, nil
+}
@@ -0,0 +1,51 @@
+package session
+
+import (
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/skupperproject/skupper/internal/certs"
+)
+
+func TestTLSSpecContainerConfig(t *testing.T) {
+	dir := t.TempDir()
+	ca := certs.GenerateSecret("ca", "ca", "", 0, nil)
+	client := certs.GenerateSecret("client", "client", "", 0, &ca)
+	files := map[string][]byte{
+		"ca.crt":  ca.Data["tls.crt"],
+		"tls.crt": client.Data["tls.crt"],
+		"tls.key": client.Data["tls.key"],
+	}
+	for name, data := range files {
+		assert.NilError(t, os.WriteFile(filepath.Join(dir, name), data, 0600))
+	}
+
+	cfg, err := TLSSpec{CA: filepath.Join(dir, "ca.crt")}.ContainerConfig()
+	assert.NilError(t, err)
+	assert.Equal(t, cfg.SASLType, SASLType(""))
+	assert.Assert(t, cfg.TLSConfig.RootCAs != nil)
+	assert.Equal(t, cfg.TLSConfig.MinVersion, uint16(tls.VersionTLS13))
+
+	cfg, err = TLSSpec{
+		CA:   filepath.Join(dir, "ca.crt"),
+		Cert: filepath.Join(dir, "tls.crt"),
+		Key:  filepath.Join(dir, "tls.key"),
+	}.ContainerConfig()
+	assert.NilError(t, err)
+	assert.Equal(t, cfg.SASLType, SASLTypeExternal)
+	assert.Equal(t, len(cfg.TLSConfig.Certificates), 1)
+
+	cfg, err = TLSSpec{CA: filepath.Join(dir, "ca.crt"), SkipVerify: true}.ContainerConfig()
+	assert.NilError(t, err)
+	assert.Assert(t, cfg.TLSConfig.InsecureSkipVerify)
+	assert.Assert(t, cfg.TLSConfig.RootCAs == nil)
+
+	_, err = TLSSpec{CA: filepath.Join(dir, "missing.crt")}.ContainerConfig()
+	assert.Assert(t, err != nil)
+	_, err = TLSSpec{CA: filepath.Join(dir, "tls.key")}.ContainerConfig()
+	assert.ErrorContains(t, err, "failed to add CA to certificate pool")
+}
@@ -0,0 +1,496 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/skupperproject/skupper/pkg/vanflow"
+)
+
+const (
+	diskLogVersion = 1
+
+	defaultCompactionRatio = 4
+	defaultFlushInterval   = time.Second
+	minCompactionEntries   = 1024
+	maxLogLineSize         = 16 * 1024 * 1024
+)
+
+// DiskStoreConfig configures a store that persists its entries to disk.
+type DiskStoreConfig struct {
+	SyncMapStoreConfig
+
+	// Path to the log file backing the store. The file and its parent
+	// directory are created when they do not exist.
+	Path string
+
+	// RecordTypes lists the record types that can be persisted. Records of
+	// other types are kept in memory only.
+	RecordTypes []vanflow.Record
+
+	// CompactionRatio controls how large the log can grow relative to the
+	// number of live entries before it is rewritten. Defaults to 4.
+	CompactionRatio int
+
+	// FlushInterval is the maximum amount of time writes are buffered
+	// before being written to the log file. Defaults to one second.
+	FlushInterval time.Duration
+}
+
+// NewDiskStore returns a store that keeps its entries in memory like the
+// store returned by NewSyncMapStore, while recording every change to an
+// append-only log on disk. When the log already exists its entries are
+// restored before returning. Indexes are not written to disk - they are
+// rebuilt from the restored entries using the configured Indexers.
+//
+// Restoring entries does not invoke the configured event handlers. The
+// returned store implements io.Closer, and should be closed to ensure
+// buffered writes are flushed to disk.
+func NewDiskStore(cfg DiskStoreConfig) (Interface, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("disk store path is required")
+	}
+	if cfg.CompactionRatio < 2 {
+		cfg.CompactionRatio = defaultCompactionRatio
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultFlushInterval
+	}
+	s := &diskStore{
+		path:            cfg.Path,
+		handlers:        cfg.Handlers,
+		types:           make(map[string]reflect.Type, len(cfg.RecordTypes)),
+		unknownTypes:    make(map[string]struct{}),
+		compactionRatio: cfg.CompactionRatio,
+		flushInterval:   cfg.FlushInterval,
+		logger: slog.New(slog.Default().Handler()).With(
+			slog.String("component", "vanflow.store.disk"),
+			slog.String("path", cfg.Path),
+		),
+	}
+	for _, record := range cfg.RecordTypes {
+		s.types[record.GetTypeMeta().String()] = reflect.Indirect(reflect.ValueOf(record)).Type()
+	}
+	s.mem = NewSyncMapStore(SyncMapStoreConfig{
+		Indexers: cfg.Indexers,
+		Handlers: EventHandlerFuncs{
+			OnAdd:    s.handleAdd,
+			OnChange: s.handleChange,
+			OnDelete: s.handleDelete,
+		},
+	}).(*syncMapStore)
+
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0755); err != nil {
+		return nil, fmt.Errorf("error creating disk store directory: %w", err)
+	}
+	entries, complete, err := s.restore()
+	if err != nil {
+		return nil, err
+	}
+	s.mem.Replace(entries)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !complete {
+		s.logger.Info("disk store log was incomplete: rewriting from restored entries",
+			slog.Int("count", len(entries)))
+	}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+type diskStore struct {
+	path            string
+	logger          *slog.Logger
+	handlers        EventHandlerFuncs
+	types           map[string]reflect.Type
+	compactionRatio int
+	flushInterval   time.Duration
+
+	// mu serializes all changes to the store so that the log contains
+	// changes in the same order that they were made to mem.
+	mu      sync.Mutex
+	mem     *syncMapStore
+	pending []func()
+
+	file         *os.File
+	w            *bufio.Writer
+	written      int
+	flushTimer   *time.Timer
+	closed       bool
+	unknownTypes map[string]struct{}
+
+	// fmu guards the writer against concurrent flushes from flushTimer
+	fmu sync.Mutex
+}
+
+type logOp string
+
+const (
+	opHeader logOp = "header"
+	opPut    logOp = "put"
+	opDelete logOp = "delete"
+)
+
+// logEntry is a single line in the disk store log
+type logEntry struct {
+	Op         logOp           `json:"op"`
+	Version    int             `json:"version,omitempty"`
+	ID         string          `json:"id,omitempty"`
+	Type       string          `json:"type,omitempty"`
+	LastUpdate time.Time       `json:"lastUpdate,omitzero"`
+	Source     *SourceRef      `json:"source,omitempty"`
+	Record     json.RawMessage `json:"record,omitempty"`
+}
+
+func (s *diskStore) Add(record vanflow.Record, source SourceRef) bool {
+	var ok bool
+	s.mutate(func() { ok = s.mem.Add(record, source) })
+	return ok
+}
+
+func (s *diskStore) Update(record vanflow.Record) bool {
+	var ok bool
+	s.mutate(func() { ok = s.mem.Update(record) })
+	return ok
+}
+
+func (s *diskStore) Get(id string) (Entry, bool) {
+	return s.mem.Get(id)
+}
+
+func (s *diskStore) Delete(id string) (Entry, bool) {
+	var (
+		entry Entry
+		ok    bool
+	)
+	s.mutate(func() { entry, ok = s.mem.Delete(id) })
+	return entry, ok
+}
+
+func (s *diskStore) Patch(record vanflow.Record, source SourceRef) {
+	s.mutate(func() { s.mem.Patch(record, source) })
+}
+
+func (s *diskStore) List() []Entry {
+	return s.mem.List()
+}
+
+func (s *diskStore) Index(index string, exemplar Entry) []Entry {
+	return s.mem.Index(index, exemplar)
+}
+
+func (s *diskStore) IndexValues(index string) []string {
+	return s.mem.IndexValues(index)
+}
+
+func (s *diskStore) Replace(entries []Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mem.Replace(entries)
+	if err := s.compact(); err != nil {
+		s.logger.Error("error rewriting disk store log", slog.Any("error", err))
+	}
+}
+
+// Close flushes any buffered writes to disk and closes the log file.
+func (s *diskStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fmu.Lock()
+	defer s.fmu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.flushTimer != nil {
+		s.flushTimer.Stop()
+	}
+	var errs []error
+	if err := s.w.Flush(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := s.file.Sync(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := s.file.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// mutate runs fn while holding the store lock and then invokes any event
+// handlers queued by the changes fn made.
+func (s *diskStore) mutate(fn func()) {
+	s.mu.Lock()
+	fn()
+	events := s.pending
+	s.pending = nil
+	if err := s.maybeCompact(); err != nil {
+		s.logger.Error("error compacting disk store log", slog.Any("error", err))
+	}
+	s.mu.Unlock()
+	for _, event := range events {
+		event()
+	}
+}
+
+func (s *diskStore) handleAdd(e Entry) {
+	s.appendPut(e)
+	if s.handlers.OnAdd != nil {
+		s.pending = append(s.pending, func() { s.handlers.OnAdd(e) })
+	}
+}
+
+func (s *diskStore) handleChange(prev, curr Entry) {
+	s.appendPut(curr)
+	if s.handlers.OnChange != nil {
+		s.pending = append(s.pending, func() { s.handlers.OnChange(prev, curr) })
+	}
+}
+
+func (s *diskStore) handleDelete(e Entry) {
+	s.append(logEntry{Op: opDelete, ID: e.Record.Identity()})
+	if s.handlers.OnDelete != nil {
+		s.pending = append(s.pending, func() { s.handlers.OnDelete(e) })
+	}
+}
+
+func (s *diskStore) appendPut(e Entry) {
+	line, ok := s.encode(e)
+	if !ok {
+		return
+	}
+	s.append(line)
+}
+
+func (s *diskStore) encode(e Entry) (logEntry, bool) {
+	typ := e.Record.GetTypeMeta().String()
+	if _, ok := s.types[typ]; !ok {
+		if _, seen := s.unknownTypes[typ]; !seen {
+			s.unknownTypes[typ] = struct{}{}
+			s.logger.Error("record type not registered with disk store: will not be persisted",
+				slog.String("type", typ))
+		}
+		return logEntry{}, false
+	}
+	raw, err := json.Marshal(e.Record)
+	if err != nil {
+		s.logger.Error("error encoding record for disk store",
+			slog.String("type", typ),
+			slog.String("id", e.Record.Identity()),
+			slog.Any("error", err))
+		return logEntry{}, false
+	}
+	source := e.Source
+	return logEntry{
+		Op:         opPut,
+		ID:         e.Record.Identity(),
+		Type:       typ,
+		LastUpdate: e.LastUpdate,
+		Source:     &source,
+		Record:     raw,
+	}, true
+}
+
+func (s *diskStore) decode(line logEntry) (Entry, error) {
+	var entry Entry
+	typ, ok := s.types[line.Type]
+	if !ok {
+		return entry, fmt.Errorf("unknown record type %q", line.Type)
+	}
+	v := reflect.New(typ)
+	if err := json.Unmarshal(line.Record, v.Interface()); err != nil {
+		return entry, fmt.Errorf("error decoding %s record %q: %w", line.Type, line.ID, err)
+	}
+	record, ok := v.Elem().Interface().(vanflow.Record)
+	if !ok {
+		return entry, fmt.Errorf("type %s does not implement vanflow.Record", typ)
+	}
+	entry.Record = record
+	entry.LastUpdate = line.LastUpdate
+	if line.Source != nil {
+		entry.Source = *line.Source
+	}
+	return entry, nil
+}
+
+func (s *diskStore) append(line logEntry) {
+	s.fmu.Lock()
+	defer s.fmu.Unlock()
+	if s.closed {
+		return
+	}
+	if err := writeLogEntry(s.w, line); err != nil {
+		s.logger.Error("error writing to disk store log", slog.Any("error", err))
+		return
+	}
+	s.written++
+	if s.flushTimer == nil {
+		s.flushTimer = time.AfterFunc(s.flushInterval, s.flush)
+	}
+}
+
+func (s *diskStore) flush() {
+	s.fmu.Lock()
+	defer s.fmu.Unlock()
+	s.flushTimer = nil
+	if s.closed {
+		return
+	}
+	if err := s.w.Flush(); err != nil {
+		s.logger.Error("error flushing disk store log", slog.Any("error", err))
+	}
+}
+
+// maybeCompact rewrites the log when it has grown past the configured ratio
+// of live entries. Must be called with mu held.
+func (s *diskStore) maybeCompact() error {
+	if s.written < minCompactionEntries {
+		return nil
+	}
+	if s.written < s.mem.len()*s.compactionRatio {
+		return nil
+	}
+	return s.compact()
+}
+
+// compact replaces the log with a new one containing only the current
+// entries. Must be called with mu held.
+func (s *diskStore) compact() error {
+	s.fmu.Lock()
+	defer s.fmu.Unlock()
+	if s.closed {
+		return nil
+	}
+
+	tmpPath := s.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error creating compacted log: %w", err)
+	}
+	w := bufio.NewWriter(tmp)
+	written := 0
+	err = func() error {
+		if err := writeLogEntry(w, logEntry{Op: opHeader, Version: diskLogVersion}); err != nil {
+			return err
+		}
+		for _, entry := range s.mem.List() {
+			line, ok := s.encode(entry)
+			if !ok {
+				continue
+			}
+			if err := writeLogEntry(w, line); err != nil {
+				return err
+			}
+			written++
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		return tmp.Sync()
+	}()
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("error writing compacted log: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("error replacing log with compacted log: %w", err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening compacted log: %w", err)
+	}
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file = file
+	s.w = bufio.NewWriter(file)
+	s.written = written
+	return nil
+}
+
+// restore reads the entries from an existing log. Returns false when the log
+// could not be read in full, such as when the last write was interrupted.
+func (s *diskStore) restore() ([]Entry, bool, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, true, nil
+		}
+		return nil, false, fmt.Errorf("error opening disk store log: %w", err)
+	}
+	defer file.Close()
+
+	var (
+		entries  = make(map[string]Entry)
+		complete = true
+	)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineSize)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		var line logEntry
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			s.logger.Error("stopped reading malformed disk store log",
+				slog.Int("line", lineNo), slog.Any("error", err))
+			complete = false
+			break
+		}
+		switch line.Op {
+		case opHeader:
+			if line.Version != diskLogVersion {
+				return nil, false, fmt.Errorf("unsupported disk store log version %d", line.Version)
+			}
+		case opPut:
+			entry, err := s.decode(line)
+			if err != nil {
+				s.logger.Error("skipping unreadable disk store log entry",
+					slog.Int("line", lineNo), slog.Any("error", err))
+				complete = false
+				continue
+			}
+			entries[line.ID] = entry
+		case opDelete:
+			delete(entries, line.ID)
+		default:
+			s.logger.Error("skipping unknown disk store log operation",
+				slog.Int("line", lineNo), slog.String("op", string(line.Op)))
+			complete = false
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		s.logger.Error("error reading disk store log", slog.Any("error", err))
+		complete = false
+	}
+	out := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		out = append(out, entry)
+	}
+	return out, complete, nil
+}
+
+func writeLogEntry(w io.Writer, line logEntry) error {
+	raw, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	raw = append(raw, '\n')
+	_, err = w.Write(raw)
+	return err
+}
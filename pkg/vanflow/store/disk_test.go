@@ -0,0 +1,137 @@
+package store
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+)
+
+func newTestDiskStore(t *testing.T, path string, handlers EventHandlerFuncs) Interface {
+	t.Helper()
+	stor, err := NewDiskStore(DiskStoreConfig{
+		SyncMapStoreConfig: SyncMapStoreConfig{Handlers: handlers},
+		Path:               path,
+		RecordTypes:        []vanflow.Record{vanflow.LogRecord{}, vanflow.RouterRecord{}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating disk store: %s", err)
+	}
+	return stor
+}
+
+func closeStore(t *testing.T, stor Interface) {
+	t.Helper()
+	if err := stor.(io.Closer).Close(); err != nil {
+		t.Fatalf("unexpected error closing disk store: %s", err)
+	}
+}
+
+func TestDiskStoreRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.log")
+	source := SourceRef{ID: "test", Version: "0"}
+	startTime := time.Now().Truncate(time.Microsecond)
+
+	var addEvents []Entry
+	stor := newTestDiskStore(t, path, EventHandlerFuncs{
+		OnAdd: func(e Entry) { addEvents = append(addEvents, e) },
+	})
+	r0 := vanflow.RouterRecord{BaseRecord: vanflow.NewBase("0", startTime), Parent: ptrTo("site")}
+	r1 := vanflow.LogRecord{BaseRecord: vanflow.NewBase("1"), LogText: ptrTo("one")}
+	r2 := vanflow.LogRecord{BaseRecord: vanflow.NewBase("2"), LogText: ptrTo("two")}
+	stor.Add(r0, source)
+	stor.Add(r1, source)
+	stor.Add(r2, source)
+	stor.Patch(vanflow.RouterRecord{BaseRecord: vanflow.NewBase("0"), Namespace: ptrTo("ns")}, source)
+	r1.LogText = ptrTo("uno")
+	stor.Update(r1)
+	stor.Delete("2")
+	// unregistered types are kept in memory only
+	stor.Add(vanflow.SiteRecord{BaseRecord: vanflow.NewBase("3")}, source)
+	if expected, actual := 4, len(addEvents); expected != actual {
+		t.Errorf("expected %d add events but got %d", expected, actual)
+	}
+	closeStore(t, stor)
+
+	restored := newTestDiskStore(t, path, EventHandlerFuncs{
+		OnAdd: func(Entry) { t.Errorf("unexpected call to OnAdd while restoring") },
+	})
+	defer closeStore(t, restored)
+
+	r0.Namespace = ptrTo("ns")
+	expected := []Entry{
+		{Record: r0, Metadata: Metadata{Source: source}},
+		{Record: r1, Metadata: Metadata{Source: source}},
+	}
+	actual := restored.List()
+	if !cmp.Equal(actual, expected, ignoreLastUpdateAndOrder...) {
+		t.Errorf("restored store contents do not match expected: %s", cmp.Diff(actual, expected, ignoreLastUpdateAndOrder...))
+	}
+	if expected, actual := 2, len(restored.Index(SourceIndex, Entry{Metadata: Metadata{Source: source}})); expected != actual {
+		t.Errorf("expected %d entries in restored source index but got %d", expected, actual)
+	}
+}
+
+func TestDiskStoreCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.log")
+	source := SourceRef{ID: "test", Version: "0"}
+	stor := newTestDiskStore(t, path, EventHandlerFuncs{})
+	for i := 0; i < 16; i++ {
+		stor.Add(vanflow.LogRecord{BaseRecord: vanflow.NewBase(fmt.Sprint(i))}, source)
+	}
+	for i := 0; i < minCompactionEntries*2; i++ {
+		stor.Update(vanflow.LogRecord{BaseRecord: vanflow.NewBase(fmt.Sprint(i % 16)), SourceLine: ptrTo(uint64(i))})
+	}
+	if written := stor.(*diskStore).written; written >= minCompactionEntries {
+		t.Errorf("expected log to have been compacted but it contains %d entries", written)
+	}
+	closeStore(t, stor)
+
+	restored := newTestDiskStore(t, path, EventHandlerFuncs{})
+	defer closeStore(t, restored)
+	if expected, actual := 16, len(restored.List()); expected != actual {
+		t.Errorf("expected %d entries after restoring compacted log but got %d", expected, actual)
+	}
+	entry, ok := restored.Get("15")
+	if !ok {
+		t.Fatal("expected record 15 to be restored")
+	}
+	if expected, actual := uint64(minCompactionEntries*2-1), *entry.Record.(vanflow.LogRecord).SourceLine; expected != actual {
+		t.Errorf("expected restored record to have latest value %d but got %d", expected, actual)
+	}
+}
+
+func TestDiskStoreTruncatedLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.log")
+	source := SourceRef{ID: "test", Version: "0"}
+	stor := newTestDiskStore(t, path, EventHandlerFuncs{})
+	stor.Add(vanflow.LogRecord{BaseRecord: vanflow.NewBase("0")}, source)
+	stor.Add(vanflow.LogRecord{BaseRecord: vanflow.NewBase("1")}, source)
+	closeStore(t, stor)
+
+	// simulate a write interrupted by a crash
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"op":"put","id":"2","type":"flow/v1/LogRec`)
+	f.Close()
+
+	restored := newTestDiskStore(t, path, EventHandlerFuncs{})
+	if expected, actual := 2, len(restored.List()); expected != actual {
+		t.Errorf("expected %d entries restored from truncated log but got %d", expected, actual)
+	}
+	restored.Add(vanflow.LogRecord{BaseRecord: vanflow.NewBase("2")}, source)
+	closeStore(t, restored)
+
+	restored = newTestDiskStore(t, path, EventHandlerFuncs{})
+	defer closeStore(t, restored)
+	if expected, actual := 3, len(restored.List()); expected != actual {
+		t.Errorf("expected %d entries after rewriting truncated log but got %d", expected, actual)
+	}
+}
@@ -247,6 +247,12 @@ func (m *syncMapStore) Replace(items []Entry) {
 	}
 }
 
+func (m *syncMapStore) len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.items)
+}
+
 func (m *syncMapStore) unindex(key string, entry Entry) {
 	for name, indexer := range m.indexers {
 		indexVals := indexer(entry)
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"github.com/skupperproject/skupper/pkg/vanflow"
+)
+
+// NewUnion returns a read-only view over several stores with disjoint
+// records. Reads return the combined results of each store in the order the
+// stores were given. Writes are not supported by the union and are ignored;
+// records should be written to the underlying stores directly.
+func NewUnion(stores ...Interface) Interface {
+	return union(stores)
+}
+
+type union []Interface
+
+func (u union) Add(record vanflow.Record, source SourceRef) bool {
+	return false
+}
+
+func (u union) Update(vanflow.Record) bool {
+	return false
+}
+
+func (u union) Delete(id string) (Entry, bool) {
+	return Entry{}, false
+}
+
+func (u union) Patch(record vanflow.Record, source SourceRef) {}
+
+func (u union) Replace([]Entry) {}
+
+func (u union) Get(id string) (Entry, bool) {
+	for _, stor := range u {
+		if entry, ok := stor.Get(id); ok {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+func (u union) List() []Entry {
+	var out []Entry
+	for _, stor := range u {
+		out = append(out, stor.List()...)
+	}
+	return out
+}
+
+func (u union) Index(index string, exemplar Entry) []Entry {
+	var out []Entry
+	for _, stor := range u {
+		out = append(out, stor.Index(index, exemplar)...)
+	}
+	return out
+}
+
+func (u union) IndexValues(index string) []string {
+	var out []string
+	seen := make(map[string]struct{})
+	for _, stor := range u {
+		for _, value := range stor.IndexValues(index) {
+			if _, ok := seen[value]; ok {
+				continue
+			}
+			seen[value] = struct{}{}
+			out = append(out, value)
+		}
+	}
+	return out
+}
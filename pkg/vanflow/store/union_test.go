@@ -0,0 +1,62 @@
+package store
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/skupperproject/skupper/pkg/vanflow"
+)
+
+func TestUnion(t *testing.T) {
+	east := NewSyncMapStore(SyncMapStoreConfig{})
+	west := NewSyncMapStore(SyncMapStoreConfig{})
+	east.Add(vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-east")}, SourceRef{ID: "router-east"})
+	east.Add(vanflow.RouterRecord{BaseRecord: vanflow.NewBase("router-east")}, SourceRef{ID: "router-east"})
+	west.Add(vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-west")}, SourceRef{ID: "router-west"})
+
+	u := NewUnion(east, west)
+
+	if entry, ok := u.Get("site-west"); !ok || entry.Record.Identity() != "site-west" {
+		t.Errorf("expected to get site-west from union: %v", entry)
+	}
+	if _, ok := u.Get("site-north"); ok {
+		t.Error("unexpected record for unknown id")
+	}
+
+	ids := func(entries []Entry) []string {
+		var out []string
+		for _, e := range entries {
+			out = append(out, e.Record.Identity())
+		}
+		sort.Strings(out)
+		return out
+	}
+	if actual, expected := ids(u.List()), []string{"router-east", "site-east", "site-west"}; !cmp.Equal(actual, expected) {
+		t.Errorf("unexpected list result: %s", cmp.Diff(expected, actual))
+	}
+	sites := u.Index(TypeIndex, Entry{Record: vanflow.SiteRecord{}})
+	if actual, expected := ids(sites), []string{"site-east", "site-west"}; !cmp.Equal(actual, expected) {
+		t.Errorf("unexpected index result: %s", cmp.Diff(expected, actual))
+	}
+	values := u.IndexValues(TypeIndex)
+	sort.Strings(values)
+	expectedValues := []string{
+		vanflow.RouterRecord{}.GetTypeMeta().String(),
+		vanflow.SiteRecord{}.GetTypeMeta().String(),
+	}
+	sort.Strings(expectedValues)
+	if !cmp.Equal(values, expectedValues) {
+		t.Errorf("unexpected index values: %s", cmp.Diff(expectedValues, values))
+	}
+
+	if u.Add(vanflow.SiteRecord{BaseRecord: vanflow.NewBase("site-north")}, SourceRef{}) {
+		t.Error("expected union to ignore writes")
+	}
+	if _, ok := u.Delete("site-east"); ok {
+		t.Error("expected union to ignore deletes")
+	}
+	if _, ok := east.Get("site-east"); !ok {
+		t.Error("expected underlying store to be unchanged")
+	}
+}